@@ -140,7 +140,10 @@ function gameStateReducerFunction(
     // or someone ran out of time in a timed game, someone terminated, etc.
     // { type: 'gameOver', endCondition: 1, playerIndex: 0 }
     case "gameOver": {
-      if (action.endCondition !== EndCondition.Normal) {
+      if (
+        action.endCondition !== EndCondition.Normal &&
+        action.endCondition !== EndCondition.Conceded
+      ) {
         state.score = 0;
       }
 
@@ -141,7 +141,8 @@ function turnReducerFunction(
       if (
         action.endCondition === EndCondition.Timeout ||
         action.endCondition === EndCondition.Terminated ||
-        action.endCondition === EndCondition.IdleTimeout
+        action.endCondition === EndCondition.IdleTimeout ||
+        action.endCondition === EndCondition.Conceded
       ) {
         turn.segment += 1;
       }
@@ -22,7 +22,8 @@ export function shouldStore(
     action.type === "gameOver" &&
     action.endCondition !== EndCondition.Timeout &&
     action.endCondition !== EndCondition.Terminated &&
-    action.endCondition !== EndCondition.IdleTimeout
+    action.endCondition !== EndCondition.IdleTimeout &&
+    action.endCondition !== EndCondition.Conceded
   ) {
     return true;
   }
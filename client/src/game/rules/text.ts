@@ -130,6 +130,10 @@ export function gameOver(
       return `${playerName} was left with 0 clues and 0 cards!`;
     }
 
+    case EndCondition.Conceded: {
+      return `Players conceded with ${score} points.`;
+    }
+
     default: {
       ensureAllCases(endCondition);
       break;
@@ -9,5 +9,6 @@ enum EndCondition {
   CharacterSoftlock,
   AllOrNothingFail,
   AllOrNothingSoftlock,
+  Conceded,
 }
 export default EndCondition;
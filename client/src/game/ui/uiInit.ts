@@ -106,5 +106,6 @@ function finishedDownloadingImages() {
   // we now need the specific actions that have taken place in this game so far
   globals.lobby.conn!.send("getGameInfo2", {
     tableID: globals.lobby.tableID,
+    batch: true,
   });
 }
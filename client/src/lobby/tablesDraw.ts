@@ -204,6 +204,12 @@ export function tableSpectate(table: Table): void {
     return;
   }
 
+  // The password is needed to spectate an ongoing game (but not to view its replay)
+  if (table.passwordProtected && !table.sharedReplay) {
+    modals.passwordShow(table.id, true);
+    return;
+  }
+
   globals.conn!.send("tableSpectate", {
     tableID: table.id,
     shadowingPlayerIndex: -1,
@@ -6,6 +6,9 @@ import * as lobbyNav from "./lobby/nav";
 import { closeAllTooltips, parseIntSafe } from "./misc";
 import * as sounds from "./sounds";
 
+// Whether the password modal is for spectating a table instead of joining it
+let passwordSpectate = false;
+
 // The list of all of the modals
 const lobbyModals = [
   "password",
@@ -39,7 +42,8 @@ export function init(): void {
   });
 }
 
-export function passwordShow(tableID: number): void {
+export function passwordShow(tableID: number, spectate = false): void {
+  passwordSpectate = spectate;
   setShadeOpacity(0.75);
   closeAllTooltips();
   globals.modalShowing = true;
@@ -73,10 +77,18 @@ function passwordSubmit() {
   if (typeof password !== "string") {
     return;
   }
-  globals.conn!.send("tableJoin", {
-    tableID,
-    password,
-  });
+  if (passwordSpectate) {
+    globals.conn!.send("tableSpectate", {
+      tableID,
+      shadowingPlayerIndex: -1,
+      password,
+    });
+  } else {
+    globals.conn!.send("tableJoin", {
+      tableID,
+      password,
+    });
+  }
 
   // Record the password in local storage (cookie)
   localStorage.setItem("joinTablePassword", password);
@@ -61,8 +61,35 @@ export default function websocketInit(): void {
   globals.conn = conn;
 }
 
+interface InitBatchData {
+  tableID: number;
+  messages: Array<{
+    command: string;
+    data: unknown;
+  }>;
+  final: boolean;
+}
+
 // We specify a callback for each command/message that we expect to receive from the server
 function initCommands(conn: Connection) {
+  // The server can bundle many messages together into one "initBatch" message
+  // (in response to a "getGameInfo2" with "batch" set to true)
+  // Handle each bundled message as if it had been received separately
+  conn.on("initBatch", (data: InitBatchData) => {
+    for (const message of data.messages) {
+      const callback = conn.callbacks[message.command];
+      if (callback === undefined) {
+        console.error(
+          "Received batched WebSocket message with no callback:",
+          message.command,
+          message.data,
+        );
+        continue;
+      }
+      callback(message.data);
+    }
+  });
+
   // Activate the command handlers for commands relating to both the lobby and the game
   for (const [commandName, commandFunction] of commands) {
     conn.on(commandName, (data: unknown) => {
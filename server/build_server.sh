@@ -17,8 +17,11 @@ if [[ -z $HOME ]] && [[ -z $CI ]]; then
 fi
 
 # Compile the Golang code
+# (the commit and the build time are embedded so that they can be queried later with "serverInfo")
 cd "$DIR/src"
-go build -o "$DIR/../$REPO"
+COMMIT="$(git rev-parse HEAD)"
+BUILD_TIME="$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+go build -ldflags "-X main.buildCommit=$COMMIT -X main.buildTime=$BUILD_TIME" -o "$DIR/../$REPO"
 if [[ $? -ne 0 ]]; then
   echo "$REPO - Go compilation failed!"
   exit 1
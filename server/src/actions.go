@@ -1,6 +1,6 @@
 // Actions represent a change in the game state
 // Different actions will have different fields
-// Any actions implemented here must also be accounted for in the "restoreTables()" function
+// Any actions implemented here must also be accounted for in the "deserializeAction()" function
 
 package main
 
@@ -61,10 +61,16 @@ type ActionPlayerTimes struct {
 }
 
 type ActionStrike struct {
-	Type  string `json:"type"`
-	Num   int    `json:"num"`   // Whether it was the first strike, the second strike, etc.
-	Turn  int    `json:"turn"`  // The turn that the strike happened
-	Order int    `json:"order"` // The order of the card that was played
+	Type        string `json:"type"`
+	Num         int    `json:"num"`   // Whether it was the first strike, the second strike, etc.
+	Turn        int    `json:"turn"`  // The turn that the strike happened
+	Order       int    `json:"order"` // The order of the card that was played
+	PlayerIndex int    `json:"playerIndex"`
+	SuitIndex   int    `json:"suitIndex"`
+	Rank        int    `json:"rank"`
+	// The ranks that would have successfully played on the stack of the suit at the time
+	// (more than one rank is possible in variants with reversible stacks)
+	Expected []int `json:"expected"`
 }
 
 type ActionStatus struct {
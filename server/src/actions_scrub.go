@@ -27,6 +27,33 @@ func CheckScrub(t *Table, action interface{}, userID int) interface{} {
 		return playAction
 	}
 
+	strikeAction, ok := action.(ActionStrike)
+	if ok && strikeAction.Type == "strike" {
+		strikeAction.Scrub(t, userID)
+		return strikeAction
+	}
+
+	return action
+}
+
+// CheckScrubForSession is like "CheckScrub()",
+// but it also removes information that a spectator has chosen not to see
+// (from the "spectateSetOptions" command)
+func CheckScrubForSession(s *Session, t *Table, action interface{}) interface{} {
+	action = CheckScrub(t, action, s.UserID())
+
+	if t.GetPlayerIndexFromID(s.UserID()) != -1 {
+		// The options only apply to spectators
+		return action
+	}
+
+	clueAction, ok := action.(ActionClue)
+	if ok && clueAction.Type == "clue" && !s.ShowTouchedCards() {
+		// The action is a copy, so this does not modify the list of the other spectators
+		clueAction.List = make([]int, 0)
+		return clueAction
+	}
+
 	return action
 }
 
@@ -88,6 +115,27 @@ func (a *ActionDiscard) Scrub(t *Table, userID int) {
 	}
 }
 
+// Scrub removes some information from strikes so that we do not reveal the identity of misplayed
+// cards to anybody (in some specific variants)
+func (a *ActionStrike) Scrub(t *Table, userID int) {
+	// Local variables
+	p := getEquivalentPlayer(t, userID)
+	variant := variants[t.Options.VariantName]
+
+	if p == nil {
+		// Spectators get to see the identities of misplayed cards
+		return
+	}
+
+	if variant.IsThrowItInAHole() {
+		// For the purposes of hiding information, misplays are equivalent to plays
+		// (the expected ranks would also reveal the play stacks)
+		a.Rank = -1
+		a.SuitIndex = -1
+		a.Expected = make([]int, 0)
+	}
+}
+
 // Scrub removes some information from a card identity action so that we do not reveal the identity
 // of sliding cards to the players who are holding those cards
 func (a *ActionCardIdentity) Scrub(t *Table, userID int) {
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"strconv"
+)
+
+// GetActionText returns a human-readable description of an action
+// (e.g. for screen reader users)
+// It returns an empty string for actions that do not need to be described (e.g. draws)
+// Only information that is visible to every player is described, so the result does not need to
+// be scrubbed
+func (g *Game) GetActionText(action interface{}) string {
+	switch a := action.(type) {
+	case ActionClue:
+		msg := g.GetPlayerName(a.Giver) + " clued " + g.GetClueText(a.Clue) + " to " +
+			g.GetPlayerName(a.Target) + ", touching " + strconv.Itoa(len(a.List)) + " card"
+		if len(a.List) != 1 {
+			msg += "s"
+		}
+		return msg
+	case ActionPlay:
+		return g.GetPlayerName(a.PlayerIndex) + " played " + g.GetCardText(a.SuitIndex, a.Rank)
+	case ActionDiscard:
+		verb := "discarded"
+		if a.Failed {
+			verb = "misplayed"
+		}
+		return g.GetPlayerName(a.PlayerIndex) + " " + verb + " " +
+			g.GetCardText(a.SuitIndex, a.Rank)
+	case ActionStrike:
+		return "Strike! (" + strconv.Itoa(a.Num) + " of " + strconv.Itoa(MaxStrikeNum) + ")"
+	case ActionGameOver:
+		switch a.EndCondition {
+		case EndConditionStrikeout:
+			return "The game ended because the team got too many strikes"
+		case EndConditionTimeout:
+			return g.GetPlayerName(a.PlayerIndex) + " ran out of time"
+		case EndConditionTerminated:
+			return g.GetPlayerName(a.PlayerIndex) + " terminated the game"
+		case EndConditionIdleTimeout:
+			return "The game ended because it was idle for too long"
+		case EndConditionConceded:
+			return "The team conceded the game"
+		default:
+			return "The game is over"
+		}
+	}
+
+	return ""
+}
+
+// GetPlayerName returns the name of the player at the given index
+// (or a placeholder if the index is not valid, e.g. when the server ends the game)
+func (g *Game) GetPlayerName(playerIndex int) string {
+	if playerIndex < 0 || playerIndex >= len(g.Players) {
+		return "The server"
+	}
+
+	return g.Players[playerIndex].Name
+}
+
+// GetClueText returns e.g. "red" or "number 3"
+func (g *Game) GetClueText(clue Clue) string {
+	variant := variants[g.Options.VariantName]
+
+	if clue.Type == ClueTypeRank {
+		return "number " + strconv.Itoa(clue.Value)
+	}
+	if clue.Value < 0 || clue.Value >= len(variant.ClueColors) {
+		return "an unknown color"
+	}
+	return variant.ClueColors[clue.Value]
+}
+
+// GetCardText returns e.g. "Red 2"
+func (g *Game) GetCardText(suitIndex int, rank int) string {
+	variant := variants[g.Options.VariantName]
+
+	if suitIndex < 0 || suitIndex >= len(variant.Suits) {
+		return "an unknown card"
+	}
+	return variant.Suits[suitIndex].Name + " " + strconv.Itoa(rank)
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestGetActionText(t *testing.T) {
+	resetTestTables(t)
+	g := newTestGame(t, 2).Game
+
+	tests := []struct {
+		name     string
+		action   interface{}
+		expected string
+	}{
+		{
+			"rank clue",
+			ActionClue{
+				Type:   "clue",
+				Clue:   Clue{Type: ClueTypeRank, Value: 3},
+				Giver:  0,
+				Target: 1,
+				List:   []int{5, 7},
+			},
+			"Alice clued number 3 to Bob, touching 2 cards",
+		},
+		{
+			"color clue",
+			ActionClue{
+				Type:   "clue",
+				Clue:   Clue{Type: ClueTypeColor, Value: 0},
+				Giver:  1,
+				Target: 0,
+				List:   []int{2},
+			},
+			"Bob clued Red to Alice, touching 1 card",
+		},
+		{
+			"play",
+			ActionPlay{Type: "play", PlayerIndex: 1, SuitIndex: 0, Rank: 2},
+			"Bob played Red 2",
+		},
+		{
+			"discard",
+			ActionDiscard{Type: "discard", PlayerIndex: 0, SuitIndex: 1, Rank: 5},
+			"Alice discarded Yellow 5",
+		},
+		{
+			"misplay",
+			ActionDiscard{Type: "discard", PlayerIndex: 0, SuitIndex: 0, Rank: 4, Failed: true},
+			"Alice misplayed Red 4",
+		},
+		{
+			"strike",
+			ActionStrike{Type: "strike", Num: 2},
+			"Strike! (2 of 3)",
+		},
+		{
+			"terminated",
+			ActionGameOver{
+				Type:         "gameOver",
+				EndCondition: EndConditionTerminated,
+				PlayerIndex:  1,
+			},
+			"Bob terminated the game",
+		},
+		{
+			"idle",
+			ActionGameOver{
+				Type:         "gameOver",
+				EndCondition: EndConditionIdleTimeout,
+				PlayerIndex:  -1,
+			},
+			"The game ended because it was idle for too long",
+		},
+		{
+			"draw",
+			ActionDraw{Type: "draw", PlayerIndex: 0, SuitIndex: 0, Rank: 1},
+			"",
+		},
+	}
+
+	for _, test := range tests {
+		if text := g.GetActionText(test.action); text != test.expected {
+			t.Errorf("expected the %v action to be \"%v\", but got \"%v\"",
+				test.name, test.expected, text)
+		}
+	}
+}
+
+func TestGetCardTextUnknownCard(t *testing.T) {
+	resetTestTables(t)
+	g := newTestGame(t, 2).Game
+
+	if text := g.GetCardText(-1, -1); text != "an unknown card" {
+		t.Errorf("expected a scrubbed card to be unknown, but got \"%v\"", text)
+	}
+	if text := g.GetClueText(Clue{Type: ClueTypeColor, Value: 100}); text != "an unknown color" {
+		t.Errorf("expected an invalid color to be unknown, but got \"%v\"", text)
+	}
+}
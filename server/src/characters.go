@@ -280,10 +280,11 @@ func characterValidateSecondAction(s *Session, d *CommandData, g *Game, p *GameP
 	return false
 }
 
-// characterValidateClue returns true if validation fails
-func characterValidateClue(s *Session, d *CommandData, g *Game, p *GamePlayer) bool {
+// characterGetClueError returns the reason that the character of the player prevents them from
+// giving the clue, or an empty string if they can give it
+func characterGetClueError(d *CommandData, g *Game, p *GamePlayer) string {
 	if !g.Options.DetrimentalCharacters {
-		return false
+		return ""
 	}
 
 	// Local variables
@@ -295,22 +296,19 @@ func characterValidateClue(s *Session, d *CommandData, g *Game, p *GamePlayer) b
 		clue.Type == ClueTypeColor &&
 		clue.Value != p.CharacterMetadata {
 
-		s.Warning("You are " + p.Character + ", so you can not give that type of clue.")
-		return true
+		return "You are " + p.Character + ", so you can not give that type of clue."
 	} else if p.Character == "Dumbfounded" && // 1
 		clue.Type == ClueTypeRank &&
 		clue.Value != p.CharacterMetadata {
 
-		s.Warning("You are " + p.Character + ", so you can not give that type of clue.")
-		return true
+		return "You are " + p.Character + ", so you can not give that type of clue."
 	} else if p.Character == "Inept" { // 2
 		cardsTouched := p2.FindCardsTouchedByClue(clue)
 		for _, order := range cardsTouched {
 			c := g.Deck[order]
 			if c.SuitIndex == p.CharacterMetadata {
-				s.Warning("You are " + p.Character + ", " +
-					"so you cannot give clues that touch a specific suit.")
-				return true
+				return "You are " + p.Character + ", " +
+					"so you cannot give clues that touch a specific suit."
 			}
 		}
 	} else if p.Character == "Awkward" { // 3
@@ -318,40 +316,35 @@ func characterValidateClue(s *Session, d *CommandData, g *Game, p *GamePlayer) b
 		for _, order := range cardsTouched {
 			c := g.Deck[order]
 			if c.Rank == p.CharacterMetadata {
-				s.Warning("You are " + p.Character + ", " +
+				return "You are " + p.Character + ", " +
 					"so you cannot give clues that touch cards with a rank of " +
-					strconv.Itoa(p.CharacterMetadata) + ".")
-				return true
+					strconv.Itoa(p.CharacterMetadata) + "."
 			}
 		}
 	} else if p.Character == "Conservative" && // 4
 		len(p2.FindCardsTouchedByClue(clue)) != 1 {
 
-		s.Warning("You are " + p.Character + ", " +
-			"so you can only give clues that touch a single card.")
-		return true
+		return "You are " + p.Character + ", " +
+			"so you can only give clues that touch a single card."
 	} else if p.Character == "Greedy" && // 5
 		len(p2.FindCardsTouchedByClue(clue)) < 2 {
 
-		s.Warning("You are " + p.Character + ", so you can only give clues that touch 2+ cards.")
-		return true
+		return "You are " + p.Character + ", so you can only give clues that touch 2+ cards."
 	} else if p.Character == "Picky" && // 6
 		((clue.Type == ClueTypeRank &&
 			clue.Value%2 == 0) ||
 			(clue.Type == ClueTypeColor &&
 				(clue.Value+1)%2 == 0)) {
 
-		s.Warning("You are " + p.Character + ", " +
-			"so you can only clue odd numbers or odd colors.")
-		return true
+		return "You are " + p.Character + ", " +
+			"so you can only clue odd numbers or odd colors."
 	} else if p.Character == "Spiteful" { // 7
 		leftIndex := p.Index + 1
 		if leftIndex == len(g.Players) {
 			leftIndex = 0
 		}
 		if d.Target == leftIndex {
-			s.Warning("You are " + p.Character + ", so you cannot clue the player to your left.")
-			return true
+			return "You are " + p.Character + ", so you cannot clue the player to your left."
 		}
 	} else if p.Character == "Insolent" { // 8
 		rightIndex := p.Index - 1
@@ -359,27 +352,23 @@ func characterValidateClue(s *Session, d *CommandData, g *Game, p *GamePlayer) b
 			rightIndex = len(g.Players) - 1
 		}
 		if d.Target == rightIndex {
-			s.Warning("You are " + p.Character + ", so you cannot clue the player to your right.")
-			return true
+			return "You are " + p.Character + ", so you cannot clue the player to your right."
 		}
 	} else if p.Character == "Miser" && // 10
 		g.ClueTokens < variant.GetAdjustedClueTokens(4) {
 
-		s.Warning("You are " + p.Character + ", " +
-			"so you cannot give a clue unless there are 4 or more clues available.")
-		return true
+		return "You are " + p.Character + ", " +
+			"so you cannot give a clue unless there are 4 or more clues available."
 	} else if p.Character == "Compulsive" && // 11
 		!p2.IsFirstCardTouchedByClue(clue) &&
 		!p2.IsLastCardTouchedByClue(clue) {
 
-		s.Warning("You are " + p.Character + ", " +
-			"so you can only give a clue if it touches either the newest or oldest card in a hand.")
-		return true
+		return "You are " + p.Character + ", " +
+			"so you can only give a clue if it touches either the newest or oldest card in a hand."
 	} else if p.Character == "Mood Swings" && // 12
 		p.CharacterMetadata == clue.Type {
 
-		s.Warning("You are " + p.Character + ", so cannot give the same clue type twice in a row.")
-		return true
+		return "You are " + p.Character + ", so cannot give the same clue type twice in a row."
 	} else if p.Character == "Insistent" && // 13
 		p.CharacterMetadata != -1 {
 
@@ -393,22 +382,19 @@ func characterValidateClue(s *Session, d *CommandData, g *Game, p *GamePlayer) b
 			}
 		}
 		if !touchedInsistentCard {
-			s.Warning("You are " + p.Character + ", " +
-				"so you must continue to clue a card until it is played or discarded.")
-			return true
+			return "You are " + p.Character + ", " +
+				"so you must continue to clue a card until it is played or discarded."
 		}
 	} else if p.Character == "Genius" && // 24
 		p.CharacterMetadata == -1 {
 
 		if g.ClueTokens < variant.GetAdjustedClueTokens(2) {
-			s.Warning("You are " + p.Character + ", " +
-				"so there needs to be at least two clues available for you to give a clue.")
-			return true
+			return "You are " + p.Character + ", " +
+				"so there needs to be at least two clues available for you to give a clue."
 		}
 
 		if clue.Type != ClueTypeColor {
-			s.Warning("You are " + p.Character + ", so you must give a color clue first.")
-			return true
+			return "You are " + p.Character + ", so you must give a color clue first."
 		}
 	}
 
@@ -416,16 +402,14 @@ func characterValidateClue(s *Session, d *CommandData, g *Game, p *GamePlayer) b
 		clue.Type == ClueTypeRank &&
 		(clue.Value == 2 || clue.Value == 5) {
 
-		s.Warning("You cannot give a number 2 or number 5 clue to a " + p2.Character + " character.")
-		return true
+		return "You cannot give a number 2 or number 5 clue to a " + p2.Character + " character."
 	} else if p2.Character == "Color-Blind" && // 15
 		clue.Type == ClueTypeColor {
 
-		s.Warning("You cannot give that color clue to a " + p2.Character + " character.")
-		return true
+		return "You cannot give that color clue to a " + p2.Character + " character."
 	}
 
-	return false
+	return ""
 }
 
 // characterCheckPlay returns true if the card cannot be played
@@ -47,15 +47,22 @@ func chatServerSend(msg string, room string) {
 func chatServerSendAll(msg string) {
 	chatServerSend(msg, "lobby")
 
-	roomNames := make([]string, 0)
+	tableIDs := make([]uint64, 0)
 	tablesMutex.RLock()
 	for _, t := range tables {
-		roomNames = append(roomNames, t.GetRoomName())
+		tableIDs = append(tableIDs, t.ID)
 	}
 	tablesMutex.RUnlock()
 
-	for _, roomName := range roomNames {
-		chatServerSend(msg, roomName)
+	// The message is stored in the chat of each table,
+	// so we must hold the table lock (e.g. so that it does not race with "serializeTables()")
+	for _, tableID := range tableIDs {
+		t, exists := getTableAndLock(nil, tableID, true)
+		if !exists {
+			continue
+		}
+		chatServerSend(msg, t.GetRoomName())
+		t.Mutex.Unlock()
 	}
 }
 
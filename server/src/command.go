@@ -14,15 +14,75 @@ type CommandData struct {
 	Recipient string `json:"recipient"`
 
 	// tableCreate
-	Name     string   `json:"name"`
-	Options  *Options `json:"options"`
-	Password string   `json:"password"`
+	Name     string          `json:"name"`
+	Options  *Options        `json:"options"`
+	Password string          `json:"password"`
+	Deck     []*CardIdentity `json:"deck"`
+
+	// tableListRunning
+	Variant string `json:"variant"`
+
+	// tableCloneSettings
+	SourceTableID uint64 `json:"sourceTableID"`
+
+	// tableTransferOwner
+	NewOwnerID int `json:"newOwnerID"`
+
+	// tableKickSpectator
+	SpectatorID int  `json:"spectatorID"`
+	Block       bool `json:"block"`
+
+	// tableReserveSeat
+	SeatIndex int `json:"seatIndex"`
+	UserID    int `json:"userID"`
+
+	// variantInfo
+	VariantName string `json:"variantName"`
+
+	// queueJoin
+	NumPlayers int  `json:"players"`
+	Timed      bool `json:"timed"`
+
+	// tableSetRequirements
+	MinGames  int     `json:"minGames"`
+	MinRating float64 `json:"minRating"`
+
+	// tableSetReady
+	Ready     bool `json:"ready"`
+	AutoStart bool `json:"autoStart"`
+
+	// tableReplacePlayer
+	NewUserID int `json:"newUserID"`
+
+	// tableSwapSeats
+	SeatA int `json:"seatA"`
+	SeatB int `json:"seatB"`
+
+	// tableRequestExtension
+	Decline bool `json:"decline"`
+
+	// tableSetAway
+	Away bool `json:"away"`
+
+	// getGameInfo2
+	Batch bool `json:"batch"`
 
 	// action
 	Type   int `json:"type"`
 	Target int `json:"target"`
 	Value  int `json:"value"`
 
+	// clueProbe
+	ClueType     int `json:"clueType"`
+	ClueValue    int `json:"clueValue"`
+	TargetPlayer int `json:"targetPlayer"`
+
+	// tableActionLog
+	FromTurn int `json:"fromTurn"`
+
+	// requestResync
+	LastSeenTurn int `json:"lastSeenTurn"`
+
 	// note
 	Note  string `json:"note"`
 	Order int    `json:"order"`
@@ -30,6 +90,16 @@ type CommandData struct {
 	// tableSpectate
 	ShadowingPlayerIndex int `json:"shadowingPlayerIndex"`
 
+	// tableGenerateSpectateToken
+	SingleUse bool `json:"singleUse"`
+
+	// spectateWithToken
+	// (also accepted by tableSpectate for password-protected tables)
+	Token string `json:"token"`
+
+	// spectateSetOptions
+	ShowTouchedCards bool `json:"showTouchedCards"`
+
 	// replayCreate
 	Source     string    `json:"source"`
 	GameJSON   *GameJSON `json:"gameJSON"`
@@ -41,6 +111,9 @@ type CommandData struct {
 	Suit    int    `json:"suit"`
 	Sound   string `json:"sound"`
 
+	// replaySeek
+	Turn int `json:"turn"`
+
 	// historyGet
 	Offset int `json:"offset"`
 	Amount int `json:"amount"`
@@ -87,11 +160,37 @@ func commandInit() {
 	commandMap["tableUnattend"] = commandTableUnattend
 	commandMap["tableReattend"] = commandTableReattend
 	commandMap["tableSetVariant"] = commandTableSetVariant
+	commandMap["tableSetName"] = commandTableSetName
+	commandMap["tableSetSeed"] = commandTableSetSeed
 	commandMap["tableSetLeader"] = commandTableSetLeader
+	commandMap["tableTransferOwner"] = commandTableTransferOwner
+	commandMap["tableClaimAbandoned"] = commandTableClaimAbandoned
+	commandMap["tablePauseVote"] = commandTablePauseVote
+	commandMap["tableConcede"] = commandTableConcede
+	commandMap["tableKickSpectator"] = commandTableKickSpectator
+	commandMap["tableRequestExtension"] = commandTableRequestExtension
+	commandMap["tableSetAway"] = commandTableSetAway
+	commandMap["tableReserveSeat"] = commandTableReserveSeat
+	commandMap["tableSetRequirements"] = commandTableSetRequirements
+	commandMap["tableSetReady"] = commandTableSetReady
+	commandMap["tableSwapSeats"] = commandTableSwapSeats
+	commandMap["tableReplacePlayer"] = commandTableReplacePlayer
+	commandMap["tableAbsentPlayers"] = commandTableAbsentPlayers
+	commandMap["tableForceTurn"] = commandTableForceTurn
+	commandMap["tableDisableIdleCheck"] = commandTableDisableIdleCheck
+	commandMap["tableEnableIdleCheck"] = commandTableEnableIdleCheck
+	commandMap["tableEnterSharedReview"] = commandTableEnterSharedReview
+	commandMap["tableExitSharedReview"] = commandTableExitSharedReview
+	commandMap["tableReviewSeek"] = commandTableReviewSeek
 	commandMap["tableStart"] = commandTableStart
 	commandMap["tableTerminate"] = commandTableTerminate
 	commandMap["tableSpectate"] = commandTableSpectate
+	commandMap["tableGenerateSpectateToken"] = commandTableGenerateSpectateToken
+	commandMap["spectateWithToken"] = commandSpectateWithToken
+	commandMap["spectateSetOptions"] = commandSpectateSetOptions
 	commandMap["tableRestart"] = commandTableRestart
+	commandMap["tableListRunning"] = commandTableListRunning
+	commandMap["tableCloneSettings"] = commandTableCloneSettings
 
 	// Other lobby commands
 	commandMap["setting"] = commandSetting
@@ -103,6 +202,14 @@ func commandInit() {
 	commandMap["chatUnfriend"] = commandChatUnfriend
 	commandMap["chatPlayerInfo"] = commandChatPlayerInfo
 	commandMap["getName"] = commandGetName
+	commandMap["variantInfo"] = commandVariantInfo
+	commandMap["queueJoin"] = commandQueueJoin
+	commandMap["queueLeave"] = commandQueueLeave
+	commandMap["lobbySubscribe"] = commandLobbySubscribe
+	commandMap["lobbyUnsubscribe"] = commandLobbyUnsubscribe
+	commandMap["serverInfo"] = commandServerInfo
+	commandMap["userActiveTable"] = commandUserActiveTable
+	commandMap["userGames"] = commandUserGames
 	commandMap["inactive"] = commandInactive
 	commandMap["historyGet"] = commandHistoryGet
 	commandMap["historyGetSeed"] = commandHistoryGetSeed
@@ -113,6 +220,14 @@ func commandInit() {
 	// Game and replay commands
 	commandMap["getGameInfo1"] = commandGetGameInfo1
 	commandMap["getGameInfo2"] = commandGetGameInfo2
+	commandMap["requestResync"] = commandRequestResync
+	commandMap["tableActionLog"] = commandTableActionLog
+	commandMap["tableStrikeLog"] = commandTableStrikeLog
+	commandMap["tableClocks"] = commandTableClocks
+	commandMap["tableDeckInfo"] = commandTableDeckInfo
+	commandMap["tableEfficiency"] = commandTableEfficiency
+	commandMap["tableFinalHands"] = commandTableFinalHands
+	commandMap["tableStateString"] = commandTableStateString
 	commandMap["loaded"] = commandLoaded
 	commandMap["tag"] = commandTag
 	commandMap["tagDelete"] = commandTagDelete
@@ -121,7 +236,12 @@ func commandInit() {
 	commandMap["action"] = commandAction
 	commandMap["note"] = commandNote
 	commandMap["pause"] = commandPause
+	commandMap["clueProbe"] = commandClueProbe
 
 	// Replay commands
 	commandMap["replayAction"] = commandReplayAction
+	commandMap["replayHypothetical"] = commandReplayHypothetical
+	commandMap["endHypothetical"] = commandEndHypothetical
+	commandMap["replaySeek"] = commandReplaySeek
+	commandMap["replayExport"] = commandReplayExport
 }
@@ -44,11 +44,20 @@ func commandAction(s *Session, d *CommandData) {
 	if !d.NoLock {
 		defer t.Mutex.Unlock()
 	}
+
+	tableAction(s, d, t)
+}
+
+// tableAction validates the action before performing it
+// (this is separate from "commandAction()" so that actions can be performed on tables that are not
+// in the tables map, e.g. in "validateGameState()")
+func tableAction(s *Session, d *CommandData, t *Table) {
+	// Local variables
 	g := t.Game
 
 	// Validate that the game has started
 	if !t.Running {
-		s.Warning(NotStartedFail)
+		s.WarningWithCode(ErrNotStarted, NotStartedFail)
 		return
 	}
 
@@ -71,14 +80,21 @@ func commandAction(s *Session, d *CommandData) {
 	if d.Type != ActionTypeEndGame {
 		// Validate that it is this player's turn
 		if g.ActivePlayerIndex != playerIndex {
-			s.Warning("It is not your turn, so you cannot perform an action.")
+			s.WarningWithCode(ErrNotYourTurn, "It is not your turn, so you cannot perform an action.")
 			g.InvalidActionOccurred = true
 			return
 		}
 
 		// Validate that the game is not paused
 		if g.Paused {
-			s.Warning("You cannot perform a game action when the game is paused.")
+			s.WarningWithCode(ErrPaused, "You cannot perform a game action when the game is paused.")
+			g.InvalidActionOccurred = true
+			return
+		}
+
+		// Validate that the table is not reviewing past turns
+		if g.Reviewing {
+			s.Warning("You cannot perform a game action during a shared review.")
 			g.InvalidActionOccurred = true
 			return
 		}
@@ -103,7 +119,8 @@ func action(s *Session, d *CommandData, t *Table, p *GamePlayer) {
 
 	// Start the idle timeout
 	// (but don't update the idle variable if we are ending the game)
-	if d.Type != ActionTypeEndGame {
+	// (the idle timeout of a restored table is started after all of its actions are restored)
+	if d.Type != ActionTypeEndGame && !t.RestoringActions {
 		go t.CheckIdle()
 	}
 
@@ -121,6 +138,11 @@ func action(s *Session, d *CommandData, t *Table, p *GamePlayer) {
 	// Do post-action tasks
 	characterPostAction(d, g, p)
 
+	// Playing on means that the team no longer wants to concede
+	if d.Type != ActionTypeEndGame && g.ClearConcedeVotes() && !t.RestoringActions {
+		chatServerSend("The vote to concede was cancelled.", t.GetRoomName())
+	}
+
 	// Send a message about the current status
 	t.NotifyStatus()
 
@@ -128,6 +150,11 @@ func action(s *Session, d *CommandData, t *Table, p *GamePlayer) {
 	// (if the game is over now due to a player running out of time, we don't need to adjust the
 	// timer because we already set it to 0 in the "checkTimer" function)
 	if d.Type != ActionTypeEndGame {
+		// If the clock was stopped while they were disconnected, that time does not count
+		g.ApplyDisconnectCredit(p)
+		p.DisconnectedAt = time.Time{}
+		p.DisconnectCreditUsed = 0
+
 		p.Time -= time.Since(g.DatetimeTurnBegin)
 		// (in non-timed games,
 		// "Time" will decrement into negative numbers to show how much time they are taking)
@@ -193,9 +220,27 @@ func action(s *Session, d *CommandData, t *Table, p *GamePlayer) {
 		return
 	}
 
+	// The rest of this function only starts goroutines and sends messages,
+	// which is handled separately for restored tables
+	if t.RestoringActions {
+		return
+	}
+
+	// Find out about abnormally long games before they use up too much memory
+	g.CheckActionsLimit()
+
+	// Record the action so that it is not lost if the server crashes before the next full
+	// serialization
+	appendToActionLog(t)
+
 	// Send everyone new clock values
 	t.NotifyTime()
 
+	// If the next player is away, take their turn for them after a short delay
+	if np.Away {
+		go g.CheckAway(g.Turn, g.PauseCount, np)
+	}
+
 	if t.Options.Timed && !t.ExtraOptions.NoWriteToDatabase {
 		// Start the function that will check to see if the current player has run out of time
 		// (since it just got to be their turn)
@@ -231,7 +276,7 @@ func commandActionPlay(s *Session, d *CommandData, g *Game, p *GamePlayer) bool
 
 	// Validate that the card is in their hand
 	if !p.InHand(d.Target) {
-		s.Warning("You cannot play a card that is not in your hand.")
+		s.WarningWithCode(ErrInvalidCard, "You cannot play a card that is not in your hand.")
 		g.InvalidActionOccurred = true
 		return false
 	}
@@ -249,7 +294,7 @@ func commandActionDiscard(s *Session, d *CommandData, g *Game, p *GamePlayer) bo
 
 	// Validate that the card is in their hand
 	if !p.InHand(d.Target) {
-		s.Warning("You cannot play a card that is not in your hand.")
+		s.WarningWithCode(ErrInvalidCard, "You cannot play a card that is not in your hand.")
 		g.InvalidActionOccurred = true
 		return false
 	}
@@ -276,28 +321,37 @@ func commandActionDiscard(s *Session, d *CommandData, g *Game, p *GamePlayer) bo
 }
 
 func commandActionClue(s *Session, d *CommandData, g *Game, p *GamePlayer) bool {
+	if code, msg := getClueError(d, g, p); msg != "" {
+		s.WarningWithCode(code, msg)
+		g.InvalidActionOccurred = true
+		return false
+	}
+
+	p.GiveClue(d)
+
+	return true
+}
+
+// getClueError returns the error code and the message for the first reason that the player cannot
+// give the clue, or an empty message if the clue is legal
+// (it does not check whether it is their turn)
+func getClueError(d *CommandData, g *Game, p *GamePlayer) (string, string) {
 	// Local variables
 	variant := variants[g.Options.VariantName]
 
 	// Validate that the target of the clue is sane
 	if d.Target < 0 || d.Target > len(g.Players)-1 {
-		s.Warning("That is an invalid clue target.")
-		g.InvalidActionOccurred = true
-		return false
+		return ErrInvalidClue, "That is an invalid clue target."
 	}
 
 	// Validate that the player is not giving a clue to themselves
-	if g.ActivePlayerIndex == d.Target {
-		s.Warning("You cannot give a clue to yourself.")
-		g.InvalidActionOccurred = true
-		return false
+	if p.Index == d.Target {
+		return ErrInvalidClue, "You cannot give a clue to yourself."
 	}
 
 	// Validate that there are clues available to use
 	if g.ClueTokens < variant.GetAdjustedClueTokens(1) {
-		s.Warning("You need at least 1 clue token available in order to give a clue.")
-		g.InvalidActionOccurred = true
-		return false
+		return ErrInvalidClue, "You need at least 1 clue token available in order to give a clue."
 	}
 
 	// Convert the incoming data to a clue object
@@ -306,50 +360,58 @@ func commandActionClue(s *Session, d *CommandData, g *Game, p *GamePlayer) bool
 	// Validate the clue value
 	if clue.Type == ClueTypeColor {
 		if clue.Value < 0 || clue.Value > len(variant.ClueColors)-1 {
-			s.Warning("You cannot give a color clue with a value of " +
-				"\"" + strconv.Itoa(clue.Value) + "\".")
-			g.InvalidActionOccurred = true
-			return false
+			return ErrInvalidClue, "You cannot give a color clue with a value of " +
+				"\"" + strconv.Itoa(clue.Value) + "\"."
 		}
 	} else if clue.Type == ClueTypeRank {
 		if !intInSlice(clue.Value, variant.ClueRanks) {
-			s.Warning("You cannot give a rank clue with a value of " +
-				"\"" + strconv.Itoa(clue.Value) + "\".")
-			g.InvalidActionOccurred = true
-			return false
+			return ErrInvalidClue, "You cannot give a rank clue with a value of " +
+				"\"" + strconv.Itoa(clue.Value) + "\"."
 		}
 	} else {
-		s.Warning("The clue type of " + strconv.Itoa(clue.Type) + " is invalid..")
-		g.InvalidActionOccurred = true
-		return false
+		return ErrInvalidClue, "The clue type of " + strconv.Itoa(clue.Type) + " is invalid.."
+	}
+
+	// Validate that the clue type can be given in this variant at all
+	// (e.g. color clues in a variant where every suit is a "mute" suit)
+	if !variant.IsClueTypeAllowed(clue.Type) {
+		return ErrClueRestricted, "You cannot give that type of clue in this variant."
+	}
+
+	// Validate that the clue type was not disabled when the table was created
+	if !g.Options.IsClueTypeAllowed(clue.Type) {
+		return ErrClueRestricted, "You cannot give that type of clue at this table."
 	}
 
 	// Validate special variant restrictions
 	if variant.IsAlternatingClues() && clue.Type == g.LastClueTypeGiven {
-		s.Warning("You cannot give two clues of the same time in a row in this variant.")
-		g.InvalidActionOccurred = true
-		return false
+		return ErrInvalidClue, "You cannot give two clues of the same time in a row in this variant."
 	}
 
 	// Validate "Detrimental Character Assignment" restrictions
-	if characterValidateClue(s, d, g, p) {
-		g.InvalidActionOccurred = true
-		return false
+	if msg := characterGetClueError(d, g, p); msg != "" {
+		return "", msg
 	}
 
 	// Validate that the clue touches at least one card
 	p2 := g.Players[d.Target] // The target of the clue
 	touchedAtLeastOneCard := false
+	numCardsSeen := 0
+	numCardsRestricted := 0
 	for _, c := range p2.Hand {
 		// Prevent characters from cluing cards that they are not supposed to see
 		if !characterSeesCard(g, p, p2, c.Order) {
 			continue
 		}
+		numCardsSeen++
 
 		if variantIsCardTouched(g.Options.VariantName, clue, c) {
 			touchedAtLeastOneCard = true
 			break
 		}
+		if variant.Suits[c.SuitIndex].IsClueTypeRestricted(clue.Type) {
+			numCardsRestricted++
+		}
 	}
 	if !touchedAtLeastOneCard &&
 		// Make an exception if they have the optional setting for "Empty Clues" turned on
@@ -359,14 +421,16 @@ func commandActionClue(s *Session, d *CommandData, g *Game, p *GamePlayer) bool
 		// Make an exception for variants where rank clues are always allowed
 		(!variant.RankCluesTouchNothing || clue.Type != ClueTypeRank) {
 
-		s.Warning("You cannot give a clue that touches 0 cards in the hand.")
-		g.InvalidActionOccurred = true
-		return false
+		if numCardsSeen > 0 && numCardsRestricted == numCardsSeen {
+			// Use a more specific error if the suits of the cards prevent them from being touched
+			// by this type of clue (e.g. giving a color clue to a hand full of "mute" cards)
+			return ErrClueRestricted,
+				"None of the cards in that hand can be touched by that type of clue."
+		}
+		return ErrInvalidClue, "You cannot give a clue that touches 0 cards in the hand."
 	}
 
-	p.GiveClue(d)
-
-	return true
+	return "", ""
 }
 
 func commandActionEndGame(s *Session, d *CommandData, g *Game, p *GamePlayer) bool {
@@ -377,7 +441,8 @@ func commandActionEndGame(s *Session, d *CommandData, g *Game, p *GamePlayer) bo
 	// Validate the value
 	if d.Value != EndConditionTimeout &&
 		d.Value != EndConditionTerminated &&
-		d.Value != EndConditionIdleTimeout {
+		d.Value != EndConditionIdleTimeout &&
+		d.Value != EndConditionConceded {
 
 		s.Warning("That is not a valid value for the end game action.")
 		g.InvalidActionOccurred = true
@@ -408,6 +473,12 @@ func commandActionEndGame(s *Session, d *CommandData, g *Game, p *GamePlayer) bo
 			Target: -1,
 			Value:  EndConditionIdleTimeout,
 		}
+	} else if g.EndCondition == EndConditionConceded {
+		endGameAction = &GameAction{
+			Type:   ActionTypeEndGame,
+			Target: -1,
+			Value:  EndConditionConceded,
+		}
 	}
 	if endGameAction != nil {
 		g.Actions2 = append(g.Actions2, endGameAction)
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"testing"
+)
+
+// newTestVariantGame is the same as "newTestGame()", but it uses the given variant
+// The hand of the second player is replaced with cards of the given suit
+func newTestVariantGame(t *testing.T, variantName string, suitIndex int) *Table {
+	tb := newTestTable(t, 2)
+	tb.Options.VariantName = variantName
+	startTestGame(t, tb)
+	for _, c := range tb.Game.Players[1].Hand {
+		c.SuitIndex = suitIndex
+	}
+
+	return tb
+}
+
+func TestCommandActionClueMuteSuit(t *testing.T) {
+	resetTestTables(t)
+	variant := variants["White (5 Suits)"]
+	whiteIndex := len(variant.Suits) - 1
+	tb := newTestVariantGame(t, variant.Name, whiteIndex)
+	conns := connectTestPlayers(t, tb)
+	g := tb.Game
+
+	// A color clue cannot touch any of the white cards
+	commandAction(tb.Players[0].Session, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		Type:    ActionTypeColorClue,
+		Target:  1,
+		Value:   0,
+		NoLock:  true,
+	})
+	expectTestWarningCode(t, conns[0], ErrClueRestricted)
+	if g.Turn != 0 {
+		t.Fatal("a color clue was given to a hand full of mute cards")
+	}
+
+	// The usual error is used if some of the cards could have been touched
+	g.InvalidActionOccurred = false
+	g.Players[1].Hand[0].SuitIndex = 0
+	commandAction(tb.Players[0].Session, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		Type:    ActionTypeColorClue,
+		Target:  1,
+		Value:   1,
+		NoLock:  true,
+	})
+	expectTestWarningCode(t, conns[0], ErrInvalidClue)
+
+	// Rank clues still touch the white cards
+	g.InvalidActionOccurred = false
+	performTestAction(t, tb, ActionTypeRankClue, 1, g.Players[1].Hand[1].Rank)
+	if !g.Players[1].Hand[1].Touched {
+		t.Error("the rank clue did not touch the white card")
+	}
+}
+
+func TestCommandActionClueRainbowSuit(t *testing.T) {
+	resetTestTables(t)
+	variant := variants["Rainbow (5 Suits)"]
+	tb := newTestVariantGame(t, variant.Name, len(variant.Suits)-1)
+	g := tb.Game
+
+	// Every color touches every rainbow card
+	performTestAction(t, tb, ActionTypeColorClue, 1, len(variant.ClueColors)-1)
+	for i, c := range g.Players[1].Hand {
+		if !c.Touched {
+			t.Errorf("card %v of the rainbow hand was not touched by the color clue", i)
+		}
+	}
+	if code, msg := getClueError(&CommandData{ // Manual invocation
+		Type:   ActionTypeColorClue,
+		Target: 1,
+		Value:  0,
+	}, g, g.Players[0]); msg != "" {
+		t.Errorf("expected a red clue to the rainbow hand to be legal, but got: %v %v", code, msg)
+	}
+}
+
+// giveTestClue makes the first player give a clue to the second player that touches their oldest
+// card
+func giveTestClue(tb *Table, clueType int) {
+	c := tb.Game.Players[1].Hand[0]
+	d := &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		Type:    ActionTypeColorClue,
+		Target:  1,
+		Value:   c.SuitIndex,
+		NoLock:  true,
+	}
+	if clueType == ClueTypeRank {
+		d.Type = ActionTypeRankClue
+		d.Value = c.Rank
+	}
+	commandAction(tb.Players[0].Session, d)
+}
+
+func TestCommandActionClueTypeDisabled(t *testing.T) {
+	for _, clueType := range []int{ClueTypeColor, ClueTypeRank} {
+		resetTestTables(t)
+		tb := newTestTable(t, 2)
+		tb.Options.NoColorClues = clueType == ClueTypeColor
+		tb.Options.NoRankClues = clueType == ClueTypeRank
+		startTestGame(t, tb)
+		conns := connectTestPlayers(t, tb)
+		g := tb.Game
+
+		giveTestClue(tb, clueType)
+		expectTestWarningCode(t, conns[0], ErrClueRestricted)
+		if g.Turn != 0 {
+			t.Fatalf("a clue of type %v was given at a table without them", clueType)
+		}
+
+		// The other type of clue can still be given
+		g.InvalidActionOccurred = false
+		otherClueType := ClueTypeRank
+		if clueType == ClueTypeRank {
+			otherClueType = ClueTypeColor
+		}
+		giveTestClue(tb, otherClueType)
+		if g.Turn != 1 {
+			t.Errorf("a clue of type %v was not given at a table without clues of type %v",
+				otherClueType, clueType)
+		}
+	}
+}
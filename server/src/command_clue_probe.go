@@ -0,0 +1,128 @@
+package main
+
+import (
+	"strconv"
+)
+
+// commandClueProbe is sent when the user wants to know whether a clue is legal and which cards it
+// would touch before they actually give it (e.g. for teaching)
+// The clue is checked as if the user gave it on their turn
+//
+// Example data:
+// {
+//   tableID: 5,
+//   // Corresponds to "ClueType" in "constants.go" (0 for color, 1 for rank)
+//   clueType: 0,
+//   // If a color clue, then 0 if red, 1 if yellow, etc.
+//   // If a rank clue, then 1 if 1, 2 if 2, etc.
+//   clueValue: 1,
+//   // The index of the player that would receive the clue
+//   targetPlayer: 1,
+// }
+func commandClueProbe(s *Session, d *CommandData) {
+	t, exists := getTableAndLock(s, d.TableID, !d.NoLock)
+	if !exists {
+		return
+	}
+	if !d.NoLock {
+		defer t.Mutex.Unlock()
+	}
+	g := t.Game
+
+	// Validate that the game has started
+	if !t.Running {
+		s.WarningWithCode(ErrNotStarted, NotStartedFail)
+		return
+	}
+
+	// Validate that it is not a replay
+	if t.Replay {
+		s.Warning("You cannot probe a clue in a replay.")
+		return
+	}
+
+	// Validate that they are in the game
+	playerIndex := t.GetPlayerIndexFromID(s.UserID())
+	if playerIndex == -1 {
+		s.Warning("You are not playing at table " + strconv.FormatUint(t.ID, 10) + ", " +
+			"so you cannot probe a clue.")
+		return
+	}
+
+	// Validate the clue type
+	if d.ClueType != ClueTypeColor && d.ClueType != ClueTypeRank {
+		s.WarningWithCode(ErrInvalidClue, "The clue type of "+strconv.Itoa(d.ClueType)+
+			" is invalid.")
+		return
+	}
+
+	clueProbe(s, d, t, g.Players[playerIndex])
+}
+
+func clueProbe(s *Session, d *CommandData, t *Table, p *GamePlayer) {
+	// Local variables
+	g := t.Game
+	variant := variants[g.Options.VariantName]
+
+	// Convert the probe to the same data as a clue action
+	actionType := ActionTypeColorClue
+	if d.ClueType == ClueTypeRank {
+		actionType = ActionTypeRankClue
+	}
+	clueData := &CommandData{
+		TableID: t.ID,
+		Type:    actionType,
+		Target:  d.TargetPlayer,
+		Value:   d.ClueValue,
+	}
+
+	code, msg := getClueError(clueData, g, p)
+	if msg == "" && g.ActivePlayerIndex != p.Index {
+		code = ErrNotYourTurn
+		msg = "It is not your turn, so you cannot give a clue."
+	}
+
+	// The touched cards are only sent for a clue to someone else,
+	// since the player is not allowed to know about their own hand
+	// (and characters may not be allowed to see some of the cards)
+	validTarget := d.TargetPlayer >= 0 && d.TargetPlayer < len(g.Players) &&
+		d.TargetPlayer != p.Index
+	validValue := d.ClueType == ClueTypeRank ||
+		(d.ClueValue >= 0 && d.ClueValue < len(variant.ClueColors))
+	touched := make([]int, 0)
+	if validTarget && validValue {
+
+		p2 := g.Players[d.TargetPlayer]
+		for _, c := range p2.Hand {
+			if !characterSeesCard(g, p, p2, c.Order) {
+				continue
+			}
+			if variantIsCardTouched(g.Options.VariantName, NewClue(clueData), c) {
+				touched = append(touched, c.Order)
+			}
+		}
+	}
+
+	type ClueProbeMessage struct {
+		TableID      uint64 `json:"tableID"`
+		ClueType     int    `json:"clueType"`
+		ClueValue    int    `json:"clueValue"`
+		TargetPlayer int    `json:"targetPlayer"`
+		Legal        bool   `json:"legal"`
+		// The orders of the cards that the clue would touch
+		Touched []int `json:"touched"`
+		// The reason that the clue is not legal (if it is not)
+		Code    string `json:"code,omitempty"`
+		Warning string `json:"warning,omitempty"`
+	}
+	s.Emit("clueProbe", &ClueProbeMessage{
+		TableID:      t.ID,
+		ClueType:     d.ClueType,
+		ClueValue:    d.ClueValue,
+		TargetPlayer: d.TargetPlayer,
+		Legal:        msg == "",
+		Touched:      touched,
+		Code:         code,
+		Warning:      msg,
+	})
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+type testClueProbe struct {
+	TableID      uint64 `json:"tableID"`
+	ClueType     int    `json:"clueType"`
+	ClueValue    int    `json:"clueValue"`
+	TargetPlayer int    `json:"targetPlayer"`
+	Legal        bool   `json:"legal"`
+	Touched      []int  `json:"touched"`
+	Code         string `json:"code"`
+	Warning      string `json:"warning"`
+}
+
+func probeTestClue(
+	t *testing.T,
+	tb *Table,
+	id int,
+	clueType int,
+	clueValue int,
+	targetPlayer int,
+) *testClueProbe {
+	s, conn := newTestWebsocket(t, id, testPlayerNames[id-1], 0)
+	commandClueProbe(s, &CommandData{ // Manual invocation
+		TableID:      tb.ID,
+		ClueType:     clueType,
+		ClueValue:    clueValue,
+		TargetPlayer: targetPlayer,
+		NoLock:       true,
+	})
+
+	probe := &testClueProbe{}
+	if err := json.Unmarshal([]byte(readTestCommand(t, conn, "clueProbe")), probe); err != nil {
+		t.Fatal("failed to unmarshal the clue probe:", err)
+	}
+	if probe.TableID != tb.ID || probe.ClueType != clueType || probe.ClueValue != clueValue ||
+		probe.TargetPlayer != targetPlayer {
+
+		t.Errorf("the clue probe is for the wrong clue: %+v", probe)
+	}
+
+	return probe
+}
+
+// getTestTouchedCards returns the orders of the cards in the hand of the player that match
+func getTestTouchedCards(g *Game, playerIndex int, f func(c *Card) bool) []int {
+	touched := make([]int, 0)
+	for _, c := range g.Players[playerIndex].Hand {
+		if f(c) {
+			touched = append(touched, c.Order)
+		}
+	}
+	return touched
+}
+
+func TestClueProbe(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+	g := tb.Game
+	rank := g.Players[1].Hand[0].Rank
+	suitIndex := g.Players[1].Hand[0].SuitIndex
+	numActions := len(g.Actions)
+
+	probe := probeTestClue(t, tb, 1, ClueTypeRank, rank, 1)
+	if !probe.Legal || probe.Code != "" || probe.Warning != "" {
+		t.Errorf("expected the rank clue to be legal, but got: %+v", probe)
+	}
+	expected := getTestTouchedCards(g, 1, func(c *Card) bool {
+		return c.Rank == rank
+	})
+	if !reflect.DeepEqual(probe.Touched, expected) {
+		t.Errorf("expected the rank clue to touch %v, but got %v", expected, probe.Touched)
+	}
+
+	probe = probeTestClue(t, tb, 1, ClueTypeColor, suitIndex, 1)
+	if !probe.Legal {
+		t.Errorf("expected the color clue to be legal, but got: %+v", probe)
+	}
+	expected = getTestTouchedCards(g, 1, func(c *Card) bool {
+		return c.SuitIndex == suitIndex
+	})
+	if !reflect.DeepEqual(probe.Touched, expected) {
+		t.Errorf("expected the color clue to touch %v, but got %v", expected, probe.Touched)
+	}
+
+	// The clue is not actually given
+	if g.Turn != 0 || len(g.Actions) != numActions {
+		t.Errorf("the clue was given (on turn %v with %v actions)", g.Turn, len(g.Actions))
+	}
+	if g.ClueTokens != variants[g.Options.VariantName].GetAdjustedClueTokens(MaxClueNum) {
+		t.Errorf("a clue token was spent (%v remaining)", g.ClueTokens)
+	}
+}
+
+func TestClueProbeVariantTouchRules(t *testing.T) {
+	resetTestTables(t)
+	variantName := "Rainbow (6 Suits)"
+	tb := newTestTable(t, 2)
+	tb.Options.VariantName = variantName
+	tb.ExtraOptions.CustomSeed = ""
+	tb.ExtraOptions.CustomDeck = newTestCustomDeck(variantName)
+	startTestGame(t, tb)
+	g := tb.Game
+
+	// The players are shuffled when there is no seed
+	id := tb.Players[g.ActivePlayerIndex].ID
+	target := (g.ActivePlayerIndex + 1) % len(g.Players)
+
+	// The reversed deck starts with the rainbow cards, so they are touched by every color clue
+	variant := variants[variantName]
+	for _, c := range g.Players[target].Hand {
+		if !variant.Suits[c.SuitIndex].AllClueColors {
+			t.Fatalf("expected every card of the target to be rainbow, but got the suit of %v",
+				variant.Suits[c.SuitIndex].Name)
+		}
+	}
+	expected := getTestTouchedCards(g, target, func(c *Card) bool {
+		return true
+	})
+	for i := range variant.ClueColors {
+		probe := probeTestClue(t, tb, id, ClueTypeColor, i, target)
+		if !probe.Legal || !reflect.DeepEqual(probe.Touched, expected) {
+			t.Errorf("expected the %v clue to be legal and touch %v, but got: %+v",
+				variant.ClueColors[i], expected, probe)
+		}
+	}
+}
+
+func TestClueProbeIllegal(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+	g := tb.Game
+
+	// Clues to yourself are not legal and do not reveal anything about your hand
+	probe := probeTestClue(t, tb, 1, ClueTypeRank, g.Players[0].Hand[0].Rank, 0)
+	if probe.Legal || probe.Code != ErrInvalidClue ||
+		probe.Warning != "You cannot give a clue to yourself." {
+
+		t.Errorf("expected a self-clue to be rejected, but got: %+v", probe)
+	}
+	if len(probe.Touched) != 0 {
+		t.Errorf("expected a self-clue to not touch any cards, but got %v", probe.Touched)
+	}
+
+	// It is only legal for the active player
+	probe = probeTestClue(t, tb, 2, ClueTypeRank, g.Players[0].Hand[0].Rank, 0)
+	if probe.Legal || probe.Code != ErrNotYourTurn {
+		t.Errorf("expected a clue from the wrong player to be rejected, but got: %+v", probe)
+	}
+
+	g.ClueTokens = 0
+	probe = probeTestClue(t, tb, 1, ClueTypeRank, g.Players[1].Hand[0].Rank, 1)
+	if probe.Legal || probe.Code != ErrInvalidClue ||
+		probe.Warning != "You need at least 1 clue token available in order to give a clue." {
+
+		t.Errorf("expected a clue without any clue tokens to be rejected, but got: %+v", probe)
+	}
+
+	// The touched cards are still reported so that the clue can be previewed
+	if len(probe.Touched) == 0 {
+		t.Error("expected the clue to touch at least one card")
+	}
+}
+
+func TestClueProbeValidation(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTable(t, 2)
+
+	s, conn := newTestWebsocket(t, 1, "Alice", 0)
+	commandClueProbe(s, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+	expectTestWarningCode(t, conn, ErrNotStarted)
+
+	startTestGame(t, tb)
+	commandClueProbe(s, &CommandData{ // Manual invocation
+		TableID:      tb.ID,
+		ClueType:     5,
+		TargetPlayer: 1,
+		NoLock:       true,
+	})
+	expectTestWarningCode(t, conn, ErrInvalidClue)
+
+	s2, conn2 := newTestWebsocket(t, 10, "Dan", 0)
+	commandClueProbe(s2, &CommandData{ // Manual invocation
+		TableID:      tb.ID,
+		TargetPlayer: 1,
+		NoLock:       true,
+	})
+	expectTestWarning(t, conn2, "so you cannot probe a clue")
+}
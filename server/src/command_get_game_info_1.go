@@ -31,7 +31,7 @@ func commandGetGameInfo1(s *Session, d *CommandData) {
 
 	// Validate that the game has started
 	if !t.Running {
-		s.Warning(NotStartedFail)
+		s.WarningWithCode(ErrNotStarted, NotStartedFail)
 		return
 	}
 
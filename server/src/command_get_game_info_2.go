@@ -6,10 +6,12 @@ import (
 
 // commandGetGameInfo2 provides all of the actions that have happened thus far in the game
 // It is sent when the user has joined a game and the UI has been initialized
+// If "batch" is true, everything is sent in one "initBatch" message instead of many separate ones
 //
 // Example data:
 // {
 //   tableID: 5,
+//   batch: true, // Optional
 // }
 func commandGetGameInfo2(s *Session, d *CommandData) {
 	t, exists := getTableAndLock(s, d.TableID, !d.NoLock)
@@ -22,7 +24,7 @@ func commandGetGameInfo2(s *Session, d *CommandData) {
 
 	// Validate that the game has started
 	if !t.Running {
-		s.Warning(NotStartedFail)
+		s.WarningWithCode(ErrNotStarted, NotStartedFail)
 		return
 	}
 
@@ -35,7 +37,13 @@ func commandGetGameInfo2(s *Session, d *CommandData) {
 		return
 	}
 
-	getGameInfo2(s, t, playerIndex, spectatorIndex)
+	if d.Batch {
+		bs := s.NewBatch()
+		getGameInfo2(bs, t, playerIndex, spectatorIndex)
+		bs.EmitBatch(t.ID)
+	} else {
+		getGameInfo2(s, t, playerIndex, spectatorIndex)
+	}
 }
 
 func getGameInfo2(s *Session, t *Table, playerIndex int, spectatorIndex int) {
@@ -46,7 +54,7 @@ func getGameInfo2(s *Session, t *Table, playerIndex int, spectatorIndex int) {
 	scrubbedActions := make([]interface{}, 0)
 	if !t.Replay {
 		for _, action := range g.Actions {
-			scrubbedAction := CheckScrub(t, action, s.UserID())
+			scrubbedAction := CheckScrubForSession(s, t, action)
 			scrubbedActions = append(scrubbedActions, scrubbedAction)
 		}
 	} else {
@@ -81,6 +89,11 @@ func getGameInfo2(s *Session, t *Table, playerIndex int, spectatorIndex int) {
 		// Send them the current time for all player's clocks
 		s.NotifyTime(t)
 
+		// Send them the turn that is being reviewed, if any
+		if g.Reviewing {
+			s.NotifySharedReview(t)
+		}
+
 		if playerIndex > -1 {
 			// They are a player in an ongoing game
 			p := g.Players[playerIndex]
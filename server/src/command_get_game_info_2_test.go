@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+// readTestMessagesUntilDone sends a "done" message to the session and returns every message that
+// the client received before it
+func readTestMessagesUntilDone(t *testing.T, s *Session, conn *websocket.Conn) []string {
+	s.Emit("done", true)
+
+	commands := make([]string, 0)
+	for {
+		command, _ := readTestMessage(t, conn)
+		if command == "done" {
+			return commands
+		}
+		commands = append(commands, command)
+	}
+}
+
+func TestGetGameInfo2Batch(t *testing.T) {
+	tb := newTestGame(t, 3)
+	clueTestPlayer(t, tb)
+	discardTestCard(t, tb)
+
+	s, conn := newTestWebsocket(t, 1, "Alice", 0)
+	tb.Players[0].Session = s
+
+	// Without batching, the game state is sent over many separate messages
+	commandGetGameInfo2(s, &CommandData{
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+	individual := readTestMessagesUntilDone(t, s, conn)
+	if len(individual) < 2 {
+		t.Fatalf("expected the game state to be sent over multiple messages, but got: %v",
+			individual)
+	}
+
+	// With batching, they are all bundled together
+	commandGetGameInfo2(s, &CommandData{
+		TableID: tb.ID,
+		Batch:   true,
+		NoLock:  true,
+	})
+	batched := readTestMessagesUntilDone(t, s, conn)
+	if len(batched) != 1 || batched[0] != "initBatch" {
+		t.Fatalf("expected a single \"initBatch\" message, but got: %v", batched)
+	}
+
+	// Check the contents of the batch
+	commandGetGameInfo2(s, &CommandData{
+		TableID: tb.ID,
+		Batch:   true,
+		NoLock:  true,
+	})
+	data := readTestCommand(t, conn, "initBatch")
+	var msg struct {
+		TableID  uint64            `json:"tableID"`
+		Messages []*BatchedMessage `json:"messages"`
+		Final    bool              `json:"final"`
+	}
+	if err := json.Unmarshal([]byte(data), &msg); err != nil {
+		t.Fatal("failed to unmarshal the \"initBatch\" message:", err)
+	}
+	if msg.TableID != tb.ID {
+		t.Errorf("expected the batch to be for table %v, but got %v", tb.ID, msg.TableID)
+	}
+	if !msg.Final {
+		t.Error("the only batch was not marked as final")
+	}
+	if len(msg.Messages) != len(individual) {
+		t.Fatalf("expected the batch to contain %v messages, but got %v",
+			len(individual), len(msg.Messages))
+	}
+	for i, message := range msg.Messages {
+		if message.Command != individual[i] {
+			t.Errorf("expected batched message %v to be \"%v\", but got \"%v\"",
+				i, individual[i], message.Command)
+		}
+	}
+}
@@ -1,5 +1,9 @@
 package main
 
+import (
+	"time"
+)
+
 // commandInactive is sent when the client detects that the user is inactive (or has returned)
 // Example data:
 // {
@@ -7,6 +11,11 @@ package main
 // }
 func commandInactive(s *Session, d *CommandData) {
 	if s != nil {
+		// Keep track of how long they have been inactive for
+		// (see "commandTableClaimAbandoned")
+		if d.Inactive && !s.Inactive() {
+			s.Set("datetimeInactive", time.Now())
+		}
 		s.Set("inactive", d.Inactive)
 		notifyAllUserInactive(s)
 	}
@@ -22,7 +22,7 @@ func commandLoaded(s *Session, d *CommandData) {
 
 	// Validate that the game has started
 	if !t.Running {
-		s.Warning(NotStartedFail)
+		s.WarningWithCode(ErrNotStarted, NotStartedFail)
 		return
 	}
 
@@ -0,0 +1,12 @@
+package main
+
+// commandLobbySubscribe is sent when the client wants to be told whenever a table is created,
+// started, or removed (instead of having to ask for the list of tables again)
+// See "lobby_subscribers.go" for the format of the "lobbyUpdate" messages
+//
+// Example data:
+// {}
+func commandLobbySubscribe(s *Session, d *CommandData) {
+	lobbySubscribe(s)
+	logger.Info("User \"" + s.Username() + "\" subscribed to lobby updates.")
+}
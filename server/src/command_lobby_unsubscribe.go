@@ -0,0 +1,15 @@
+package main
+
+// commandLobbyUnsubscribe is sent when the client no longer wants lobby updates
+// (from the "lobbySubscribe" command)
+//
+// Example data:
+// {}
+func commandLobbyUnsubscribe(s *Session, d *CommandData) {
+	if !lobbyUnsubscribe(s) {
+		s.Warning("You are not subscribed to lobby updates.")
+		return
+	}
+
+	logger.Info("User \"" + s.Username() + "\" unsubscribed from lobby updates.")
+}
@@ -27,7 +27,7 @@ func commandNote(s *Session, d *CommandData) {
 
 	// Validate that the game has started
 	if !t.Running {
-		s.Warning(NotStartedFail)
+		s.WarningWithCode(ErrNotStarted, NotStartedFail)
 		return
 	}
 
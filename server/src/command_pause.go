@@ -25,7 +25,7 @@ func commandPause(s *Session, d *CommandData) {
 
 	// Validate that the game has started
 	if !t.Running {
-		s.Warning(NotStartedFail)
+		s.WarningWithCode(ErrNotStarted, NotStartedFail)
 		return
 	}
 
@@ -44,6 +44,13 @@ func commandPause(s *Session, d *CommandData) {
 	}
 	p := g.Players[playerIndex]
 
+	// Validate that the table is not reviewing past turns
+	// (the game stays paused until the review is over)
+	if g.Reviewing {
+		s.Warning("You can not pause or unpause during a shared review.")
+		return
+	}
+
 	// Validate that it is a timed game
 	if !t.Options.Timed {
 		s.Warning("This is not a timed game, so you cannot pause / unpause.")
@@ -99,6 +106,11 @@ func pause(s *Session, d *CommandData, t *Table, playerIndex int) {
 	}
 
 	if d.Setting == "pause" {
+		// If the clock was stopped while they were disconnected, that time does not count
+		// (this must happen before the game is paused, since there is no credit while paused)
+		// (if they are still disconnected after the unpause, the rest of the grace period applies)
+		g.ApplyDisconnectCredit(p)
+
 		g.Paused = true
 		g.PausePlayerIndex = playerIndex
 		g.PauseCount++
@@ -108,6 +120,7 @@ func pause(s *Session, d *CommandData, t *Table, playerIndex int) {
 	} else if d.Setting == "unpause" {
 		g.Paused = false
 		g.PausePlayerIndex = -1
+		g.DisconnectPaused = false
 
 		// Technically, a players turn should not begin when the game is unpaused,
 		// but this variable is only used for decrementing time taken at the end of a player's turn
@@ -117,8 +130,14 @@ func pause(s *Session, d *CommandData, t *Table, playerIndex int) {
 		// (the old "CheckTimer()" invocation will return and do nothing because the pause count of
 		// the game will not match)
 		go g.CheckTimer(g.Turn, g.PauseCount, g.Players[g.ActivePlayerIndex])
+
+		// The same applies to the function that takes the turn of an away player
+		g.CheckAwayAfterResume()
 	}
 
+	// Any outstanding votes were for the previous pause state
+	g.ClearPauseVotes()
+
 	t.NotifyPause()
 
 	// Also send a chat message about it
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"strconv"
+	"time"
+)
+
+// commandQueueJoin is sent when the user wants the server to find a game for them
+// Once enough users are waiting for the same kind of game, a table is created for them
+//
+// Example data:
+// {
+//   variant: 'No Variant',
+//   players: 3,
+//   timed: false,
+// }
+func commandQueueJoin(s *Session, d *CommandData) {
+	// Validate that the server is not about to go offline
+	if checkImminentShutdown(s) {
+		return
+	}
+
+	// Validate that the server is not undergoing maintenance
+	if maintenanceMode.IsSet() {
+		s.Warning("The server is undergoing maintenance. " +
+			"You cannot start any new games for the time being.")
+		return
+	}
+
+	// Validate the variant name
+	if d.Variant == "" {
+		d.Variant = "No Variant"
+	}
+	if _, ok := variants[d.Variant]; !ok {
+		s.WarningWithCode(ErrVariantNotFound, "\""+d.Variant+"\" is not a valid variant.")
+		return
+	}
+	if variantIsDisabled(d.Variant) {
+		s.Warning("The variant of \"" + d.Variant + "\" is temporarily disabled.")
+		return
+	}
+
+	// Validate the number of players
+	if d.NumPlayers < 2 || d.NumPlayers > MaxPlayers {
+		s.Warning("Games must have between 2 and " + strconv.Itoa(MaxPlayers) + " players.")
+		return
+	}
+
+	// Validate that the player is not joined to another table
+	if t2 := s.GetJoinedTable(); t2 != nil {
+		s.WarningWithCode(ErrAlreadyAtTable, "You cannot join the queue while you are at a table.")
+		return
+	}
+
+	queueJoin(s, d)
+}
+
+func queueJoin(s *Session, d *CommandData) {
+	entry := &QueueEntry{
+		Session:        s,
+		VariantName:    d.Variant,
+		NumPlayers:     d.NumPlayers,
+		Timed:          d.Timed,
+		DatetimeJoined: time.Now(),
+	}
+
+	matchmakingQueueMutex.Lock()
+	if matchmakingGetEntry(s.UserID()) != nil {
+		matchmakingQueueMutex.Unlock()
+		s.Warning("You are already in the queue.")
+		return
+	}
+	matchmakingQueue = append(matchmakingQueue, entry)
+	matchedEntries := matchmakingTakeMatch(entry)
+	matchmakingQueueMutex.Unlock()
+
+	if matchedEntries == nil {
+		logger.Info("User \"" + s.Username() + "\" joined the queue for a " +
+			strconv.Itoa(entry.NumPlayers) + "-player game of: " + entry.VariantName)
+		s.NotifyQueue(entry)
+		return
+	}
+
+	matchmakingCreateTable(matchedEntries)
+}
@@ -0,0 +1,15 @@
+package main
+
+// commandQueueLeave is sent when the user no longer wants the server to find a game for them
+//
+// Example data:
+// {}
+func commandQueueLeave(s *Session, d *CommandData) {
+	if !matchmakingRemove(s.UserID()) {
+		s.Warning("You are not in the queue.")
+		return
+	}
+
+	logger.Info("User \"" + s.Username() + "\" left the queue.")
+	s.NotifyQueue(nil)
+}
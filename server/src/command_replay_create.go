@@ -547,7 +547,7 @@ func newFakeSession(id int, name string) *Session {
 	keys["fakeUser"] = true
 
 	return &Session{
-		&melody.Session{
+		Session: &melody.Session{
 			Keys: keys,
 		},
 	}
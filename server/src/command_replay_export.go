@@ -0,0 +1,86 @@
+package main
+
+// commandReplayExport is sent when the user wants to download the replay of a finished game
+// The game is exported in the same format as the "/export" HTTP endpoint,
+// so it can be loaded again with the "replayCreate" command (with a source of "json")
+// Unlike the HTTP endpoint, this does not require the game to be in the database
+//
+// Example data:
+// {
+//   tableID: 5,
+// }
+func commandReplayExport(s *Session, d *CommandData) {
+	t, exists := getTableAndLock(s, d.TableID, !d.NoLock)
+	if !exists {
+		return
+	}
+	if !d.NoLock {
+		defer t.Mutex.Unlock()
+	}
+
+	// Validate that this is a replay
+	// (finished games are automatically converted to shared replays)
+	if !t.Replay {
+		s.WarningWithCode(ErrNotReplay, NotReplayFail)
+		return
+	}
+
+	replayExport(s, t)
+}
+
+func replayExport(s *Session, t *Table) {
+	// Local variables
+	g := t.Game
+
+	playerNames := make([]string, 0)
+	notes := make([][]string, 0)
+	for _, p := range g.Players {
+		playerNames = append(playerNames, p.Name)
+		notes = append(notes, p.Notes)
+	}
+
+	// If this was a game with the "Detrimental Characters" option turned on,
+	// make a list of the characters for each player
+	var characterAssignments []*CharacterAssignment
+	if t.Options.DetrimentalCharacters {
+		characterAssignments = make([]*CharacterAssignment, 0)
+		for _, p := range g.Players {
+			characterAssignments = append(characterAssignments, &CharacterAssignment{
+				Name:     p.Character,
+				Metadata: p.CharacterMetadata,
+			})
+		}
+	}
+
+	// Games created from arbitrary JSON data do not have a real seed
+	seed := g.Seed
+	if seed == "JSON" {
+		seed = ""
+	}
+
+	// The ID is only included if the game exists in the database
+	gameID := 0
+	if t.ExtraOptions.DatabaseID > 0 {
+		gameID = t.ExtraOptions.DatabaseID
+	}
+
+	gameJSON := &GameJSON{
+		ID:         gameID,
+		Players:    playerNames,
+		Deck:       g.CardIdentities,
+		Actions:    g.Actions2,
+		Options:    t.Options.GetOptionsJSON(),
+		Notes:      trimPlayerNotes(notes),
+		Characters: characterAssignments,
+		Seed:       seed,
+	}
+
+	type ReplayExportMessage struct {
+		TableID uint64    `json:"tableID"`
+		Game    *GameJSON `json:"game"`
+	}
+	s.Emit("replayExport", &ReplayExportMessage{
+		TableID: t.ID,
+		Game:    gameJSON,
+	})
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+func exportTestReplay(t *testing.T, s *Session, conn *websocket.Conn, tableID uint64) *GameJSON {
+	commandReplayExport(s, &CommandData{ // Manual invocation
+		TableID: tableID,
+	})
+
+	var msg struct {
+		TableID uint64    `json:"tableID"`
+		Game    *GameJSON `json:"game"`
+	}
+	if err := json.Unmarshal([]byte(readTestCommand(t, conn, "replayExport")), &msg); err != nil {
+		t.Fatal("failed to unmarshal the message:", err)
+	}
+	if msg.TableID != tableID {
+		t.Errorf("expected the export to be for table %v, but it was for table %v",
+			tableID, msg.TableID)
+	}
+
+	return msg.Game
+}
+
+func TestCommandReplayExportRoundTrip(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestReplay(t)
+	sp, conn := newTestWebsocketSpectator(t, tb, 10, "Spectator")
+
+	exported := exportTestReplay(t, sp.Session, conn, tb.ID)
+	if !reflect.DeepEqual(exported.Actions, tb.Game.Actions2) {
+		t.Error("the exported actions do not match the actions of the game")
+	}
+	if !reflect.DeepEqual(exported.Deck, tb.Game.CardIdentities) {
+		t.Error("the exported deck does not match the deck of the game")
+	}
+	if !reflect.DeepEqual(exported.Players, []string{"Alice", "Bob"}) {
+		t.Errorf("the exported players are wrong: %v", exported.Players)
+	}
+	if exported.Seed != tb.Game.Seed {
+		t.Errorf("expected the exported seed to be \"%v\", but it was \"%v\"",
+			tb.Game.Seed, exported.Seed)
+	}
+
+	// Load the exported game as a new replay
+	s, conn2 := newTestWebsocket(t, 11, "Importer", 0)
+	commandReplayCreate(s, &CommandData{ // Manual invocation
+		Source:     "json",
+		GameJSON:   exported,
+		Visibility: "solo",
+	})
+	var imported *Table
+	for _, t2 := range tables {
+		if t2.ID != tb.ID {
+			imported = t2
+		}
+	}
+	if imported == nil {
+		t.Fatal("the exported game was not imported")
+	}
+	if !reflect.DeepEqual(imported.Game.Actions2, tb.Game.Actions2) {
+		t.Error("the actions of the imported game do not match the original game")
+	}
+
+	// Exporting the imported game results in the same game again
+	reexported := exportTestReplay(t, s, conn2, imported.ID)
+	if !reflect.DeepEqual(reexported.Actions, exported.Actions) ||
+		!reflect.DeepEqual(reexported.Deck, exported.Deck) ||
+		!reflect.DeepEqual(reexported.Players, exported.Players) {
+
+		t.Error("the re-exported game does not match the original export")
+	}
+}
+
+func TestCommandReplayExportOngoingGame(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+	_, conn := newTestWebsocketSpectator(t, tb, 10, "Spectator")
+
+	commandReplayExport(tb.Spectators[0].Session, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+	})
+	expectTestWarning(t, conn, NotReplayFail)
+}
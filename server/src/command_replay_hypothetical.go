@@ -0,0 +1,133 @@
+package main
+
+import (
+	"strconv"
+)
+
+// commandReplayHypothetical is sent when the leader of a shared replay wants to explore an
+// alternative line of play from a specific turn
+// The moves of the hypothetical are sent with the "hypoAction" replay action and are only stored
+// in the list of hypothetical actions, so the actions of the game itself are never changed
+//
+// Example data:
+// {
+//   tableID: 5,
+//   fromTurn: 10,
+// }
+func commandReplayHypothetical(s *Session, d *CommandData) {
+	t, exists := getTableAndLock(s, d.TableID, !d.NoLock)
+	if !exists {
+		return
+	}
+	if !d.NoLock {
+		defer t.Mutex.Unlock()
+	}
+
+	if !validateReplayHypothetical(s, t) {
+		return
+	}
+
+	// Validate that they are not already in a hypothetical
+	if t.Game.Hypothetical {
+		s.Warning("You are already in a hypothetical, so you cannot start a new one.")
+		return
+	}
+
+	// Validate the turn
+	if d.FromTurn < 0 {
+		s.Warning("The turn must not be negative.")
+		return
+	}
+
+	replayHypothetical(s, d, t)
+}
+
+func replayHypothetical(s *Session, d *CommandData, t *Table) {
+	// Local variables
+	g := t.Game
+
+	// Start the idle timeout
+	go t.CheckIdle()
+
+	// Move everyone to the turn that the hypothetical branches from
+	// (we borrow the turn variable to use as a stand-in for the current shared replay segment)
+	g.HypoStartSegment = d.FromTurn
+	replayActionSegment(s, &CommandData{ // Manual invocation
+		TableID: t.ID,
+		Segment: d.FromTurn,
+		NoLock:  true,
+	}, t)
+
+	replayActionHypoStart(s, d, t)
+}
+
+// commandEndHypothetical is sent when the leader of a shared replay wants to discard the
+// hypothetical that was started with the "replayHypothetical" command
+// Everyone is returned to the turn that the hypothetical branched from
+//
+// Example data:
+// {
+//   tableID: 5,
+// }
+func commandEndHypothetical(s *Session, d *CommandData) {
+	t, exists := getTableAndLock(s, d.TableID, !d.NoLock)
+	if !exists {
+		return
+	}
+	if !d.NoLock {
+		defer t.Mutex.Unlock()
+	}
+
+	if !validateReplayHypothetical(s, t) {
+		return
+	}
+
+	// Validate that they are in a hypothetical
+	if !t.Game.Hypothetical {
+		s.Warning("You are not in a hypothetical, so you cannot end one.")
+		return
+	}
+
+	endHypothetical(s, d, t)
+}
+
+func endHypothetical(s *Session, d *CommandData, t *Table) {
+	// Local variables
+	g := t.Game
+
+	// Start the idle timeout
+	go t.CheckIdle()
+
+	replayActionHypoEnd(s, d, t)
+
+	replayActionSegment(s, &CommandData{ // Manual invocation
+		TableID: t.ID,
+		Segment: g.HypoStartSegment,
+		NoLock:  true,
+	}, t)
+}
+
+// validateReplayHypothetical checks that the user is allowed to control the hypotheticals of a
+// table (in the same way as the "commandReplayAction()" function)
+func validateReplayHypothetical(s *Session, t *Table) bool {
+	// Validate that this is a shared replay
+	if !t.Replay || !t.Visible {
+		s.Warning("Table " + strconv.FormatUint(t.ID, 10) + " is not a shared replay, " +
+			"so you cannot start or end a hypothetical.")
+		return false
+	}
+
+	// Validate that this person is spectating the shared replay
+	if t.GetSpectatorIndexFromID(s.UserID()) == -1 {
+		s.Warning("You are not in shared replay " + strconv.FormatUint(t.ID, 10) + ".")
+		return false
+	}
+
+	// Validate that this person is leading the shared replay
+	if s.UserID() != t.Owner {
+		s.Warning("You cannot start or end a hypothetical unless you are the leader.")
+		return false
+	}
+
+	return true
+}
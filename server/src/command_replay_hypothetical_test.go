@@ -0,0 +1,184 @@
+package main
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+type testSpectatorConn struct {
+	sp   *Spectator
+	conn *websocket.Conn
+}
+
+// newTestSharedReplay creates a shared replay that is led by a spectator, who is returned along
+// with another spectator watching the replay
+func newTestSharedReplay(t *testing.T) (*Table, *testSpectatorConn, *testSpectatorConn) {
+	tb := newTestReplay(t)
+	tb.Visible = true
+	leader, leaderConn := newTestWebsocketSpectator(t, tb, 10, "Leader")
+	viewer, viewerConn := newTestWebsocketSpectator(t, tb, 11, "Viewer")
+	tb.Owner = leader.ID
+
+	return tb, &testSpectatorConn{leader, leaderConn}, &testSpectatorConn{viewer, viewerConn}
+}
+
+// marshalTestGame returns a snapshot of the state of the game that is not part of a hypothetical
+func marshalTestGame(t *testing.T, g *Game) string {
+	snapshot := struct {
+		Actions    []interface{}
+		Score      int
+		ClueTokens int
+		Hands      [][]*Card
+		Deck       []*Card
+	}{
+		Actions:    g.Actions,
+		Score:      g.Score,
+		ClueTokens: g.ClueTokens,
+		Deck:       g.Deck,
+	}
+	for _, p := range g.Players {
+		snapshot.Hands = append(snapshot.Hands, p.Hand)
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		t.Fatal("failed to marshal the game:", err)
+	}
+	return string(data)
+}
+
+func readTestSegment(t *testing.T, sc *testSpectatorConn) int {
+	var msg struct {
+		Segment int `json:"segment"`
+	}
+	data := readTestCommand(t, sc.conn, "replaySegment")
+	if err := json.Unmarshal([]byte(data), &msg); err != nil {
+		t.Fatal("failed to unmarshal the segment:", err)
+	}
+	return msg.Segment
+}
+
+func TestCommandReplayHypotheticalDoesNotMutateGame(t *testing.T) {
+	resetTestTables(t)
+	tb, leader, viewer := newTestSharedReplay(t)
+	g := tb.Game
+	base := marshalTestGame(t, g)
+
+	commandReplayHypothetical(leader.sp.Session, &CommandData{ // Manual invocation
+		TableID:  tb.ID,
+		FromTurn: 1,
+	})
+	if !g.Hypothetical || g.HypoStartSegment != 1 {
+		t.Fatal("the hypothetical was not started from turn 1")
+	}
+	for _, sc := range []*testSpectatorConn{leader, viewer} {
+		if segment := readTestSegment(t, sc); segment != 1 {
+			t.Errorf("expected %v to be moved to turn 1, but got turn %v", sc.sp.Name, segment)
+		}
+		readTestCommand(t, sc.conn, "hypoStart")
+	}
+
+	// Moves in the hypothetical are sent to everyone in the replay
+	actionJSON := `{"type":"discard","playerIndex":1,"order":` +
+		strconv.Itoa(g.Players[1].Hand[0].Order) + `}`
+	commandReplayAction(leader.sp.Session, &CommandData{ // Manual invocation
+		TableID:    tb.ID,
+		Type:       ReplayActionTypeHypoAction,
+		ActionJSON: actionJSON,
+	})
+	for _, sc := range []*testSpectatorConn{leader, viewer} {
+		var data string
+		msg := readTestCommand(t, sc.conn, "hypoAction")
+		if err := json.Unmarshal([]byte(msg), &data); err != nil {
+			t.Fatal("failed to unmarshal the hypothetical action:", err)
+		}
+		if data != actionJSON {
+			t.Errorf("expected %v to get the hypothetical action %v, but got %v", sc.sp.Name,
+				actionJSON, data)
+		}
+	}
+	if len(g.HypoActions) != 1 {
+		t.Errorf("expected 1 hypothetical action, but got %v", len(g.HypoActions))
+	}
+	if v := marshalTestGame(t, g); v != base {
+		t.Errorf("the hypothetical action changed the game:\n%v\n%v", base, v)
+	}
+
+	// Only the leader can control the hypothetical
+	commandEndHypothetical(viewer.sp.Session, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+	})
+	expectTestWarning(t, viewer.conn, "unless you are the leader")
+
+	// A second hypothetical cannot be started until the first one is ended
+	commandReplayHypothetical(leader.sp.Session, &CommandData{ // Manual invocation
+		TableID:  tb.ID,
+		FromTurn: 2,
+	})
+	expectTestWarning(t, leader.conn, "You are already in a hypothetical")
+}
+
+func TestCommandEndHypotheticalRestoresReplay(t *testing.T) {
+	resetTestTables(t)
+	tb, leader, viewer := newTestSharedReplay(t)
+	g := tb.Game
+	base := marshalTestGame(t, g)
+
+	commandEndHypothetical(leader.sp.Session, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+	})
+	expectTestWarning(t, leader.conn, "You are not in a hypothetical")
+
+	commandReplayHypothetical(leader.sp.Session, &CommandData{ // Manual invocation
+		TableID:  tb.ID,
+		FromTurn: 2,
+	})
+	commandReplayAction(leader.sp.Session, &CommandData{ // Manual invocation
+		TableID:    tb.ID,
+		Type:       ReplayActionTypeHypoAction,
+		ActionJSON: `{"type":"clue","clue":{"type":0,"value":1},"target":0}`,
+	})
+	commandEndHypothetical(leader.sp.Session, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+	})
+
+	if g.Hypothetical {
+		t.Error("the hypothetical was not ended")
+	}
+	if len(g.HypoActions) != 0 {
+		t.Errorf("expected the hypothetical actions to be discarded, but there are %v",
+			len(g.HypoActions))
+	}
+	if v := marshalTestGame(t, g); v != base {
+		t.Errorf("the hypothetical changed the game:\n%v\n%v", base, v)
+	}
+
+	// Everyone is returned to the turn that the hypothetical started from
+	for _, sc := range []*testSpectatorConn{leader, viewer} {
+		readTestCommand(t, sc.conn, "hypoEnd")
+		if segment := readTestSegment(t, sc); segment != 2 {
+			t.Errorf("expected %v to be returned to turn 2, but got turn %v", sc.sp.Name, segment)
+		}
+	}
+	if g.Turn != 2 {
+		t.Errorf("expected the replay to be on turn 2, but it is on turn %v", g.Turn)
+	}
+}
+
+func TestCommandReplayHypotheticalNotSharedReplay(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+	sp, conn := newTestWebsocketSpectator(t, tb, 10, "Spectator")
+
+	commandReplayHypothetical(sp.Session, &CommandData{ // Manual invocation
+		TableID:  tb.ID,
+		FromTurn: 0,
+	})
+	expectTestWarning(t, conn, "is not a shared replay")
+	if tb.Game.Hypothetical {
+		t.Error("a hypothetical was started in an ongoing game")
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"strconv"
+)
+
+// commandReplaySeek is sent when the user wants to jump to a specific turn in a replay
+// The server sends back all of the actions up to that turn so that the client can reconstruct the
+// state of the game at that point without having to step through every turn
+//
+// Example data:
+// {
+//   tableID: 5,
+//   turn: 10,
+// }
+func commandReplaySeek(s *Session, d *CommandData) {
+	t, exists := getTableAndLock(s, d.TableID, !d.NoLock)
+	if !exists {
+		return
+	}
+	if !d.NoLock {
+		defer t.Mutex.Unlock()
+	}
+	g := t.Game
+
+	// Validate that this is a replay
+	if !t.Replay {
+		s.WarningWithCode(ErrNotReplay, NotReplayFail)
+		return
+	}
+
+	// Validate that they are spectating the replay
+	if t.GetSpectatorIndexFromID(s.UserID()) == -1 {
+		s.Warning("You are not in replay " + strconv.FormatUint(t.ID, 10) + ".")
+		return
+	}
+
+	// Validate the turn
+	if d.Turn < 0 || d.Turn > g.EndTurn {
+		s.Warning("The turn must be between 0 and " + strconv.Itoa(g.EndTurn) + ".")
+		return
+	}
+
+	replaySeek(s, t, d.Turn)
+}
+
+func replaySeek(s *Session, t *Table, turn int) {
+	type ReplaySeekMessage struct {
+		TableID uint64        `json:"tableID"`
+		Turn    int           `json:"turn"`
+		List    []interface{} `json:"list"`
+	}
+	s.Emit("replaySeek", &ReplaySeekMessage{
+		TableID: t.ID,
+		Turn:    turn,
+		List:    t.Game.GetActionsUpToTurn(turn),
+	})
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type testReplaySeekMessage struct {
+	TableID uint64                   `json:"tableID"`
+	Turn    int                      `json:"turn"`
+	List    []map[string]interface{} `json:"list"`
+}
+
+// newTestReplay creates a shared replay of a game where a few turns were played
+func newTestReplay(t *testing.T) *Table {
+	tb := newTestGame(t, 2)
+	clueTestPlayer(t, tb)
+	discardTestCard(t, tb)
+	clueTestPlayer(t, tb)
+
+	tb.Replay = true
+	tb.Game.EndTurn = tb.Game.Turn
+	return tb
+}
+
+func TestReplaySeek(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestReplay(t)
+	sp, conn := newTestWebsocketSpectator(t, tb, 10, "Spectator")
+	g := tb.Game
+
+	numDrawActions := 2 * g.GetHandSize()
+	for turn, expectedLength := range map[int]int{
+		0:         numDrawActions,
+		g.EndTurn: len(g.Actions),
+	} {
+		commandReplaySeek(sp.Session, &CommandData{
+			TableID: tb.ID,
+			Turn:    turn,
+		})
+
+		var msg testReplaySeekMessage
+		if err := json.Unmarshal([]byte(readTestCommand(t, conn, "replaySeek")), &msg); err != nil {
+			t.Fatal("failed to unmarshal the message:", err)
+		}
+		if msg.TableID != tb.ID || msg.Turn != turn {
+			t.Errorf("the message is for table %v and turn %v", msg.TableID, msg.Turn)
+		}
+		if len(msg.List) != expectedLength {
+			t.Errorf("expected %v actions for turn %v, but got %v", expectedLength, turn,
+				len(msg.List))
+		}
+	}
+
+	// The first turn only consists of the cards being dealt
+	commandReplaySeek(sp.Session, &CommandData{
+		TableID: tb.ID,
+		Turn:    1,
+	})
+	var msg testReplaySeekMessage
+	if err := json.Unmarshal([]byte(readTestCommand(t, conn, "replaySeek")), &msg); err != nil {
+		t.Fatal("failed to unmarshal the message:", err)
+	}
+	if last := msg.List[len(msg.List)-1]; last["type"] != "turn" || last["num"] != 1.0 {
+		t.Errorf("expected the actions for turn 1 to end with the turn action, but got: %v", last)
+	}
+}
+
+func TestReplaySeekOutOfRange(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestReplay(t)
+	sp, conn := newTestWebsocketSpectator(t, tb, 10, "Spectator")
+
+	for _, turn := range []int{-1, tb.Game.EndTurn + 1} {
+		commandReplaySeek(sp.Session, &CommandData{
+			TableID: tb.ID,
+			Turn:    turn,
+		})
+		expectTestWarning(t, conn, "The turn must be between 0 and")
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"strconv"
+)
+
+const (
+	// If a client is more than this many turns behind,
+	// it is simpler to send them all of the actions again
+	MaxResyncTurns = 20
+)
+
+// commandRequestResync is sent when the client suspects that it missed a game action
+// (e.g. on a flaky network)
+// The server will reply with only the actions that happened after the specified turn,
+// along with the current turn so that the client can confirm that it is caught up
+// If the turn is not valid or is too far behind,
+// all of the actions are sent instead (with "full" set to true)
+//
+// Example data:
+// {
+//   tableID: 5,
+//   lastSeenTurn: 10,
+// }
+func commandRequestResync(s *Session, d *CommandData) {
+	t, exists := getTableAndLock(s, d.TableID, !d.NoLock)
+	if !exists {
+		return
+	}
+	if !d.NoLock {
+		defer t.Mutex.Unlock()
+	}
+
+	// Validate that the game has started
+	if !t.Running {
+		s.WarningWithCode(ErrNotStarted, NotStartedFail)
+		return
+	}
+
+	// Validate that it is not a replay
+	// (all of the actions of a replay are sent at once when it is loaded)
+	if t.Replay {
+		s.Warning("You can not resync a replay.")
+		return
+	}
+
+	// Validate that they are either a player or a spectator
+	playerIndex := t.GetPlayerIndexFromID(s.UserID())
+	spectatorIndex := t.GetSpectatorIndexFromID(s.UserID())
+	if playerIndex == -1 && spectatorIndex == -1 {
+		s.Warning("You are not a player or a spectator at table " +
+			strconv.FormatUint(t.ID, 10) + ", so you cannot resync it.")
+		return
+	}
+
+	requestResync(s, d, t)
+}
+
+func requestResync(s *Session, d *CommandData, t *Table) {
+	// Local variables
+	g := t.Game
+
+	// Find the action that marks the beginning of the turn after the last turn that they have seen
+	// (the actions for a turn end with a "turn" action that has the number of the next turn)
+	startIndex := -1
+	if d.LastSeenTurn == g.Turn {
+		// They are already caught up
+		startIndex = len(g.Actions)
+	} else if d.LastSeenTurn >= 0 &&
+		d.LastSeenTurn < g.Turn &&
+		g.Turn-d.LastSeenTurn <= MaxResyncTurns {
+
+		for i, action := range g.Actions {
+			if turnAction, ok := action.(ActionTurn); ok && turnAction.Num == d.LastSeenTurn {
+				startIndex = i + 1
+				break
+			}
+		}
+	}
+
+	// Fall back to sending all of the actions
+	full := startIndex == -1
+	if full {
+		startIndex = 0
+	}
+
+	// Check to see if we need to remove some card information
+	scrubbedActions := make([]interface{}, 0)
+	for _, action := range g.Actions[startIndex:] {
+		scrubbedAction := CheckScrubForSession(s, t, action)
+		scrubbedActions = append(scrubbedActions, scrubbedAction)
+	}
+
+	type GameActionResyncMessage struct {
+		TableID uint64        `json:"tableID"`
+		List    []interface{} `json:"list"`
+		Turn    int           `json:"turn"`
+		Full    bool          `json:"full"`
+	}
+	s.Emit("gameActionResync", &GameActionResyncMessage{
+		TableID: t.ID,
+		List:    scrubbedActions,
+		Turn:    g.Turn,
+		Full:    full,
+	})
+}
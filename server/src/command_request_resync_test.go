@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+type testResyncMessage struct {
+	TableID uint64                   `json:"tableID"`
+	List    []map[string]interface{} `json:"list"`
+	Turn    int                      `json:"turn"`
+	Full    bool                     `json:"full"`
+}
+
+func requestTestResync(
+	t *testing.T,
+	tb *Table,
+	s *Session,
+	conn *websocket.Conn,
+	lastSeenTurn int,
+) *testResyncMessage {
+	commandRequestResync(s, &CommandData{
+		TableID:      tb.ID,
+		LastSeenTurn: lastSeenTurn,
+		NoLock:       true,
+	})
+
+	data := readTestCommand(t, conn, "gameActionResync")
+	var msg testResyncMessage
+	if err := json.Unmarshal([]byte(data), &msg); err != nil {
+		t.Fatal("failed to unmarshal the \"gameActionResync\" message:", err)
+	}
+
+	return &msg
+}
+
+// newTestResyncGame creates a game where 3 turns have been played
+func newTestResyncGame(t *testing.T) (*Table, *Session, *websocket.Conn) {
+	tb := newTestGame(t, 3)
+	clueTestPlayer(t, tb)
+	discardTestCard(t, tb)
+	clueTestPlayer(t, tb)
+
+	s, conn := newTestWebsocket(t, 1, "Alice", 0)
+	tb.Players[0].Session = s
+	return tb, s, conn
+}
+
+func TestRequestResyncDelta(t *testing.T) {
+	resetTestTables(t)
+	tb, s, conn := newTestResyncGame(t)
+
+	msg := requestTestResync(t, tb, s, conn, 2)
+	if msg.Full {
+		t.Error("all of the actions were sent for a small delta")
+	}
+	if msg.Turn != 3 {
+		t.Errorf("expected the current turn to be 3, but got %v", msg.Turn)
+	}
+
+	// Only the clue from turn 2, the status update, and the turn action that followed are sent
+	if len(msg.List) != 3 {
+		t.Fatalf("expected 3 actions, but got %v: %v", len(msg.List), msg.List)
+	}
+	if msg.List[0]["type"] != "clue" {
+		t.Errorf("expected the first action to be a clue, but got: %v", msg.List[0])
+	}
+	if msg.List[2]["type"] != "turn" || msg.List[2]["num"] != float64(3) {
+		t.Errorf("expected the last action to be the start of turn 3, but got: %v", msg.List[2])
+	}
+}
+
+func TestRequestResyncCaughtUp(t *testing.T) {
+	resetTestTables(t)
+	tb, s, conn := newTestResyncGame(t)
+
+	msg := requestTestResync(t, tb, s, conn, 3)
+	if msg.Full {
+		t.Error("all of the actions were sent to a client that was caught up")
+	}
+	if len(msg.List) != 0 {
+		t.Errorf("expected no actions, but got: %v", msg.List)
+	}
+	if msg.Turn != 3 {
+		t.Errorf("expected the current turn to be 3, but got %v", msg.Turn)
+	}
+}
+
+func TestRequestResyncOutOfRange(t *testing.T) {
+	for _, lastSeenTurn := range []int{-1, 4, 100} {
+		resetTestTables(t)
+		tb, s, conn := newTestResyncGame(t)
+
+		msg := requestTestResync(t, tb, s, conn, lastSeenTurn)
+		if !msg.Full {
+			t.Errorf("a full re-send was not done for a last seen turn of %v", lastSeenTurn)
+		}
+		if len(msg.List) != len(tb.Game.Actions) {
+			t.Errorf("expected all %v actions for a last seen turn of %v, but got %v",
+				len(tb.Game.Actions), lastSeenTurn, len(msg.List))
+		}
+	}
+}
+
+func TestRequestResyncTooFarBehind(t *testing.T) {
+	resetTestTables(t)
+	tb, s, conn := newTestResyncGame(t)
+	for i := 0; i < MaxResyncTurns; i++ {
+		if tb.Game.ClueTokens > 0 {
+			clueTestPlayer(t, tb)
+		} else {
+			discardTestCard(t, tb)
+		}
+	}
+
+	msg := requestTestResync(t, tb, s, conn, 2)
+	if !msg.Full {
+		t.Error("a full re-send was not done for a client that is too far behind")
+	}
+	if len(msg.List) != len(tb.Game.Actions) {
+		t.Errorf("expected all %v actions, but got %v", len(tb.Game.Actions), len(msg.List))
+	}
+}
+
+func TestRequestResyncNotAtTable(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+
+	s, conn := newTestWebsocket(t, 10, "Dan", 0)
+	commandRequestResync(s, &CommandData{
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+
+	expectTestWarning(t, conn, "You are not a player or a spectator")
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"time"
+)
+
+type ServerInfo struct {
+	// The commit and the build time are only known if they were set with linker flags
+	BuildCommit string `json:"buildCommit"`
+	BuildTime   string `json:"buildTime"`
+	// This is the commit that the repository was at when the server started
+	// (which can be different from the build commit if the server was not rebuilt)
+	GitCommit        string    `json:"gitCommit"`
+	DatetimeStarted  time.Time `json:"datetimeStarted"`
+	UptimeSeconds    int64     `json:"uptimeSeconds"`
+	NumRunningTables int       `json:"numRunningTables"`
+	NumSessions      int       `json:"numSessions"`
+}
+
+// commandServerInfo is sent when the user wants to know which version of the server is running
+// and how long it has been up for
+//
+// Has no data
+func commandServerInfo(s *Session, d *CommandData) {
+	s.Emit("serverInfo", getServerInfo())
+}
+
+func getServerInfo() *ServerInfo {
+	numRunningTables := 0
+	tablesMutex.RLock()
+	for _, t := range tables {
+		if t.Running && !t.Replay {
+			numRunningTables++
+		}
+	}
+	tablesMutex.RUnlock()
+
+	sessionsMutex.RLock()
+	numSessions := len(sessions)
+	sessionsMutex.RUnlock()
+
+	return &ServerInfo{
+		BuildCommit:      buildCommit,
+		BuildTime:        buildTime,
+		GitCommit:        gitCommitOnStart,
+		DatetimeStarted:  datetimeStarted,
+		UptimeSeconds:    int64(time.Since(datetimeStarted).Seconds()),
+		NumRunningTables: numRunningTables,
+		NumSessions:      numSessions,
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func useTestBuildInfo(t *testing.T, started time.Time) {
+	oldBuildCommit := buildCommit
+	oldBuildTime := buildTime
+	oldDatetimeStarted := datetimeStarted
+	buildCommit = "abc123"
+	buildTime = "2020-08-01T00:00:00Z"
+	datetimeStarted = started
+	t.Cleanup(func() {
+		buildCommit = oldBuildCommit
+		buildTime = oldBuildTime
+		datetimeStarted = oldDatetimeStarted
+	})
+}
+
+func TestCommandServerInfo(t *testing.T) {
+	resetTestTables(t)
+	useTestBuildInfo(t, time.Now().Add(-time.Hour))
+	newTestGame(t, 2)
+	newTestTable(t, 2)
+	s, conn := newTestWebsocket(t, 1, "Alice", 0)
+	sessionsMutex.Lock()
+	sessions[s.UserID()] = s
+	sessionsMutex.Unlock()
+
+	commandServerInfo(s, &CommandData{}) // Manual invocation
+	data := readTestCommand(t, conn, "serverInfo")
+
+	// The version fields are always sent (even if they are empty)
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(data), &fields); err != nil {
+		t.Fatal("failed to unmarshal the server info:", err)
+	}
+	for _, field := range []string{"buildCommit", "buildTime", "gitCommit", "datetimeStarted"} {
+		if _, ok := fields[field]; !ok {
+			t.Errorf("the server info does not have the \"%v\" field: %v", field, data)
+		}
+	}
+
+	var info ServerInfo
+	if err := json.Unmarshal([]byte(data), &info); err != nil {
+		t.Fatal("failed to unmarshal the server info:", err)
+	}
+	if info.BuildCommit != buildCommit || info.BuildTime != buildTime {
+		t.Errorf("the build info from the linker flags was not sent: %v", data)
+	}
+	if info.UptimeSeconds < 3600 || info.UptimeSeconds > 3610 {
+		t.Errorf("expected an uptime of an hour, but got %v seconds", info.UptimeSeconds)
+	}
+
+	// The unstarted table does not count as a running table
+	if info.NumRunningTables != 1 {
+		t.Errorf("expected 1 running table, but got %v", info.NumRunningTables)
+	}
+	if info.NumSessions != 1 {
+		t.Errorf("expected 1 session, but got %v", info.NumSessions)
+	}
+}
+
+func TestServerInfoUptimeIncreases(t *testing.T) {
+	resetTestTables(t)
+	useTestBuildInfo(t, time.Now().Add(-900*time.Millisecond))
+
+	uptime := getServerInfo().UptimeSeconds
+	time.Sleep(200 * time.Millisecond)
+	if v := getServerInfo().UptimeSeconds; v <= uptime {
+		t.Errorf("expected the uptime to increase from %v seconds, but it is %v seconds",
+			uptime, v)
+	}
+}
+
+func TestHttpLocalhostInfo(t *testing.T) {
+	resetTestTables(t)
+	useTestBuildInfo(t, time.Now())
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/info", nil)
+	httpLocalhostInfo(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected a status code of %v, but got %v", http.StatusOK, w.Code)
+	}
+
+	var info ServerInfo
+	if err := json.Unmarshal(w.Body.Bytes(), &info); err != nil {
+		t.Fatal("failed to unmarshal the server info:", err)
+	}
+	if info.BuildCommit != buildCommit || !info.DatetimeStarted.Equal(datetimeStarted) {
+		t.Errorf("the server info was not returned: %v", w.Body)
+	}
+}
@@ -0,0 +1,18 @@
+package main
+
+// commandSpectateSetOptions is sent when a user changes how games are shown to them while they are
+// spectating (e.g. some spectators find it spoilery to see which cards a clue touched)
+// The options are stored on the session, so they apply to every table that the user spectates
+// until they reconnect
+//
+// Example data:
+// {
+//   showTouchedCards: false,
+// }
+func commandSpectateSetOptions(s *Session, d *CommandData) {
+	if s == nil {
+		return
+	}
+
+	s.Set("showTouchedCards", d.ShowTouchedCards)
+}
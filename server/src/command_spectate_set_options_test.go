@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+// readTestClueAction reads game actions until it finds a clue and returns the cards that it touched
+func readTestClueAction(t *testing.T, conn *websocket.Conn) []int {
+	for {
+		var msg struct {
+			Action ActionClue `json:"action"`
+		}
+		if err := json.Unmarshal([]byte(readTestCommand(t, conn, "gameAction")), &msg); err != nil {
+			t.Fatal("failed to unmarshal the game action:", err)
+		}
+		if msg.Action.Type == "clue" {
+			return msg.Action.List
+		}
+	}
+}
+
+func TestCommandSpectateSetOptions(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+	playerConns := connectTestPlayers(t, tb)
+	detailed, detailedConn := newTestWebsocketSpectator(t, tb, 10, "Zed")
+	simplified, simplifiedConn := newTestWebsocketSpectator(t, tb, 11, "Yoda")
+
+	commandSpectateSetOptions(simplified.Session, &CommandData{ // Manual invocation
+		ShowTouchedCards: false,
+	})
+	if simplified.Session.ShowTouchedCards() || !detailed.Session.ShowTouchedCards() {
+		t.Fatal("the option was not set for only one of the spectators")
+	}
+
+	clueTestPlayer(t, tb)
+
+	// The same clue is sent differently to each spectator
+	if list := readTestClueAction(t, detailedConn); len(list) == 0 {
+		t.Error("the spectator who wants to see the touched cards did not get them")
+	}
+	if list := readTestClueAction(t, simplifiedConn); len(list) != 0 {
+		t.Errorf("expected the touched cards to be hidden, but got %v", list)
+	}
+
+	// The option does not apply to the players or to the game itself
+	if list := readTestClueAction(t, playerConns[1]); len(list) == 0 {
+		t.Error("the player did not get the touched cards")
+	}
+	for _, action := range tb.Game.Actions {
+		if clueAction, ok := action.(ActionClue); ok && len(clueAction.List) == 0 {
+			t.Error("the touched cards were removed from the game")
+		}
+	}
+}
+
+func TestCheckScrubForSessionPlayer(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+	s, _ := newTestWebsocket(t, 1, "Alice", 0)
+	commandSpectateSetOptions(s, &CommandData{ // Manual invocation
+		ShowTouchedCards: false,
+	})
+
+	action := ActionClue{
+		Type: "clue",
+		List: []int{1, 2},
+	}
+	scrubbed := CheckScrubForSession(s, tb, action).(ActionClue)
+	if len(scrubbed.List) != 2 {
+		t.Errorf("the touched cards were hidden from a player: %v", scrubbed.List)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"time"
+)
+
+// commandSpectateWithToken is sent when the user follows a spectate link that the owner of a table
+// gave to them (see "commandTableGenerateSpectateToken")
+//
+// Example data:
+// {
+//   tableID: 123,
+//   token: '0e1f5b8a-0a43-4f8b-9d3e-5a3c1e2f7b6d',
+//   // A value of "-1" must be specified if we do not want to shadow a player
+//   shadowingPlayerIndex: -1,
+// }
+func commandSpectateWithToken(s *Session, d *CommandData) {
+	t, exists := getTableAndLock(s, d.TableID, !d.NoLock)
+	if !exists {
+		return
+	}
+	if !d.NoLock {
+		defer t.Mutex.Unlock()
+	}
+
+	// Validate the token
+	spectateToken, ok := t.SpectateTokens[d.Token]
+	if !ok {
+		s.Warning("That spectate token is not valid.")
+		return
+	}
+	if time.Now().After(spectateToken.DatetimeExpires) {
+		delete(t.SpectateTokens, d.Token)
+		s.Warning("That spectate token has expired.")
+		return
+	}
+
+	spectateWithToken(s, d, t, spectateToken)
+}
+
+func spectateWithToken(s *Session, d *CommandData, t *Table, spectateToken *SpectateToken) {
+	// The rest of the validation is the same as for a normal spectator
+	// (the token lets them in without the password if the table is password-protected)
+	commandTableSpectate(s, &CommandData{ // Manual invocation
+		TableID:              t.ID,
+		ShadowingPlayerIndex: d.ShadowingPlayerIndex,
+		Token:                d.Token,
+		NoLock:               true,
+	})
+
+	// A single-use token is only used up if they were able to spectate with it
+	// (on a password-protected table, it was already used up when checking the password)
+	if spectateToken.SingleUse && t.GetSpectatorIndexFromID(s.UserID()) != -1 {
+		delete(t.SpectateTokens, d.Token)
+	}
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func generateTestSpectateToken(t *testing.T, tb *Table, singleUse bool) string {
+	s, conn := newTestWebsocket(t, tb.Owner, "Alice", 0)
+	commandTableGenerateSpectateToken(s, &CommandData{ // Manual invocation
+		TableID:   tb.ID,
+		SingleUse: singleUse,
+		NoLock:    true,
+	})
+
+	var msg struct {
+		TableID   uint64 `json:"tableID"`
+		Token     string `json:"token"`
+		SingleUse bool   `json:"singleUse"`
+		ExpiresIn int    `json:"expiresIn"`
+	}
+	if err := json.Unmarshal([]byte(readTestCommand(t, conn, "spectateToken")), &msg); err != nil {
+		t.Fatal("failed to unmarshal the spectate token:", err)
+	}
+	if msg.TableID != tb.ID || msg.SingleUse != singleUse ||
+		msg.ExpiresIn != int(SpectateTokenLifetime.Seconds()) {
+
+		t.Errorf("the spectate token message is wrong: %+v", msg)
+	}
+
+	return msg.Token
+}
+
+func spectateTestTableWithToken(tb *Table, s *Session, token string) {
+	commandSpectateWithToken(s, &CommandData{ // Manual invocation
+		TableID:              tb.ID,
+		Token:                token,
+		ShadowingPlayerIndex: -1,
+		NoLock:               true,
+	})
+}
+
+func TestSpectateWithToken(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+	usePasswordTestTable(t, tb)
+	token := generateTestSpectateToken(t, tb, false)
+
+	// The token can be used by more than one user instead of the password
+	for id, name := range map[int]string{10: "Dan", 11: "Eve"} {
+		s, _ := newTestWebsocket(t, id, name, 0)
+		spectateTestTableWithToken(tb, s, token)
+		if tb.GetSpectatorIndexFromID(id) == -1 {
+			t.Errorf("user %v was not able to spectate with the token", id)
+		}
+	}
+
+	// It can also be sent along with a normal spectate command
+	s, _ := newTestWebsocket(t, 12, "Emily", 0)
+	commandTableSpectate(s, &CommandData{ // Manual invocation
+		TableID:              tb.ID,
+		ShadowingPlayerIndex: -1,
+		Token:                token,
+		NoLock:               true,
+	})
+	if tb.GetSpectatorIndexFromID(12) == -1 {
+		t.Error("the user was not able to spectate with the token")
+	}
+}
+
+func TestSpectateWithTokenSingleUse(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+	usePasswordTestTable(t, tb)
+	token := generateTestSpectateToken(t, tb, true)
+
+	s, _ := newTestWebsocket(t, 10, "Dan", 0)
+	spectateTestTableWithToken(tb, s, token)
+	if tb.GetSpectatorIndexFromID(10) == -1 {
+		t.Fatal("the user was not able to spectate with the token")
+	}
+
+	s2, conn2 := newTestWebsocket(t, 11, "Eve", 0)
+	spectateTestTableWithToken(tb, s2, token)
+	expectTestWarning(t, conn2, "That spectate token is not valid.")
+	if tb.GetSpectatorIndexFromID(11) != -1 {
+		t.Error("a single-use token was used twice")
+	}
+}
+
+func TestTableSpectateWithTokenSingleUse(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+	usePasswordTestTable(t, tb)
+	token := generateTestSpectateToken(t, tb, true)
+
+	// A single-use token sent along with a normal spectate command is also used up
+	s, _ := newTestWebsocket(t, 10, "Dan", 0)
+	commandTableSpectate(s, &CommandData{ // Manual invocation
+		TableID:              tb.ID,
+		ShadowingPlayerIndex: -1,
+		Token:                token,
+		NoLock:               true,
+	})
+	if tb.GetSpectatorIndexFromID(10) == -1 {
+		t.Fatal("the user was not able to spectate with the token")
+	}
+	if _, ok := tb.SpectateTokens[token]; ok {
+		t.Error("the single-use token was not removed")
+	}
+
+	s2, conn2 := newTestWebsocket(t, 11, "Eve", 0)
+	commandTableSpectate(s2, &CommandData{ // Manual invocation
+		TableID:              tb.ID,
+		ShadowingPlayerIndex: -1,
+		Token:                token,
+		NoLock:               true,
+	})
+	expectTestWarningCode(t, conn2, ErrWrongPassword)
+	if tb.GetSpectatorIndexFromID(11) != -1 {
+		t.Error("a single-use token was used twice")
+	}
+}
+
+func TestSpectateWithTokenExpired(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+	usePasswordTestTable(t, tb)
+	token := generateTestSpectateToken(t, tb, false)
+	tb.SpectateTokens[token].DatetimeExpires = time.Now().Add(-time.Second)
+
+	// An expired token does not let them skip the password either
+	s, conn := newTestWebsocket(t, 10, "Dan", 0)
+	commandTableSpectate(s, &CommandData{ // Manual invocation
+		TableID:              tb.ID,
+		ShadowingPlayerIndex: -1,
+		Token:                token,
+		NoLock:               true,
+	})
+	expectTestWarningCode(t, conn, ErrWrongPassword)
+
+	spectateTestTableWithToken(tb, s, token)
+	expectTestWarning(t, conn, "That spectate token has expired.")
+	if tb.GetSpectatorIndexFromID(10) != -1 {
+		t.Fatal("the user was able to spectate with an expired token")
+	}
+	if _, ok := tb.SpectateTokens[token]; ok {
+		t.Error("the expired token was not removed")
+	}
+}
+
+func TestSpectateWithTokenInvalid(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+	usePasswordTestTable(t, tb)
+	generateTestSpectateToken(t, tb, false)
+
+	s, conn := newTestWebsocket(t, 10, "Dan", 0)
+	spectateTestTableWithToken(tb, s, "not-a-token")
+	expectTestWarning(t, conn, "That spectate token is not valid.")
+	if tb.GetSpectatorIndexFromID(10) != -1 {
+		t.Error("the user was able to spectate with an invalid token")
+	}
+}
+
+func TestSpectateTokensClearedOnGameEnd(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+	generateTestSpectateToken(t, tb, false)
+
+	performTestAction(t, tb, ActionTypeEndGame, 0, EndConditionTerminated)
+	if len(tb.SpectateTokens) != 0 {
+		t.Errorf("expected the spectate tokens to be removed, but there are %v",
+			len(tb.SpectateTokens))
+	}
+}
+
+func TestTableGenerateSpectateTokenValidation(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+
+	s, conn := newTestWebsocket(t, 2, "Bob", 0)
+	commandTableGenerateSpectateToken(s, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+	expectTestWarningCode(t, conn, ErrNotOwner)
+	if len(tb.SpectateTokens) != 0 {
+		t.Error("a token was generated for a user who is not the owner")
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"strconv"
+	"time"
+)
+
+// commandTableAbsentPlayers is sent when the user wants to know which players at a table are not
+// currently connected to it and how long they have been gone
+// (e.g. so that the owner can decide whether to wait for them or to replace them)
+//
+// Example data:
+// {
+//   tableID: 5,
+// }
+func commandTableAbsentPlayers(s *Session, d *CommandData) {
+	t, exists := getTableAndLock(s, d.TableID, !d.NoLock)
+	if !exists {
+		return
+	}
+	if !d.NoLock {
+		defer t.Mutex.Unlock()
+	}
+
+	// Validate that they are either a player or a spectator
+	playerIndex := t.GetPlayerIndexFromID(s.UserID())
+	spectatorIndex := t.GetSpectatorIndexFromID(s.UserID())
+	if playerIndex == -1 && spectatorIndex == -1 {
+		s.Warning("You are not a player or a spectator at table " +
+			strconv.FormatUint(t.ID, 10) + ", so you cannot get the absent players for it.")
+		return
+	}
+
+	tableAbsentPlayers(s, t)
+}
+
+func tableAbsentPlayers(s *Session, t *Table) {
+	type AbsentPlayer struct {
+		Index int    `json:"index"`
+		Name  string `json:"name"`
+		// In seconds
+		AbsentFor int `json:"absentFor"`
+	}
+	absentPlayers := make([]*AbsentPlayer, 0)
+	for i, p := range t.Players {
+		if p.Present {
+			continue
+		}
+
+		absentFor := 0
+		if !p.DatetimeAbsent.IsZero() {
+			absentFor = int(time.Since(p.DatetimeAbsent).Seconds())
+		}
+		absentPlayers = append(absentPlayers, &AbsentPlayer{
+			Index:     i,
+			Name:      p.Name,
+			AbsentFor: absentFor,
+		})
+	}
+
+	type TableAbsentPlayersMessage struct {
+		TableID uint64          `json:"tableID"`
+		Players []*AbsentPlayer `json:"players"`
+	}
+	s.Emit("tableAbsentPlayers", &TableAbsentPlayersMessage{
+		TableID: t.ID,
+		Players: absentPlayers,
+	})
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+type testAbsentPlayer struct {
+	Index     int    `json:"index"`
+	Name      string `json:"name"`
+	AbsentFor int    `json:"absentFor"`
+}
+
+func getTestAbsentPlayers(t *testing.T, tb *Table, id int, name string) []*testAbsentPlayer {
+	var msg struct {
+		Players []*testAbsentPlayer `json:"players"`
+	}
+	getTestTableResponse(t, tb, id, name, commandTableAbsentPlayers, "tableAbsentPlayers", &msg)
+
+	return msg.Players
+}
+
+func TestCommandTableAbsentPlayers(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+
+	if v := getTestAbsentPlayers(t, tb, 1, "Alice"); len(v) != 0 {
+		t.Fatalf("expected no absent players, but got %v", len(v))
+	}
+
+	commandTableUnattend(tb.Players[1].Session, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+	absentPlayers := getTestAbsentPlayers(t, tb, 1, "Alice")
+	if len(absentPlayers) != 1 {
+		t.Fatalf("expected 1 absent player, but got %v", len(absentPlayers))
+	}
+	if p := absentPlayers[0]; p.Index != 1 || p.Name != "Bob" || p.AbsentFor != 0 {
+		t.Errorf("expected Bob to have just become absent, but got %+v", p)
+	}
+
+	// The amount of time is reported in seconds
+	tb.Players[1].DatetimeAbsent = time.Now().Add(-90 * time.Second)
+	if v := getTestAbsentPlayers(t, tb, 1, "Alice"); v[0].AbsentFor != 90 {
+		t.Errorf("expected Bob to be absent for 90 seconds, but got %v", v[0].AbsentFor)
+	}
+}
+
+func TestCommandTableAbsentPlayersSpectator(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+	tb.Players[0].Present = false
+	newTestWebsocketSpectator(t, tb, 10, "Spectator")
+
+	absentPlayers := getTestAbsentPlayers(t, tb, 10, "Spectator")
+	if len(absentPlayers) != 1 || absentPlayers[0].Name != "Alice" {
+		t.Errorf("expected Alice to be the only absent player, but got %v", absentPlayers)
+	}
+}
+
+func TestCommandTableAbsentPlayersValidation(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+
+	s, conn := newTestWebsocket(t, 10, "Dan", 0)
+	commandTableAbsentPlayers(s, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+	expectTestWarning(t, conn, "You are not a player or a spectator")
+}
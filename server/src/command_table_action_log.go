@@ -0,0 +1,86 @@
+package main
+
+import (
+	"strconv"
+)
+
+// commandTableActionLog is sent when the user wants a textual log of the moves in a game
+// (e.g. for screen reader users)
+// If "fromTurn" is specified, only the moves from that turn onwards are sent
+//
+// Example data:
+// {
+//   tableID: 5,
+//   fromTurn: 10,
+// }
+func commandTableActionLog(s *Session, d *CommandData) {
+	t, exists := getTableAndLock(s, d.TableID, !d.NoLock)
+	if !exists {
+		return
+	}
+	if !d.NoLock {
+		defer t.Mutex.Unlock()
+	}
+
+	// Validate that the game has started
+	if !t.Running {
+		s.WarningWithCode(ErrNotStarted, NotStartedFail)
+		return
+	}
+
+	// Validate that they are either a player or a spectator
+	playerIndex := t.GetPlayerIndexFromID(s.UserID())
+	spectatorIndex := t.GetSpectatorIndexFromID(s.UserID())
+	if playerIndex == -1 && spectatorIndex == -1 {
+		s.Warning("You are not a player or a spectator at table " +
+			strconv.FormatUint(t.ID, 10) + ", so you cannot get the action log for it.")
+		return
+	}
+
+	// Validate the turn
+	if d.FromTurn < 0 {
+		s.Warning("The turn must not be negative.")
+		return
+	}
+
+	tableActionLog(s, d, t)
+}
+
+func tableActionLog(s *Session, d *CommandData, t *Table) {
+	// Local variables
+	g := t.Game
+
+	type ActionLogEntry struct {
+		Turn int    `json:"turn"`
+		Text string `json:"text"`
+	}
+	log := make([]*ActionLogEntry, 0)
+
+	// The actions for a turn end with a "turn" action that has the number of the next turn
+	turn := 0
+	for _, action := range g.Actions {
+		if turnAction, ok := action.(ActionTurn); ok {
+			turn = turnAction.Num
+			continue
+		}
+		if turn < d.FromTurn {
+			continue
+		}
+
+		if text := g.GetActionText(action); text != "" {
+			log = append(log, &ActionLogEntry{
+				Turn: turn,
+				Text: text,
+			})
+		}
+	}
+
+	type TableActionLogMessage struct {
+		TableID uint64            `json:"tableID"`
+		Log     []*ActionLogEntry `json:"log"`
+	}
+	s.Emit("tableActionLog", &TableActionLogMessage{
+		TableID: t.ID,
+		Log:     log,
+	})
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type testActionLogEntry struct {
+	Turn int    `json:"turn"`
+	Text string `json:"text"`
+}
+
+func getTestActionLog(t *testing.T, tb *Table, fromTurn int) []*testActionLogEntry {
+	s, conn := newTestWebsocket(t, 1, "Alice", 0)
+	tb.Players[0].Session = s
+	commandTableActionLog(s, &CommandData{
+		TableID:  tb.ID,
+		FromTurn: fromTurn,
+		NoLock:   true,
+	})
+
+	var msg struct {
+		Log []*testActionLogEntry `json:"log"`
+	}
+	data := readTestCommand(t, conn, "tableActionLog")
+	if err := json.Unmarshal([]byte(data), &msg); err != nil {
+		t.Fatal("failed to unmarshal the \"tableActionLog\" message:", err)
+	}
+
+	return msg.Log
+}
+
+// newTestActionLogGame creates a game where Alice clues, Bob discards, and Alice clues again
+func newTestActionLogGame(t *testing.T) *Table {
+	tb := newTestGame(t, 2)
+	clueTestPlayer(t, tb)
+	discardTestCard(t, tb)
+	clueTestPlayer(t, tb)
+
+	return tb
+}
+
+func TestTableActionLog(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestActionLogGame(t)
+
+	log := getTestActionLog(t, tb, 0)
+	if len(log) != 3 {
+		t.Fatalf("expected 3 entries, but got %v", len(log))
+	}
+	prefixes := []string{"Alice clued", "Bob discarded", "Alice clued"}
+	for i, entry := range log {
+		if entry.Turn != i {
+			t.Errorf("expected entry %v to be on turn %v, but it is on turn %v", i, i, entry.Turn)
+		}
+		if !strings.HasPrefix(entry.Text, prefixes[i]) {
+			t.Errorf("expected entry %v to start with \"%v\", but got \"%v\"",
+				i, prefixes[i], entry.Text)
+		}
+	}
+}
+
+func TestTableActionLogFromTurn(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestActionLogGame(t)
+
+	log := getTestActionLog(t, tb, 1)
+	if len(log) != 2 {
+		t.Fatalf("expected 2 entries, but got %v", len(log))
+	}
+	if log[0].Turn != 1 || !strings.HasPrefix(log[0].Text, "Bob discarded") {
+		t.Errorf("expected the log to start with the discard on turn 1, but got: %+v", log[0])
+	}
+
+	// A turn that has not happened yet has no entries
+	if log := getTestActionLog(t, tb, 10); len(log) != 0 {
+		t.Errorf("expected no entries, but got %v", len(log))
+	}
+}
+
+func TestTableActionLogValidation(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestActionLogGame(t)
+
+	s, conn := newTestWebsocket(t, 1, "Alice", 0)
+	commandTableActionLog(s, &CommandData{
+		TableID:  tb.ID,
+		FromTurn: -1,
+		NoLock:   true,
+	})
+	expectTestWarning(t, conn, "The turn must not be negative.")
+
+	s2, conn2 := newTestWebsocket(t, 10, "Dan", 0)
+	commandTableActionLog(s2, &CommandData{
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+	expectTestWarning(t, conn2, "You are not a player or a spectator")
+}
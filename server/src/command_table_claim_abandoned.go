@@ -0,0 +1,98 @@
+package main
+
+import (
+	"strconv"
+	"time"
+)
+
+const (
+	// The amount of time that the owner of an unstarted table must be inactive for before another
+	// player can take the table from them
+	AbandonedTableThreshold = time.Minute * 5
+)
+
+// commandTableClaimAbandoned is sent when a player at an unstarted table wants to become the owner
+// because the current owner has gone away (so that the rest of the players are not stuck waiting
+// for them to start the game)
+// It only works if the owner has been inactive for long enough (see "commandInactive")
+//
+// Example data:
+// {
+//   tableID: 123,
+// }
+func commandTableClaimAbandoned(s *Session, d *CommandData) {
+	t, exists := getTableAndLock(s, d.TableID, !d.NoLock)
+	if !exists {
+		return
+	}
+	if !d.NoLock {
+		defer t.Mutex.Unlock()
+	}
+
+	// Validate that the game has not started
+	if t.Running {
+		s.WarningWithCode(ErrStarted, StartedFail)
+		return
+	}
+
+	// Validate that they are at the table
+	playerIndex := t.GetPlayerIndexFromID(s.UserID())
+	if playerIndex == -1 {
+		s.Warning("You are not at table " + strconv.FormatUint(t.ID, 10) + ", " +
+			"so you cannot claim it.")
+		return
+	}
+
+	// Validate that they are not already the owner
+	if s.UserID() == t.Owner {
+		s.Warning("You are already the owner of this table.")
+		return
+	}
+
+	// Validate that the owner has been gone for long enough
+	ownerIndex := t.GetPlayerIndexFromID(t.Owner)
+	if ownerIndex != -1 && !isPlayerAbandoningTable(t.Players[ownerIndex]) {
+		s.Warning("The owner of this table has not been inactive for " +
+			strconv.Itoa(int(AbandonedTableThreshold.Minutes())) + " minutes, " +
+			"so you cannot claim it.")
+		return
+	}
+
+	tableClaimAbandoned(s, t, playerIndex, ownerIndex)
+}
+
+// isPlayerAbandoningTable returns true if the player has been inactive for longer than the
+// threshold
+func isPlayerAbandoningTable(p *Player) bool {
+	// A player's session should never be nil
+	// If it is, then they are certainly not here
+	if p.Session == nil {
+		return true
+	}
+
+	return p.Session.Inactive() &&
+		time.Since(p.Session.DatetimeInactive()) >= AbandonedTableThreshold
+}
+
+func tableClaimAbandoned(s *Session, t *Table, playerIndex int, ownerIndex int) {
+	logger.Info(t.GetName() + "User \"" + s.Username() + "\" claimed the abandoned table.")
+
+	t.Owner = s.UserID()
+
+	ownerName := "the owner"
+	if ownerIndex != -1 {
+		ownerName = t.Players[ownerIndex].Name
+
+		// On the pregame screen, the leader should always be the leftmost player,
+		// so we need to swap elements in the players slice
+		// (this is the same as in the "tableSetLeader()" function)
+		t.Players[ownerIndex], t.Players[playerIndex] = t.Players[playerIndex], t.Players[ownerIndex]
+	}
+
+	// Re-send the "game" message that draws the pregame screen
+	// and enables/disables the "Start Game" button
+	t.NotifyPlayerChange()
+
+	chatServerSend(s.Username()+" has claimed table ownership since "+ownerName+" was inactive "+
+		"for too long.", t.GetRoomName())
+}
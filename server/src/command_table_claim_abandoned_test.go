@@ -0,0 +1,139 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// setTestInactive marks the player as having been inactive for the given amount of time
+func setTestInactive(p *Player, inactiveFor time.Duration) {
+	commandInactive(p.Session, &CommandData{ // Manual invocation
+		Inactive: true,
+	})
+	p.Session.Set("datetimeInactive", time.Now().Add(-inactiveFor))
+}
+
+func claimTestTable(tb *Table, s *Session) {
+	commandTableClaimAbandoned(s, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+}
+
+// expectTestClaimed fails the test if Bob is not the owner and the leader of the table
+func expectTestClaimed(t *testing.T, tb *Table, ownerName string) {
+	if tb.Owner != 2 {
+		t.Fatalf("expected Bob to be the owner, but the owner is user %v", tb.Owner)
+	}
+	if tb.Players[0].ID != 2 {
+		t.Errorf("expected Bob to be the leftmost player, but got %v", tb.Players[0].Name)
+	}
+
+	expected := "Bob has claimed table ownership since " + ownerName + " was inactive"
+	if chatMsg := tb.Chat[len(tb.Chat)-1].Msg; !strings.HasPrefix(chatMsg, expected) {
+		t.Errorf("the chat message was wrong: %v", chatMsg)
+	}
+}
+
+func TestTableClaimAbandonedInactiveOwner(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTable(t, 3)
+	setTestInactive(tb.Players[0], AbandonedTableThreshold+time.Second)
+
+	claimTestTable(tb, tb.Players[1].Session)
+	expectTestClaimed(t, tb, "Alice")
+	if tb.Players[1].ID != 1 {
+		t.Errorf("expected Alice to be swapped with Bob, but got %v", tb.Players[1].Name)
+	}
+}
+
+func TestTableClaimAbandonedDisconnectedOwner(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTable(t, 3)
+	tb.Players[0].Session = nil
+	tb.Players[0].Present = false
+
+	claimTestTable(tb, tb.Players[1].Session)
+	expectTestClaimed(t, tb, "Alice")
+}
+
+func TestTableClaimAbandonedOwnerNotAtTable(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTable(t, 3)
+	tb.Players = tb.Players[1:]
+
+	claimTestTable(tb, tb.Players[0].Session)
+	expectTestClaimed(t, tb, "the owner")
+}
+
+func TestTableClaimAbandonedOwnerPresent(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTable(t, 3)
+	s, conn := newTestWebsocket(t, 2, "Bob", 0)
+	tb.Players[1].Session = s
+
+	// The owner is active
+	claimTestTable(tb, s)
+	expectTestWarning(t, conn, "The owner of this table has not been inactive for 5 minutes")
+
+	// The owner has not been inactive for long enough
+	setTestInactive(tb.Players[0], AbandonedTableThreshold-time.Minute)
+	claimTestTable(tb, s)
+	expectTestWarning(t, conn, "The owner of this table has not been inactive for 5 minutes")
+
+	// The owner came back
+	setTestInactive(tb.Players[0], AbandonedTableThreshold+time.Second)
+	commandInactive(tb.Players[0].Session, &CommandData{ // Manual invocation
+		Inactive: false,
+	})
+	claimTestTable(tb, s)
+	expectTestWarning(t, conn, "The owner of this table has not been inactive for 5 minutes")
+
+	if tb.Owner != 1 || tb.Players[0].ID != 1 {
+		t.Errorf("the table was claimed while the owner was present (the owner is user %v)",
+			tb.Owner)
+	}
+}
+
+func TestCommandInactiveKeepsDatetime(t *testing.T) {
+	s := newTestSession(1, "Alice")
+	commandInactive(s, &CommandData{ // Manual invocation
+		Inactive: true,
+	})
+	datetimeInactive := s.DatetimeInactive()
+	if time.Since(datetimeInactive) > time.Second {
+		t.Fatalf("the time that they became inactive was not recorded: %v", datetimeInactive)
+	}
+
+	// Being reported as inactive again does not reset the time
+	time.Sleep(10 * time.Millisecond)
+	commandInactive(s, &CommandData{ // Manual invocation
+		Inactive: true,
+	})
+	if v := s.DatetimeInactive(); !v.Equal(datetimeInactive) {
+		t.Errorf("expected the inactive time to stay at %v, but got %v", datetimeInactive, v)
+	}
+}
+
+func TestTableClaimAbandonedValidation(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTable(t, 2)
+	tb.Players[0].Session = nil
+
+	s, conn := newTestWebsocket(t, 1, "Alice", 0)
+	claimTestTable(tb, s)
+	expectTestWarning(t, conn, "You are already the owner of this table.")
+
+	s2, conn2 := newTestWebsocket(t, 10, "Dan", 0)
+	claimTestTable(tb, s2)
+	expectTestWarning(t, conn2, "so you cannot claim it")
+
+	startTestGame(t, tb)
+	s3, conn3 := newTestWebsocket(t, 2, "Bob", 0)
+	claimTestTable(tb, s3)
+	expectTestWarningCode(t, conn3, ErrStarted)
+	if tb.Owner != 1 {
+		t.Errorf("the running table was claimed by user %v", tb.Owner)
+	}
+}
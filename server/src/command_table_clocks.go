@@ -0,0 +1,84 @@
+package main
+
+import (
+	"strconv"
+	"time"
+)
+
+// commandTableClocks is sent when the user wants the current clocks for a timed game
+// (e.g. when they reconnect in the middle of a turn, since the clocks are normally only sent when
+// the turn changes)
+//
+// Example data:
+// {
+//   tableID: 5,
+// }
+func commandTableClocks(s *Session, d *CommandData) {
+	t, exists := getTableAndLock(s, d.TableID, !d.NoLock)
+	if !exists {
+		return
+	}
+	if !d.NoLock {
+		defer t.Mutex.Unlock()
+	}
+
+	// Validate that the game has started
+	if !t.Running {
+		s.WarningWithCode(ErrNotStarted, NotStartedFail)
+		return
+	}
+
+	// Validate that this is not a replay
+	if t.Replay {
+		s.Warning("You cannot get the clocks for a replay.")
+		return
+	}
+
+	// Validate that it is a timed game
+	if !t.Options.Timed {
+		s.Warning("This is not a timed game, so there are no clocks.")
+		return
+	}
+
+	// Validate that they are either a player or a spectator
+	playerIndex := t.GetPlayerIndexFromID(s.UserID())
+	spectatorIndex := t.GetSpectatorIndexFromID(s.UserID())
+	if playerIndex == -1 && spectatorIndex == -1 {
+		s.Warning("You are not a player or a spectator at table " +
+			strconv.FormatUint(t.ID, 10) + ", so you cannot get the clocks for it.")
+		return
+	}
+
+	tableClocks(s, t)
+}
+
+func tableClocks(s *Session, t *Table) {
+	// Local variables
+	g := t.Game
+
+	type PlayerClock struct {
+		Name string `json:"name"`
+		// JavaScript expects time in milliseconds
+		TimeLeft int64 `json:"timeLeft"`
+	}
+	clocks := make([]*PlayerClock, 0)
+	for i, p := range g.Players {
+		clocks = append(clocks, &PlayerClock{
+			Name:     p.Name,
+			TimeLeft: int64(g.GetTimeLeft(i) / time.Millisecond),
+		})
+	}
+
+	type TableClocksMessage struct {
+		TableID           uint64         `json:"tableID"`
+		Clocks            []*PlayerClock `json:"clocks"`
+		ActivePlayerIndex int            `json:"activePlayerIndex"`
+		Paused            bool           `json:"paused"`
+	}
+	s.Emit("tableClocks", &TableClocksMessage{
+		TableID:           t.ID,
+		Clocks:            clocks,
+		ActivePlayerIndex: g.ActivePlayerIndex,
+		Paused:            g.Paused,
+	})
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+type testTableClocks struct {
+	Clocks []struct {
+		Name     string `json:"name"`
+		TimeLeft int64  `json:"timeLeft"`
+	} `json:"clocks"`
+	ActivePlayerIndex int  `json:"activePlayerIndex"`
+	Paused            bool `json:"paused"`
+}
+
+func getTestTableClocks(t *testing.T, tb *Table, s *Session, conn *websocket.Conn) *testTableClocks {
+	commandTableClocks(s, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+
+	var clocks testTableClocks
+	if err := json.Unmarshal([]byte(readTestCommand(t, conn, "tableClocks")), &clocks); err != nil {
+		t.Fatal("failed to unmarshal the clocks:", err)
+	}
+	if len(clocks.Clocks) != len(tb.Players) {
+		t.Fatalf("expected %v clocks, but got %v", len(tb.Players), len(clocks.Clocks))
+	}
+	return &clocks
+}
+
+func TestCommandTableClocksRunning(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTimedGame(t, 2)
+	conns := connectTestPlayers(t, tb)
+	s := tb.Players[1].Session
+
+	before := getTestTableClocks(t, tb, s, conns[1])
+	time.Sleep(50 * time.Millisecond)
+	after := getTestTableClocks(t, tb, s, conns[1])
+
+	// Only the clock of the active player is running
+	if after.ActivePlayerIndex != 0 || after.Paused {
+		t.Fatalf("expected the first player to be active in an unpaused game, but got %+v", after)
+	}
+	if elapsed := before.Clocks[0].TimeLeft - after.Clocks[0].TimeLeft; elapsed < 50 {
+		t.Errorf("expected the clock of the active player to decrease by at least 50ms, "+
+			"but it decreased by %vms", elapsed)
+	}
+	if before.Clocks[1].TimeLeft != after.Clocks[1].TimeLeft {
+		t.Error("the clock of the other player changed")
+	}
+	if v := after.Clocks[1].TimeLeft; v != int64(tb.Options.TimeBase)*1000 {
+		t.Errorf("expected the other player to have %vms, but they have %vms",
+			tb.Options.TimeBase*1000, v)
+	}
+}
+
+func TestCommandTableClocksPaused(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTimedGame(t, 2)
+	conns := connectTestPlayers(t, tb)
+	s := tb.Players[0].Session
+
+	commandPause(s, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		Setting: "pause",
+		NoLock:  true,
+	})
+	if !tb.Game.Paused {
+		t.Fatal("the game was not paused")
+	}
+
+	// The clocks are frozen while the game is paused
+	before := getTestTableClocks(t, tb, s, conns[0])
+	time.Sleep(50 * time.Millisecond)
+	after := getTestTableClocks(t, tb, s, conns[0])
+	if !after.Paused {
+		t.Error("the clocks do not show that the game is paused")
+	}
+	for i := range before.Clocks {
+		if before.Clocks[i].TimeLeft != after.Clocks[i].TimeLeft {
+			t.Errorf("the clock of player %v changed while the game was paused", i)
+		}
+	}
+}
+
+func TestCommandTableClocksNotTimed(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+	conns := connectTestPlayers(t, tb)
+
+	commandTableClocks(tb.Players[0].Session, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+	expectTestWarning(t, conns[0], "This is not a timed game")
+
+	// Only players and spectators can get the clocks
+	tb2 := newTestTimedGame(t, 2)
+	s, conn := newTestWebsocket(t, 10, "Zed", 0)
+	commandTableClocks(s, &CommandData{ // Manual invocation
+		TableID: tb2.ID,
+		NoLock:  true,
+	})
+	expectTestWarning(t, conn, "You are not a player or a spectator")
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"strconv"
+)
+
+// commandTableCloneSettings is sent when the user wants to create a new table with the same
+// settings as an existing table (which can be unstarted, ongoing, or a replay)
+// Only the options are copied; the players and the deck are not
+//
+// Example data:
+// {
+//   sourceTableID: 5,
+// }
+func commandTableCloneSettings(s *Session, d *CommandData) {
+	t, exists := getTableAndLock(s, d.SourceTableID, true)
+	if !exists {
+		return
+	}
+
+	// Validate that they can see the table
+	// (solo replays are not visible in the lobby)
+	if !t.Visible &&
+		t.GetPlayerIndexFromID(s.UserID()) == -1 &&
+		t.GetSpectatorIndexFromID(s.UserID()) == -1 {
+
+		t.Mutex.Unlock()
+		s.Warning("Table " + strconv.FormatUint(t.ID, 10) + " does not exist.")
+		return
+	}
+
+	options := cloneTableOptions(t)
+
+	// We must release the lock on the source table before creating the new table
+	// in order to avoid holding two table locks at the same time
+	t.Mutex.Unlock()
+
+	// The extra options are not copied because they only relate to the deck and the actions of the
+	// source game (e.g. a custom seed or a custom deck)
+	// The new table is given a random name so that special prefixes (e.g. "!seed") are not copied
+	// (this also validates that they are not already joined to another table)
+	commandTableCreate(s, &CommandData{ // Manual invocation
+		Options: options,
+	})
+}
+
+// cloneTableOptions makes a copy of the options of a table so that the source table is not affected
+// The number of players and the starting player are determined when the game starts
+func cloneTableOptions(t *Table) *Options {
+	options := *t.Options
+	options.NumPlayers = 0
+	options.StartingPlayer = 0
+	return &options
+}
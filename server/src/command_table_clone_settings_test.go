@@ -0,0 +1,108 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestCloneTableOptions(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTimedGame(t, 3)
+	tb.Options.VariantName = "Rainbow (6 Suits)"
+	tb.Options.DeckPlays = true
+	tb.Options.NoColorClues = true
+
+	options := cloneTableOptions(tb)
+
+	// Everything apart from the number of players and the starting player is copied
+	expected := *tb.Options
+	expected.NumPlayers = 0
+	expected.StartingPlayer = 0
+	if *options != expected {
+		t.Errorf("expected the cloned options to be %+v, but got %+v", expected, *options)
+	}
+
+	// Changing the cloned options does not affect the source table
+	options.VariantName = "No Variant"
+	if tb.Options.VariantName != "Rainbow (6 Suits)" {
+		t.Error("changing the cloned options changed the options of the source table")
+	}
+}
+
+func TestTableCloneSettings(t *testing.T) {
+	if db == nil {
+		t.Skip("creating a table requires a database")
+	}
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+	tb.Options.VariantName = "Rainbow (6 Suits)"
+	tb.Options.OneExtraCard = true
+	tb.Replay = true
+
+	s, _ := newTestWebsocket(t, 10, "Dan", 0)
+	commandTableCloneSettings(s, &CommandData{
+		SourceTableID: tb.ID,
+	})
+
+	clone := s.GetJoinedTable()
+	if clone == nil || clone == tb {
+		t.Fatal("a new table was not created")
+	}
+	if *clone.Options != *cloneTableOptions(tb) {
+		t.Errorf("expected the options of the new table to be %+v, but got %+v",
+			*cloneTableOptions(tb), *clone.Options)
+	}
+	if clone.Running {
+		t.Error("the new table was started")
+	}
+	if clone.Owner != s.UserID() {
+		t.Errorf("expected the new table to be owned by %v, but it is owned by %v",
+			s.UserID(), clone.Owner)
+	}
+	if len(clone.Players) != 1 || clone.Players[0].ID != s.UserID() {
+		t.Errorf("expected only the requester to be seated at the new table, but got %v players",
+			len(clone.Players))
+	}
+}
+
+func TestTableCloneSettingsSourceDoesNotExist(t *testing.T) {
+	resetTestTables(t)
+
+	s, conn := newTestWebsocket(t, 10, "Dan", 0)
+	commandTableCloneSettings(s, &CommandData{
+		SourceTableID: 1000,
+	})
+
+	expectTestWarning(t, conn, "does not exist")
+}
+
+func TestTableCloneSettingsHiddenTable(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTable(t, 2)
+	tb.Visible = false
+
+	s, conn := newTestWebsocket(t, 10, "Dan", 0)
+	commandTableCloneSettings(s, &CommandData{
+		SourceTableID: tb.ID,
+	})
+
+	expectTestWarning(t, conn, "does not exist")
+	if len(tables) != 1 {
+		t.Errorf("expected 1 table, but got %v", len(tables))
+	}
+}
+
+func TestTableCloneSettingsAlreadyAtTable(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+
+	// Alice is already playing in the source game
+	s, conn := newTestWebsocket(t, 1, "Alice", 0)
+	commandTableCloneSettings(s, &CommandData{
+		SourceTableID: tb.ID,
+	})
+
+	expectTestWarning(t, conn, "You cannot join more than one table at a time.")
+	if len(tables) != 1 {
+		t.Errorf("expected 1 table, but got %v", len(tables))
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"strconv"
+)
+
+// commandTableConcede is sent when a player votes to end the game early
+// Once all of the players that are present have voted, the game ends with the current score
+// (as opposed to terminating, which ends the game with a score of 0)
+// The votes are cleared if anyone performs a game action
+//
+// Example data:
+// {
+//   tableID: 5,
+// }
+func commandTableConcede(s *Session, d *CommandData) {
+	t, exists := getTableAndLock(s, d.TableID, !d.NoLock)
+	if !exists {
+		return
+	}
+	if !d.NoLock {
+		defer t.Mutex.Unlock()
+	}
+
+	// Validate that the game has started
+	if !t.Running {
+		s.WarningWithCode(ErrNotStarted, NotStartedFail)
+		return
+	}
+
+	// Validate that it is not a replay
+	if t.Replay {
+		s.Warning("You can not concede a replay.")
+		return
+	}
+
+	// Validate that they are in the game
+	playerIndex := t.GetPlayerIndexFromID(s.UserID())
+	if playerIndex == -1 {
+		s.Warning("You are not playing at table " + strconv.FormatUint(t.ID, 10) + ", " +
+			"so you cannot vote to concede.")
+		return
+	}
+
+	// Validate that they have not already voted
+	if t.Game.Players[playerIndex].VotedConcede {
+		s.Warning("You have already voted to concede.")
+		return
+	}
+
+	tableConcede(s, t, playerIndex)
+}
+
+func tableConcede(s *Session, t *Table, playerIndex int) {
+	// Local variables
+	g := t.Game
+
+	g.Players[playerIndex].VotedConcede = true
+
+	// Only the players that are present need to vote
+	// (this is only checked when someone votes so that a player leaving the table does not cause
+	// the game to end)
+	numVotes := 0
+	numPresentPlayers := 0
+	unanimous := true
+	for i, p := range t.Players {
+		if p.Present {
+			numPresentPlayers++
+			if !g.Players[i].VotedConcede {
+				unanimous = false
+			}
+		}
+		if g.Players[i].VotedConcede {
+			numVotes++
+		}
+	}
+
+	msg := s.Username() + " voted to concede the game. " +
+		"(" + strconv.Itoa(numVotes) + "/" + strconv.Itoa(numPresentPlayers) + ")"
+	chatServerSend(msg, t.GetRoomName())
+
+	if !unanimous {
+		return
+	}
+
+	// The votes will be cleared when the game ends
+	commandAction(s, &CommandData{ // Manual invocation
+		TableID: t.ID,
+		Type:    ActionTypeEndGame,
+		Target:  playerIndex,
+		Value:   EndConditionConceded,
+		NoLock:  true,
+	})
+}
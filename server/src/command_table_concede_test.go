@@ -0,0 +1,101 @@
+package main
+
+import (
+	"testing"
+)
+
+func concedeTestTable(tb *Table, playerIndex int) {
+	commandTableConcede(tb.Players[playerIndex].Session, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+}
+
+func TestCommandTableConcedeUnanimous(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 3)
+	g := tb.Game
+	g.Score = 3
+
+	concedeTestTable(tb, 0)
+	concedeTestTable(tb, 1)
+	if g.EndCondition != EndConditionInProgress {
+		t.Fatal("the game ended before every player voted to concede")
+	}
+
+	concedeTestTable(tb, 2)
+	if g.EndCondition != EndConditionConceded {
+		t.Fatalf("expected the game to end with an end condition of %v, but got %v",
+			EndConditionConceded, g.EndCondition)
+	}
+
+	// Unlike a termination, the score that the team had is kept
+	if g.Score != 3 {
+		t.Errorf("expected the score of 3 to be kept, but it is %v", g.Score)
+	}
+	for i, gp := range g.Players {
+		if gp.VotedConcede {
+			t.Errorf("the concede vote of player %v was not cleared", i)
+		}
+	}
+}
+
+func TestCommandTableConcedePlayerLeaves(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 3)
+	g := tb.Game
+
+	// A player leaving does not finish the vote on its own
+	concedeTestTable(tb, 0)
+	concedeTestTable(tb, 1)
+	tb.Players[2].Present = false
+	if g.EndCondition != EndConditionInProgress {
+		t.Fatal("the game ended when a player left")
+	}
+
+	// Only the players that are present need to vote
+	g.Players[1].VotedConcede = false
+	concedeTestTable(tb, 1)
+	if g.EndCondition != EndConditionConceded {
+		t.Error("the game did not end after every present player voted to concede")
+	}
+}
+
+func TestCommandTableConcedeVotesClearedByAction(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+	conns := connectTestPlayers(t, tb)
+	g := tb.Game
+
+	concedeTestTable(tb, 1)
+	concedeTestTable(tb, 1)
+	expectTestWarning(t, conns[1], "You have already voted to concede.")
+
+	// A game action means that the team wants to keep playing
+	clueTestPlayer(t, tb)
+	if g.Players[1].VotedConcede {
+		t.Fatal("the concede vote was not cleared by a game action")
+	}
+
+	concedeTestTable(tb, 0)
+	if g.EndCondition != EndConditionInProgress {
+		t.Error("the game ended with a vote from before the game action")
+	}
+}
+
+func TestCommandTableConcedeEntersReplay(t *testing.T) {
+	if db == nil {
+		t.Skip("converting the game to a shared replay requires a database")
+	}
+
+	resetTestTables(t)
+	tb := newTestTable(t, 2)
+	tb.ExtraOptions.NoWriteToDatabase = false
+	startTestGame(t, tb)
+
+	concedeTestTable(tb, 0)
+	concedeTestTable(tb, 1)
+	if !tb.Replay {
+		t.Error("the conceded game was not converted to a shared replay")
+	}
+}
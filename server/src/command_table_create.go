@@ -39,6 +39,11 @@ type SpecialGameData struct {
 //     [other options omitted; see "Options.ts"]
 //   },
 //   password: 'super_secret',
+//   // Optional; the cards in the order that they are drawn (instead of a shuffled deck)
+//   deck: [
+//     { suitIndex: 0, rank: 1 },
+//     [other cards omitted]
+//   ],
 // }
 func commandTableCreate(s *Session, d *CommandData) {
 	// Validate that the server is not about to go offline
@@ -56,12 +61,17 @@ func commandTableCreate(s *Session, d *CommandData) {
 	// Validate that the player is not joined to another table
 	if !strings.HasPrefix(s.Username(), "Bot-") {
 		if t2 := s.GetJoinedTable(); t2 != nil {
-			s.Warning("You cannot join more than one table at a time. " +
+			s.WarningWithCode(ErrAlreadyAtTable, "You cannot join more than one table at a time. "+
 				"Terminate your other game before creating a new one.")
 			return
 		}
 	}
 
+	// Validate that the server is not at capacity
+	if !validateTableCapacity(s) {
+		return
+	}
+
 	// Truncate long table names
 	// (we do this first to prevent wasting CPU cycles on validating extremely long table names)
 	if len(d.Name) > MaxGameNameLength {
@@ -79,32 +89,8 @@ func commandTableCreate(s *Session, d *CommandData) {
 		d.Name = getName()
 	}
 
-	// Check for non-ASCII characters
-	if !containsAllPrintableASCII(d.Name) {
-		s.Warning("Game names can only contain ASCII characters.")
-		return
-	}
-
-	// Validate that the game name does not contain any special characters
-	// (this mitigates XSS attacks)
-	if !isValidTableName(d.Name) {
-		msg := "Game names can only contain English letters, numbers, spaces, " +
-			"<code>!</code>, " +
-			"<code>@</code>, " +
-			"<code>#</code>, " +
-			"<code>$</code>, " +
-			"<code>(</code>, " +
-			"<code>)</code>, " +
-			"<code>-</code>, " +
-			"<code>_</code>, " +
-			"<code>=</code>, " +
-			"<code>+</code>, " +
-			"<code>;</code>, " +
-			"<code>:</code>, " +
-			"<code>,</code>, " +
-			"<code>.</code>, " +
-			"and <code>?</code>."
-		s.Warning(msg)
+	// Validate that the game name does not contain any illegal characters
+	if !validateTableName(s, d.Name) {
 		return
 	}
 
@@ -223,6 +209,11 @@ func commandTableCreate(s *Session, d *CommandData) {
 		return
 	}
 
+	// Validate that the variant has not been disabled by an administrator
+	if !validateVariantEnabled(s, d.Options.VariantName) {
+		return
+	}
+
 	// Validate that the time controls are sane
 	if d.Options.Timed {
 		if d.Options.TimeBase <= 0 {
@@ -249,6 +240,11 @@ func commandTableCreate(s *Session, d *CommandData) {
 		d.Options.TimePerTurn = 0
 	}
 
+	// Validate what happens when a player runs out of time
+	if !validateTimeoutAction(s, d.Options) {
+		return
+	}
+
 	// Validate that a speedrun cannot be timed
 	if d.Options.Speedrun {
 		d.Options.Timed = false
@@ -256,6 +252,11 @@ func commandTableCreate(s *Session, d *CommandData) {
 		d.Options.TimePerTurn = 0
 	}
 
+	// Validate that there is at least one type of clue that can be given
+	if !validateClueTypes(s, d.Options, d.Options.VariantName) {
+		return
+	}
+
 	// Validate that they did not send both the "One Extra Card" and the "One Less Card" option at
 	// the same time (they effectively cancel each other out)
 	if d.Options.OneExtraCard && d.Options.OneLessCard {
@@ -270,6 +271,18 @@ func commandTableCreate(s *Session, d *CommandData) {
 		}
 	}
 
+	// Validate games with a custom deck
+	if len(d.Deck) > 0 {
+		if d.GameJSON != nil || data.SetSeedSuffix != "" || data.SetReplay {
+			s.Warning("You cannot create a table with a custom deck if the deck is already " +
+				"determined by a seed or by JSON data.")
+			return
+		}
+		if !validateCustomDeck(s, d.Options.VariantName, d.Deck) {
+			return
+		}
+	}
+
 	tableCreate(s, d, data)
 }
 
@@ -295,6 +308,7 @@ func tableCreate(s *Session, d *CommandData, data *SpecialGameData) {
 	t.ExtraOptions = &ExtraOptions{
 		DatabaseID:       data.DatabaseID,
 		CustomNumPlayers: data.CustomNumPlayers,
+		CustomDeck:       d.Deck,
 		SetSeedSuffix:    data.SetSeedSuffix,
 	}
 
@@ -344,6 +358,7 @@ func tableCreate(s *Session, d *CommandData, data *SpecialGameData) {
 
 	logger.Info(t.GetName() + "User \"" + s.Username() + "\" created a table.")
 	// (a "table" message will be sent in the "commandTableJoin" function below)
+	notifyLobbySubscribers(LobbyUpdateCreated, t)
 
 	// Log a chat message so that future players can see a timestamp of when the table was created
 	msg := s.Username() + " created the table."
@@ -366,3 +381,88 @@ func tableCreate(s *Session, d *CommandData, data *SpecialGameData) {
 		chatServerSend(msg, t.GetRoomName())
 	}
 }
+
+// validateClueTypes checks that the options do not prevent every type of clue from being given in
+// the variant
+func validateClueTypes(s *Session, options *Options, variantName string) bool {
+	// Local variables
+	variant := variants[variantName]
+
+	for _, clueType := range []int{ClueTypeColor, ClueTypeRank} {
+		if options.IsClueTypeAllowed(clueType) && variant.IsClueTypeAllowed(clueType) {
+			return true
+		}
+	}
+
+	s.Warning("At least one type of clue must be allowed in the variant of \"" + variantName +
+		"\".")
+	return false
+}
+
+// validateCustomDeck checks that a custom deck has exactly the same cards as a normal deck for the
+// variant (the only difference can be their order)
+func validateCustomDeck(s *Session, variantName string, deck []*CardIdentity) bool {
+	// Make a normal deck to compare against
+	g := &Game{
+		Options: &Options{
+			VariantName: variantName,
+		},
+		ExtraOptions: &ExtraOptions{},
+	}
+	g.InitDeck()
+
+	if len(deck) != len(g.CardIdentities) {
+		s.Warning("The deck must have " + strconv.Itoa(len(g.CardIdentities)) + " cards in it.")
+		return false
+	}
+
+	cardCounts := make(map[CardIdentity]int)
+	for _, cardIdentity := range g.CardIdentities {
+		cardCounts[*cardIdentity]++
+	}
+	for i, cardIdentity := range deck {
+		if cardIdentity == nil || cardCounts[*cardIdentity] == 0 {
+			s.Warning("The card at index " + strconv.Itoa(i) + " is not valid or there are " +
+				"too many copies of it for the variant of \"" + variantName + "\".")
+			return false
+		}
+		cardCounts[*cardIdentity]--
+	}
+
+	return true
+}
+
+// validateTableName sends a warning and returns false if the given table name contains characters
+// that are not allowed
+func validateTableName(s *Session, name string) bool {
+	// Check for non-ASCII characters
+	if !containsAllPrintableASCII(name) {
+		s.Warning("Game names can only contain ASCII characters.")
+		return false
+	}
+
+	// Validate that the game name does not contain any special characters
+	// (this mitigates XSS attacks)
+	if !isValidTableName(name) {
+		msg := "Game names can only contain English letters, numbers, spaces, " +
+			"<code>!</code>, " +
+			"<code>@</code>, " +
+			"<code>#</code>, " +
+			"<code>$</code>, " +
+			"<code>(</code>, " +
+			"<code>)</code>, " +
+			"<code>-</code>, " +
+			"<code>_</code>, " +
+			"<code>=</code>, " +
+			"<code>+</code>, " +
+			"<code>;</code>, " +
+			"<code>:</code>, " +
+			"<code>,</code>, " +
+			"<code>.</code>, " +
+			"and <code>?</code>."
+		s.Warning(msg)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// newTestCustomDeck returns every card of the variant in the reverse of the normal order
+func newTestCustomDeck(variantName string) []*CardIdentity {
+	g := &Game{
+		Options: &Options{
+			VariantName: variantName,
+		},
+		ExtraOptions: &ExtraOptions{},
+	}
+	g.InitDeck()
+
+	deck := make([]*CardIdentity, 0)
+	for i := len(g.CardIdentities) - 1; i >= 0; i-- {
+		deck = append(deck, g.CardIdentities[i])
+	}
+
+	return deck
+}
+
+// newTestCustomDeckGame starts a game that uses the given deck instead of a seed
+func newTestCustomDeckGame(t *testing.T, deck []*CardIdentity) *Table {
+	tb := newTestTable(t, 2)
+	tb.ExtraOptions.CustomSeed = ""
+	tb.ExtraOptions.CustomDeck = deck
+	startTestGame(t, tb)
+	return tb
+}
+
+func TestCustomDeckUsedInOrder(t *testing.T) {
+	resetTestTables(t)
+	deck := newTestCustomDeck("No Variant")
+	tb := newTestCustomDeckGame(t, deck)
+	g := tb.Game
+
+	if !reflect.DeepEqual(g.CardIdentities, deck) {
+		t.Fatal("the game does not use the custom deck")
+	}
+	for i, c := range g.Deck {
+		if c.SuitIndex != deck[i].SuitIndex || c.Rank != deck[i].Rank {
+			t.Fatalf("expected card %v to be %+v, but got suit %v and rank %v", i, deck[i],
+				c.SuitIndex, c.Rank)
+		}
+	}
+	if g.Seed != "JSON" {
+		t.Errorf("expected the seed to be \"JSON\", but got \"%v\"", g.Seed)
+	}
+}
+
+func TestCustomDeckSurvivesRestore(t *testing.T) {
+	resetTestTables(t)
+	useTestTableStore(t, false)
+	deck := newTestCustomDeck("No Variant")
+	tb := newTestCustomDeckGame(t, deck)
+	clueTestPlayer(t, tb)
+
+	serializeAndRestoreTestTables(t)
+	restored := getTestTable(t, tb.ID)
+	if !reflect.DeepEqual(restored.ExtraOptions.CustomDeck, deck) {
+		t.Error("the custom deck of the table was not restored")
+	}
+	if !reflect.DeepEqual(restored.Game.CardIdentities, deck) {
+		t.Error("the restored game does not use the custom deck")
+	}
+}
+
+func TestValidateCustomDeck(t *testing.T) {
+	s, conn := newTestWebsocket(t, 1, "Alice", 0)
+
+	deck := newTestCustomDeck("No Variant")
+	if !validateCustomDeck(s, "No Variant", deck) {
+		t.Fatal("a valid custom deck was rejected")
+	}
+
+	// The deck has to match the variant
+	if validateCustomDeck(s, "Rainbow (6 Suits)", deck) {
+		t.Error("a custom deck with too few cards for the variant was accepted")
+	}
+	expectTestWarning(t, conn, "The deck must have 60 cards in it.")
+
+	// Every card has to be a legal card with the right amount of copies
+	invalidDecks := map[string]func(deck []*CardIdentity){
+		"an extra copy of a 5": func(deck []*CardIdentity) {
+			deck[len(deck)-1] = &CardIdentity{SuitIndex: 0, Rank: 5}
+		},
+		"a card of a suit that does not exist": func(deck []*CardIdentity) {
+			deck[0] = &CardIdentity{SuitIndex: 5, Rank: 5}
+		},
+		"a missing card": func(deck []*CardIdentity) {
+			deck[10] = nil
+		},
+	}
+	for description, f := range invalidDecks {
+		invalidDeck := newTestCustomDeck("No Variant")
+		f(invalidDeck)
+		if validateCustomDeck(s, "No Variant", invalidDeck) {
+			t.Errorf("a custom deck with %v was accepted", description)
+			continue
+		}
+		expectTestWarning(t, conn, "is not valid or there are too many copies of it")
+	}
+}
+
+func TestTableCreateCustomDeckValidation(t *testing.T) {
+	resetTestTables(t)
+	s, conn := newTestWebsocket(t, 1, "Alice", 0)
+
+	deck := newTestCustomDeck("No Variant")
+	deck[0] = deck[1]
+	commandTableCreate(s, &CommandData{ // Manual invocation
+		Name: "Test Table",
+		Options: &Options{
+			VariantName: "No Variant",
+		},
+		Deck: deck,
+	})
+	expectTestWarning(t, conn, "is not valid or there are too many copies of it")
+
+	// A custom deck cannot be combined with a seed
+	commandTableCreate(s, &CommandData{ // Manual invocation
+		Name: "!seed 5",
+		Options: &Options{
+			VariantName: "No Variant",
+		},
+		Deck: newTestCustomDeck("No Variant"),
+	})
+	expectTestWarning(t, conn, "You cannot create a table with a custom deck")
+
+	if v := countNonReplayTables(); v != 0 {
+		t.Errorf("expected no tables to be created, but got %v", v)
+	}
+}
+
+func TestTableSetVariantCustomDeck(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTable(t, 2)
+	tb.ExtraOptions.CustomDeck = newTestCustomDeck("No Variant")
+
+	s, conn := newTestWebsocket(t, 1, "Alice", 0)
+	commandTableSetVariant(s, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		Options: &Options{
+			VariantName: "Rainbow (6 Suits)",
+		},
+		NoLock: true,
+	})
+	expectTestWarning(t, conn, "You cannot change the variant of a table with a custom deck.")
+	if tb.Options.VariantName != "No Variant" {
+		t.Errorf("the variant was changed to %v", tb.Options.VariantName)
+	}
+}
+
+func TestValidateClueTypes(t *testing.T) {
+	s, conn := newTestWebsocket(t, 1, "Alice", 0)
+
+	for _, options := range []*Options{
+		{},
+		{NoColorClues: true},
+		{NoRankClues: true},
+	} {
+		if !validateClueTypes(s, options, "No Variant") {
+			t.Errorf("the options of %+v were rejected", options)
+		}
+	}
+
+	if validateClueTypes(s, &Options{NoColorClues: true, NoRankClues: true}, "No Variant") {
+		t.Error("a table without any clues was accepted")
+	}
+	expectTestWarning(t, conn, "At least one type of clue must be allowed")
+}
+
+func TestTableCreateWithoutClues(t *testing.T) {
+	resetTestTables(t)
+	s, conn := newTestWebsocket(t, 1, "Alice", 0)
+
+	commandTableCreate(s, &CommandData{ // Manual invocation
+		Name: "Test Table",
+		Options: &Options{
+			VariantName:  "No Variant",
+			NoColorClues: true,
+			NoRankClues:  true,
+		},
+	})
+	expectTestWarning(t, conn, "At least one type of clue must be allowed")
+	if v := countNonReplayTables(); v != 0 {
+		t.Errorf("expected no tables to be created, but got %v", v)
+	}
+}
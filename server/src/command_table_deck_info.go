@@ -0,0 +1,123 @@
+package main
+
+import (
+	"strconv"
+)
+
+// commandTableDeckInfo is sent when the user wants to know the composition of the deck for the
+// current game (e.g. to show how many copies of each card are left)
+// Along with the total amount of each card, the server sends the amount of each card that the user
+// has seen (played, discarded, or in the hand of another player)
+// Cards that are hidden from the user (e.g. the cards in their own hand) are not counted
+//
+// Example data:
+// {
+//   tableID: 5,
+// }
+func commandTableDeckInfo(s *Session, d *CommandData) {
+	t, exists := getTableAndLock(s, d.TableID, !d.NoLock)
+	if !exists {
+		return
+	}
+	if !d.NoLock {
+		defer t.Mutex.Unlock()
+	}
+
+	// Validate that the game has started
+	if !t.Running {
+		s.WarningWithCode(ErrNotStarted, NotStartedFail)
+		return
+	}
+
+	// Validate that they are either a player or a spectator
+	playerIndex := t.GetPlayerIndexFromID(s.UserID())
+	spectatorIndex := t.GetSpectatorIndexFromID(s.UserID())
+	if playerIndex == -1 && spectatorIndex == -1 {
+		s.Warning("You are not a player or a spectator at table " +
+			strconv.FormatUint(t.ID, 10) + ", so you cannot get the deck for it.")
+		return
+	}
+
+	tableDeckInfo(s, t)
+}
+
+func tableDeckInfo(s *Session, t *Table) {
+	// Local variables
+	g := t.Game
+	variant := variants[g.Options.VariantName]
+
+	// Find out which cards the user can see
+	// In a replay, everything is visible
+	var p *GamePlayer
+	if !t.Replay {
+		p = getEquivalentPlayer(t, s.UserID())
+	}
+	seenCardOrders := make(map[int]struct{})
+	for _, c := range g.Deck {
+		if c.Played || c.Discarded {
+			// Played cards (and failed discards) are hidden from the players in some variants
+			// (this mirrors the "Scrub()" functions in "actions_scrub.go")
+			if p != nil && variant.IsThrowItInAHole() && (c.Played || c.Failed) {
+				continue
+			}
+			seenCardOrders[c.Order] = struct{}{}
+		}
+	}
+	for _, p2 := range g.Players {
+		if p != nil && p2.Index == p.Index {
+			// Players cannot see their own hand
+			continue
+		}
+		for _, c := range p2.Hand {
+			if p != nil && !characterSeesCard(g, p, p2, c.Order) {
+				continue
+			}
+			seenCardOrders[c.Order] = struct{}{}
+		}
+	}
+
+	type DeckInfoEntry struct {
+		SuitIndex int    `json:"suitIndex"`
+		Suit      string `json:"suit"`
+		Rank      int    `json:"rank"`
+		Total     int    `json:"total"`
+		Seen      int    `json:"seen"`
+	}
+	entries := make([]*DeckInfoEntry, 0)
+	for suitIndex, suit := range variant.Suits {
+		for _, rank := range variant.Ranks {
+			entry := &DeckInfoEntry{
+				SuitIndex: suitIndex,
+				Suit:      suit.Name,
+				Rank:      rank,
+			}
+
+			// We count the cards in the actual deck instead of deriving them from the variant,
+			// since the game could have been created with a custom deck
+			for _, c := range g.Deck {
+				if c.SuitIndex != suitIndex || c.Rank != rank {
+					continue
+				}
+				entry.Total++
+				if _, ok := seenCardOrders[c.Order]; ok {
+					entry.Seen++
+				}
+			}
+
+			if entry.Total > 0 {
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	type TableDeckInfoMessage struct {
+		TableID  uint64           `json:"tableID"`
+		DeckSize int              `json:"deckSize"`
+		Cards    []*DeckInfoEntry `json:"cards"`
+	}
+	s.Emit("tableDeckInfo", &TableDeckInfoMessage{
+		TableID:  t.ID,
+		DeckSize: len(g.Deck),
+		Cards:    entries,
+	})
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+type testDeckInfo struct {
+	DeckSize int `json:"deckSize"`
+	Cards    []struct {
+		SuitIndex int    `json:"suitIndex"`
+		Suit      string `json:"suit"`
+		Rank      int    `json:"rank"`
+		Total     int    `json:"total"`
+		Seen      int    `json:"seen"`
+	} `json:"cards"`
+}
+
+func getTestDeckInfo(t *testing.T, tb *Table, s *Session, conn *websocket.Conn) *testDeckInfo {
+	commandTableDeckInfo(s, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+
+	var deckInfo testDeckInfo
+	if err := json.Unmarshal([]byte(readTestCommand(t, conn, "tableDeckInfo")), &deckInfo); err != nil {
+		t.Fatal("failed to unmarshal the deck info:", err)
+	}
+	return &deckInfo
+}
+
+// getNumSeen returns the total amount of cards that the user has seen
+func (d *testDeckInfo) getNumSeen() int {
+	numSeen := 0
+	for _, entry := range d.Cards {
+		numSeen += entry.Seen
+	}
+	return numSeen
+}
+
+func TestCommandTableDeckInfoStandardVariant(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+	conns := connectTestPlayers(t, tb)
+
+	deckInfo := getTestDeckInfo(t, tb, tb.Players[0].Session, conns[0])
+	if deckInfo.DeckSize != 50 || len(deckInfo.Cards) != 25 {
+		t.Fatalf("expected 25 different cards in a deck of 50, but got %v in a deck of %v",
+			len(deckInfo.Cards), deckInfo.DeckSize)
+	}
+	expectedTotals := map[int]int{1: 3, 2: 2, 3: 2, 4: 2, 5: 1}
+	for _, entry := range deckInfo.Cards {
+		if entry.Total != expectedTotals[entry.Rank] {
+			t.Errorf("expected %v copies of %v %v, but got %v",
+				expectedTotals[entry.Rank], entry.Suit, entry.Rank, entry.Total)
+		}
+	}
+
+	// A player can only see the hand of their teammate
+	handSize := len(tb.Game.Players[1].Hand)
+	if v := deckInfo.getNumSeen(); v != handSize {
+		t.Errorf("expected the player to have seen %v cards, but they have seen %v", handSize, v)
+	}
+
+	// Discarded cards are seen by everyone
+	clueTestPlayer(t, tb)
+	discardTestCard(t, tb)
+	deckInfo = getTestDeckInfo(t, tb, tb.Players[0].Session, conns[0])
+	if v := deckInfo.getNumSeen(); v != handSize+1 {
+		t.Errorf("expected the player to have seen %v cards after a discard, "+
+			"but they have seen %v", handSize+1, v)
+	}
+
+	// A spectator can see every hand
+	sp, spectatorConn := newTestWebsocketSpectator(t, tb, 10, "Zed")
+	deckInfo = getTestDeckInfo(t, tb, sp.Session, spectatorConn)
+	if v := deckInfo.getNumSeen(); v != handSize*2+1 {
+		t.Errorf("expected the spectator to have seen %v cards, but they have seen %v",
+			handSize*2+1, v)
+	}
+}
+
+func TestCommandTableDeckInfoSixSuits(t *testing.T) {
+	resetTestTables(t)
+	for _, test := range []struct {
+		variantName string
+		deckSize    int
+	}{
+		{"6 Suits", 60},
+		// There is only one of each card in a dark suit
+		{"Black (6 Suits)", 55},
+	} {
+		tb := newTestTable(t, 2)
+		tb.Options.VariantName = test.variantName
+		startTestGame(t, tb)
+		conns := connectTestPlayers(t, tb)
+
+		deckInfo := getTestDeckInfo(t, tb, tb.Players[0].Session, conns[0])
+		if deckInfo.DeckSize != test.deckSize || len(deckInfo.Cards) != 30 {
+			t.Errorf("%v: expected 30 different cards in a deck of %v, "+
+				"but got %v in a deck of %v", test.variantName, test.deckSize,
+				len(deckInfo.Cards), deckInfo.DeckSize)
+		}
+
+		total := 0
+		for _, entry := range deckInfo.Cards {
+			total += entry.Total
+			if entry.SuitIndex == 5 && entry.Suit != variants[test.variantName].Suits[5].Name {
+				t.Errorf("%v: the sixth suit has the wrong name: %v", test.variantName, entry.Suit)
+			}
+		}
+		if total != test.deckSize {
+			t.Errorf("%v: expected the totals to add up to %v, but they add up to %v",
+				test.variantName, test.deckSize, total)
+		}
+	}
+}
+
+func TestCommandTableDeckInfoNotAtTable(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+	s, conn := newTestWebsocket(t, 10, "Zed", 0)
+
+	commandTableDeckInfo(s, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+	expectTestWarning(t, conn, "You are not a player or a spectator")
+}
@@ -0,0 +1,40 @@
+package main
+
+// commandTableDisableIdleCheck is sent when the owner of a table does not want it to be
+// automatically ended for being idle (e.g. for a tournament game where the players are discussing
+// something elsewhere)
+// The check can be turned back on with the "tableEnableIdleCheck" command
+//
+// Example data:
+// {
+//   tableID: 123,
+// }
+func commandTableDisableIdleCheck(s *Session, d *CommandData) {
+	t, exists := getTableAndLock(s, d.TableID, !d.NoLock)
+	if !exists {
+		return
+	}
+	if !d.NoLock {
+		defer t.Mutex.Unlock()
+	}
+
+	// Validate that they are the owner of the table
+	if s.UserID() != t.Owner {
+		s.WarningWithCode(ErrNotOwner, NotOwnerFail)
+		return
+	}
+
+	// Validate that the idle check is not already disabled
+	if t.IdleCheckDisabled {
+		s.Warning("The idle check is already disabled for this table.")
+		return
+	}
+
+	tableDisableIdleCheck(s, t)
+}
+
+func tableDisableIdleCheck(s *Session, t *Table) {
+	t.IdleCheckDisabled = true
+	chatServerSend(s.Username()+" has disabled the idle check, so this table will not be "+
+		"automatically ended for being idle.", t.GetRoomName())
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func setTestIdleCheck(t *testing.T, tb *Table, enabled bool) {
+	s := newTestSession(tb.Owner, "Alice")
+	d := &CommandData{
+		TableID: tb.ID,
+		NoLock:  true,
+	}
+	if enabled {
+		commandTableEnableIdleCheck(s, d) // Manual invocation
+	} else {
+		commandTableDisableIdleCheck(s, d) // Manual invocation
+	}
+
+	if tb.IdleCheckDisabled == enabled {
+		t.Fatalf("expected the idle check to be enabled to be %v", enabled)
+	}
+}
+
+func TestTableDisableIdleCheck(t *testing.T) {
+	resetTestTables(t)
+	useTestIdleTimeouts(t, 400*time.Millisecond, 200*time.Millisecond)
+	tb := newTestTable(t, 2)
+	setTestIdleCheck(t, tb, false)
+
+	chatMsg := tb.Chat[len(tb.Chat)-1].Msg
+	if !strings.Contains(chatMsg, "Alice has disabled the idle check") {
+		t.Errorf("the chat message was wrong: %v", chatMsg)
+	}
+
+	go tb.WatchIdle()
+	time.Sleep(600 * time.Millisecond)
+	if isTestTableDeleted(tb) {
+		t.Error("the table was ended after the idle check was disabled")
+	}
+	if numWarnings := countTestIdleWarnings(tb); numWarnings != 0 {
+		t.Errorf("expected no idle warnings, but got %v", numWarnings)
+	}
+}
+
+func TestTableEnableIdleCheck(t *testing.T) {
+	resetTestTables(t)
+	useTestIdleTimeouts(t, 400*time.Millisecond, 0)
+	tb := newTestTable(t, 2)
+	setTestIdleCheck(t, tb, false)
+
+	// The check that was running when it was disabled gives up on the table
+	go tb.WatchIdle()
+	time.Sleep(600 * time.Millisecond)
+	if isTestTableDeleted(tb) {
+		t.Fatal("the table was ended after the idle check was disabled")
+	}
+
+	// Turning it back on makes the next check end the table
+	// (the check that the command starts does nothing in development, so we start one manually)
+	tb.Mutex.Lock()
+	setTestIdleCheck(t, tb, true)
+	tb.Mutex.Unlock()
+	go tb.WatchIdle()
+	time.Sleep(200 * time.Millisecond)
+	if isTestTableDeleted(tb) {
+		t.Error("the table was ended before the idle timeout elapsed")
+	}
+	time.Sleep(400 * time.Millisecond)
+	if !isTestTableDeleted(tb) {
+		t.Error("the table was not ended after the idle check was enabled")
+	}
+}
+
+func TestTableDisableIdleCheckSurvivesRestore(t *testing.T) {
+	resetTestTables(t)
+	useTestTableStore(t, false)
+	tb := newTestGame(t, 2)
+	setTestIdleCheck(t, tb, false)
+
+	serializeAndRestoreTestTables(t)
+	if !getTestTable(t, tb.ID).IdleCheckDisabled {
+		t.Error("the idle check was enabled again after the table was restored")
+	}
+}
+
+func TestTableIdleCheckValidation(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTable(t, 2)
+
+	s, conn := newTestWebsocket(t, 2, "Bob", 0)
+	commandTableDisableIdleCheck(s, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+	expectTestWarningCode(t, conn, ErrNotOwner)
+	if tb.IdleCheckDisabled {
+		t.Fatal("a player who is not the owner disabled the idle check")
+	}
+
+	s2, conn2 := newTestWebsocket(t, 1, "Alice", 0)
+	commandTableEnableIdleCheck(s2, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+	expectTestWarning(t, conn2, "The idle check is already enabled for this table.")
+
+	setTestIdleCheck(t, tb, false)
+	commandTableDisableIdleCheck(s2, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+	expectTestWarning(t, conn2, "The idle check is already disabled for this table.")
+
+	commandTableEnableIdleCheck(s, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+	expectTestWarningCode(t, conn, ErrNotOwner)
+	if !tb.IdleCheckDisabled {
+		t.Error("a player who is not the owner enabled the idle check")
+	}
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"math"
+	"sort"
+	"strconv"
+)
+
+// commandTableEfficiency is sent when the user wants the efficiency statistics of a game
+// (the same ones that the client shows next to the deck)
+// The formulas are explained in:
+// https://github.com/Zamiell/hanabi-conventions/blob/master/misc/Efficiency.md
+//
+// Example data:
+// {
+//   tableID: 5,
+// }
+func commandTableEfficiency(s *Session, d *CommandData) {
+	t, exists := getTableAndLock(s, d.TableID, !d.NoLock)
+	if !exists {
+		return
+	}
+	if !d.NoLock {
+		defer t.Mutex.Unlock()
+	}
+
+	// Validate that the game has started
+	if !t.Running {
+		s.WarningWithCode(ErrNotStarted, NotStartedFail)
+		return
+	}
+
+	// Validate that they are either a player or a spectator
+	playerIndex := t.GetPlayerIndexFromID(s.UserID())
+	spectatorIndex := t.GetSpectatorIndexFromID(s.UserID())
+	if playerIndex == -1 && spectatorIndex == -1 {
+		s.Warning("You are not a player or a spectator at table " +
+			strconv.FormatUint(t.ID, 10) + ", so you cannot get the efficiency for it.")
+		return
+	}
+
+	tableEfficiency(s, t)
+}
+
+func tableEfficiency(s *Session, t *Table) {
+	// Local variables
+	g := t.Game
+	variant := variants[g.Options.VariantName]
+	endGameLength := getEndGameLength(g)
+
+	// In some variants, the players do not know which of the played cards were misplays
+	hidePlayedCards := !t.Replay &&
+		getEquivalentPlayer(t, s.UserID()) != nil &&
+		variant.IsThrowItInAHole()
+
+	// Every clue that is given or that the team misses out on counts against the efficiency
+	discardValue := 1.0
+	if variant.IsClueStarved() {
+		// In "Clue Starved" variants, each discard only grants half of a clue
+		discardValue = 0.5
+	}
+	suitValue := discardValue
+	if variant.IsThrowItInAHole() {
+		// Completing a suit does not grant a clue in these variants
+		suitValue = 0
+	}
+
+	// Cards that are played or clued count as "gotten"
+	// (unlike the client, we count every clued card,
+	// since the server does not keep track of which cards are known to be trash)
+	score := 0
+	scorePerStack := make([]int, len(g.Stacks))
+	cardsGotten := 0
+	for _, c := range g.Deck {
+		if c.Played || (hidePlayedCards && c.Failed) {
+			score++
+			cardsGotten++
+			if c.Played {
+				scorePerStack[c.SuitIndex]++
+			}
+		} else if c.Touched && !c.Discarded {
+			// Only cards in the hands of players can be touched
+			cardsGotten++
+		}
+	}
+	if cardsGotten > g.MaxScore {
+		cardsGotten = g.MaxScore
+	}
+
+	potentialCluesLost := 0.0
+	clueTokens := variant.GetAdjustedClueTokens(MaxClueNum)
+	numPlaysPerSuit := make([]int, len(g.Stacks))
+	completedSuit := false
+	for _, action := range g.Actions {
+		switch a := action.(type) {
+		case ActionClue:
+			potentialCluesLost++
+		case ActionStrike:
+			// A strike is equivalent to losing a clue
+			// (but the players in some variants are not supposed to know that it happened)
+			if !hidePlayedCards {
+				potentialCluesLost += discardValue
+			}
+		case ActionPlay:
+			if a.SuitIndex >= 0 && a.SuitIndex < len(numPlaysPerSuit) {
+				numPlaysPerSuit[a.SuitIndex]++
+				completedSuit = numPlaysPerSuit[a.SuitIndex] == PointsPerSuit
+			}
+		case ActionStatus:
+			// If a suit was completed while at the maximum amount of clues,
+			// then the extra clue is wasted (similar to a strike)
+			if completedSuit && suitValue > 0 && a.Clues == clueTokens {
+				potentialCluesLost += discardValue
+			}
+			completedSuit = false
+			clueTokens = a.Clues
+		}
+	}
+
+	deckSize := len(g.Deck) - g.DeckIndex
+	var pace *int
+	if g.EndCondition == EndConditionInProgress && deckSize > 0 {
+		v := score + deckSize - g.MaxScore + endGameLength
+		pace = &v
+	}
+
+	// The number of clues that can still be given in the rest of the game
+	var cluesStillUsable *float64
+	unadjustedClueTokens := float64(g.ClueTokens)
+	if variant.IsClueStarved() {
+		unadjustedClueTokens /= 2
+	}
+	if pace != nil {
+		v := getCluesStillUsable(scorePerStack, score, g.GetMaxScorePerStack(), *pace,
+			endGameLength, discardValue, suitValue, unadjustedClueTokens)
+		cluesStillUsable = &v
+	}
+
+	// The efficiency that is needed at the start of the game in order to get the maximum score
+	startingPace := len(g.Deck) + endGameLength - g.GetHandSize()*len(g.Players) -
+		PointsPerSuit*len(variant.Suits)
+	startingScorePerStack := make([]int, len(variant.Suits))
+	startingMaxScorePerStack := make([]int, len(variant.Suits))
+	for i := range startingMaxScorePerStack {
+		startingMaxScorePerStack[i] = PointsPerSuit
+	}
+	startingCluesUsable := getCluesStillUsable(startingScorePerStack, 0,
+		startingMaxScorePerStack, startingPace, endGameLength, discardValue, suitValue,
+		MaxClueNum)
+
+	type TableEfficiencyMessage struct {
+		TableID            uint64   `json:"tableID"`
+		CardsGotten        int      `json:"cardsGotten"`
+		PotentialCluesLost float64  `json:"potentialCluesLost"`
+		MaxScore           int      `json:"maxScore"`
+		Pace               *int     `json:"pace"`
+		CluesStillUsable   *float64 `json:"cluesStillUsable"`
+		// Each of the efficiencies is null if it cannot be calculated (e.g. before any clues are
+		// given)
+		Efficiency         *float64 `json:"efficiency"`
+		FutureEfficiency   *float64 `json:"futureEfficiency"`
+		RequiredEfficiency *float64 `json:"requiredEfficiency"`
+	}
+	msg := &TableEfficiencyMessage{
+		TableID:            t.ID,
+		CardsGotten:        cardsGotten,
+		PotentialCluesLost: potentialCluesLost,
+		MaxScore:           g.MaxScore,
+		Pace:               pace,
+		CluesStillUsable:   cluesStillUsable,
+	}
+	if potentialCluesLost > 0 {
+		v := float64(cardsGotten) / potentialCluesLost
+		msg.Efficiency = &v
+	}
+	if cluesStillUsable != nil && *cluesStillUsable > 0 {
+		v := float64(g.MaxScore-cardsGotten) / *cluesStillUsable
+		msg.FutureEfficiency = &v
+	}
+	if startingCluesUsable > 0 {
+		v := float64(variant.MaxScore) / startingCluesUsable
+		msg.RequiredEfficiency = &v
+	}
+	s.Emit("tableEfficiency", msg)
+}
+
+// getEndGameLength returns the number of turns that are taken after the final card is drawn
+func getEndGameLength(g *Game) int {
+	// The "Contrarian" detrimental character has a 2-turn end game
+	if g.Options.DetrimentalCharacters {
+		for _, gp := range g.Players {
+			if gp.Character == "Contrarian" {
+				return 2
+			}
+		}
+	}
+
+	return len(g.Players)
+}
+
+// getCluesStillUsable returns the maximum number of clues that can be given while still getting
+// the maximum score from the given game state onwards
+// (this mirrors the "cluesStillUsable()" function in the client)
+func getCluesStillUsable(
+	scorePerStack []int,
+	score int,
+	maxScorePerStack []int,
+	pace int,
+	endGameLength int,
+	discardValue float64,
+	suitValue float64,
+	clueTokens float64,
+) float64 {
+	// If the pace is negative, then we can play that many fewer cards and we cannot discard at all
+	maxScore := 0
+	for _, stackMaxScore := range maxScorePerStack {
+		maxScore += stackMaxScore
+	}
+	cluesFromDiscards := 0.0
+	if pace < 0 {
+		maxScore += pace
+	} else {
+		cluesFromDiscards = float64(pace) * discardValue
+	}
+	cardsToBePlayed := maxScore - score
+
+	cluesFromSuits := 0.0
+	if suitValue > 0 {
+		// Find out how many suits we can complete before the final round
+		maxPlaysBeforeFinalRound := cardsToBePlayed - (endGameLength + 1)
+		missingCardsPerCompletableSuit := make([]int, 0)
+		for suitIndex, stackMaxScore := range maxScorePerStack {
+			if stackMaxScore == PointsPerSuit && scorePerStack[suitIndex] < PointsPerSuit {
+				missingCardsPerCompletableSuit = append(missingCardsPerCompletableSuit,
+					stackMaxScore-scorePerStack[suitIndex])
+			}
+		}
+		sort.Ints(missingCardsPerCompletableSuit)
+
+		cardsPlayed := 0
+		suitsCompletedBeforeFinalRound := 0
+		for _, missingCards := range missingCardsPerCompletableSuit {
+			if cardsPlayed+missingCards > maxPlaysBeforeFinalRound {
+				break
+			}
+			cardsPlayed += missingCards
+			suitsCompletedBeforeFinalRound++
+		}
+		cluesFromSuits = float64(suitsCompletedBeforeFinalRound) * suitValue
+	}
+
+	return math.Floor(cluesFromDiscards + cluesFromSuits + clueTokens)
+}
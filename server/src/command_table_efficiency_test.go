@@ -0,0 +1,150 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+type testTableEfficiency struct {
+	TableID            uint64   `json:"tableID"`
+	CardsGotten        int      `json:"cardsGotten"`
+	PotentialCluesLost float64  `json:"potentialCluesLost"`
+	MaxScore           int      `json:"maxScore"`
+	Pace               *int     `json:"pace"`
+	CluesStillUsable   *float64 `json:"cluesStillUsable"`
+	Efficiency         *float64 `json:"efficiency"`
+	FutureEfficiency   *float64 `json:"futureEfficiency"`
+	RequiredEfficiency *float64 `json:"requiredEfficiency"`
+}
+
+func getTestTableEfficiency(t *testing.T, tb *Table, id int, name string) *testTableEfficiency {
+	var msg testTableEfficiency
+	getTestTableResponse(t, tb, id, name, commandTableEfficiency, "tableEfficiency", &msg)
+
+	return &msg
+}
+
+func expectTestFloat(t *testing.T, description string, v *float64, expected float64) {
+	if v == nil {
+		t.Errorf("expected the %v to be %v, but it is null", description, expected)
+	} else if math.Abs(*v-expected) > 1e-9 {
+		t.Errorf("expected the %v to be %v, but got %v", description, expected, *v)
+	}
+}
+
+func expectTestPace(t *testing.T, efficiency *testTableEfficiency, expected int) {
+	if efficiency.Pace == nil {
+		t.Errorf("expected the pace to be %v, but it is null", expected)
+	} else if *efficiency.Pace != expected {
+		t.Errorf("expected the pace to be %v, but got %v", expected, *efficiency.Pace)
+	}
+}
+
+func TestCommandTableEfficiencyFreshGame(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+	efficiency := getTestTableEfficiency(t, tb, 1, "Alice")
+
+	if efficiency.TableID != tb.ID || efficiency.MaxScore != 25 {
+		t.Errorf("expected the efficiency of table %v with a max score of 25, but got %+v",
+			tb.ID, efficiency)
+	}
+	if efficiency.CardsGotten != 0 || efficiency.PotentialCluesLost != 0 ||
+		efficiency.Efficiency != nil {
+
+		t.Errorf("expected no cards gotten and no clues lost, but got %+v", efficiency)
+	}
+
+	// 40 cards are left in the deck and there are 2 turns after the final card is drawn,
+	// so 17 cards can be discarded
+	// Along with 4 suits that can be completed before the final round and the 8 clue tokens that
+	// the team starts with, that makes 29 clues for 25 cards
+	expectTestPace(t, efficiency, 17)
+	expectTestFloat(t, "clues still usable", efficiency.CluesStillUsable, 29)
+	expectTestFloat(t, "future efficiency", efficiency.FutureEfficiency, 25.0/29)
+	expectTestFloat(t, "required efficiency", efficiency.RequiredEfficiency, 25.0/29)
+}
+
+func TestCommandTableEfficiencyMidGame(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+	g := tb.Game
+
+	// Give a clue that touches every card of a rank in the hand of Bob
+	rank := g.Players[1].Hand[0].Rank
+	numTouched := 0
+	for _, c := range g.Players[1].Hand {
+		if c.Rank == rank {
+			numTouched++
+		}
+	}
+	clueTestPlayer(t, tb)
+
+	// Bob discards a card that was not clued
+	var discarded *Card
+	for _, c := range g.Players[1].Hand {
+		if c.Rank != rank {
+			discarded = c
+			break
+		}
+	}
+	if discarded == nil {
+		t.Fatal("every card in the hand of Bob was clued")
+	}
+	performTestAction(t, tb, ActionTypeDiscard, discarded.Order, 0)
+
+	efficiency := getTestTableEfficiency(t, tb, 1, "Alice")
+	if efficiency.CardsGotten != numTouched || efficiency.PotentialCluesLost != 1 {
+		t.Errorf("expected %v cards gotten from 1 clue, but got %v from %v", numTouched,
+			efficiency.CardsGotten, efficiency.PotentialCluesLost)
+	}
+	expectTestFloat(t, "efficiency", efficiency.Efficiency, float64(numTouched))
+
+	// A card was drawn, so one less card can be discarded
+	// (the clue that was given was gotten back from the discard)
+	expectTestPace(t, efficiency, 16)
+	expectTestFloat(t, "clues still usable", efficiency.CluesStillUsable, 28)
+	expectTestFloat(t, "future efficiency", efficiency.FutureEfficiency,
+		float64(25-numTouched)/28)
+	expectTestFloat(t, "required efficiency", efficiency.RequiredEfficiency, 25.0/29)
+}
+
+func TestCommandTableEfficiencyThrowItInAHole(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTable(t, 2)
+	tb.Options.VariantName = "Throw It in a Hole (5 Suits)"
+	startTestGame(t, tb)
+	newTestWebsocketSpectator(t, tb, 10, "Spectator")
+	misplayTestCard(t, tb)
+
+	// The players cannot tell that the card was misplayed
+	efficiency := getTestTableEfficiency(t, tb, 2, "Bob")
+	if efficiency.CardsGotten != 1 || efficiency.PotentialCluesLost != 0 {
+		t.Errorf("the misplay was revealed to a player: %+v", efficiency)
+	}
+
+	// The spectators can
+	efficiency = getTestTableEfficiency(t, tb, 10, "Spectator")
+	if efficiency.CardsGotten != 0 || efficiency.PotentialCluesLost != 1 {
+		t.Errorf("the misplay was hidden from a spectator: %+v", efficiency)
+	}
+}
+
+func TestCommandTableEfficiencyValidation(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTable(t, 2)
+	s, conn := newTestWebsocket(t, 1, "Alice", 0)
+	commandTableEfficiency(s, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+	expectTestWarningCode(t, conn, ErrNotStarted)
+
+	startTestGame(t, tb)
+	s2, conn2 := newTestWebsocket(t, 10, "Dan", 0)
+	commandTableEfficiency(s2, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+	expectTestWarning(t, conn2, "You are not a player or a spectator")
+}
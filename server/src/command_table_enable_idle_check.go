@@ -0,0 +1,41 @@
+package main
+
+// commandTableEnableIdleCheck is sent when the owner of a table wants it to be automatically ended
+// for being idle again (after the "tableDisableIdleCheck" command)
+//
+// Example data:
+// {
+//   tableID: 123,
+// }
+func commandTableEnableIdleCheck(s *Session, d *CommandData) {
+	t, exists := getTableAndLock(s, d.TableID, !d.NoLock)
+	if !exists {
+		return
+	}
+	if !d.NoLock {
+		defer t.Mutex.Unlock()
+	}
+
+	// Validate that they are the owner of the table
+	if s.UserID() != t.Owner {
+		s.WarningWithCode(ErrNotOwner, NotOwnerFail)
+		return
+	}
+
+	// Validate that the idle check is disabled
+	if !t.IdleCheckDisabled {
+		s.Warning("The idle check is already enabled for this table.")
+		return
+	}
+
+	tableEnableIdleCheck(s, t)
+}
+
+func tableEnableIdleCheck(s *Session, t *Table) {
+	t.IdleCheckDisabled = false
+	chatServerSend(s.Username()+" has enabled the idle check.", t.GetRoomName())
+
+	// Any check that finished while it was disabled gave up on the table,
+	// so we have to start a new one
+	go t.CheckIdle()
+}
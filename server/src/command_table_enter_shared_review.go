@@ -0,0 +1,82 @@
+package main
+
+import (
+	"strconv"
+)
+
+// commandTableEnterSharedReview is sent when the owner of an ongoing game wants to step everyone at
+// the table through the past turns of the game together (e.g. for teaching)
+// The game cannot advance until the owner sends a "tableExitSharedReview" command
+// (timed games are automatically paused for the duration of the review)
+//
+// Example data:
+// {
+//   tableID: 5,
+// }
+func commandTableEnterSharedReview(s *Session, d *CommandData) {
+	t, exists := getTableAndLock(s, d.TableID, !d.NoLock)
+	if !exists {
+		return
+	}
+	if !d.NoLock {
+		defer t.Mutex.Unlock()
+	}
+	g := t.Game
+
+	// Validate that the game has started
+	if !t.Running {
+		s.WarningWithCode(ErrNotStarted, NotStartedFail)
+		return
+	}
+
+	// Validate that it is not a replay
+	if t.Replay {
+		s.Warning("You can not start a shared review in a replay.")
+		return
+	}
+
+	// Validate that they are in the game
+	playerIndex := t.GetPlayerIndexFromID(s.UserID())
+	if playerIndex == -1 {
+		s.Warning("You are not at table " + strconv.FormatUint(t.ID, 10) + ", " +
+			"so you cannot start a shared review.")
+		return
+	}
+
+	// Validate that they are the owner of the table
+	if s.UserID() != t.Owner {
+		s.WarningWithCode(ErrNotOwner, NotOwnerFail)
+		return
+	}
+
+	// Validate that the table is not already reviewing
+	if g.Reviewing {
+		s.Warning("The table is already in a shared review.")
+		return
+	}
+
+	tableEnterSharedReview(s, d, t)
+}
+
+func tableEnterSharedReview(s *Session, d *CommandData, t *Table) {
+	// Local variables
+	g := t.Game
+
+	// The game clock should not run while the review is going on
+	if t.Options.Timed && !g.Paused {
+		pause(s, &CommandData{ // Manual invocation
+			TableID: t.ID,
+			Setting: "pause",
+			NoLock:  true,
+		}, t, g.ActivePlayerIndex)
+		g.ReviewPaused = true
+	}
+
+	// Start the review on the current turn
+	g.Reviewing = true
+	g.ReviewTurn = g.Turn
+	t.NotifySharedReview()
+
+	msg := s.Username() + " started a shared review of the game."
+	chatServerSend(msg, t.GetRoomName())
+}
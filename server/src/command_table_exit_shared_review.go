@@ -0,0 +1,67 @@
+package main
+
+// commandTableExitSharedReview is sent when the owner of an ongoing game wants to stop a shared
+// review and return everyone to the live game
+//
+// Example data:
+// {
+//   tableID: 5,
+// }
+func commandTableExitSharedReview(s *Session, d *CommandData) {
+	t, exists := getTableAndLock(s, d.TableID, !d.NoLock)
+	if !exists {
+		return
+	}
+	if !d.NoLock {
+		defer t.Mutex.Unlock()
+	}
+	g := t.Game
+
+	// Validate that the game has started
+	if !t.Running {
+		s.WarningWithCode(ErrNotStarted, NotStartedFail)
+		return
+	}
+
+	// Validate that they are the owner of the table
+	if s.UserID() != t.Owner {
+		s.WarningWithCode(ErrNotOwner, NotOwnerFail)
+		return
+	}
+
+	// Validate that the table is reviewing
+	if !g.Reviewing {
+		s.Warning("The table is not in a shared review.")
+		return
+	}
+
+	tableExitSharedReview(s, d, t)
+}
+
+func tableExitSharedReview(s *Session, d *CommandData, t *Table) {
+	// Local variables
+	g := t.Game
+
+	g.Reviewing = false
+	g.ReviewTurn = 0
+	t.NotifySharedReview()
+
+	msg := s.Username() + " ended the shared review of the game."
+	chatServerSend(msg, t.GetRoomName())
+
+	// If we paused the game for the review, unpause it
+	// (which also restarts the function that takes the turn of an away player)
+	if g.ReviewPaused {
+		g.ReviewPaused = false
+		if g.Paused {
+			pause(s, &CommandData{ // Manual invocation
+				TableID: t.ID,
+				Setting: "unpause",
+				NoLock:  true,
+			}, t, g.ActivePlayerIndex)
+			return
+		}
+	}
+
+	g.CheckAwayAfterResume()
+}
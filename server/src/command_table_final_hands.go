@@ -0,0 +1,90 @@
+package main
+
+import (
+	"strconv"
+)
+
+// commandTableFinalHands is sent when the user wants to see what every player was holding at the
+// end of a game (e.g. to review the game without having to go to the final turn of the replay)
+// This would leak information in an ongoing game, so it only works for finished games and replays
+//
+// Example data:
+// {
+//   tableID: 5,
+// }
+func commandTableFinalHands(s *Session, d *CommandData) {
+	t, exists := getTableAndLock(s, d.TableID, !d.NoLock)
+	if !exists {
+		return
+	}
+	if !d.NoLock {
+		defer t.Mutex.Unlock()
+	}
+
+	// Validate that the game has started
+	if !t.Running {
+		s.WarningWithCode(ErrNotStarted, NotStartedFail)
+		return
+	}
+
+	// Validate that the game is over
+	if !t.Replay && t.Game.EndCondition == EndConditionInProgress {
+		s.Warning("You can only see the final hands after the game has ended.")
+		return
+	}
+
+	// Validate that they are either a player or a spectator
+	playerIndex := t.GetPlayerIndexFromID(s.UserID())
+	spectatorIndex := t.GetSpectatorIndexFromID(s.UserID())
+	if playerIndex == -1 && spectatorIndex == -1 {
+		s.Warning("You are not a player or a spectator at table " +
+			strconv.FormatUint(t.ID, 10) + ", so you cannot get the final hands for it.")
+		return
+	}
+
+	tableFinalHands(s, t)
+}
+
+func tableFinalHands(s *Session, t *Table) {
+	// Local variables
+	g := t.Game
+	variant := variants[g.Options.VariantName]
+
+	type FinalHandCard struct {
+		Order     int    `json:"order"`
+		SuitIndex int    `json:"suitIndex"`
+		Suit      string `json:"suit"`
+		Rank      int    `json:"rank"`
+	}
+	type FinalHand struct {
+		Index int              `json:"index"`
+		Name  string           `json:"name"`
+		Cards []*FinalHandCard `json:"cards"`
+	}
+	hands := make([]*FinalHand, 0, len(g.Players))
+	for _, gp := range g.Players {
+		hand := &FinalHand{
+			Index: gp.Index,
+			Name:  gp.Name,
+			Cards: make([]*FinalHandCard, 0, len(gp.Hand)),
+		}
+		for _, c := range gp.Hand {
+			hand.Cards = append(hand.Cards, &FinalHandCard{
+				Order:     c.Order,
+				SuitIndex: c.SuitIndex,
+				Suit:      variant.Suits[c.SuitIndex].Name,
+				Rank:      c.Rank,
+			})
+		}
+		hands = append(hands, hand)
+	}
+
+	type TableFinalHandsMessage struct {
+		TableID uint64       `json:"tableID"`
+		Hands   []*FinalHand `json:"hands"`
+	}
+	s.Emit("tableFinalHands", &TableFinalHandsMessage{
+		TableID: t.ID,
+		Hands:   hands,
+	})
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"testing"
+)
+
+type testFinalHand struct {
+	Index int    `json:"index"`
+	Name  string `json:"name"`
+	Cards []struct {
+		Order     int    `json:"order"`
+		SuitIndex int    `json:"suitIndex"`
+		Suit      string `json:"suit"`
+		Rank      int    `json:"rank"`
+	} `json:"cards"`
+}
+
+func getTestFinalHands(t *testing.T, tb *Table, id int, name string) []*testFinalHand {
+	var msg struct {
+		Hands []*testFinalHand `json:"hands"`
+	}
+	getTestTableResponse(t, tb, id, name, commandTableFinalHands, "tableFinalHands", &msg)
+
+	return msg.Hands
+}
+
+// expectTestFinalHands fails the test if the hands do not match the hands of the players
+func expectTestFinalHands(t *testing.T, g *Game, hands []*testFinalHand) {
+	variant := variants[g.Options.VariantName]
+	if len(hands) != len(g.Players) {
+		t.Fatalf("expected %v hands, but got %v", len(g.Players), len(hands))
+	}
+	for i, gp := range g.Players {
+		hand := hands[i]
+		if hand.Index != i || hand.Name != gp.Name || len(hand.Cards) != len(gp.Hand) {
+			t.Errorf("expected the hand of %v with %v cards, but got the hand of %v with %v "+
+				"cards", gp.Name, len(gp.Hand), hand.Name, len(hand.Cards))
+			continue
+		}
+		for j, c := range gp.Hand {
+			c2 := hand.Cards[j]
+			if c2.Order != c.Order || c2.SuitIndex != c.SuitIndex || c2.Rank != c.Rank ||
+				c2.Suit != variant.Suits[c.SuitIndex].Name {
+
+				t.Errorf("expected card %v of %v to be %v %v (order %v), but got %+v", j,
+					gp.Name, variant.Suits[c.SuitIndex].Name, c.Rank, c.Order, c2)
+			}
+		}
+	}
+}
+
+func TestCommandTableFinalHands(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 3)
+	clueTestPlayer(t, tb)
+	discardTestCard(t, tb)
+	performTestAction(t, tb, ActionTypeEndGame, 0, EndConditionTerminated)
+
+	expectTestFinalHands(t, tb.Game, getTestFinalHands(t, tb, 2, "Bob"))
+}
+
+func TestCommandTableFinalHandsReplay(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestReplay(t)
+	newTestWebsocketSpectator(t, tb, 10, "Spectator")
+
+	expectTestFinalHands(t, tb.Game, getTestFinalHands(t, tb, 10, "Spectator"))
+}
+
+func TestCommandTableFinalHandsRunningGame(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+
+	s, conn := newTestWebsocket(t, 1, "Alice", 0)
+	commandTableFinalHands(s, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+	expectTestWarning(t, conn, "You can only see the final hands after the game has ended.")
+}
+
+func TestCommandTableFinalHandsValidation(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTable(t, 2)
+
+	s, conn := newTestWebsocket(t, 1, "Alice", 0)
+	commandTableFinalHands(s, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+	expectTestWarningCode(t, conn, ErrNotStarted)
+
+	startTestGame(t, tb)
+	performTestAction(t, tb, ActionTypeEndGame, 0, EndConditionTerminated)
+	s2, conn2 := newTestWebsocket(t, 10, "Dan", 0)
+	commandTableFinalHands(s2, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+	expectTestWarning(t, conn2, "You are not a player or a spectator")
+}
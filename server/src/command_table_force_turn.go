@@ -0,0 +1,89 @@
+package main
+
+// commandTableForceTurn is sent when the owner of an ongoing game wants to take the turn of an
+// active player who is not in the game (instead of waiting for them or terminating the game)
+// The turn is taken in the same way as for a player who is away
+// (a discard of their chop, or a rank clue if the team is at the maximum amount of clues)
+//
+// Example data:
+// {
+//   tableID: 123,
+// }
+func commandTableForceTurn(s *Session, d *CommandData) {
+	t, exists := getTableAndLock(s, d.TableID, !d.NoLock)
+	if !exists {
+		return
+	}
+	if !d.NoLock {
+		defer t.Mutex.Unlock()
+	}
+
+	// Validate that they are the owner of the table
+	if s.UserID() != t.Owner {
+		s.WarningWithCode(ErrNotOwner, NotOwnerFail)
+		return
+	}
+
+	// Validate that the game has started
+	if !t.Running {
+		s.WarningWithCode(ErrNotStarted, NotStartedFail)
+		return
+	}
+
+	// Validate that it is not a replay
+	if t.Replay {
+		s.Warning("You cannot take a turn in a replay.")
+		return
+	}
+
+	// Local variables
+	g := t.Game
+
+	// Validate that the game is not paused or being reviewed
+	if g.Paused {
+		s.WarningWithCode(ErrPaused, "You cannot take a turn while the game is paused.")
+		return
+	}
+	if g.Reviewing {
+		s.Warning("You cannot take a turn during a shared review.")
+		return
+	}
+
+	// Validate that the active player has left
+	p := t.Players[g.ActivePlayerIndex]
+	if p.Present {
+		s.Warning("You can only take the turn of a player who is not currently in the game.")
+		return
+	}
+
+	tableForceTurn(s, t)
+}
+
+func tableForceTurn(s *Session, t *Table) {
+	// Local variables
+	g := t.Game
+	gp := g.Players[g.ActivePlayerIndex]
+	p := t.Players[gp.Index]
+
+	d := g.GetAutomaticAction(gp, gp.GetChopIndex())
+	if d == nil {
+		s.Warning("There is no action that can be taken for " + p.Name + ".")
+		return
+	}
+
+	// The action must come from the player whose turn it is
+	s2 := p.Session
+	if s2 == nil {
+		// A player's session should never be nil
+		// They might be in the process of reconnecting,
+		// so make a fake session that will represent them
+		s2 = newFakeSession(p.ID, p.Name)
+		logger.Info("Created a new fake session in the \"tableForceTurn()\" function.")
+	}
+
+	logger.Info(t.GetName() + "User \"" + s.Username() + "\" forced the turn of \"" +
+		p.Name + "\".")
+	chatServerSend(s.Username()+" took the turn of "+p.Name+", since they are not in the game.",
+		t.GetRoomName())
+	commandAction(s2, d)
+}
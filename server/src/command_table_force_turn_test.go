@@ -0,0 +1,79 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTableForceTurn(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+	g := tb.Game
+	_, conn := newTestWebsocketSpectator(t, tb, 10, "Spectator")
+
+	// The team has to be below the maximum amount of clues for a discard to be possible
+	clueTestPlayer(t, tb)
+	tb.Players[1].Present = false
+	gp := g.Players[1]
+	c := gp.Hand[gp.GetChopIndex()]
+	turn := g.Turn
+
+	s, _ := newTestWebsocket(t, 1, "Alice", 0)
+	commandTableForceTurn(s, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+	if g.Turn != turn+1 || g.ActivePlayerIndex != 0 {
+		t.Fatal("the turn of the absent player was not taken")
+	}
+	if !c.Discarded {
+		t.Error("the chop of the absent player was not discarded")
+	}
+	if data := readTestCommand(t, conn, "chat"); !strings.Contains(data, "took the turn of Bob") {
+		t.Errorf("expected everyone to be told that the turn was taken, but got: %v", data)
+	}
+}
+
+func TestTableForceTurnPresentPlayer(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+	turn := tb.Game.Turn
+
+	s, conn := newTestWebsocket(t, 1, "Alice", 0)
+	commandTableForceTurn(s, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+	expectTestWarning(t, conn, "You can only take the turn of a player who is not currently")
+	if tb.Game.Turn != turn {
+		t.Error("the turn of a player who is present was taken")
+	}
+}
+
+func TestTableForceTurnValidation(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+	tb.Players[0].Present = false
+	turn := tb.Game.Turn
+
+	// Only the owner can take the turn
+	s, conn := newTestWebsocket(t, 2, "Bob", 0)
+	commandTableForceTurn(s, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+	expectTestWarningCode(t, conn, ErrNotOwner)
+
+	// The game cannot be paused
+	tb.Game.Paused = true
+	s2, conn2 := newTestWebsocket(t, 1, "Alice", 0)
+	commandTableForceTurn(s2, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+	expectTestWarningCode(t, conn2, ErrPaused)
+
+	if tb.Game.Turn != turn {
+		t.Error("the turn was taken")
+	}
+}
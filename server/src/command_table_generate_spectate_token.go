@@ -0,0 +1,90 @@
+package main
+
+import (
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	// The amount of time that a spectate token can be used for after it is generated
+	SpectateTokenLifetime = time.Hour
+)
+
+// SpectateToken allows a user to spectate a table without having to find it in the lobby
+// (see "commandSpectateWithToken")
+type SpectateToken struct {
+	SingleUse       bool
+	DatetimeExpires time.Time
+}
+
+// commandTableGenerateSpectateToken is sent when the owner of a table wants to give specific users
+// (e.g. their friends) a way to spectate the game
+// The token is only valid until the game ends
+//
+// Example data:
+// {
+//   tableID: 123,
+//   // If true, the token is invalidated after the first user spectates with it
+//   singleUse: true,
+// }
+func commandTableGenerateSpectateToken(s *Session, d *CommandData) {
+	t, exists := getTableAndLock(s, d.TableID, !d.NoLock)
+	if !exists {
+		return
+	}
+	if !d.NoLock {
+		defer t.Mutex.Unlock()
+	}
+
+	// Validate that they are the owner of the table
+	if s.UserID() != t.Owner {
+		s.WarningWithCode(ErrNotOwner, NotOwnerFail)
+		return
+	}
+
+	// Validate that this is not a replay
+	// (anyone can already join a shared replay)
+	if t.Replay {
+		s.Warning("You cannot generate a spectate token for a replay.")
+		return
+	}
+
+	tableGenerateSpectateToken(s, d, t)
+}
+
+func tableGenerateSpectateToken(s *Session, d *CommandData, t *Table) {
+	// Expired tokens are never used again, so we clean them up as new ones are made
+	for token, spectateToken := range t.SpectateTokens {
+		if time.Now().After(spectateToken.DatetimeExpires) {
+			delete(t.SpectateTokens, token)
+		}
+	}
+
+	token := uuid.NewV4().String()
+	t.SpectateTokens[token] = &SpectateToken{
+		SingleUse:       d.SingleUse,
+		DatetimeExpires: time.Now().Add(SpectateTokenLifetime),
+	}
+
+	type SpectateTokenMessage struct {
+		TableID   uint64 `json:"tableID"`
+		Token     string `json:"token"`
+		SingleUse bool   `json:"singleUse"`
+		// In seconds
+		ExpiresIn int `json:"expiresIn"`
+	}
+	s.Emit("spectateToken", &SpectateTokenMessage{
+		TableID:   t.ID,
+		Token:     token,
+		SingleUse: d.SingleUse,
+		ExpiresIn: int(SpectateTokenLifetime.Seconds()),
+	})
+}
+
+// IsValidSpectateToken returns true if the token was given out by the owner and has not expired
+// The table mutex must be held when calling this function
+func (t *Table) IsValidSpectateToken(token string) bool {
+	spectateToken, ok := t.SpectateTokens[token]
+	return ok && !time.Now().After(spectateToken.DatetimeExpires)
+}
@@ -33,7 +33,7 @@ func commandTableJoin(s *Session, d *CommandData) {
 	// Validate that the player is not joined to another table
 	if !strings.HasPrefix(s.Username(), "Bot-") {
 		if t2 := s.GetJoinedTable(); t2 != nil {
-			s.Warning("You cannot join more than one table at a time. " +
+			s.WarningWithCode(ErrAlreadyAtTable, "You cannot join more than one table at a time. "+
 				"Terminate your other game before joining a new one.")
 			return
 		}
@@ -41,7 +41,14 @@ func commandTableJoin(s *Session, d *CommandData) {
 
 	// Validate that this table does not already have 6 players
 	if len(t.Players) >= 6 {
-		s.Warning("That table is already full. (You can not play with more than 6 players.)")
+		s.WarningWithCode(ErrTableFull,
+			"That table is already full. (You can not play with more than 6 players.)")
+		return
+	}
+
+	// Validate that the remaining seats are not reserved for other users
+	if len(t.Players)+t.GetNumReservedSeats(s.UserID()) >= MaxPlayers {
+		s.Warning("The remaining seats at that table are reserved for other players.")
 		return
 	}
 
@@ -64,7 +71,7 @@ func commandTableJoin(s *Session, d *CommandData) {
 			s.Error(DefaultErrorMsg)
 			return
 		} else if !match {
-			s.Warning("That is not the correct password for this game.")
+			s.WarningWithCode(ErrWrongPassword, "That is not the correct password for this game.")
 			return
 		}
 	}
@@ -75,9 +82,69 @@ func commandTableJoin(s *Session, d *CommandData) {
 		return
 	}
 
+	// Validate that they meet the experience requirements set by the table owner
+	if t.HasRequirementsFor(s.UserID()) && !tableJoinCheckRequirements(s, t) {
+		return
+	}
+
 	tableJoin(s, t)
 }
 
+// tableJoinCheckRequirements returns false (and warns the user) if the user does not meet the
+// minimum number of games or the minimum rating for the table
+func tableJoinCheckRequirements(s *Session, t *Table) bool {
+	// Local variables
+	variant := variants[t.Options.VariantName]
+
+	numGames := 0
+	if t.MinGames > 0 {
+		if v, err := models.Games.GetUserNumGames(s.UserID(), false); err != nil {
+			logger.Error("Failed to get the number of non-speedrun games for player "+
+				"\""+s.Username()+"\":", err)
+			s.Error(DefaultErrorMsg)
+			return false
+		} else {
+			numGames = v
+		}
+	}
+
+	averageScore := 0.0
+	if t.MinRating > 0 {
+		if v, err := models.UserStats.Get(s.UserID(), variant.ID); err != nil {
+			logger.Error("Failed to get the stats for player \""+s.Username()+"\" for variant "+
+				strconv.Itoa(variant.ID)+":", err)
+			s.Error(DefaultErrorMsg)
+			return false
+		} else {
+			averageScore = v.AverageScore
+		}
+	}
+
+	if msg := getTableRequirementsWarning(t, numGames, averageScore); msg != "" {
+		s.Warning(msg)
+		return false
+	}
+
+	return true
+}
+
+// getTableRequirementsWarning returns the reason that a user with the given number of games and
+// average score cannot join the table (or an empty string if they meet the requirements)
+func getTableRequirementsWarning(t *Table, numGames int, averageScore float64) string {
+	if t.MinGames > 0 && numGames < t.MinGames {
+		return "That table requires at least " + strconv.Itoa(t.MinGames) + " games " +
+			"played, but you have only played " + strconv.Itoa(numGames) + "."
+	}
+
+	if t.MinRating > 0 && averageScore < t.MinRating {
+		return "That table requires an average score of at least " +
+			formatRating(t.MinRating) + " in this variant, but yours is only " +
+			formatRating(averageScore) + "."
+	}
+
+	return ""
+}
+
 func tableJoin(s *Session, t *Table) {
 	// Local variables
 	variant := variants[t.Options.VariantName]
@@ -85,6 +152,11 @@ func tableJoin(s *Session, t *Table) {
 	logger.Info(t.GetName() + "User \"" + s.Username() + "\" joined. " +
 		"(There are now " + strconv.Itoa(len(t.Players)+1) + " players.)")
 
+	// A player who joins a table on their own no longer needs a game from the queue
+	if matchmakingRemove(s.UserID()) {
+		s.NotifyQueue(nil)
+	}
+
 	// Get the total number of non-speedrun games that this player has played
 	var numGames int
 	if v, err := models.Games.GetUserNumGames(s.UserID(), false); err != nil {
@@ -118,9 +190,18 @@ func tableJoin(s *Session, t *Table) {
 		},
 	}
 	t.Players = append(t.Players, p)
+	// The players that were ready might not want to play with the new player
+	if t.ResetReady() {
+		chatServerSend("Everyone has been marked as not ready since a player joined.",
+			t.GetRoomName())
+	}
 	notifyAllTable(t)
 	t.NotifyPlayerChange()
 
+	// Someone joining counts as activity, so reset the idle timeout
+	// (this is also where the idle timeout is started for a newly created table)
+	go t.CheckIdle()
+
 	// Set their status
 	if s != nil {
 		s.Set("status", StatusPregame)
@@ -0,0 +1,73 @@
+package main
+
+// commandTableKickSpectator is sent when the owner of a table wants to remove a spectator
+// If "block" is true, the spectator will not be able to spectate the table again
+//
+// Example data:
+// {
+//   tableID: 5,
+//   spectatorID: 12,
+//   block: true,
+// }
+func commandTableKickSpectator(s *Session, d *CommandData) {
+	t, exists := getTableAndLock(s, d.TableID, !d.NoLock)
+	if !exists {
+		return
+	}
+	if !d.NoLock {
+		defer t.Mutex.Unlock()
+	}
+
+	// Validate that they are the owner of the table
+	if s.UserID() != t.Owner {
+		s.WarningWithCode(ErrNotOwner, NotOwnerFail)
+		return
+	}
+
+	// Validate that they did not target themselves
+	if d.SpectatorID == s.UserID() {
+		s.Warning("You cannot kick yourself.")
+		return
+	}
+
+	// Validate that the target is spectating the table
+	spectatorIndex := t.GetSpectatorIndexFromID(d.SpectatorID)
+	if spectatorIndex == -1 {
+		s.Warning("That user is not spectating this table.")
+		return
+	}
+
+	tableKickSpectator(t, spectatorIndex, d.Block, s.Username())
+}
+
+// tableKickSpectator is also used by administrators to remove spectators from any table
+// (from the localhost server)
+// The table mutex must be held when calling this function
+func tableKickSpectator(t *Table, spectatorIndex int, block bool, kickedBy string) {
+	sp := t.Spectators[spectatorIndex]
+
+	if block {
+		// Record this spectator's user ID so that they cannot spectate the table again
+		t.KickedSpectators[sp.ID] = struct{}{}
+	}
+
+	s2 := sp.Session
+	if s2 == nil {
+		// A spectator's session should never be nil
+		// They might be in the process of reconnecting,
+		// so make a fake session that will represent them
+		s2 = newFakeSession(sp.ID, sp.Name)
+		logger.Info("Created a new fake session in the \"tableKickSpectator()\" function.")
+	}
+
+	// Send them back to the lobby and remove them from the table
+	s2.NotifyBoot(t)
+	commandTableUnattend(s2, &CommandData{ // Manual invocation
+		TableID: t.ID,
+		NoLock:  true,
+	})
+
+	logger.Info(t.GetName() + kickedBy + " kicked spectator \"" + sp.Name + "\".")
+	msg := kickedBy + " kicked the spectator: " + sp.Name
+	chatServerSend(msg, t.GetRoomName())
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func kickTestSpectator(tb *Table, s *Session, spectatorID int, block bool) {
+	commandTableKickSpectator(s, &CommandData{ // Manual invocation
+		TableID:     tb.ID,
+		SpectatorID: spectatorID,
+		Block:       block,
+		NoLock:      true,
+	})
+}
+
+func spectateTestTable(tb *Table, s *Session) {
+	commandTableSpectate(s, &CommandData{ // Manual invocation
+		TableID:              tb.ID,
+		ShadowingPlayerIndex: -1,
+		NoLock:               true,
+	})
+}
+
+func TestCommandTableKickSpectator(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+	newTestSpectator(tb, 10, "Spectator")
+
+	kickTestSpectator(tb, tb.Players[0].Session, 10, false)
+	if tb.GetSpectatorIndexFromID(10) != -1 {
+		t.Fatal("the spectator was not removed from the table")
+	}
+
+	// They were not blocked, so they can spectate again
+	spectateTestTable(tb, newTestSession(10, "Spectator"))
+	if tb.GetSpectatorIndexFromID(10) == -1 {
+		t.Error("the spectator was not able to spectate the table again")
+	}
+}
+
+func TestCommandTableKickSpectatorNotOwner(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+	newTestSpectator(tb, 10, "Spectator")
+
+	kickTestSpectator(tb, tb.Players[1].Session, 10, true)
+	if tb.GetSpectatorIndexFromID(10) == -1 {
+		t.Error("a player who is not the owner was able to kick a spectator")
+	}
+	if _, ok := tb.KickedSpectators[10]; ok {
+		t.Error("a player who is not the owner was able to block a spectator")
+	}
+}
+
+func TestCommandTableKickSpectatorBlock(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+	newTestSpectator(tb, 10, "Spectator")
+
+	kickTestSpectator(tb, tb.Players[0].Session, 10, true)
+	if tb.GetSpectatorIndexFromID(10) != -1 {
+		t.Fatal("the spectator was not removed from the table")
+	}
+
+	s, conn := newTestWebsocket(t, 10, "Spectator", 0)
+	spectateTestTable(tb, s)
+	expectTestWarning(t, conn, "you have been kicked from")
+	if tb.GetSpectatorIndexFromID(10) != -1 {
+		t.Error("a blocked spectator was able to spectate the table again")
+	}
+}
+
+func kickTestSpectatorFromLocalhost(tableID uint64, spectatorID int) *httptest.ResponseRecorder {
+	form := url.Values{}
+	form.Set("tableID", strconv.FormatUint(tableID, 10))
+	form.Set("spectatorID", strconv.Itoa(spectatorID))
+	form.Set("block", "true")
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/kickSpectator", strings.NewReader(form.Encode()))
+	c.Request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	httpLocalhostKickSpectator(c)
+
+	return w
+}
+
+func TestHttpLocalhostKickSpectator(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+	newTestSpectator(tb, 10, "Spectator")
+
+	if w := kickTestSpectatorFromLocalhost(tb.ID, 10); w.Code != http.StatusOK {
+		t.Fatalf("expected a status code of %v, but got %v: %v", http.StatusOK, w.Code, w.Body)
+	}
+	if tb.GetSpectatorIndexFromID(10) != -1 {
+		t.Error("the spectator was not removed from the table")
+	}
+	if _, ok := tb.KickedSpectators[10]; !ok {
+		t.Error("the spectator was not blocked")
+	}
+
+	// They are no longer spectating
+	if w := kickTestSpectatorFromLocalhost(tb.ID, 10); w.Code != http.StatusBadRequest {
+		t.Errorf("expected a status code of %v, but got %v", http.StatusBadRequest, w.Code)
+	}
+}
@@ -49,6 +49,10 @@ func tableLeave(s *Session, t *Table, playerIndex int) {
 
 	// Remove the player
 	t.Players = append(t.Players[:playerIndex], t.Players[playerIndex+1:]...)
+	if t.ResetReady() {
+		chatServerSend("Everyone has been marked as not ready since a player left.",
+			t.GetRoomName())
+	}
 	notifyAllTable(t)
 	t.NotifyPlayerChange()
 
@@ -0,0 +1,52 @@
+package main
+
+// commandTableListRunning is sent when a client wants a summary of every ongoing game
+// (without having to join each one)
+// This is distinct from the "tableList" message that the server sends to every user upon
+// connecting, which contains every table in the lobby
+//
+// Example data:
+// {
+//   variant: 'No Variant', // Optional
+// }
+func commandTableListRunning(s *Session, d *CommandData) {
+	// Make a copy of the tables so that we do not hold the tables lock while acquiring the lock
+	// for each individual table
+	tableList := make([]*Table, 0)
+	tablesMutex.RLock()
+	for _, t := range tables {
+		tableList = append(tableList, t)
+	}
+	tablesMutex.RUnlock()
+
+	type RunningTable struct {
+		ID          uint64 `json:"id"`
+		VariantName string `json:"variantName"`
+		NumPlayers  int    `json:"numPlayers"`
+		Turn        int    `json:"turn"`
+		Timed       bool   `json:"timed"`
+	}
+	runningTables := make([]*RunningTable, 0)
+	for _, t := range tableList {
+		t.Mutex.Lock()
+		if !t.Deleted && t.Running && !t.Replay &&
+			(d.Variant == "" || t.Options.VariantName == d.Variant) {
+
+			runningTables = append(runningTables, &RunningTable{
+				ID:          t.ID,
+				VariantName: t.Options.VariantName,
+				NumPlayers:  len(t.Players),
+				Turn:        t.Game.Turn,
+				Timed:       t.Options.Timed,
+			})
+		}
+		t.Mutex.Unlock()
+	}
+
+	type TableListRunningMessage struct {
+		List []*RunningTable `json:"list"`
+	}
+	s.Emit("tableListRunning", &TableListRunningMessage{
+		List: runningTables,
+	})
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+)
+
+type testRunningTable struct {
+	ID          uint64 `json:"id"`
+	VariantName string `json:"variantName"`
+	NumPlayers  int    `json:"numPlayers"`
+	Turn        int    `json:"turn"`
+	Timed       bool   `json:"timed"`
+}
+
+func TestTableListRunning(t *testing.T) {
+	resetTestTables(t)
+
+	running := newTestGame(t, 2)
+	clueTestPlayer(t, running)
+	timed := newTestTimedGame(t, 3)
+	timed.Options.VariantName = "6 Suits"
+	newTestTable(t, 2) // Unstarted tables are not listed
+	replay := newTestGame(t, 2)
+	replay.Replay = true
+
+	s, conn := newTestWebsocket(t, 10, "Monitor", 0)
+	getList := func(variant string) []*testRunningTable {
+		commandTableListRunning(s, &CommandData{
+			Variant: variant,
+		})
+
+		var msg struct {
+			List []*testRunningTable `json:"list"`
+		}
+		data := readTestCommand(t, conn, "tableListRunning")
+		if err := json.Unmarshal([]byte(data), &msg); err != nil {
+			t.Fatal("failed to unmarshal the message:", err)
+		}
+		sort.Slice(msg.List, func(i, j int) bool {
+			return msg.List[i].ID < msg.List[j].ID
+		})
+		return msg.List
+	}
+
+	list := getList("")
+	if len(list) != 2 {
+		t.Fatalf("expected 2 running tables, but got %v", len(list))
+	}
+	expected := []testRunningTable{
+		{ID: running.ID, VariantName: "No Variant", NumPlayers: 2, Turn: 1, Timed: false},
+		{ID: timed.ID, VariantName: "6 Suits", NumPlayers: 3, Turn: 0, Timed: true},
+	}
+	for i := range expected {
+		if *list[i] != expected[i] {
+			t.Errorf("expected %+v, but got %+v", expected[i], *list[i])
+		}
+	}
+
+	list = getList("6 Suits")
+	if len(list) != 1 || list[0].ID != timed.ID {
+		t.Errorf("expected only the timed table to be listed, but got %v tables", len(list))
+	}
+
+	if list := getList("Nonexistent Variant"); len(list) != 0 {
+		t.Errorf("expected no tables to be listed, but got %v", len(list))
+	}
+}
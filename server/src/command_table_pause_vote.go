@@ -0,0 +1,98 @@
+package main
+
+import (
+	"strconv"
+)
+
+// commandTablePauseVote is sent when a player votes to pause or unpause a timed game
+// Once a majority of the players have voted, the game is automatically paused (or unpaused)
+//
+// Example data:
+// {
+//   tableID: 5,
+// }
+func commandTablePauseVote(s *Session, d *CommandData) {
+	t, exists := getTableAndLock(s, d.TableID, !d.NoLock)
+	if !exists {
+		return
+	}
+	if !d.NoLock {
+		defer t.Mutex.Unlock()
+	}
+	g := t.Game
+
+	// Validate that the game has started
+	if !t.Running {
+		s.WarningWithCode(ErrNotStarted, NotStartedFail)
+		return
+	}
+
+	// Validate that it is not a replay
+	if t.Replay {
+		s.Warning("You can not pause or unpause in a replay.")
+		return
+	}
+
+	// Validate that they are in the game
+	playerIndex := t.GetPlayerIndexFromID(s.UserID())
+	if playerIndex == -1 {
+		s.Warning("You are not at table " + strconv.FormatUint(t.ID, 10) + ", " +
+			"so you cannot vote to pause / unpause.")
+		return
+	}
+
+	// Validate that the table is not reviewing past turns
+	// (the game stays paused until the review is over)
+	if g.Reviewing {
+		s.Warning("You can not pause or unpause during a shared review.")
+		return
+	}
+
+	// Validate that it is a timed game
+	if !t.Options.Timed {
+		s.Warning("This is not a timed game, so you cannot vote to pause / unpause.")
+		return
+	}
+
+	// Validate that they have not already voted
+	if g.Players[playerIndex].VotedPause {
+		s.Warning("You have already voted.")
+		return
+	}
+
+	tablePauseVote(s, t, playerIndex)
+}
+
+func tablePauseVote(s *Session, t *Table, playerIndex int) {
+	// Local variables
+	g := t.Game
+
+	g.Players[playerIndex].VotedPause = true
+
+	numVotes := 0
+	for _, p := range g.Players {
+		if p.VotedPause {
+			numVotes++
+		}
+	}
+
+	verb := "pause"
+	if g.Paused {
+		verb = "unpause"
+	}
+	msg := s.Username() + " voted to " + verb + " the game. " +
+		"(" + strconv.Itoa(numVotes) + "/" + strconv.Itoa(len(g.Players)) + ")"
+	chatServerSend(msg, t.GetRoomName())
+
+	// A strict majority of the players is required
+	if numVotes*2 <= len(g.Players) {
+		return
+	}
+
+	// The votes will be cleared as part of the pause logic
+	pause(s, &CommandData{ // Manual invocation
+		TableID: t.ID,
+		Setting: verb,
+		NoLock:  true,
+	}, t, playerIndex)
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"testing"
+)
+
+func votePauseTestTable(tb *Table, playerIndex int) {
+	commandTablePauseVote(tb.Players[playerIndex].Session, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+}
+
+func TestCommandTablePauseVoteMajority(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTimedGame(t, 3)
+	g := tb.Game
+
+	// One vote out of three is not enough
+	votePauseTestTable(tb, 0)
+	if g.Paused {
+		t.Fatal("the game was paused without a majority")
+	}
+
+	// Two votes out of three is a majority
+	votePauseTestTable(tb, 1)
+	if !g.Paused {
+		t.Fatal("the game was not paused after a majority of the players voted")
+	}
+	for i, p := range g.Players {
+		if p.VotedPause {
+			t.Errorf("the vote of player %v was not reset after the game was paused", i)
+		}
+	}
+
+	// The same mechanism is used to unpause
+	votePauseTestTable(tb, 2)
+	if !g.Paused {
+		t.Fatal("the game was unpaused without a majority")
+	}
+	votePauseTestTable(tb, 0)
+	if g.Paused {
+		t.Fatal("the game was not unpaused after a majority of the players voted")
+	}
+}
+
+func TestCommandTablePauseVoteLosesQuorum(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTimedGame(t, 3)
+	g := tb.Game
+
+	votePauseTestTable(tb, 0)
+
+	// The player who voted disconnects, so their vote no longer counts
+	commandTableUnattend(tb.Players[0].Session, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+	if g.Players[0].VotedPause {
+		t.Error("the vote was not reset after the player left")
+	}
+
+	votePauseTestTable(tb, 1)
+	if g.Paused {
+		t.Error("the game was paused with a vote from a player who left")
+	}
+}
+
+func TestCommandTablePauseVoteClearedAtGameEnd(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTimedGame(t, 3)
+	g := tb.Game
+
+	votePauseTestTable(tb, 0)
+	performTestAction(t, tb, ActionTypeEndGame, 0, EndConditionTerminated)
+	if g.EndCondition != EndConditionTerminated {
+		t.Fatal("the game was not ended")
+	}
+	for i, p := range g.Players {
+		if p.VotedPause {
+			t.Errorf("the vote of player %v was not reset after the game ended", i)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"strconv"
+	"time"
+)
+
+// commandTableReplacePlayer is sent when the owner of an ongoing game wants to substitute another
+// user into the seat of a player who has left (so that the game does not stall forever)
+// The new player takes over the hand and the turn position of the seat
+// The user must be online and either in the lobby or spectating the game
+//
+// Example data:
+// {
+//   tableID: 123,
+//   seatIndex: 2,
+//   newUserID: 5,
+// }
+func commandTableReplacePlayer(s *Session, d *CommandData) {
+	t, exists := getTableAndLock(s, d.TableID, !d.NoLock)
+	if !exists {
+		return
+	}
+	if !d.NoLock {
+		defer t.Mutex.Unlock()
+	}
+
+	// Validate that they are the owner of the table
+	if s.UserID() != t.Owner {
+		s.WarningWithCode(ErrNotOwner, NotOwnerFail)
+		return
+	}
+
+	// Validate that the game has started
+	if !t.Running {
+		s.WarningWithCode(ErrNotStarted, NotStartedFail)
+		return
+	}
+
+	// Validate that it is not a replay
+	if t.Replay {
+		s.Warning("You cannot replace players in a replay.")
+		return
+	}
+
+	// Validate the seat index
+	if d.SeatIndex < 0 || d.SeatIndex >= len(t.Players) {
+		s.Warning("The seat index must be between 0 and " + strconv.Itoa(len(t.Players)-1) +
+			".")
+		return
+	}
+
+	// Validate that the player in the seat has left
+	p := t.Players[d.SeatIndex]
+	if p.ID == s.UserID() {
+		s.Warning("You cannot replace yourself.")
+		return
+	}
+	if p.Present {
+		s.Warning("You can only replace a player who is not currently in the game.")
+		return
+	}
+
+	// Validate that the new user is not already playing in this game
+	if t.GetPlayerIndexFromID(d.NewUserID) != -1 {
+		s.Warning("That user is already playing in this game.")
+		return
+	}
+
+	// Validate that they have not been previously kicked from this game
+	if _, ok := t.KickedPlayers[d.NewUserID]; ok {
+		s.Warning("That user has been kicked from this game.")
+		return
+	}
+
+	// Validate that the new user is online
+	sessionsMutex.RLock()
+	s2, ok := sessions[d.NewUserID]
+	sessionsMutex.RUnlock()
+	if !ok {
+		s.Warning("That user is not online.")
+		return
+	}
+
+	// Validate that the new user is not busy with something else
+	if s2.Status() != StatusLobby &&
+		(s2.Status() != StatusSpectating || s2.TableID() != t.ID) {
+
+		s.Warning("That user must be in the lobby or spectating this game to be substituted in.")
+		return
+	}
+
+	tableReplacePlayer(s, t, d.SeatIndex, s2)
+}
+
+func tableReplacePlayer(s *Session, t *Table, seatIndex int, s2 *Session) {
+	// Local variables
+	g := t.Game
+	p := t.Players[seatIndex]
+	oldID := p.ID
+	oldName := p.Name
+
+	// If the new player was spectating, they will now see the game from the seat instead
+	if i := t.GetSpectatorIndexFromID(s2.UserID()); i != -1 {
+		t.Spectators = append(t.Spectators[:i], t.Spectators[i+1:]...)
+		t.NotifySpectators()
+	}
+
+	// The hand, notes, time, and so forth are kept on the GamePlayer object for the seat,
+	// so only the identity of the player needs to change
+	p.ID = s2.UserID()
+	p.Name = s2.Username()
+	p.Session = s2
+	p.Present = false // This will be set to true in the "getGameInfo2()" function
+	p.DatetimeAbsent = time.Now()
+	g.Players[seatIndex].Name = s2.Username()
+
+	logger.Info(t.GetName() + "User \"" + s2.Username() + "\" replaced \"" + oldName + "\" " +
+		"in seat " + strconv.Itoa(seatIndex) + ".")
+
+	// If the old player is still online, take them out of the game
+	sessionsMutex.RLock()
+	s3, ok := sessions[oldID]
+	sessionsMutex.RUnlock()
+	if ok && s3.TableID() == t.ID {
+		s3.Set("status", StatusLobby)
+		s3.Set("tableID", uint64(0))
+		notifyAllUser(s3)
+		s3.Warning("You have been replaced in your game at table " +
+			strconv.FormatUint(t.ID, 10) + ".")
+	}
+
+	// Make the client of the new player switch screens to show the game UI
+	s2.NotifyTableStart(t)
+	s2.Set("status", StatusPlaying)
+	s2.Set("tableID", t.ID)
+	notifyAllUser(s2)
+
+	notifyAllTable(t)
+	t.NotifyConnected()
+	msg := s.Username() + " replaced " + oldName + " with " + s2.Username() + "."
+	chatServerSend(msg, t.GetRoomName())
+}
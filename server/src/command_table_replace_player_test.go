@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+// newTestReplacement creates a 3-player game where it is Bob's turn, but Bob has left,
+// and connects a user in the lobby who can replace him
+func newTestReplacement(t *testing.T) (*Table, *Session, *websocket.Conn) {
+	tb := newTestGame(t, 3)
+	clueTestPlayer(t, tb)
+	tb.Players[1].Present = false
+
+	s, conn := newTestWebsocket(t, 10, "Dan", 0)
+	sessionsMutex.Lock()
+	sessions[s.UserID()] = s
+	sessionsMutex.Unlock()
+
+	return tb, s, conn
+}
+
+func replaceTestPlayer(tb *Table, s *Session, seatIndex int, newUserID int) {
+	commandTableReplacePlayer(s, &CommandData{
+		TableID:   tb.ID,
+		SeatIndex: seatIndex,
+		NewUserID: newUserID,
+		NoLock:    true,
+	})
+}
+
+func TestTableReplacePlayer(t *testing.T) {
+	resetTestTables(t)
+	tb, s, conn := newTestReplacement(t)
+	g := tb.Game
+	hand := g.Players[1].Hand
+
+	replaceTestPlayer(tb, tb.Players[0].Session, 1, s.UserID())
+
+	// The new player takes over the seat
+	if tb.GetPlayerIndexFromID(s.UserID()) != 1 || tb.GetPlayerIndexFromID(2) != -1 {
+		t.Fatal("the new player did not take over the seat")
+	}
+	if g.Players[1].Name != "Dan" || len(g.Players[1].Hand) != len(hand) {
+		t.Error("the game player for the seat was not updated")
+	}
+	for i, c := range g.Players[1].Hand {
+		if c != hand[i] {
+			t.Errorf("card %v of the hand changed", i)
+		}
+	}
+	readTestCommand(t, conn, "tableStart")
+	if s.Status() != StatusPlaying || s.TableID() != tb.ID {
+		t.Error("the status of the new player was not updated")
+	}
+
+	// The new player sees the game like the original player would
+	commandGetGameInfo2(s, &CommandData{
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+	var actionList struct {
+		List []map[string]interface{} `json:"list"`
+	}
+	data := readTestCommand(t, conn, "gameActionList")
+	if err := json.Unmarshal([]byte(data), &actionList); err != nil {
+		t.Fatal("failed to unmarshal the \"gameActionList\" message:", err)
+	}
+	numDraws := 0
+	for _, action := range actionList.List {
+		if action["type"] != "draw" {
+			continue
+		}
+		numDraws++
+		hidden := action["suitIndex"] == float64(-1) && action["rank"] == float64(-1)
+		if ownCard := action["playerIndex"] == float64(1); ownCard != hidden {
+			t.Errorf("the card visibility is wrong for a draw action: %v", action)
+		}
+	}
+	if numDraws == 0 {
+		t.Fatal("there were no draw actions")
+	}
+
+	// The new player has the turn of the seat
+	commandAction(s, &CommandData{
+		TableID: tb.ID,
+		Type:    ActionTypeColorClue,
+		Target:  0,
+		Value:   0,
+		NoLock:  true,
+	})
+	if g.InvalidActionOccurred || g.Turn != 2 || g.ActivePlayerIndex != 2 {
+		t.Error("the new player was not able to take the turn of the seat")
+	}
+}
+
+func TestTableReplacePlayerValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		seatIndex int
+		newUserID int
+		warning   string
+	}{
+		{"invalid seat", 3, 10, "The seat index must be between 0 and 2."},
+		{"present player", 2, 10, "You can only replace a player who is not currently"},
+		{"already playing", 1, 3, "That user is already playing in this game."},
+		{"offline", 1, 11, "That user is not online."},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resetTestTables(t)
+			tb, _, _ := newTestReplacement(t)
+			s, conn := newTestWebsocket(t, 1, "Alice", 0)
+			tb.Players[0].Session = s
+
+			replaceTestPlayer(tb, s, test.seatIndex, test.newUserID)
+
+			expectTestWarning(t, conn, test.warning)
+			if tb.GetPlayerIndexFromID(10) != -1 {
+				t.Error("the player was replaced")
+			}
+		})
+	}
+}
+
+func TestTableReplacePlayerNotOwner(t *testing.T) {
+	resetTestTables(t)
+	tb, _, _ := newTestReplacement(t)
+	s, conn := newTestWebsocket(t, 3, "Cathy", 0)
+	tb.Players[2].Session = s
+
+	replaceTestPlayer(tb, s, 1, 10)
+
+	expectTestWarning(t, conn, NotOwnerFail)
+	if tb.GetPlayerIndexFromID(10) != -1 {
+		t.Error("the player was replaced")
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	// By default, a player that is given an extension gets 60 extra seconds on their clock
+	DefaultExtensionSeconds = 60
+)
+
+var (
+	extensionBonus time.Duration
+)
+
+func extensionInit() {
+	extensionSeconds := DefaultExtensionSeconds
+	extensionSecondsString := os.Getenv("EXTENSION_SECONDS")
+	if len(extensionSecondsString) != 0 {
+		if v, err := strconv.Atoi(extensionSecondsString); err != nil {
+			logger.Fatal("Failed to convert the \"EXTENSION_SECONDS\" " +
+				"environment variable to a number.")
+			return
+		} else {
+			extensionSeconds = v
+		}
+	}
+	if extensionSeconds <= 0 {
+		logger.Fatal("The \"EXTENSION_SECONDS\" environment variable must be positive.")
+		return
+	}
+	extensionBonus = time.Duration(extensionSeconds) * time.Second
+}
+
+// commandTableRequestExtension is sent when a player in a timed game wants some extra time
+// The first player to send it makes the request and every other player must then send it to
+// approve the request (or send it with "decline" set to true to cancel the request)
+// Once everyone has approved, the extra time is added to the requester's clock
+//
+// Example data:
+// {
+//   tableID: 5,
+//   decline: false,
+// }
+func commandTableRequestExtension(s *Session, d *CommandData) {
+	t, exists := getTableAndLock(s, d.TableID, !d.NoLock)
+	if !exists {
+		return
+	}
+	if !d.NoLock {
+		defer t.Mutex.Unlock()
+	}
+	g := t.Game
+
+	// Validate that the game has started
+	if !t.Running {
+		s.WarningWithCode(ErrNotStarted, NotStartedFail)
+		return
+	}
+
+	// Validate that it is not a replay
+	if t.Replay {
+		s.Warning("You can not request an extension in a replay.")
+		return
+	}
+
+	// Validate that they are in the game
+	playerIndex := t.GetPlayerIndexFromID(s.UserID())
+	if playerIndex == -1 {
+		s.Warning("You are not at table " + strconv.FormatUint(t.ID, 10) + ", " +
+			"so you cannot request an extension.")
+		return
+	}
+	p := g.Players[playerIndex]
+
+	// Validate that it is a timed game
+	if !t.Options.Timed {
+		s.Warning("This is not a timed game, so you cannot request an extension.")
+		return
+	}
+
+	requester := g.GetExtensionRequester()
+	if requester == nil {
+		if d.Decline {
+			s.Warning("There is no extension request to decline.")
+			return
+		}
+
+		if p.UsedExtension {
+			s.Warning("You have already been given an extension in this game.")
+			return
+		}
+	} else if !d.Decline {
+		if p == requester {
+			s.Warning("You have already requested an extension.")
+			return
+		}
+
+		if p.ApprovedExtension {
+			s.Warning("You have already approved the extension request.")
+			return
+		}
+	}
+
+	tableRequestExtension(s, d, t, playerIndex)
+}
+
+func tableRequestExtension(s *Session, d *CommandData, t *Table, playerIndex int) {
+	// Local variables
+	g := t.Game
+	p := g.Players[playerIndex]
+
+	requester := g.GetExtensionRequester()
+	if d.Decline {
+		g.ClearExtensionRequest()
+		msg := s.Username() + " declined the extension request from " + requester.Name + "."
+		chatServerSend(msg, t.GetRoomName())
+		return
+	}
+
+	if requester == nil {
+		requester = p
+		p.RequestedExtension = true
+		msg := s.Username() + " requested " + strconv.Itoa(int(extensionBonus.Seconds())) +
+			" seconds of extra time. Every other player must approve the request."
+		chatServerSend(msg, t.GetRoomName())
+	} else {
+		p.ApprovedExtension = true
+		msg := s.Username() + " approved the extension request from " + requester.Name + "."
+		chatServerSend(msg, t.GetRoomName())
+	}
+
+	// Check to see if everyone else has approved the request
+	for _, p2 := range g.Players {
+		if p2 != requester && !p2.ApprovedExtension {
+			return
+		}
+	}
+
+	g.ClearExtensionRequest()
+	requester.UsedExtension = true
+	// If it is currently their turn, the existing "CheckTimer()" invocation will see that they
+	// have more time left once it wakes up and go back to sleep
+	// (we do not restart it by incrementing the pause count, since that would also stop the
+	// "CheckAway()" and "CheckDisconnectPause()" invocations for the current turn)
+	requester.Time += extensionBonus
+
+	msg := requester.Name + " was given " + strconv.Itoa(int(extensionBonus.Seconds())) +
+		" seconds of extra time."
+	chatServerSend(msg, t.GetRoomName())
+
+	// Send everyone new clock values
+	t.NotifyTime()
+}
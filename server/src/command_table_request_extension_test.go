@@ -0,0 +1,97 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func requestTestExtension(tb *Table, playerIndex int, decline bool) {
+	commandTableRequestExtension(tb.Players[playerIndex].Session, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		Decline: decline,
+		NoLock:  true,
+	})
+}
+
+func TestCommandTableRequestExtensionApproved(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTimedGame(t, 3)
+	g := tb.Game
+	gp := g.Players[0]
+	timeBefore := gp.Time
+	pauseCountBefore := g.PauseCount
+
+	requestTestExtension(tb, 0, false)
+	requestTestExtension(tb, 1, false)
+	if gp.Time != timeBefore {
+		t.Fatal("the extension was granted before every player approved it")
+	}
+
+	requestTestExtension(tb, 2, false)
+	if gp.Time != timeBefore+extensionBonus {
+		t.Errorf("expected the requester to have %v, but they have %v",
+			timeBefore+extensionBonus, gp.Time)
+	}
+	if !gp.UsedExtension || g.GetExtensionRequester() != nil {
+		t.Error("the extension request was not cleared after it was granted")
+	}
+
+	// The goroutines for the current turn (e.g. "CheckAway()") must keep running
+	if g.PauseCount != pauseCountBefore {
+		t.Error("the pause count was changed when the extension was granted")
+	}
+
+	// An extension can only be given once per player
+	requestTestExtension(tb, 0, false)
+	if g.GetExtensionRequester() != nil {
+		t.Error("a player was able to request a second extension")
+	}
+}
+
+func TestCommandTableRequestExtensionDeclined(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTimedGame(t, 3)
+	g := tb.Game
+	gp := g.Players[0]
+	timeBefore := gp.Time
+
+	requestTestExtension(tb, 0, false)
+	requestTestExtension(tb, 1, false)
+	requestTestExtension(tb, 2, true)
+	if g.GetExtensionRequester() != nil {
+		t.Fatal("the extension request was not canceled")
+	}
+	for i, p := range g.Players {
+		if p.ApprovedExtension {
+			t.Errorf("the approval of player %v was not reset", i)
+		}
+	}
+
+	// The next approval does not grant the canceled request
+	requestTestExtension(tb, 1, false)
+	if gp.Time != timeBefore || gp.UsedExtension {
+		t.Error("the requester was given extra time after the request was declined")
+	}
+}
+
+func TestCommandTableRequestExtensionTimer(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTimedGame(t, 2)
+	g := tb.Game
+	gp := g.Players[0]
+
+	// The active player is about to run out of time
+	tb.Mutex.Lock()
+	gp.Time = 200 * time.Millisecond
+	g.DatetimeTurnBegin = time.Now()
+	go g.CheckTimer(g.Turn, g.PauseCount, gp)
+	requestTestExtension(tb, 0, false)
+	requestTestExtension(tb, 1, false)
+	tb.Mutex.Unlock()
+
+	// Their turn is not taken for them at the original deadline
+	time.Sleep(400 * time.Millisecond)
+	if getTestTurn(tb) != 0 {
+		t.Error("the turn was taken for the player even though they were given an extension")
+	}
+}
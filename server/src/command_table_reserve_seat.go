@@ -0,0 +1,105 @@
+package main
+
+import (
+	"strconv"
+)
+
+// commandTableReserveSeat is sent when the owner of an unstarted table wants to save a seat for a
+// specific user (e.g. for organized games)
+// Other users cannot take a reserved seat, but the user that it is reserved for can join normally
+// Seating is randomized when the game starts, so a seat index only distinguishes one reservation
+// from another
+// A user ID of 0 removes the reservation for the seat
+//
+// Example data:
+// {
+//   tableID: 123,
+//   seatIndex: 2,
+//   userID: 5,
+// }
+func commandTableReserveSeat(s *Session, d *CommandData) {
+	t, exists := getTableAndLock(s, d.TableID, !d.NoLock)
+	if !exists {
+		return
+	}
+	if !d.NoLock {
+		defer t.Mutex.Unlock()
+	}
+
+	// Validate that they are the owner of the table
+	if s.UserID() != t.Owner {
+		s.WarningWithCode(ErrNotOwner, NotOwnerFail)
+		return
+	}
+
+	// Validate that the game has not started
+	if t.Running {
+		s.WarningWithCode(ErrStarted, StartedFail)
+		return
+	}
+
+	// Validate the seat index
+	if d.SeatIndex < 0 || d.SeatIndex >= MaxPlayers {
+		s.Warning("The seat index must be between 0 and " + strconv.Itoa(MaxPlayers-1) + ".")
+		return
+	}
+
+	if d.UserID == 0 {
+		if _, ok := t.ReservedSeats[d.SeatIndex]; !ok {
+			s.Warning("That seat is not reserved.")
+			return
+		}
+	} else {
+		// Validate that the user is not already at the table
+		if t.GetPlayerIndexFromID(d.UserID) != -1 {
+			s.Warning("That user is already joined to this table.")
+			return
+		}
+
+		// Validate that the user does not already have a reserved seat
+		for seatIndex, userID := range t.ReservedSeats {
+			if userID == d.UserID && seatIndex != d.SeatIndex {
+				s.Warning("That user already has a reserved seat.")
+				return
+			}
+		}
+
+		// Validate that there is room for everyone
+		if _, ok := t.ReservedSeats[d.SeatIndex]; !ok &&
+			len(t.Players)+t.GetNumReservedSeats(-1) >= MaxPlayers {
+
+			s.Warning("There are no free seats left at this table.")
+			return
+		}
+
+		// Validate that they have not been previously kicked from this game
+		if _, ok := t.KickedPlayers[d.UserID]; ok {
+			s.Warning("You cannot reserve a seat for a user that has been kicked from this game.")
+			return
+		}
+	}
+
+	tableReserveSeat(s, d, t)
+}
+
+func tableReserveSeat(s *Session, d *CommandData, t *Table) {
+	if d.UserID == 0 {
+		delete(t.ReservedSeats, d.SeatIndex)
+		msg := s.Username() + " removed the reservation on seat " + strconv.Itoa(d.SeatIndex) + "."
+		chatServerSend(msg, t.GetRoomName())
+		return
+	}
+
+	var username string
+	if v, err := models.Users.GetUsername(d.UserID); err != nil {
+		// This is most likely caused by the user ID not existing in the database
+		s.Warning("That user does not exist.")
+		return
+	} else {
+		username = v
+	}
+
+	t.ReservedSeats[d.SeatIndex] = d.UserID
+	msg := s.Username() + " reserved seat " + strconv.Itoa(d.SeatIndex) + " for " + username + "."
+	chatServerSend(msg, t.GetRoomName())
+}
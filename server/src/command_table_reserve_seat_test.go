@@ -0,0 +1,155 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestTableReserveSeatRejectsOthers(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTable(t, MaxPlayers-1)
+	tb.ReservedSeats[MaxPlayers-1] = 10
+
+	// The last seat is reserved for someone else
+	s, conn := newTestWebsocket(t, 11, "Eve", 0)
+	commandTableJoin(s, &CommandData{
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+
+	expectTestWarning(t, conn, "reserved for other players")
+	if len(tb.Players) != MaxPlayers-1 {
+		t.Errorf("expected %v players, but got %v", MaxPlayers-1, len(tb.Players))
+	}
+}
+
+func TestTableReserveSeatAcceptsReservedUser(t *testing.T) {
+	if db == nil {
+		t.Skip("joining a table requires a database")
+	}
+	resetTestTables(t)
+	tb := newTestTable(t, MaxPlayers-1)
+	tb.ReservedSeats[MaxPlayers-1] = 10
+
+	s, _ := newTestWebsocket(t, 10, "Dan", 0)
+	commandTableJoin(s, &CommandData{
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+
+	if tb.GetPlayerIndexFromID(10) == -1 {
+		t.Error("the user with the reserved seat was not able to join")
+	}
+}
+
+func TestGetNumReservedSeats(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTable(t, 2)
+	tb.ReservedSeats[2] = 10
+	tb.ReservedSeats[3] = 11
+
+	// The seat of the user who is joining is not counted against them
+	if v := tb.GetNumReservedSeats(10); v != 1 {
+		t.Errorf("expected 1 reserved seat for the reserved user, but got %v", v)
+	}
+	if v := tb.GetNumReservedSeats(12); v != 2 {
+		t.Errorf("expected 2 reserved seats for another user, but got %v", v)
+	}
+	if !tb.IsSeatReservedFor(10) || tb.IsSeatReservedFor(12) {
+		t.Error("\"IsSeatReservedFor()\" returned the wrong result")
+	}
+
+	// Claimed seats are no longer counted
+	tb.Players = append(tb.Players, newTestPlayer(10, "Dan"))
+	if v := tb.GetNumReservedSeats(12); v != 1 {
+		t.Errorf("expected 1 reserved seat after a claim, but got %v", v)
+	}
+}
+
+func TestTableReserveSeatClearedOnStart(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTable(t, 2)
+	tb.ReservedSeats[2] = 10
+
+	startTestGame(t, tb)
+
+	if len(tb.ReservedSeats) != 0 {
+		t.Errorf("expected the reservations to be cleared, but got %v", tb.ReservedSeats)
+	}
+}
+
+func TestTableReserveSeatValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		userID    int
+		seatIndex int
+		reserveID int
+		started   bool
+		warning   string
+	}{
+		{"not the owner", 2, 2, 10, false, NotOwnerFail},
+		{"started", 1, 2, 10, true, StartedFail},
+		{"invalid seat", 1, MaxPlayers, 10, false, "The seat index must be between"},
+		{"already joined", 1, 2, 2, false, "already joined to this table"},
+		{"not reserved", 1, 2, 0, false, "That seat is not reserved."},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resetTestTables(t)
+			tb := newTestTable(t, 2)
+			if test.started {
+				startTestGame(t, tb)
+			}
+
+			s, conn := newTestWebsocket(t, test.userID, testPlayerNames[test.userID-1], 0)
+			commandTableReserveSeat(s, &CommandData{
+				TableID:   tb.ID,
+				SeatIndex: test.seatIndex,
+				UserID:    test.reserveID,
+				NoLock:    true,
+			})
+
+			expectTestWarning(t, conn, test.warning)
+			if len(tb.ReservedSeats) != 0 {
+				t.Errorf("a seat was reserved: %v", tb.ReservedSeats)
+			}
+		})
+	}
+}
+
+func TestTableReserveSeatNoFreeSeats(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTable(t, MaxPlayers-1)
+	tb.ReservedSeats[MaxPlayers-1] = 10
+
+	s, conn := newTestWebsocket(t, 1, "Alice", 0)
+	commandTableReserveSeat(s, &CommandData{
+		TableID:   tb.ID,
+		SeatIndex: 0,
+		UserID:    11,
+		NoLock:    true,
+	})
+
+	expectTestWarning(t, conn, "There are no free seats left at this table.")
+	if len(tb.ReservedSeats) != 1 {
+		t.Errorf("expected 1 reserved seat, but got %v", len(tb.ReservedSeats))
+	}
+}
+
+func TestTableReserveSeatRemove(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTable(t, 2)
+	tb.ReservedSeats[2] = 10
+
+	s, _ := newTestWebsocket(t, 1, "Alice", 0)
+	commandTableReserveSeat(s, &CommandData{
+		TableID:   tb.ID,
+		SeatIndex: 2,
+		UserID:    0,
+		NoLock:    true,
+	})
+
+	if len(tb.ReservedSeats) != 0 {
+		t.Errorf("expected the reservation to be removed, but got %v", tb.ReservedSeats)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"strconv"
+)
+
+// commandTableReviewSeek is sent when the owner of a table in a shared review changes the turn that
+// everyone is looking at
+//
+// Example data:
+// {
+//   tableID: 5,
+//   turn: 3,
+// }
+func commandTableReviewSeek(s *Session, d *CommandData) {
+	t, exists := getTableAndLock(s, d.TableID, !d.NoLock)
+	if !exists {
+		return
+	}
+	if !d.NoLock {
+		defer t.Mutex.Unlock()
+	}
+	g := t.Game
+
+	// Validate that the game has started
+	if !t.Running {
+		s.WarningWithCode(ErrNotStarted, NotStartedFail)
+		return
+	}
+
+	// Validate that they are the owner of the table
+	if s.UserID() != t.Owner {
+		s.WarningWithCode(ErrNotOwner, NotOwnerFail)
+		return
+	}
+
+	// Validate that the table is reviewing
+	if !g.Reviewing {
+		s.Warning("The table is not in a shared review.")
+		return
+	}
+
+	// Validate the turn
+	// (only the turns that have already happened can be reviewed)
+	if d.Turn < 0 || d.Turn > g.Turn {
+		s.Warning("The turn must be between 0 and " + strconv.Itoa(g.Turn) + ".")
+		return
+	}
+
+	g.ReviewTurn = d.Turn
+	t.NotifySharedReview()
+}
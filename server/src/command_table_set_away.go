@@ -0,0 +1,183 @@
+package main
+
+import (
+	"strconv"
+	"time"
+)
+
+var (
+	// The amount of time that the turn of an away player lasts before it is taken for them
+	awayActionDelay = time.Second * 10
+)
+
+// commandTableSetAway is sent when a player in an ongoing game knows that they will be away for a
+// little while
+// While they are away, their oldest unclued card is automatically discarded on their turn
+// (or a clue is given to the next player if the team is at the maximum amount of clues),
+// so that the game keeps moving without their whole time bank being used up
+// Only the player themselves can mark that they are back
+//
+// Example data:
+// {
+//   tableID: 5,
+//   away: true,
+// }
+func commandTableSetAway(s *Session, d *CommandData) {
+	t, exists := getTableAndLock(s, d.TableID, !d.NoLock)
+	if !exists {
+		return
+	}
+	if !d.NoLock {
+		defer t.Mutex.Unlock()
+	}
+
+	// Validate that the game has started
+	if !t.Running {
+		s.WarningWithCode(ErrNotStarted, NotStartedFail)
+		return
+	}
+
+	// Validate that it is not a replay
+	if t.Replay {
+		s.Warning("You cannot be away in a replay.")
+		return
+	}
+
+	// Validate that they are in the game
+	playerIndex := t.GetPlayerIndexFromID(s.UserID())
+	if playerIndex == -1 {
+		s.Warning("You are not playing at table " + strconv.FormatUint(t.ID, 10) + ", " +
+			"so you cannot set yourself as away.")
+		return
+	}
+
+	// Validate that their status is changing
+	gp := t.Game.Players[playerIndex]
+	if gp.Away == d.Away {
+		if d.Away {
+			s.Warning("You are already marked as away.")
+		} else {
+			s.Warning("You are not marked as away.")
+		}
+		return
+	}
+
+	tableSetAway(s, d, t, gp)
+}
+
+func tableSetAway(s *Session, d *CommandData, t *Table, gp *GamePlayer) {
+	// Local variables
+	g := t.Game
+
+	gp.Away = d.Away
+
+	var msg string
+	if gp.Away {
+		msg = gp.Name + " is away, so their turns will be taken for them automatically."
+	} else {
+		msg = gp.Name + " is back."
+	}
+	chatServerSend(msg, t.GetRoomName())
+
+	// If it is already their turn, we need to start the delay now
+	if gp.Away && g.ActivePlayerIndex == gp.Index {
+		go g.CheckAway(g.Turn, g.PauseCount, gp)
+	}
+}
+
+// CheckAwayAfterResume restarts the "CheckAway()" function for the active player once the game is
+// no longer paused or reviewing (the existing invocation gives up as soon as it sees either one)
+func (g *Game) CheckAwayAfterResume() {
+	if g.Paused || g.Reviewing || g.EndCondition > EndConditionInProgress {
+		return
+	}
+
+	gp := g.Players[g.ActivePlayerIndex]
+	if gp.Away {
+		go g.CheckAway(g.Turn, g.PauseCount, gp)
+	}
+}
+
+// CheckAway is meant to be called in a new goroutine
+// It takes the turn for a player who is away if they are still away once the delay has elapsed
+func (g *Game) CheckAway(turn int, pauseCount int, gp *GamePlayer) {
+	// Local variables
+	t := g.Table
+
+	time.Sleep(awayActionDelay)
+
+	// Check to see if the table still exists
+	t2, exists := getTableAndLock(nil, t.ID, false)
+	if !exists || t != t2 {
+		return
+	}
+	t.Mutex.Lock()
+	defer t.Mutex.Unlock()
+
+	// Check to see if we have made a move in the meanwhile or if the game was paused
+	if turn != g.Turn || g.Paused || pauseCount != g.PauseCount || g.Reviewing {
+		return
+	}
+
+	// Check to see if the game ended already
+	if g.EndCondition > EndConditionInProgress {
+		return
+	}
+
+	// Check to see if the player came back in the meantime
+	if !gp.Away || len(gp.Hand) == 0 {
+		return
+	}
+
+	// Get the session of this player
+	p := t.Players[gp.Index]
+	s := p.Session
+	if s == nil {
+		// A player's session should never be nil
+		// They might be in the process of reconnecting,
+		// so make a fake session that will represent them
+		s = newFakeSession(p.ID, p.Name)
+		logger.Info("Created a new fake session in the \"CheckAway()\" function.")
+	}
+
+	d := g.GetAutomaticAction(gp, gp.GetChopIndex())
+	if d == nil {
+		return
+	}
+
+	logger.Info(t.GetName() + "Taking the turn for away player \"" + gp.Name + "\".")
+	commandAction(s, d)
+}
+
+// GetAutomaticAction returns the action to take on behalf of a player who is not taking their
+// own turn, which is a discard of the card at the given index of their hand
+// (or nil if there is no action that can be taken for them)
+// The table mutex must be held when calling this function
+func (g *Game) GetAutomaticAction(gp *GamePlayer, discardIndex int) *CommandData {
+	// Local variables
+	t := g.Table
+	variant := variants[g.Options.VariantName]
+
+	d := &CommandData{ // Manual invocation
+		TableID: t.ID,
+		NoLock:  true,
+	}
+	if variant.AtMaxClueTokens(g.ClueTokens) {
+		// Discarding is not allowed, so clue the rank of the newest card of the next player
+		nextPlayer := g.Players[(gp.Index+1)%len(g.Players)]
+		if len(nextPlayer.Hand) == 0 {
+			return nil
+		}
+		d.Type = ActionTypeRankClue
+		d.Target = nextPlayer.Index
+		d.Value = nextPlayer.Hand[len(nextPlayer.Hand)-1].Rank
+	} else {
+		if discardIndex < 0 || discardIndex >= len(gp.Hand) {
+			return nil
+		}
+		d.Type = ActionTypeDiscard
+		d.Target = gp.Hand[discardIndex].Order
+	}
+
+	return d
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func useTestAwayActionDelay(t *testing.T, delay time.Duration) {
+	oldAwayActionDelay := awayActionDelay
+	awayActionDelay = delay
+	t.Cleanup(func() {
+		awayActionDelay = oldAwayActionDelay
+	})
+}
+
+func setTestAway(tb *Table, playerIndex int, away bool) {
+	commandTableSetAway(tb.Players[playerIndex].Session, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		Away:    away,
+	})
+}
+
+// waitForTestTurn waits for the game to reach the given turn
+// (the turns of away players are taken in a separate goroutine)
+func waitForTestTurn(t *testing.T, tb *Table, turn int) {
+	deadline := time.Now().Add(time.Second)
+	for {
+		tb.Mutex.Lock()
+		currentTurn := tb.Game.Turn
+		tb.Mutex.Unlock()
+		if currentTurn == turn {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the game to reach turn %v, but it is on turn %v", turn, currentTurn)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestCommandTableSetAwayTakesTurns(t *testing.T) {
+	resetTestTables(t)
+	useTestAwayActionDelay(t, 20*time.Millisecond)
+	tb := newTestGame(t, 2)
+	g := tb.Game
+
+	// It is already their turn, so it is taken for them once the delay has elapsed
+	// (discarding is not allowed at the maximum amount of clues, so they give a clue instead)
+	setTestAway(tb, 0, true)
+	if !g.Players[0].Away {
+		t.Fatal("the player was not marked as away")
+	}
+	waitForTestTurn(t, tb, 1)
+	tb.Mutex.Lock()
+	if g.ClueTokens != MaxClueNum-1 {
+		t.Errorf("expected a clue to be given for the away player, but there are %v clues",
+			g.ClueTokens)
+	}
+
+	// When their turn comes around again, their oldest unclued card is discarded
+	clueTestPlayer(t, tb)
+	gp := g.Players[0]
+	chopOrder := gp.Hand[gp.GetChopIndex()].Order
+	tb.Mutex.Unlock()
+	waitForTestTurn(t, tb, 3)
+
+	tb.Mutex.Lock()
+	defer tb.Mutex.Unlock()
+	for _, c := range gp.Hand {
+		if c.Order == chopOrder {
+			t.Error("the oldest unclued card of the away player was not discarded")
+		}
+	}
+	if g.ClueTokens != MaxClueNum-1 {
+		t.Errorf("expected %v clues after the discard, but there are %v",
+			MaxClueNum-1, g.ClueTokens)
+	}
+	gp.Away = false
+}
+
+func TestCommandTableSetAwayCleared(t *testing.T) {
+	resetTestTables(t)
+	useTestAwayActionDelay(t, 20*time.Millisecond)
+	tb := newTestGame(t, 2)
+	g := tb.Game
+
+	setTestAway(tb, 0, true)
+
+	// Other players cannot mark them as back
+	setTestAway(tb, 1, false)
+	if !g.Players[0].Away {
+		t.Fatal("another player was able to clear the away status")
+	}
+
+	// Once they are back, their turn is no longer taken for them
+	setTestAway(tb, 0, false)
+	if g.Players[0].Away {
+		t.Fatal("the away status was not cleared")
+	}
+	time.Sleep(60 * time.Millisecond)
+	tb.Mutex.Lock()
+	defer tb.Mutex.Unlock()
+	if g.Turn != 0 {
+		t.Errorf("the turn was taken for a player who is back (the game is on turn %v)", g.Turn)
+	}
+}
+
+func TestCommandTableSetAwayNotPlaying(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+	s, conn := newTestWebsocket(t, 10, "Zed", 0)
+
+	commandTableSetAway(s, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		Away:    true,
+	})
+	expectTestWarning(t, conn, "You are not playing at table")
+
+	// Setting the same status twice is not allowed
+	s2, conn2 := newTestWebsocket(t, 1, "Alice", 0)
+	tb.Players[0].Session = s2
+	commandTableSetAway(s2, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		Away:    false,
+	})
+	expectTestWarning(t, conn2, "You are not marked as away.")
+}
+
+func TestCommandTableSetAwayAfterSharedReview(t *testing.T) {
+	resetTestTables(t)
+	useTestAwayActionDelay(t, 20*time.Millisecond)
+	tb, _ := newTestSharedReview(t, false)
+
+	// Their turn is not taken during the review
+	setTestAway(tb, 0, true)
+	time.Sleep(100 * time.Millisecond)
+	if turn := getTestTurn(tb); turn != 2 {
+		t.Fatalf("expected the game to stay on turn 2 during the review, but it is on turn %v", turn)
+	}
+
+	commandTableExitSharedReview(tb.Players[0].Session, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+	})
+	waitForTestTurn(t, tb, 3)
+
+	tb.Mutex.Lock()
+	tb.Game.Players[0].Away = false
+	tb.Mutex.Unlock()
+}
+
+func TestCommandTableSetAwayAfterUnpause(t *testing.T) {
+	resetTestTables(t)
+	useTestAwayActionDelay(t, 20*time.Millisecond)
+	tb := newTestTimedGame(t, 2)
+
+	pauseTestGame := func(setting string) {
+		commandPause(tb.Players[0].Session, &CommandData{ // Manual invocation
+			TableID: tb.ID,
+			Setting: setting,
+		})
+	}
+
+	// Their turn is not taken while the game is paused
+	pauseTestGame("pause")
+	setTestAway(tb, 0, true)
+	time.Sleep(100 * time.Millisecond)
+	if turn := getTestTurn(tb); turn != 0 {
+		t.Fatalf("expected the game to stay on turn 0 while paused, but it is on turn %v", turn)
+	}
+
+	pauseTestGame("unpause")
+	waitForTestTurn(t, tb, 1)
+
+	tb.Mutex.Lock()
+	tb.Game.Players[0].Away = false
+	tb.Mutex.Unlock()
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+)
+
+// commandTableSetName is sent when the owner of a table wants to rename it
+// (e.g. so that a table for a specific event can be found in the lobby)
+//
+// Example data:
+// {
+//   tableID: 123,
+//   name: 'Coaching session - beginners welcome',
+// }
+func commandTableSetName(s *Session, d *CommandData) {
+	t, exists := getTableAndLock(s, d.TableID, !d.NoLock)
+	if !exists {
+		return
+	}
+	if !d.NoLock {
+		defer t.Mutex.Unlock()
+	}
+
+	// Validate that they are the owner of the table
+	if s.UserID() != t.Owner {
+		s.WarningWithCode(ErrNotOwner, NotOwnerFail)
+		return
+	}
+
+	// Validate that the name is not too long
+	// (we do this first to prevent wasting CPU cycles on validating extremely long table names)
+	if len(d.Name) > MaxGameNameLength {
+		s.Warning("Game names cannot be longer than " + strconv.Itoa(MaxGameNameLength) +
+			" characters.")
+		return
+	}
+
+	// Remove any non-printable characters, if any
+	d.Name = removeNonPrintableCharacters(d.Name)
+
+	// Trim whitespace from both sides
+	d.Name = strings.TrimSpace(d.Name)
+
+	// Validate that the name is not empty
+	if len(d.Name) == 0 {
+		s.Warning("You must specify a name for the table.")
+		return
+	}
+
+	// Special prefixes are only parsed when a table is created,
+	// so a renamed table should not look like it has one
+	if strings.HasPrefix(d.Name, "!") {
+		s.Warning("You cannot rename a table to a name that starts with an exclamation mark.")
+		return
+	}
+
+	// Validate that the game name does not contain any illegal characters
+	if !validateTableName(s, d.Name) {
+		return
+	}
+
+	tableSetName(s, d, t)
+}
+
+func tableSetName(s *Session, d *CommandData, t *Table) {
+	t.Name = d.Name
+
+	// Update the name in the lobby and for the people in the pre-game
+	notifyAllTable(t)
+	if !t.Running {
+		t.NotifyPlayerChange()
+	}
+
+	msg := s.Username() + " renamed the table to: " + t.Name
+	chatServerSend(msg, t.GetRoomName())
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func setTestTableName(tb *Table, s *Session, name string) {
+	commandTableSetName(s, &CommandData{
+		TableID: tb.ID,
+		Name:    name,
+		NoLock:  true,
+	})
+}
+
+func TestTableSetName(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTable(t, 2)
+
+	// Control characters and surrounding whitespace are removed
+	setTestTableName(tb, tb.Players[0].Session, "  Coaching session - beginners welcome\x07 ")
+
+	if tb.Name != "Coaching session - beginners welcome" {
+		t.Errorf("expected the table to be renamed, but it is named \"%v\"", tb.Name)
+	}
+}
+
+func TestTableSetNameValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		userID  int
+		newName string
+		warning string
+	}{
+		{"not the owner", 2, "New Name", NotOwnerFail},
+		{"too long", 1, strings.Repeat("a", MaxGameNameLength+1), "cannot be longer than"},
+		{"empty", 1, " \x07 ", "You must specify a name for the table."},
+		{"special prefix", 1, "!seed 5", "starts with an exclamation mark"},
+		{"illegal characters", 1, "<script>", "Game names can only contain"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resetTestTables(t)
+			tb := newTestTable(t, 2)
+
+			s, conn := newTestWebsocket(t, test.userID, testPlayerNames[test.userID-1], 0)
+			setTestTableName(tb, s, test.newName)
+
+			expectTestWarning(t, conn, test.warning)
+			if tb.Name != "Test Table" {
+				t.Errorf("the table was renamed to \"%v\"", tb.Name)
+			}
+		})
+	}
+}
+
+func TestTableSetNameNotifiesLobby(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTable(t, 2)
+	s, conn := newTestWebsocket(t, 10, "Dan", 0)
+	sessionsMutex.Lock()
+	sessions[s.UserID()] = s
+	sessionsMutex.Unlock()
+
+	setTestTableName(tb, tb.Players[0].Session, "Event Table")
+
+	// The lobby is sent the new name
+	var table TableMessage
+	if err := json.Unmarshal([]byte(readTestCommand(t, conn, "table")), &table); err != nil {
+		t.Fatal("failed to unmarshal the \"table\" message:", err)
+	}
+	if table.ID != tb.ID || table.Name != "Event Table" {
+		t.Errorf("expected the lobby to be sent table %v with the new name, but got %+v",
+			tb.ID, table)
+	}
+
+	// The new name is also in the list of tables that is sent upon connecting
+	websocketConnectTableList(s)
+	var tableList []*TableMessage
+	if err := json.Unmarshal([]byte(readTestCommand(t, conn, "tableList")), &tableList); err != nil {
+		t.Fatal("failed to unmarshal the \"tableList\" message:", err)
+	}
+	if len(tableList) != 1 || tableList[0].Name != "Event Table" {
+		t.Errorf("expected the table list to have the new name, but got %+v", tableList)
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+// commandTableSetReady is sent when a player in an unstarted table marks themselves as ready or
+// not ready to start the game
+// If the owner of the table sends "autoStart", the game will automatically start once every player
+// is ready
+// Everyone is marked as not ready whenever a player joins or leaves
+//
+// Example data:
+// {
+//   tableID: 123,
+//   ready: true,
+//   // Optional; only used if sent by the owner of the table
+//   autoStart: true,
+// }
+func commandTableSetReady(s *Session, d *CommandData) {
+	t, exists := getTableAndLock(s, d.TableID, !d.NoLock)
+	if !exists {
+		return
+	}
+	if !d.NoLock {
+		defer t.Mutex.Unlock()
+	}
+
+	// Validate that the game has not started
+	if t.Running {
+		s.WarningWithCode(ErrStarted, StartedFail)
+		return
+	}
+
+	// Validate that it is not a replay
+	if t.Replay {
+		s.Warning("You can not mark yourself as ready in a replay.")
+		return
+	}
+
+	// Validate that they are at the table
+	playerIndex := t.GetPlayerIndexFromID(s.UserID())
+	if playerIndex == -1 {
+		s.Warning("You are not playing at this table, so you cannot mark yourself as ready.")
+		return
+	}
+
+	tableSetReady(s, d, t, playerIndex)
+}
+
+func tableSetReady(s *Session, d *CommandData, t *Table, playerIndex int) {
+	p := t.Players[playerIndex]
+
+	if s.UserID() == t.Owner && d.AutoStart != t.AutoStartWhenReady {
+		t.AutoStartWhenReady = d.AutoStart
+		var msg string
+		if t.AutoStartWhenReady {
+			msg = "The game will automatically start once everyone is ready."
+		} else {
+			msg = "The game will no longer automatically start once everyone is ready."
+		}
+		chatServerSend(msg, t.GetRoomName())
+	}
+
+	if p.Ready != d.Ready {
+		p.Ready = d.Ready
+		var msg string
+		if p.Ready {
+			msg = p.Name + " is ready."
+		} else {
+			msg = p.Name + " is not ready."
+		}
+		chatServerSend(msg, t.GetRoomName())
+		t.NotifyPlayerChange()
+	}
+
+	if !t.AutoStartWhenReady || len(t.Players) < 2 {
+		return
+	}
+	for _, p2 := range t.Players {
+		if !p2.Ready {
+			return
+		}
+	}
+
+	// Everyone is ready, so start the game on behalf of the owner
+	for _, p2 := range t.Players {
+		if p2.ID == t.Owner {
+			if !p2.Present {
+				msg := "Aborting automatic game start since the table creator is away."
+				chatServerSend(msg, t.GetRoomName())
+				return
+			}
+
+			commandTableStart(p2.Session, &CommandData{ // Manual invocation
+				TableID: t.ID,
+				NoLock:  true,
+			})
+			return
+		}
+	}
+
+	logger.Error("Failed to find the owner of the game when attempting to automatically start it.")
+}
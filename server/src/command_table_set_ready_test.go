@@ -0,0 +1,145 @@
+package main
+
+import (
+	"testing"
+)
+
+func setTestReady(tb *Table, playerIndex int, ready bool, autoStart bool) {
+	commandTableSetReady(tb.Players[playerIndex].Session, &CommandData{ // Manual invocation
+		TableID:   tb.ID,
+		Ready:     ready,
+		AutoStart: autoStart,
+		NoLock:    true,
+	})
+}
+
+func TestTableSetReadyAutoStart(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTable(t, 3)
+
+	// The owner enables the automatic start along with marking themselves as ready
+	setTestReady(tb, 0, true, true)
+	if !tb.AutoStartWhenReady || !tb.Players[0].Ready {
+		t.Fatal("the owner was not marked as ready with the automatic start enabled")
+	}
+	setTestReady(tb, 1, true, false)
+	if tb.Running {
+		t.Fatal("the game started before everyone was ready")
+	}
+
+	// Other players cannot disable the automatic start
+	if !tb.AutoStartWhenReady {
+		t.Fatal("another player disabled the automatic start")
+	}
+
+	setTestReady(tb, 2, true, false)
+	if !tb.Running {
+		t.Error("the game did not start once everyone was ready")
+	}
+}
+
+func TestTableSetReadyNotReadyPlayer(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTable(t, 3)
+
+	setTestReady(tb, 0, true, true)
+	setTestReady(tb, 1, true, false)
+	setTestReady(tb, 1, false, false)
+	if tb.Players[1].Ready {
+		t.Fatal("the player was not marked as not ready")
+	}
+
+	// A single player who is not ready blocks the automatic start
+	setTestReady(tb, 2, true, false)
+	if tb.Running {
+		t.Error("the game started while a player was not ready")
+	}
+
+	// Without the automatic start, the game does not start even if everyone is ready
+	setTestReady(tb, 0, true, false)
+	setTestReady(tb, 1, true, false)
+	if tb.AutoStartWhenReady || tb.Running {
+		t.Error("the game started with the automatic start disabled")
+	}
+}
+
+func TestTableSetReadyResetOnLeave(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTable(t, 3)
+	setTestReady(tb, 0, true, true)
+	setTestReady(tb, 1, true, false)
+
+	commandTableLeave(tb.Players[2].Session, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+	for _, p := range tb.Players {
+		if p.Ready {
+			t.Errorf("%v is still ready after a player left", p.Name)
+		}
+	}
+
+	// Everyone has to mark themselves as ready again
+	setTestReady(tb, 1, true, false)
+	if tb.Running {
+		t.Error("the game started without the owner being ready again")
+	}
+}
+
+func TestTableSetReadyResetOnJoin(t *testing.T) {
+	if db == nil {
+		t.Skip("joining a table requires a database")
+	}
+	resetTestTables(t)
+	tb := newTestTable(t, 2)
+	setTestReady(tb, 0, true, true)
+
+	s, _ := newTestWebsocket(t, 10, "Dan", 0)
+	commandTableJoin(s, &CommandData{
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+	if tb.GetPlayerIndexFromID(10) == -1 {
+		t.Fatal("the user was not able to join")
+	}
+	if tb.Players[0].Ready {
+		t.Error("the owner is still ready after a player joined")
+	}
+}
+
+func TestTableResetReady(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTable(t, 3)
+
+	if tb.ResetReady() {
+		t.Error("\"ResetReady()\" returned true when no one was ready")
+	}
+	tb.Players[1].Ready = true
+	if !tb.ResetReady() {
+		t.Error("\"ResetReady()\" returned false when a player was ready")
+	}
+	if tb.Players[1].Ready {
+		t.Error("the player is still ready")
+	}
+}
+
+func TestTableSetReadyValidation(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+	s, conn := newTestWebsocket(t, 1, "Alice", 0)
+	commandTableSetReady(s, &CommandData{
+		TableID: tb.ID,
+		Ready:   true,
+		NoLock:  true,
+	})
+	expectTestWarning(t, conn, StartedFail)
+
+	tb2 := newTestTable(t, 2)
+	s2, conn2 := newTestWebsocket(t, 10, "Dan", 0)
+	commandTableSetReady(s2, &CommandData{
+		TableID: tb2.ID,
+		Ready:   true,
+		NoLock:  true,
+	})
+	expectTestWarning(t, conn2, "You are not playing at this table")
+}
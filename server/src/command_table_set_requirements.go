@@ -0,0 +1,86 @@
+package main
+
+import (
+	"strconv"
+)
+
+// commandTableSetRequirements is sent when the owner of an unstarted table wants to restrict the
+// table to experienced players
+// "minGames" is the minimum number of non-speedrun games that a user must have played and
+// "minRating" is the minimum average score that a user must have in the variant of the table
+// A value of 0 removes the respective requirement
+// The owner and users with a reserved seat are not subject to the requirements
+// The requirements are cleared when the game starts
+//
+// Example data:
+// {
+//   tableID: 123,
+//   minGames: 100,
+//   minRating: 22.5,
+// }
+func commandTableSetRequirements(s *Session, d *CommandData) {
+	t, exists := getTableAndLock(s, d.TableID, !d.NoLock)
+	if !exists {
+		return
+	}
+	if !d.NoLock {
+		defer t.Mutex.Unlock()
+	}
+
+	// Validate that they are the owner of the table
+	if s.UserID() != t.Owner {
+		s.WarningWithCode(ErrNotOwner, NotOwnerFail)
+		return
+	}
+
+	// Validate that the game has not started
+	if t.Running {
+		s.WarningWithCode(ErrStarted, StartedFail)
+		return
+	}
+
+	// Validate that it is not a replay
+	if t.Replay {
+		s.Warning("You can not set requirements for a replay.")
+		return
+	}
+
+	// Validate the requirements
+	if d.MinGames < 0 {
+		s.Warning("The minimum number of games cannot be negative.")
+		return
+	}
+	if d.MinRating < 0 {
+		s.Warning("The minimum rating cannot be negative.")
+		return
+	}
+
+	tableSetRequirements(s, d, t)
+}
+
+func tableSetRequirements(s *Session, d *CommandData, t *Table) {
+	t.MinGames = d.MinGames
+	t.MinRating = d.MinRating
+
+	var msg string
+	if t.MinGames == 0 && t.MinRating == 0 {
+		msg = s.Username() + " removed the requirements for joining this table."
+	} else {
+		msg = s.Username() + " set the requirements for joining this table to"
+		if t.MinGames > 0 {
+			msg += " at least " + strconv.Itoa(t.MinGames) + " games played"
+			if t.MinRating > 0 {
+				msg += " and"
+			}
+		}
+		if t.MinRating > 0 {
+			msg += " an average score of at least " + formatRating(t.MinRating)
+		}
+		msg += "."
+	}
+	chatServerSend(msg, t.GetRoomName())
+}
+
+func formatRating(rating float64) string {
+	return strconv.FormatFloat(rating, 'f', 2, 64)
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestTableSetRequirements(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTable(t, 2)
+	s, _ := newTestWebsocket(t, 1, "Alice", 0)
+
+	commandTableSetRequirements(s, &CommandData{
+		TableID:   tb.ID,
+		MinGames:  100,
+		MinRating: 22.5,
+		NoLock:    true,
+	})
+	if tb.MinGames != 100 || tb.MinRating != 22.5 {
+		t.Fatalf("expected the requirements to be 100 games and a rating of 22.5, but got %v "+
+			"games and a rating of %v", tb.MinGames, tb.MinRating)
+	}
+
+	// The requirements only apply before the game starts
+	startTestGame(t, tb)
+	if tb.MinGames != 0 || tb.MinRating != 0 {
+		t.Errorf("expected the requirements to be cleared, but got %v games and a rating of %v",
+			tb.MinGames, tb.MinRating)
+	}
+}
+
+func TestTableSetRequirementsValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		userID    int
+		minGames  int
+		minRating float64
+		started   bool
+		warning   string
+	}{
+		{"not the owner", 2, 100, 0, false, NotOwnerFail},
+		{"started", 1, 100, 0, true, StartedFail},
+		{"negative games", 1, -1, 0, false, "The minimum number of games cannot be negative."},
+		{"negative rating", 1, 0, -1, false, "The minimum rating cannot be negative."},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resetTestTables(t)
+			tb := newTestTable(t, 2)
+			if test.started {
+				startTestGame(t, tb)
+			}
+
+			s, conn := newTestWebsocket(t, test.userID, testPlayerNames[test.userID-1], 0)
+			commandTableSetRequirements(s, &CommandData{
+				TableID:   tb.ID,
+				MinGames:  test.minGames,
+				MinRating: test.minRating,
+				NoLock:    true,
+			})
+
+			expectTestWarning(t, conn, test.warning)
+			if tb.MinGames != 0 || tb.MinRating != 0 {
+				t.Errorf("the requirements were set to %v games and a rating of %v",
+					tb.MinGames, tb.MinRating)
+			}
+		})
+	}
+}
+
+func TestTableHasRequirementsFor(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTable(t, 2)
+	tb.ReservedSeats[2] = 10
+
+	if tb.HasRequirementsFor(11) {
+		t.Error("a table without requirements has requirements")
+	}
+
+	// The owner and the users with a reserved seat bypass the requirements
+	tb.MinGames = 100
+	if !tb.HasRequirementsFor(11) {
+		t.Error("the requirements do not apply to other users")
+	}
+	if tb.HasRequirementsFor(tb.Owner) {
+		t.Error("the requirements apply to the owner")
+	}
+	if tb.HasRequirementsFor(10) {
+		t.Error("the requirements apply to a user with a reserved seat")
+	}
+}
+
+func TestGetTableRequirementsWarning(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTable(t, 2)
+	tb.MinGames = 100
+	tb.MinRating = 22.5
+
+	tests := []struct {
+		name         string
+		numGames     int
+		averageScore float64
+		warning      string
+	}{
+		{"qualified", 100, 22.5, ""},
+		{"too few games", 99, 25, "That table requires at least 100 games played, " +
+			"but you have only played 99."},
+		{"rating too low", 200, 20, "That table requires an average score of at least 22.50 " +
+			"in this variant, but yours is only 20.00."},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v := getTableRequirementsWarning(tb, test.numGames, test.averageScore)
+			if v != test.warning {
+				t.Errorf("expected the warning \"%v\", but got \"%v\"", test.warning, v)
+			}
+		})
+	}
+
+	// Requirements that are not set are not checked
+	tb.MinRating = 0
+	if v := getTableRequirementsWarning(tb, 100, 0); v != "" {
+		t.Errorf("the rating was checked even though there is no minimum rating: %v", v)
+	}
+}
+
+func TestTableJoinRequirements(t *testing.T) {
+	if db == nil {
+		t.Skip("looking up the experience of a user requires a database")
+	}
+	resetTestTables(t)
+	tb := newTestTable(t, 2)
+	tb.MinGames = 1000000
+
+	// A user without enough games is rejected
+	s, conn := newTestWebsocket(t, 11, "Eve", 0)
+	commandTableJoin(s, &CommandData{
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+	expectTestWarning(t, conn, "That table requires at least 1000000 games played")
+	if tb.GetPlayerIndexFromID(11) != -1 {
+		t.Fatal("a user who does not meet the requirements was able to join")
+	}
+
+	// The owner bypasses the requirements
+	tb.Owner = 11
+	commandTableJoin(s, &CommandData{
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+	if tb.GetPlayerIndexFromID(11) == -1 {
+		t.Error("the owner was not able to join")
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	// The maximum number of characters that a seed set with the "tableSetSeed" command can be
+	MaxSeedSuffixLength = 30
+)
+
+// commandTableSetSeed is sent when the owner of a table wants the game to be played on a specific
+// deck (e.g. so that a group can practice the same deal repeatedly)
+// It works in the same way as a table created with the "!seed" prefix
+// An empty seed will make the game use a random deck again
+//
+// Example data:
+// {
+//   tableID: 123,
+//   seed: '42',
+// }
+func commandTableSetSeed(s *Session, d *CommandData) {
+	t, exists := getTableAndLock(s, d.TableID, !d.NoLock)
+	if !exists {
+		return
+	}
+	if !d.NoLock {
+		defer t.Mutex.Unlock()
+	}
+
+	// Validate that they are the owner of the table
+	if s.UserID() != t.Owner {
+		s.WarningWithCode(ErrNotOwner, NotOwnerFail)
+		return
+	}
+
+	// Validate that the game has not started
+	if t.Running {
+		s.WarningWithCode(ErrStarted, StartedFail)
+		return
+	}
+
+	// Validate that this is not a replay or a "!replay" table
+	// (the deck for these tables is already determined)
+	if t.ExtraOptions.CustomSeed != "" || t.ExtraOptions.JSONReplay {
+		s.Warning("You cannot set the seed for a table that is replaying an existing game.")
+		return
+	}
+
+	// Validate that the seed is not too long
+	if len(d.Seed) > MaxSeedSuffixLength {
+		s.Warning("Seeds cannot be longer than " + strconv.Itoa(MaxSeedSuffixLength) +
+			" characters.")
+		return
+	}
+
+	// Trim whitespace from both sides
+	d.Seed = strings.TrimSpace(d.Seed)
+
+	// Validate that the seed does not contain any spaces or non-printable characters,
+	// since it becomes part of the seed string that is stored in the database (e.g. "p2v0s42")
+	if strings.Contains(d.Seed, " ") || removeNonPrintableCharacters(d.Seed) != d.Seed {
+		s.Warning("Seeds cannot contain spaces or special characters.")
+		return
+	}
+
+	tableSetSeed(s, d, t)
+}
+
+func tableSetSeed(s *Session, d *CommandData, t *Table) {
+	// The seed is combined with the number of players and the variant when the game starts
+	// (see "commandTableStart()"), so it will always produce the same deck for the same settings
+	t.ExtraOptions.SetSeedSuffix = d.Seed
+
+	var msg string
+	if d.Seed == "" {
+		msg = s.Username() + " cleared the seed; the game will use a random deck."
+	} else {
+		msg = s.Username() + " set the seed to: " + d.Seed
+	}
+	chatServerSend(msg, t.GetRoomName())
+}
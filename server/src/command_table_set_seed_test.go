@@ -0,0 +1,104 @@
+package main
+
+import (
+	"testing"
+)
+
+func setTestSeed(tb *Table, s *Session, seed string) {
+	commandTableSetSeed(s, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		Seed:    seed,
+		NoLock:  true,
+	})
+}
+
+// newTestSeededGame starts a game that uses a deck from the "tableSetSeed" command
+func newTestSeededGame(t *testing.T, seed string) *Table {
+	tb := newTestTable(t, 3)
+	tb.ExtraOptions.CustomSeed = ""
+	setTestSeed(tb, tb.Players[0].Session, seed)
+	if tb.ExtraOptions.SetSeedSuffix != seed {
+		t.Fatal("the seed was not set")
+	}
+	startTestGame(t, tb)
+
+	return tb
+}
+
+func getTestDeckOrder(tb *Table) [][2]int {
+	deck := make([][2]int, 0, len(tb.Game.Deck))
+	for _, c := range tb.Game.Deck {
+		deck = append(deck, [2]int{c.SuitIndex, c.Rank})
+	}
+	return deck
+}
+
+func TestCommandTableSetSeedIsDeterministic(t *testing.T) {
+	resetTestTables(t)
+	tb1 := newTestSeededGame(t, "42")
+	tb2 := newTestSeededGame(t, "42")
+	tb3 := newTestSeededGame(t, "43")
+
+	if tb1.Game.Seed != "p3v0s42" || tb2.Game.Seed != tb1.Game.Seed {
+		t.Errorf("expected both games to use the seed of \"p3v0s42\", but got \"%v\" and \"%v\"",
+			tb1.Game.Seed, tb2.Game.Seed)
+	}
+	deck1 := getTestDeckOrder(tb1)
+	deck2 := getTestDeckOrder(tb2)
+	for i := range deck1 {
+		if deck1[i] != deck2[i] {
+			t.Fatalf("the decks are different at card %v: %v and %v", i, deck1[i], deck2[i])
+		}
+	}
+
+	// A different seed produces a different deck
+	deck3 := getTestDeckOrder(tb3)
+	same := true
+	for i := range deck1 {
+		if deck1[i] != deck3[i] {
+			same = false
+			break
+		}
+	}
+	if same {
+		t.Error("two different seeds produced the same deck")
+	}
+}
+
+func TestCommandTableSetSeedValidation(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTable(t, 2)
+	tb.ExtraOptions.CustomSeed = ""
+	conns := connectTestPlayers(t, tb)
+
+	// Only the owner can set the seed
+	setTestSeed(tb, tb.Players[1].Session, "42")
+	expectTestWarningCode(t, conns[1], ErrNotOwner)
+
+	setTestSeed(tb, tb.Players[0].Session, "4 2")
+	expectTestWarning(t, conns[0], "cannot contain spaces")
+	if tb.ExtraOptions.SetSeedSuffix != "" {
+		t.Fatal("an invalid seed was set")
+	}
+
+	// The seed cannot be changed once the game has started
+	setTestSeed(tb, tb.Players[0].Session, "42")
+	readTestCommand(t, conns[0], "chat")
+	startTestGame(t, tb)
+	seed := tb.Game.Seed
+	setTestSeed(tb, tb.Players[0].Session, "43")
+	expectTestWarningCode(t, conns[0], ErrStarted)
+	if tb.ExtraOptions.SetSeedSuffix != "42" || tb.Game.Seed != seed {
+		t.Error("the seed was changed after the game started")
+	}
+}
+
+func TestCommandTableSetSeedReplayTable(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTable(t, 2)
+	conns := connectTestPlayers(t, tb)
+
+	// Tables that replay an existing seed already have a deck
+	setTestSeed(tb, tb.Players[0].Session, "42")
+	expectTestWarning(t, conns[0], "replaying an existing game")
+}
@@ -23,12 +23,12 @@ func commandTableSetVariant(s *Session, d *CommandData) {
 	}
 
 	if t.Running {
-		s.Warning(StartedFail)
+		s.WarningWithCode(ErrStarted, StartedFail)
 		return
 	}
 
 	if s.UserID() != t.Owner {
-		s.Warning(NotOwnerFail)
+		s.WarningWithCode(ErrNotOwner, NotOwnerFail)
 		return
 	}
 
@@ -47,6 +47,21 @@ func commandTableSetVariant(s *Session, d *CommandData) {
 		return
 	}
 
+	// The cards of a custom deck are only valid for the variant that it was created for
+	if len(t.ExtraOptions.CustomDeck) > 0 {
+		s.Warning("You cannot change the variant of a table with a custom deck.")
+		return
+	}
+
+	if !validateVariantEnabled(s, d.Options.VariantName) {
+		return
+	}
+
+	// The table could have been created without the only type of clue that the new variant allows
+	if !validateClueTypes(s, t.Options, d.Options.VariantName) {
+		return
+	}
+
 	tableSetVariant(s, d, t)
 }
 
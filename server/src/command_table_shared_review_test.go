@@ -0,0 +1,174 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+type testSharedReviewMessage struct {
+	TableID uint64 `json:"tableID"`
+	Active  bool   `json:"active"`
+	Turn    int    `json:"turn"`
+}
+
+// expectTestSharedReview checks that every client was sent the given shared review state
+func expectTestSharedReview(t *testing.T, conns []*websocket.Conn, active bool, turn int) {
+	for i, conn := range conns {
+		data := readTestCommand(t, conn, "sharedReview")
+		var msg testSharedReviewMessage
+		if err := json.Unmarshal([]byte(data), &msg); err != nil {
+			t.Fatal("failed to unmarshal the \"sharedReview\" message:", err)
+		}
+		if msg.Active != active || msg.Turn != turn {
+			t.Errorf("expected client %v to be sent an active state of %v on turn %v, but got: %v",
+				i, active, turn, data)
+		}
+	}
+}
+
+// newTestSharedReview creates a game where 2 turns have been played and starts a shared review
+func newTestSharedReview(t *testing.T, timed bool) (*Table, []*websocket.Conn) {
+	var tb *Table
+	if timed {
+		tb = newTestTimedGame(t, 2)
+	} else {
+		tb = newTestGame(t, 2)
+	}
+	clueTestPlayer(t, tb)
+	discardTestCard(t, tb)
+	conns := connectTestPlayers(t, tb)
+
+	commandTableEnterSharedReview(tb.Players[0].Session, &CommandData{
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+	if !tb.Game.Reviewing {
+		t.Fatal("the table did not enter a shared review")
+	}
+
+	return tb, conns
+}
+
+func TestTableEnterSharedReview(t *testing.T) {
+	resetTestTables(t)
+	tb, conns := newTestSharedReview(t, false)
+	g := tb.Game
+
+	// Everyone starts on the current turn
+	expectTestSharedReview(t, conns, true, 2)
+
+	// The live game cannot advance
+	p := tb.Players[g.ActivePlayerIndex]
+	commandAction(p.Session, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		Type:    ActionTypeColorClue,
+		Target:  (g.ActivePlayerIndex + 1) % 2,
+		Value:   0,
+		NoLock:  true,
+	})
+	expectTestWarning(t, conns[g.ActivePlayerIndex], "during a shared review")
+	if g.Turn != 2 {
+		t.Errorf("expected the game to stay on turn 2, but it is on turn %v", g.Turn)
+	}
+}
+
+func TestTableEnterSharedReviewNotOwner(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+	conns := connectTestPlayers(t, tb)
+
+	commandTableEnterSharedReview(tb.Players[1].Session, &CommandData{
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+
+	expectTestWarning(t, conns[1], NotOwnerFail)
+	if tb.Game.Reviewing {
+		t.Error("a player that is not the owner started a shared review")
+	}
+}
+
+func TestTableReviewSeek(t *testing.T) {
+	resetTestTables(t)
+	tb, conns := newTestSharedReview(t, false)
+	expectTestSharedReview(t, conns, true, 2)
+
+	// Everyone follows the turn of the leader
+	commandTableReviewSeek(tb.Players[0].Session, &CommandData{
+		TableID: tb.ID,
+		Turn:    1,
+		NoLock:  true,
+	})
+	expectTestSharedReview(t, conns, true, 1)
+	if tb.Game.Turn != 2 {
+		t.Errorf("expected the live game to stay on turn 2, but it is on turn %v", tb.Game.Turn)
+	}
+
+	// Only the leader can change the turn
+	commandTableReviewSeek(tb.Players[1].Session, &CommandData{
+		TableID: tb.ID,
+		Turn:    0,
+		NoLock:  true,
+	})
+	expectTestWarning(t, conns[1], NotOwnerFail)
+
+	// Future turns cannot be reviewed
+	commandTableReviewSeek(tb.Players[0].Session, &CommandData{
+		TableID: tb.ID,
+		Turn:    3,
+		NoLock:  true,
+	})
+	expectTestWarning(t, conns[0], "The turn must be between 0 and 2.")
+	if tb.Game.ReviewTurn != 1 {
+		t.Errorf("expected the review to stay on turn 1, but it is on turn %v",
+			tb.Game.ReviewTurn)
+	}
+}
+
+func TestTableExitSharedReview(t *testing.T) {
+	resetTestTables(t)
+	tb, conns := newTestSharedReview(t, false)
+	expectTestSharedReview(t, conns, true, 2)
+
+	commandTableExitSharedReview(tb.Players[0].Session, &CommandData{
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+	expectTestSharedReview(t, conns, false, 0)
+
+	// The live game can continue
+	clueTestPlayer(t, tb)
+	if tb.Game.Turn != 3 {
+		t.Errorf("expected the game to be on turn 3, but it is on turn %v", tb.Game.Turn)
+	}
+}
+
+func TestTableSharedReviewPausesTimedGames(t *testing.T) {
+	resetTestTables(t)
+	tb, conns := newTestSharedReview(t, true)
+	g := tb.Game
+	if !g.Paused {
+		t.Fatal("the timed game was not paused for the shared review")
+	}
+
+	// The players cannot unpause the game during the review
+	commandPause(tb.Players[0].Session, &CommandData{
+		TableID: tb.ID,
+		Setting: "unpause",
+		NoLock:  true,
+	})
+	expectTestWarning(t, conns[0], "during a shared review")
+	if !g.Paused {
+		t.Error("the game was unpaused during the shared review")
+	}
+
+	commandTableExitSharedReview(tb.Players[0].Session, &CommandData{
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+	if g.Paused {
+		t.Error("the timed game was not unpaused after the shared review")
+	}
+}
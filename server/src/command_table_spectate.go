@@ -1,5 +1,9 @@
 package main
 
+import (
+	"github.com/alexedwards/argon2id"
+)
+
 // commandTableSpectate is sent when:
 // 1) the user clicks on the "Spectate" button in the lobby
 // 2) the user creates a solo replay
@@ -11,6 +15,9 @@ package main
 //   tableID: 15103,
 //   // A value of "-1" must be specified if we do not want to shadow a player
 //   shadowingPlayerIndex: -1,
+//   // Only needed for a password-protected game (either the password or a spectate token)
+//   password: 'super_secret',
+//   token: '0e1f5b8a-0a43-4f8b-9d3e-5a3c1e2f7b6d',
 // }
 func commandTableSpectate(s *Session, d *CommandData) {
 	t, exists := getTableAndLock(s, d.TableID, !d.NoLock)
@@ -23,7 +30,7 @@ func commandTableSpectate(s *Session, d *CommandData) {
 
 	// Validate that the game has started
 	if !t.Running {
-		s.Warning(NotStartedFail)
+		s.WarningWithCode(ErrNotStarted, NotStartedFail)
 		return
 	}
 
@@ -35,6 +42,18 @@ func commandTableSpectate(s *Session, d *CommandData) {
 		}
 	}
 
+	// Validate that they have not been kicked from this table
+	if _, ok := t.KickedSpectators[s.UserID()]; ok {
+		s.Warning("You cannot spectate a table that you have been kicked from.")
+		return
+	}
+
+	// Validate that they entered the correct password (or that the owner gave them a token)
+	// (anyone can view the replay after the game has ended)
+	if t.PasswordHash != "" && !t.Replay && !tableSpectateCheckPassword(s, d, t) {
+		return
+	}
+
 	// Validate that they are not already spectating another table
 	alreadySpectating := false
 	tablesMutex.RLock()
@@ -67,6 +86,35 @@ func commandTableSpectate(s *Session, d *CommandData) {
 	tableSpectate(s, d, t)
 }
 
+// tableSpectateCheckPassword returns true if the user is allowed to spectate a password-protected
+// table
+func tableSpectateCheckPassword(s *Session, d *CommandData, t *Table) bool {
+	// The players of the game and the users who already got in do not need the password
+	if _, ok := t.AllowedSpectators[s.UserID()]; ok || t.GetPlayerIndexFromID(s.UserID()) != -1 {
+		return true
+	}
+
+	if t.IsValidSpectateToken(d.Token) {
+		// A single-use token is used up as soon as it lets someone in
+		// (they are now an allowed spectator, so they do not need it again)
+		if t.SpectateTokens[d.Token].SingleUse {
+			delete(t.SpectateTokens, d.Token)
+		}
+	} else {
+		if match, err := argon2id.ComparePasswordAndHash(d.Password, t.PasswordHash); err != nil {
+			logger.Error("Failed to compare the submitted password to the Argon2 hash:", err)
+			s.Error(DefaultErrorMsg)
+			return false
+		} else if !match {
+			s.WarningWithCode(ErrWrongPassword, "That is not the correct password for this game.")
+			return false
+		}
+	}
+
+	t.AllowedSpectators[s.UserID()] = struct{}{}
+	return true
+}
+
 func tableSpectate(s *Session, d *CommandData, t *Table) {
 	// Local variables
 	g := t.Game
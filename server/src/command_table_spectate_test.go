@@ -0,0 +1,93 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/alexedwards/argon2id"
+)
+
+const testTablePassword = "hunter2"
+
+// usePasswordTestTable makes the table require the test password
+func usePasswordTestTable(t *testing.T, tb *Table) {
+	if v, err := argon2id.CreateHash(testTablePassword, argon2id.DefaultParams); err != nil {
+		t.Fatal("failed to hash the password:", err)
+	} else {
+		tb.PasswordHash = v
+	}
+}
+
+func spectateTestTableWithPassword(tb *Table, s *Session, password string) {
+	commandTableSpectate(s, &CommandData{ // Manual invocation
+		TableID:              tb.ID,
+		ShadowingPlayerIndex: -1,
+		Password:             password,
+		NoLock:               true,
+	})
+}
+
+func TestTableSpectatePassword(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+	usePasswordTestTable(t, tb)
+	s, conn := newTestWebsocket(t, 10, "Dan", 0)
+
+	for _, password := range []string{"", "hunter3"} {
+		spectateTestTableWithPassword(tb, s, password)
+		expectTestWarningCode(t, conn, ErrWrongPassword)
+		if tb.GetSpectatorIndexFromID(10) != -1 {
+			t.Fatalf("the user was able to spectate with the password of \"%v\"", password)
+		}
+	}
+
+	spectateTestTableWithPassword(tb, s, testTablePassword)
+	if tb.GetSpectatorIndexFromID(10) == -1 {
+		t.Fatal("the user was not able to spectate with the correct password")
+	}
+
+	// They do not have to enter the password again after leaving
+	// (e.g. to shadow a different player)
+	commandTableUnattend(s, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+	spectateTestTable(tb, s)
+	if tb.GetSpectatorIndexFromID(10) == -1 {
+		t.Error("the user had to enter the password again")
+	}
+}
+
+func TestTableSpectatePasswordReplay(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestReplay(t)
+	usePasswordTestTable(t, tb)
+
+	// Anyone can view the replay of a password-protected game
+	s, _ := newTestWebsocket(t, 10, "Dan", 0)
+	spectateTestTable(tb, s)
+	if tb.GetSpectatorIndexFromID(10) == -1 {
+		t.Error("the user was not able to spectate the replay")
+	}
+}
+
+func TestTableSpectatePasswordSurvivesRestore(t *testing.T) {
+	resetTestTables(t)
+	useTestTableStore(t, false)
+	tb := newTestGame(t, 2)
+	usePasswordTestTable(t, tb)
+	s, _ := newTestWebsocket(t, 10, "Dan", 0)
+	spectateTestTableWithPassword(tb, s, testTablePassword)
+
+	// The spectators are automatically put back into the game when they reconnect,
+	// so they must not be asked for the password again
+	serializeAndRestoreTestTables(t)
+	restored := getTestTable(t, tb.ID)
+	if _, ok := restored.AllowedSpectators[10]; !ok {
+		t.Fatal("the spectator lost access to the table after it was restored")
+	}
+	s2, _ := newTestWebsocket(t, 10, "Dan", 0)
+	spectateTestTable(restored, s2)
+	if restored.GetSpectatorIndexFromID(10) == -1 {
+		t.Error("the spectator was not able to spectate the restored table")
+	}
+}
@@ -76,6 +76,11 @@ func tableStart(s *Session, d *CommandData, t *Table) {
 	// Record the number of players
 	t.Options.NumPlayers = len(t.Players)
 
+	// Seat reservations only apply before the game starts
+	t.ReservedSeats = make(map[int]int)
+	t.MinGames = 0
+	t.MinRating = 0
+
 	// Make everyone stop typing
 	for _, p := range t.Players {
 		if p.Typing {
@@ -88,7 +93,10 @@ func tableStart(s *Session, d *CommandData, t *Table) {
 	g := NewGame(t)
 
 	// Start the idle timeout
-	go t.CheckIdle()
+	// (but not for games that are only emulated, e.g. in "validateGameState()")
+	if !t.RestoringActions {
+		go t.CheckIdle()
+	}
 
 	g.InitDeck()
 
@@ -115,6 +123,11 @@ func tableStart(s *Session, d *CommandData, t *Table) {
 		// This is a replay from the database (or a custom "!replay" game)
 		g.Seed = t.ExtraOptions.CustomSeed
 		shufflePlayers = false
+	} else if len(t.ExtraOptions.CustomDeck) > 0 {
+		// This is a custom table that was created with a specific deck
+		// Like a deck from JSON data, it is not derived from a seed
+		g.Seed = "JSON"
+		shuffleDeck = false
 	} else if t.ExtraOptions.SetSeedSuffix != "" {
 		// This is a custom table created with the "!seed" prefix
 		// (e.g. playing a deal with a specific seed)
@@ -149,6 +162,10 @@ func tableStart(s *Session, d *CommandData, t *Table) {
 			}
 		}
 	}
+	if t.SeatsArranged {
+		// The table owner has chosen the turn order with the "tableSwapSeats" command
+		shufflePlayers = false
+	}
 	logger.Info(t.GetName()+"Using seed:", g.Seed)
 	logger.Info("Shuffling deck:", shuffleDeck)
 	logger.Info("Shuffling players:", shufflePlayers)
@@ -207,6 +224,7 @@ func tableStart(s *Session, d *CommandData, t *Table) {
 	for _, p := range t.Players {
 		if p.Present {
 			p.Present = false
+			p.DatetimeAbsent = time.Now()
 		} else {
 			listOfAwayPlayers = append(listOfAwayPlayers, p.ID)
 		}
@@ -251,6 +269,7 @@ func tableStart(s *Session, d *CommandData, t *Table) {
 		// Let everyone know that the game has started, which will turn the
 		// "Join Game" button into "Spectate"
 		notifyAllTable(t)
+		notifyLobbySubscribers(LobbyUpdateStarted, t)
 
 		// Set the status for all of the users in the game
 		for _, p := range t.Players {
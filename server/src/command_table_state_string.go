@@ -0,0 +1,60 @@
+package main
+
+import (
+	"strconv"
+)
+
+// commandTableStateString is sent when the user wants a compact encoding of the current state of
+// the game (e.g. to share it on a forum or to feed it to a solver)
+// Cards that are hidden from the user (e.g. the cards in their own hand) are hidden in the string
+// The format is documented in "game_state_string.go"
+//
+// Example data:
+// {
+//   tableID: 5,
+// }
+func commandTableStateString(s *Session, d *CommandData) {
+	t, exists := getTableAndLock(s, d.TableID, !d.NoLock)
+	if !exists {
+		return
+	}
+	if !d.NoLock {
+		defer t.Mutex.Unlock()
+	}
+
+	// Validate that the game has started
+	if !t.Running {
+		s.WarningWithCode(ErrNotStarted, NotStartedFail)
+		return
+	}
+
+	// Validate that they are either a player or a spectator
+	playerIndex := t.GetPlayerIndexFromID(s.UserID())
+	spectatorIndex := t.GetSpectatorIndexFromID(s.UserID())
+	if playerIndex == -1 && spectatorIndex == -1 {
+		s.Warning("You are not a player or a spectator at table " +
+			strconv.FormatUint(t.ID, 10) + ", so you cannot get the state of it.")
+		return
+	}
+
+	tableStateString(s, t)
+}
+
+func tableStateString(s *Session, t *Table) {
+	// In a replay, everything is visible
+	var p *GamePlayer
+	if !t.Replay {
+		p = getEquivalentPlayer(t, s.UserID())
+	}
+
+	type TableStateStringMessage struct {
+		TableID     uint64 `json:"tableID"`
+		Turn        int    `json:"turn"`
+		StateString string `json:"stateString"`
+	}
+	s.Emit("tableStateString", &TableStateStringMessage{
+		TableID:     t.ID,
+		Turn:        t.Game.Turn,
+		StateString: NewGameState(t.Game, p).String(),
+	})
+}
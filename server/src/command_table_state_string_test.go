@@ -0,0 +1,56 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func getTestStateString(t *testing.T, tb *Table, id int, name string) string {
+	var msg struct {
+		StateString string `json:"stateString"`
+	}
+	getTestTableResponse(t, tb, id, name, commandTableStateString, "tableStateString", &msg)
+
+	return msg.StateString
+}
+
+func TestCommandTableStateString(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+	g := tb.Game
+	newTestWebsocketSpectator(t, tb, 10, "Spectator")
+
+	// The player cannot see their own hand
+	stateString := getTestStateString(t, tb, 1, "Alice")
+	if stateString != NewGameState(g, g.Players[0]).String() {
+		t.Errorf("the state string is not from the perspective of the player: %v", stateString)
+	}
+	hands := strings.Split(strings.Fields(stateString)[6], "/")
+	if !strings.Contains(hands[0], "xx") || strings.Contains(hands[1], "xx") {
+		t.Errorf("the wrong cards are hidden from the player: %v", stateString)
+	}
+
+	// The spectator can see everything
+	if v := getTestStateString(t, tb, 10, "Spectator"); v != NewGameState(g, nil).String() {
+		t.Errorf("expected the spectator to see every card, but got: %v", v)
+	}
+}
+
+func TestCommandTableStateStringValidation(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTable(t, 2)
+	s, conn := newTestWebsocket(t, 1, "Alice", 0)
+	commandTableStateString(s, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+	expectTestWarning(t, conn, NotStartedFail)
+
+	tb2 := newTestGame(t, 2)
+	s2, conn2 := newTestWebsocket(t, 10, "Dan", 0)
+	commandTableStateString(s2, &CommandData{ // Manual invocation
+		TableID: tb2.ID,
+		NoLock:  true,
+	})
+	expectTestWarning(t, conn2, "You are not a player or a spectator")
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"strconv"
+)
+
+// commandTableStrikeLog is sent when the user wants to review the misplays of a game
+// (e.g. after the game ended in a strikeout)
+//
+// Example data:
+// {
+//   tableID: 5,
+// }
+func commandTableStrikeLog(s *Session, d *CommandData) {
+	t, exists := getTableAndLock(s, d.TableID, !d.NoLock)
+	if !exists {
+		return
+	}
+	if !d.NoLock {
+		defer t.Mutex.Unlock()
+	}
+
+	// Validate that the game has started
+	if !t.Running {
+		s.WarningWithCode(ErrNotStarted, NotStartedFail)
+		return
+	}
+
+	// Validate that they are either a player or a spectator
+	playerIndex := t.GetPlayerIndexFromID(s.UserID())
+	spectatorIndex := t.GetSpectatorIndexFromID(s.UserID())
+	if playerIndex == -1 && spectatorIndex == -1 {
+		s.Warning("You are not a player or a spectator at table " +
+			strconv.FormatUint(t.ID, 10) + ", so you cannot get the strikes for it.")
+		return
+	}
+
+	tableStrikeLog(s, t)
+}
+
+func tableStrikeLog(s *Session, t *Table) {
+	// Local variables
+	g := t.Game
+
+	type StrikeLogEntry struct {
+		Num        int    `json:"num"`
+		Turn       int    `json:"turn"`
+		PlayerName string `json:"playerName"`
+		Order      int    `json:"order"`
+		SuitIndex  int    `json:"suitIndex"`
+		Rank       int    `json:"rank"`
+		Expected   []int  `json:"expected"`
+	}
+	strikes := make([]*StrikeLogEntry, 0)
+	for _, action := range g.Actions {
+		strikeAction, ok := action.(ActionStrike)
+		if !ok {
+			continue
+		}
+
+		// Hide the identities of the misplayed cards in some variants
+		strikeAction.Scrub(t, s.UserID())
+
+		strikes = append(strikes, &StrikeLogEntry{
+			Num:        strikeAction.Num,
+			Turn:       strikeAction.Turn,
+			PlayerName: g.GetPlayerName(strikeAction.PlayerIndex),
+			Order:      strikeAction.Order,
+			SuitIndex:  strikeAction.SuitIndex,
+			Rank:       strikeAction.Rank,
+			Expected:   strikeAction.Expected,
+		})
+	}
+
+	type TableStrikeLogMessage struct {
+		TableID uint64            `json:"tableID"`
+		Strikes []*StrikeLogEntry `json:"strikes"`
+	}
+	s.Emit("tableStrikeLog", &TableStrikeLogMessage{
+		TableID: t.ID,
+		Strikes: strikes,
+	})
+}
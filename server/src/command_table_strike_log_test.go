@@ -0,0 +1,149 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testStrikeLogEntry struct {
+	Num        int    `json:"num"`
+	Turn       int    `json:"turn"`
+	PlayerName string `json:"playerName"`
+	Order      int    `json:"order"`
+	SuitIndex  int    `json:"suitIndex"`
+	Rank       int    `json:"rank"`
+	Expected   []int  `json:"expected"`
+}
+
+func getTestStrikeLog(t *testing.T, tb *Table, id int, name string) []*testStrikeLogEntry {
+	var msg struct {
+		Strikes []*testStrikeLogEntry `json:"strikes"`
+	}
+	getTestTableResponse(t, tb, id, name, commandTableStrikeLog, "tableStrikeLog", &msg)
+
+	return msg.Strikes
+}
+
+// misplayTestCard plays a card of the active player that does not play on its stack
+func misplayTestCard(t *testing.T, tb *Table) *Card {
+	g := tb.Game
+	for _, c := range g.Players[g.ActivePlayerIndex].Hand {
+		if g.Stacks[c.SuitIndex]+1 != c.Rank {
+			performTestAction(t, tb, ActionTypePlay, c.Order, 0)
+			return c
+		}
+	}
+
+	t.Fatal("the active player does not have a card that would misplay")
+	return nil
+}
+
+func getTestStrikeActions(g *Game) []ActionStrike {
+	strikeActions := make([]ActionStrike, 0)
+	for _, action := range g.Actions {
+		if strikeAction, ok := action.(ActionStrike); ok {
+			strikeActions = append(strikeActions, strikeAction)
+		}
+	}
+
+	return strikeActions
+}
+
+func TestCommandTableStrikeLog(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+	clueTestPlayer(t, tb)
+	c := misplayTestCard(t, tb)
+
+	strikes := getTestStrikeLog(t, tb, 1, "Alice")
+	if len(strikes) != 1 {
+		t.Fatalf("expected 1 strike, but got %v", len(strikes))
+	}
+	expected := &testStrikeLogEntry{
+		Num:        1,
+		Turn:       1,
+		PlayerName: "Bob",
+		Order:      c.Order,
+		SuitIndex:  c.SuitIndex,
+		Rank:       c.Rank,
+		Expected:   []int{1},
+	}
+	if !reflect.DeepEqual(strikes[0], expected) {
+		t.Errorf("expected the strike to be %+v, but got %+v", expected, strikes[0])
+	}
+}
+
+func TestTableStrikeLogSurvivesRestore(t *testing.T) {
+	resetTestTables(t)
+	useTestTableStore(t, false)
+	tb := newTestGame(t, 2)
+	misplayTestCard(t, tb)
+	clueTestPlayer(t, tb)
+	misplayTestCard(t, tb)
+	strikeActions := getTestStrikeActions(tb.Game)
+	strikes := getTestStrikeLog(t, tb, 1, "Alice")
+	if len(strikeActions) != 2 {
+		t.Fatalf("expected 2 strikes, but got %v", len(strikeActions))
+	}
+
+	serializeAndRestoreTestTables(t)
+	restored := getTestTable(t, tb.ID)
+	if v := getTestStrikeActions(restored.Game); !reflect.DeepEqual(v, strikeActions) {
+		t.Errorf("expected the strikes to be restored as %+v, but got %+v", strikeActions, v)
+	}
+	if v := getTestStrikeLog(t, restored, 1, "Alice"); !reflect.DeepEqual(v, strikes) {
+		t.Errorf("the strike log changed after the table was restored: %+v", v)
+	}
+}
+
+func TestCommandTableStrikeLogThrowItInAHole(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTable(t, 2)
+	tb.Options.VariantName = "Throw It in a Hole (5 Suits)"
+	startTestGame(t, tb)
+	newTestWebsocketSpectator(t, tb, 10, "Spectator")
+	c := misplayTestCard(t, tb)
+
+	// The players cannot see the identity of the misplayed card
+	strikes := getTestStrikeLog(t, tb, 2, "Bob")
+	if len(strikes) != 1 {
+		t.Fatalf("expected 1 strike, but got %v", len(strikes))
+	}
+	if strikes[0].SuitIndex != -1 || strikes[0].Rank != -1 || len(strikes[0].Expected) != 0 {
+		t.Errorf("the misplayed card was revealed to a player: %+v", strikes[0])
+	}
+
+	// The spectators can
+	strikes = getTestStrikeLog(t, tb, 10, "Spectator")
+	if strikes[0].SuitIndex != c.SuitIndex || strikes[0].Rank != c.Rank {
+		t.Errorf("the misplayed card was hidden from a spectator: %+v", strikes[0])
+	}
+}
+
+func TestGetPlayableRanks(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+	g := tb.Game
+
+	if v := g.GetPlayableRanks(0); !reflect.DeepEqual(v, []int{1}) {
+		t.Errorf("expected a 1 to be playable on an empty stack, but got %v", v)
+	}
+	g.Stacks[0] = PointsPerSuit
+	if v := g.GetPlayableRanks(0); len(v) != 0 {
+		t.Errorf("expected nothing to be playable on a finished stack, but got %v", v)
+	}
+
+	// Stacks in "Up or Down" can start from either end
+	tb2 := newTestTable(t, 2)
+	tb2.Options.VariantName = "Up or Down (5 Suits)"
+	startTestGame(t, tb2)
+	g2 := tb2.Game
+	if v := g2.GetPlayableRanks(0); !reflect.DeepEqual(v, []int{1, 5, StartCardRank}) {
+		t.Errorf("expected 1, 5, or a start card to be playable on an empty stack, but got %v", v)
+	}
+	g2.Stacks[0] = 3
+	g2.PlayStackDirections[0] = StackDirectionDown
+	if v := g2.GetPlayableRanks(0); !reflect.DeepEqual(v, []int{2}) {
+		t.Errorf("expected a 2 to be playable on a descending 3, but got %v", v)
+	}
+}
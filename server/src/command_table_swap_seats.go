@@ -0,0 +1,83 @@
+package main
+
+import (
+	"strconv"
+)
+
+// commandTableSwapSeats is sent when the owner of an unstarted table wants to rearrange the turn
+// order by swapping the players in two seats
+// If one of the seats is empty, the player in the other seat is moved to the last seat
+// After the seats are arranged, the players are no longer shuffled when the game starts
+//
+// Example data:
+// {
+//   tableID: 123,
+//   seatA: 0,
+//   seatB: 2,
+// }
+func commandTableSwapSeats(s *Session, d *CommandData) {
+	t, exists := getTableAndLock(s, d.TableID, !d.NoLock)
+	if !exists {
+		return
+	}
+	if !d.NoLock {
+		defer t.Mutex.Unlock()
+	}
+
+	// Validate that they are the owner of the table
+	if s.UserID() != t.Owner {
+		s.WarningWithCode(ErrNotOwner, NotOwnerFail)
+		return
+	}
+
+	// Validate that the game has not started
+	if t.Running {
+		s.WarningWithCode(ErrStarted, StartedFail)
+		return
+	}
+
+	// Validate the seat indexes
+	for _, seatIndex := range []int{d.SeatA, d.SeatB} {
+		if seatIndex < 0 || seatIndex >= MaxPlayers {
+			s.Warning("The seat index must be between 0 and " + strconv.Itoa(MaxPlayers-1) + ".")
+			return
+		}
+	}
+	if d.SeatA == d.SeatB {
+		s.Warning("You cannot swap a seat with itself.")
+		return
+	}
+	if d.SeatA >= len(t.Players) && d.SeatB >= len(t.Players) {
+		s.Warning("Both of those seats are empty.")
+		return
+	}
+
+	tableSwapSeats(s, d, t)
+}
+
+func tableSwapSeats(s *Session, d *CommandData, t *Table) {
+	seatA := d.SeatA
+	seatB := d.SeatB
+	if seatA > seatB {
+		seatA, seatB = seatB, seatA
+	}
+
+	var msg string
+	if seatB < len(t.Players) {
+		t.Players[seatA], t.Players[seatB] = t.Players[seatB], t.Players[seatA]
+		msg = s.Username() + " swapped the seats of " + t.Players[seatB].Name + " and " +
+			t.Players[seatA].Name + "."
+	} else {
+		// The players are always seated contiguously,
+		// so moving a player to an empty seat moves them to the end of the list
+		p := t.Players[seatA]
+		t.Players = append(t.Players[:seatA], t.Players[seatA+1:]...)
+		t.Players = append(t.Players, p)
+		msg = s.Username() + " moved " + p.Name + " to the last seat."
+	}
+	t.SeatsArranged = true
+
+	notifyAllTable(t)
+	t.NotifyPlayerChange()
+	chatServerSend(msg, t.GetRoomName())
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"testing"
+)
+
+// getTestPlayerNames returns the names of the players at the table in seat order
+func getTestPlayerNames(tb *Table) []string {
+	names := make([]string, 0)
+	for _, p := range tb.Players {
+		names = append(names, p.Name)
+	}
+
+	return names
+}
+
+func expectTestPlayerNames(t *testing.T, tb *Table, expected []string) {
+	names := getTestPlayerNames(tb)
+	if len(names) != len(expected) {
+		t.Fatalf("expected the players to be %v, but got %v", expected, names)
+	}
+	for i := range names {
+		if names[i] != expected[i] {
+			t.Fatalf("expected the players to be %v, but got %v", expected, names)
+		}
+	}
+}
+
+func swapTestSeats(tb *Table, s *Session, seatA int, seatB int) {
+	commandTableSwapSeats(s, &CommandData{
+		TableID: tb.ID,
+		SeatA:   seatA,
+		SeatB:   seatB,
+		NoLock:  true,
+	})
+}
+
+func TestTableSwapSeats(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTable(t, 3)
+
+	swapTestSeats(tb, tb.Players[0].Session, 0, 2)
+
+	expectTestPlayerNames(t, tb, []string{"Cathy", "Bob", "Alice"})
+	if !tb.SeatsArranged {
+		t.Error("the table was not marked as having arranged seats")
+	}
+}
+
+func TestTableSwapSeatsWithEmptySeat(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTable(t, 3)
+
+	// The seats are always contiguous, so the player is moved to the end
+	swapTestSeats(tb, tb.Players[0].Session, 4, 0)
+
+	expectTestPlayerNames(t, tb, []string{"Bob", "Cathy", "Alice"})
+}
+
+func TestTableSwapSeatsValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		userID  int
+		seatA   int
+		seatB   int
+		started bool
+		warning string
+	}{
+		{"not the owner", 2, 0, 1, false, NotOwnerFail},
+		{"running", 1, 0, 1, true, StartedFail},
+		{"invalid seat", 1, 0, MaxPlayers, false, "The seat index must be between"},
+		{"same seat", 1, 1, 1, false, "You cannot swap a seat with itself."},
+		{"both empty", 1, 4, 5, false, "Both of those seats are empty."},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resetTestTables(t)
+			tb := newTestTable(t, 3)
+			if test.started {
+				startTestGame(t, tb)
+			}
+			before := getTestPlayerNames(tb)
+
+			s, conn := newTestWebsocket(t, test.userID, testPlayerNames[test.userID-1], 0)
+			swapTestSeats(tb, s, test.seatA, test.seatB)
+
+			expectTestWarning(t, conn, test.warning)
+			expectTestPlayerNames(t, tb, before)
+		})
+	}
+}
+
+func TestTableSwapSeatsKeptOnStart(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTable(t, 5)
+
+	// Use a seed that would normally shuffle the players
+	tb.ExtraOptions.CustomSeed = ""
+	tb.ExtraOptions.SetSeedSuffix = "1"
+	swapTestSeats(tb, tb.Players[0].Session, 0, 4)
+	expected := getTestPlayerNames(tb)
+
+	startTestGame(t, tb)
+
+	expectTestPlayerNames(t, tb, expected)
+}
@@ -0,0 +1,71 @@
+package main
+
+// commandTableTransferOwner is sent when the owner of an unstarted table wants to give the table
+// to another player (e.g. because they have to leave)
+// Unlike the "tableSetLeader" command, the new owner is specified by user ID
+//
+// Example data:
+// {
+//   tableID: 123,
+//   newOwnerID: 5,
+// }
+func commandTableTransferOwner(s *Session, d *CommandData) {
+	t, exists := getTableAndLock(s, d.TableID, !d.NoLock)
+	if !exists {
+		return
+	}
+	if !d.NoLock {
+		defer t.Mutex.Unlock()
+	}
+
+	// Validate that they are the owner of the table
+	if s.UserID() != t.Owner {
+		s.WarningWithCode(ErrNotOwner, NotOwnerFail)
+		return
+	}
+
+	// Validate that the game has not started
+	if t.Running {
+		s.WarningWithCode(ErrStarted, StartedFail)
+		return
+	}
+
+	// Validate that they did not target themselves
+	if d.NewOwnerID == s.UserID() {
+		s.Warning("You are already the owner of this table.")
+		return
+	}
+
+	// Validate that the new owner is joined to the table
+	newOwnerIndex := t.GetPlayerIndexFromID(d.NewOwnerID)
+	if newOwnerIndex == -1 {
+		s.Warning("That player is not joined to this table.")
+		return
+	}
+
+	tableTransferOwner(t, newOwnerIndex, s.Username())
+}
+
+// tableTransferOwner gives the table to the player at the specified index
+// It is also used by administrators to rescue tables with an idle owner (from the localhost server)
+// The table mutex must be held when calling this function
+func tableTransferOwner(t *Table, newOwnerIndex int, transferredBy string) {
+	oldOwnerIndex := t.GetPlayerIndexFromID(t.Owner)
+	p := t.Players[newOwnerIndex]
+	t.Owner = p.ID
+
+	if !t.Running {
+		// On the pregame screen, the owner should always be the leftmost player,
+		// so we need to swap elements in the players slice
+		if oldOwnerIndex != -1 {
+			t.Players[oldOwnerIndex], t.Players[newOwnerIndex] = t.Players[newOwnerIndex], t.Players[oldOwnerIndex]
+		}
+
+		// Re-send the "game" message that draws the pregame screen
+		// and enables/disables the "Start Game" button
+		t.NotifyPlayerChange()
+	}
+
+	msg := transferredBy + " has passed table ownership to: " + p.Name
+	chatServerSend(msg, t.GetRoomName())
+}
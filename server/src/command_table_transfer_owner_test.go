@@ -0,0 +1,100 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func transferTestTable(tb *Table, fromIndex int, newOwnerID int) {
+	commandTableTransferOwner(tb.Players[fromIndex].Session, &CommandData{ // Manual invocation
+		TableID:    tb.ID,
+		NewOwnerID: newOwnerID,
+		NoLock:     true,
+	})
+}
+
+func TestCommandTableTransferOwner(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTable(t, 3)
+
+	transferTestTable(tb, 0, 3)
+	if tb.Owner != 3 {
+		t.Fatalf("expected the owner to be 3, but it is %v", tb.Owner)
+	}
+
+	// The new owner is moved to the leftmost seat on the pregame screen
+	if tb.Players[0].ID != 3 || tb.Players[2].ID != 1 {
+		t.Error("the old owner and the new owner did not swap seats")
+	}
+}
+
+func TestCommandTableTransferOwnerRejected(t *testing.T) {
+	resetTestTables(t)
+
+	// By a player who is not the owner
+	tb := newTestTable(t, 3)
+	transferTestTable(tb, 1, 3)
+	if tb.Owner != 1 {
+		t.Error("a player who is not the owner was able to transfer the table")
+	}
+
+	// To a user who is not joined to the table
+	transferTestTable(tb, 0, 1000)
+	if tb.Owner != 1 {
+		t.Error("the table was transferred to a user who is not joined to it")
+	}
+
+	// After the game has started
+	game := newTestGame(t, 3)
+	transferTestTable(game, 0, 2)
+	if game.Owner != 1 {
+		t.Error("the table was transferred after the game started")
+	}
+}
+
+func transferTestTableFromLocalhost(tableID uint64, newOwnerID int) *httptest.ResponseRecorder {
+	form := url.Values{}
+	form.Set("tableID", strconv.FormatUint(tableID, 10))
+	form.Set("newOwnerID", strconv.Itoa(newOwnerID))
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/transferOwner", strings.NewReader(form.Encode()))
+	c.Request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	httpLocalhostTransferOwner(c)
+
+	return w
+}
+
+func TestHttpLocalhostTransferOwner(t *testing.T) {
+	resetTestTables(t)
+
+	// An administrator can transfer a game that has already started
+	tb := newTestGame(t, 3)
+	if w := transferTestTableFromLocalhost(tb.ID, 2); w.Code != http.StatusOK {
+		t.Fatalf("expected a status code of %v, but got %v: %v", http.StatusOK, w.Code, w.Body)
+	}
+	if tb.Owner != 2 {
+		t.Errorf("expected the owner to be 2, but it is %v", tb.Owner)
+	}
+
+	// But not to a user who is not joined to the table
+	if w := transferTestTableFromLocalhost(tb.ID, 1000); w.Code != http.StatusBadRequest {
+		t.Errorf("expected a status code of %v, but got %v", http.StatusBadRequest, w.Code)
+	}
+	if tb.Owner != 2 {
+		t.Error("the table was transferred to a user who is not joined to it")
+	}
+
+	// Or for a table that does not exist
+	if w := transferTestTableFromLocalhost(1000, 1); w.Code != http.StatusBadRequest {
+		t.Errorf("expected a status code of %v, but got %v", http.StatusBadRequest, w.Code)
+	}
+}
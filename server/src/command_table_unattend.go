@@ -2,6 +2,7 @@ package main
 
 import (
 	"strconv"
+	"time"
 )
 
 // commandTableUnattend is sent when the user clicks on the "Lobby" button while they are:
@@ -62,8 +63,15 @@ func tableUnattendPlayer(s *Session, t *Table, i int) {
 	// (or set them to "AWAY" if the game has not started yet)
 	p := t.Players[i]
 	p.Present = false
+	p.DatetimeAbsent = time.Now()
 
 	if t.Running {
+		// Players that are not here should not count towards a pause vote
+		t.Game.Players[i].VotedPause = false
+
+		// Extensions require the approval of everyone, so this also cancels any extension request
+		t.Game.ClearExtensionRequest()
+
 		t.NotifyConnected()
 	} else {
 		t.NotifyPlayerChange()
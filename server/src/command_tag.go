@@ -29,7 +29,7 @@ func commandTag(s *Session, d *CommandData) {
 	}
 
 	if !t.Running {
-		s.Warning(NotStartedFail)
+		s.WarningWithCode(ErrNotStarted, NotStartedFail)
 		return
 	}
 
@@ -0,0 +1,61 @@
+package main
+
+// commandUserActiveTable is sent by the client after it reconnects to find out whether the user
+// should be put back into a table
+// The server responds with the table that the user is seated at
+// (or the replay that they are watching, if they are not seated anywhere)
+// If the user is not at any table, the table ID will be null
+//
+// Has no data
+func commandUserActiveTable(s *Session, d *CommandData) {
+	type UserActiveTableMessage struct {
+		TableID *uint64 `json:"tableID"`
+		Status  string  `json:"status,omitempty"` // "unstarted", "running", or "replay"
+	}
+	msg := &UserActiveTableMessage{}
+
+	if t := getUserActiveTable(s.UserID()); t != nil {
+		tableID := t.ID
+		msg.TableID = &tableID
+		if t.Replay {
+			msg.Status = "replay"
+		} else if t.Running {
+			msg.Status = "running"
+		} else {
+			msg.Status = "unstarted"
+		}
+	}
+
+	s.Emit("userActiveTable", msg)
+}
+
+// getUserActiveTable returns the game that the user is seated at
+// Since the players of a game remain in the table after it becomes a shared replay,
+// replays are matched by spectators instead
+func getUserActiveTable(userID int) *Table {
+	tablesMutex.RLock()
+	defer tablesMutex.RUnlock()
+
+	var replayTable *Table
+	for _, t := range tables {
+		if t.Replay {
+			if replayTable == nil {
+				for _, sp := range t.Spectators {
+					if sp.ID == userID {
+						replayTable = t
+						break
+					}
+				}
+			}
+			continue
+		}
+
+		for _, p := range t.Players {
+			if p.ID == userID {
+				return t
+			}
+		}
+	}
+
+	return replayTable
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type testUserActiveTableMessage struct {
+	TableID *uint64 `json:"tableID"`
+	Status  string  `json:"status"`
+}
+
+func getTestUserActiveTable(t *testing.T, id int, name string) *testUserActiveTableMessage {
+	s, conn := newTestWebsocket(t, id, name, 0)
+	commandUserActiveTable(s, &CommandData{})
+
+	data := readTestCommand(t, conn, "userActiveTable")
+	var msg testUserActiveTableMessage
+	if err := json.Unmarshal([]byte(data), &msg); err != nil {
+		t.Fatal("failed to unmarshal the \"userActiveTable\" message:", err)
+	}
+
+	return &msg
+}
+
+func expectTestUserActiveTable(
+	t *testing.T,
+	msg *testUserActiveTableMessage,
+	tb *Table,
+	status string,
+) {
+	if msg.TableID == nil || *msg.TableID != tb.ID {
+		t.Errorf("expected the active table to be %v, but got %v", tb.ID, msg.TableID)
+	}
+	if msg.Status != status {
+		t.Errorf("expected a status of \"%v\", but got \"%v\"", status, msg.Status)
+	}
+}
+
+func TestUserActiveTableRunning(t *testing.T) {
+	resetTestTables(t)
+	newTestTable(t, 2)
+	tb := newTestGame(t, 2)
+	tb.Players[1].ID = 10
+
+	msg := getTestUserActiveTable(t, 10, "Dan")
+	expectTestUserActiveTable(t, msg, tb, "running")
+}
+
+func TestUserActiveTableUnstarted(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTable(t, 2)
+	tb.Players[1].ID = 10
+
+	msg := getTestUserActiveTable(t, 10, "Dan")
+	expectTestUserActiveTable(t, msg, tb, "unstarted")
+}
+
+func TestUserActiveTableReplay(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+	tb.Replay = true
+	newTestSpectator(tb, 10, "Dan")
+
+	msg := getTestUserActiveTable(t, 10, "Dan")
+	expectTestUserActiveTable(t, msg, tb, "replay")
+}
+
+func TestUserActiveTablePrefersSeatedTables(t *testing.T) {
+	resetTestTables(t)
+	replay := newTestGame(t, 2)
+	replay.Replay = true
+	newTestSpectator(replay, 10, "Dan")
+	tb := newTestTable(t, 2)
+	tb.Players[1].ID = 10
+
+	msg := getTestUserActiveTable(t, 10, "Dan")
+	expectTestUserActiveTable(t, msg, tb, "unstarted")
+}
+
+func TestUserActiveTableNone(t *testing.T) {
+	resetTestTables(t)
+	newTestGame(t, 2)
+
+	msg := getTestUserActiveTable(t, 10, "Dan")
+	if msg.TableID != nil {
+		t.Errorf("expected no active table, but got %v", *msg.TableID)
+	}
+	if msg.Status != "" {
+		t.Errorf("expected no status, but got \"%v\"", msg.Status)
+	}
+}
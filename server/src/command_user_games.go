@@ -0,0 +1,108 @@
+package main
+
+const (
+	// The maximum number of recently finished games that are sent in response to the "userGames"
+	// command
+	MaxUserRecentGames = 10
+)
+
+type UserLiveGame struct {
+	TableID     uint64   `json:"tableID"`
+	Name        string   `json:"name"`
+	Variant     string   `json:"variant"`
+	PlayerNames []string `json:"playerNames"`
+	Status      string   `json:"status"` // "unstarted" or "running"
+}
+
+type UserRecentGame struct {
+	*GameHistory
+	SharedReplayTableID *uint64 `json:"sharedReplayTableID"`
+}
+
+type UserGamesMessage struct {
+	Live   []*UserLiveGame   `json:"live"`
+	Recent []*UserRecentGame `json:"recent"`
+}
+
+// commandUserGames is sent when the user wants a list of the games that they are currently
+// playing and the games that they have recently finished (e.g. to show a personal dashboard)
+// If a recently finished game is still being watched as a shared replay,
+// the ID of the replay table is included so that the client can link to it
+//
+// Has no data
+func commandUserGames(s *Session, d *CommandData) {
+	// Get the recently finished games from the database
+	var gameIDs []int
+	if v, err := models.Games.GetGameIDsUser(s.UserID(), 0, MaxUserRecentGames); err != nil {
+		logger.Error("Failed to get the game IDs for user \""+s.Username()+"\":", err)
+		s.Error(DefaultErrorMsg)
+		return
+	} else {
+		gameIDs = v
+	}
+
+	var gameHistoryList []*GameHistory
+	if v, err := models.Games.GetHistory(gameIDs); err != nil {
+		logger.Error("Failed to get the history:", err)
+		s.Error(DefaultErrorMsg)
+		return
+	} else {
+		gameHistoryList = v
+	}
+
+	s.Emit("userGames", getUserGames(s.UserID(), gameHistoryList))
+}
+
+// getUserGames finds the games that the user is playing in and the shared replays of their recent
+// games
+func getUserGames(userID int, gameHistoryList []*GameHistory) *UserGamesMessage {
+	msg := &UserGamesMessage{
+		Live:   make([]*UserLiveGame, 0),
+		Recent: make([]*UserRecentGame, 0),
+	}
+
+	sharedReplayTableIDs := make(map[int]uint64)
+	tablesMutex.RLock()
+	for _, t := range tables {
+		if t.Replay {
+			if t.ExtraOptions.DatabaseID > 0 {
+				sharedReplayTableIDs[t.ExtraOptions.DatabaseID] = t.ID
+			}
+			continue
+		}
+
+		playerIndex := t.GetPlayerIndexFromID(userID)
+		if playerIndex == -1 {
+			continue
+		}
+
+		playerNames := make([]string, 0)
+		for _, p := range t.Players {
+			playerNames = append(playerNames, p.Name)
+		}
+		status := "unstarted"
+		if t.Running {
+			status = "running"
+		}
+		msg.Live = append(msg.Live, &UserLiveGame{
+			TableID:     t.ID,
+			Name:        t.Name,
+			Variant:     t.Options.VariantName,
+			PlayerNames: playerNames,
+			Status:      status,
+		})
+	}
+	tablesMutex.RUnlock()
+
+	for _, gameHistory := range gameHistoryList {
+		recentGame := &UserRecentGame{
+			GameHistory: gameHistory,
+		}
+		if tableID, ok := sharedReplayTableIDs[gameHistory.ID]; ok {
+			recentGame.SharedReplayTableID = &tableID
+		}
+		msg.Recent = append(msg.Recent, recentGame)
+	}
+
+	return msg
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetUserGames(t *testing.T) {
+	resetTestTables(t)
+	live := newTestGame(t, 2)
+	newTestTable(t, 1) // Bob is not at this table
+	for _, p := range newTestTable(t, 2).Players {
+		p.ID += 10
+	}
+
+	// One of the recent games is still being watched as a shared replay
+	replay := newTestTable(t, 2)
+	replay.Replay = true
+	replay.ExtraOptions.DatabaseID = 101
+	recentGames := []*GameHistory{
+		{
+			ID:          101,
+			PlayerNames: []string{"Alice", "Bob"},
+		},
+		{
+			ID:          100,
+			PlayerNames: []string{"Alice", "Cathy"},
+		},
+	}
+
+	msg := getUserGames(2, recentGames)
+	if len(msg.Live) != 1 {
+		t.Fatalf("expected 1 live game, but got %v", len(msg.Live))
+	}
+	liveGame := msg.Live[0]
+	if liveGame.TableID != live.ID || liveGame.Status != "running" ||
+		liveGame.Variant != live.Options.VariantName ||
+		!reflect.DeepEqual(liveGame.PlayerNames, []string{"Alice", "Bob"}) {
+
+		t.Errorf("the live game is not correct: %+v", liveGame)
+	}
+
+	if len(msg.Recent) != 2 {
+		t.Fatalf("expected 2 recent games, but got %v", len(msg.Recent))
+	}
+	if msg.Recent[0].ID != 101 || msg.Recent[0].SharedReplayTableID == nil ||
+		*msg.Recent[0].SharedReplayTableID != replay.ID {
+
+		t.Errorf("the recent game with a shared replay does not link to it: %+v", msg.Recent[0])
+	}
+	if msg.Recent[1].ID != 100 || msg.Recent[1].SharedReplayTableID != nil {
+		t.Errorf("the recent game without a shared replay is not correct: %+v", msg.Recent[1])
+	}
+}
+
+func TestGetUserGamesUnstarted(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTable(t, 3)
+
+	msg := getUserGames(3, nil)
+	if len(msg.Live) != 1 || msg.Live[0].TableID != tb.ID || msg.Live[0].Status != "unstarted" {
+		t.Errorf("the unstarted table was not listed: %+v", msg.Live)
+	}
+	if msg.Recent == nil || len(msg.Recent) != 0 {
+		t.Errorf("expected an empty list of recent games, but got %v", msg.Recent)
+	}
+
+	// Other users are not playing in anything
+	if msg := getUserGames(10, nil); len(msg.Live) != 0 {
+		t.Errorf("expected no live games for another user, but got %v", len(msg.Live))
+	}
+}
+
+func TestCommandUserGames(t *testing.T) {
+	if db == nil {
+		t.Skip("getting the recently finished games requires a database")
+	}
+
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+	s, conn := newTestWebsocket(t, 1, "Alice", 0)
+	tb.Players[0].Session = s
+
+	commandUserGames(s, &CommandData{}) // Manual invocation
+	readTestCommand(t, conn, "userGames")
+}
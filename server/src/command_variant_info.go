@@ -0,0 +1,99 @@
+package main
+
+// commandVariantInfo is sent when the client (or a bot) needs to know the structure of a variant
+// without hardcoding it
+//
+// Example data:
+// {
+//   variantName: 'Rainbow (6 Suits)',
+// }
+func commandVariantInfo(s *Session, d *CommandData) {
+	// Validate the variant name
+	variant, ok := variants[d.VariantName]
+	if !ok {
+		s.WarningWithCode(ErrVariantNotFound,
+			"The variant of \""+d.VariantName+"\" does not exist.")
+		return
+	}
+
+	variantInfo(s, variant)
+}
+
+type VariantInfoSuit struct {
+	Name         string   `json:"name"`
+	DisplayName  string   `json:"displayName"`
+	Abbreviation string   `json:"abbreviation"`
+	ClueColors   []string `json:"clueColors"`
+	// The fills of the clue colors that touch the suit, in the same order
+	// (a suit that is touched by more than one color is displayed as a mix of them)
+	DisplayColors []string `json:"displayColors"`
+	OneOfEach     bool     `json:"oneOfEach"`
+	Reversed      bool     `json:"reversed"`
+	AllClueColors bool     `json:"allClueColors"`
+	AllClueRanks  bool     `json:"allClueRanks"`
+	NoClueColors  bool     `json:"noClueColors"`
+	NoClueRanks   bool     `json:"noClueRanks"`
+}
+
+type VariantInfoClueColor struct {
+	Name string `json:"name"`
+	Fill string `json:"fill"`
+}
+
+func variantInfo(s *Session, variant *Variant) {
+	suits := make([]*VariantInfoSuit, 0, len(variant.Suits))
+	for _, suit := range variant.Suits {
+		displayColors := make([]string, 0, len(suit.ClueColors))
+		for _, colorName := range suit.ClueColors {
+			if color, ok := colors[colorName]; ok {
+				displayColors = append(displayColors, color.Fill)
+			}
+		}
+
+		suits = append(suits, &VariantInfoSuit{
+			Name:          suit.Name,
+			DisplayName:   suit.DisplayName,
+			Abbreviation:  suit.Abbreviation,
+			ClueColors:    suit.ClueColors,
+			DisplayColors: displayColors,
+			OneOfEach:     suit.OneOfEach,
+			Reversed:      suit.Reversed,
+			AllClueColors: suit.AllClueColors,
+			AllClueRanks:  suit.AllClueRanks,
+			NoClueColors:  suit.NoClueColors,
+			NoClueRanks:   suit.NoClueRanks,
+		})
+	}
+
+	clueColors := make([]*VariantInfoClueColor, 0, len(variant.ClueColors))
+	for _, colorName := range variant.ClueColors {
+		clueColor := &VariantInfoClueColor{
+			Name: colorName,
+		}
+		if color, ok := colors[colorName]; ok {
+			clueColor.Fill = color.Fill
+		}
+		clueColors = append(clueColors, clueColor)
+	}
+
+	type VariantInfoMessage struct {
+		Name       string                  `json:"name"`
+		ID         int                     `json:"id"`
+		Suits      []*VariantInfoSuit      `json:"suits"`
+		Ranks      []int                   `json:"ranks"`
+		ClueColors []*VariantInfoClueColor `json:"clueColors"`
+		ClueRanks  []int                   `json:"clueRanks"`
+		MaxStrikes int                     `json:"maxStrikes"`
+		MaxScore   int                     `json:"maxScore"`
+	}
+	s.Emit("variantInfo", &VariantInfoMessage{
+		Name:       variant.Name,
+		ID:         variant.ID,
+		Suits:      suits,
+		Ranks:      variant.Ranks,
+		ClueColors: clueColors,
+		ClueRanks:  variant.ClueRanks,
+		MaxStrikes: MaxStrikeNum,
+		MaxScore:   variant.MaxScore,
+	})
+}
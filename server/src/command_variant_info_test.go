@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+type testVariantInfo struct {
+	Name       string                  `json:"name"`
+	ID         int                     `json:"id"`
+	Suits      []*VariantInfoSuit      `json:"suits"`
+	Ranks      []int                   `json:"ranks"`
+	ClueColors []*VariantInfoClueColor `json:"clueColors"`
+	ClueRanks  []int                   `json:"clueRanks"`
+	MaxStrikes int                     `json:"maxStrikes"`
+	MaxScore   int                     `json:"maxScore"`
+}
+
+func getTestVariantInfo(t *testing.T, variantName string) *testVariantInfo {
+	s, conn := newTestWebsocket(t, 1, "Alice", 0)
+	commandVariantInfo(s, &CommandData{ // Manual invocation
+		VariantName: variantName,
+	})
+
+	var info testVariantInfo
+	if err := json.Unmarshal([]byte(readTestCommand(t, conn, "variantInfo")), &info); err != nil {
+		t.Fatal("failed to unmarshal the variant info:", err)
+	}
+
+	return &info
+}
+
+func TestCommandVariantInfoStandard(t *testing.T) {
+	info := getTestVariantInfo(t, "No Variant")
+
+	if info.Name != "No Variant" || info.ID != variants["No Variant"].ID {
+		t.Errorf("the info is for variant %v (%v)", info.Name, info.ID)
+	}
+	if info.MaxScore != 25 || info.MaxStrikes != MaxStrikeNum {
+		t.Errorf("expected a max score of 25 and %v strikes, but got %v and %v", MaxStrikeNum,
+			info.MaxScore, info.MaxStrikes)
+	}
+	if !reflect.DeepEqual(info.Ranks, []int{1, 2, 3, 4, 5}) ||
+		!reflect.DeepEqual(info.ClueRanks, []int{1, 2, 3, 4, 5}) {
+
+		t.Errorf("expected ranks 1 through 5, but got %v and clue ranks %v", info.Ranks,
+			info.ClueRanks)
+	}
+
+	expectedSuits := []string{"Red", "Yellow", "Green", "Blue", "Purple"}
+	if len(info.Suits) != len(expectedSuits) || len(info.ClueColors) != len(expectedSuits) {
+		t.Fatalf("expected 5 suits and 5 clue colors, but got %v and %v", len(info.Suits),
+			len(info.ClueColors))
+	}
+	for i, suit := range info.Suits {
+		if suit.Name != expectedSuits[i] {
+			t.Errorf("expected suit %v to be %v, but got %v", i, expectedSuits[i], suit.Name)
+		}
+
+		// Each suit is touched by the clue color of the same name
+		if !reflect.DeepEqual(suit.ClueColors, []string{expectedSuits[i]}) ||
+			len(suit.DisplayColors) != 1 || suit.DisplayColors[0] != colors[expectedSuits[i]].Fill {
+
+			t.Errorf("expected the %v suit to be touched by %v, but got %v (displayed as %v)",
+				suit.Name, expectedSuits[i], suit.ClueColors, suit.DisplayColors)
+		}
+		if clueColor := info.ClueColors[i]; clueColor.Name != expectedSuits[i] ||
+			clueColor.Fill != colors[expectedSuits[i]].Fill {
+
+			t.Errorf("expected clue color %v to be %v, but got %+v", i, expectedSuits[i],
+				clueColor)
+		}
+	}
+}
+
+func TestCommandVariantInfoMultiColor(t *testing.T) {
+	info := getTestVariantInfo(t, "Rainbow (6 Suits)")
+	if len(info.Suits) != 6 || info.MaxScore != 30 {
+		t.Fatalf("expected 6 suits and a max score of 30, but got %v and %v", len(info.Suits),
+			info.MaxScore)
+	}
+
+	// The rainbow suit is touched by every color, but there is no rainbow clue
+	rainbow := info.Suits[5]
+	if rainbow.Name != "Rainbow" || !rainbow.AllClueColors {
+		t.Errorf("expected the last suit to be touched by all clue colors, but got %+v", rainbow)
+	}
+	if len(info.ClueColors) != 5 {
+		t.Errorf("expected 5 clue colors, but got %v", len(info.ClueColors))
+	}
+	for _, clueColor := range info.ClueColors {
+		if clueColor.Name == "Rainbow" {
+			t.Error("there is a rainbow clue color")
+		}
+	}
+
+	// Dual-color suits are displayed as a mix of both of their clue colors
+	info = getTestVariantInfo(t, "Dual-Color (6 Suits)")
+	for _, suit := range info.Suits {
+		if len(suit.ClueColors) != 2 || len(suit.DisplayColors) != 2 {
+			t.Errorf("expected the %v suit to have 2 clue colors and 2 display colors, but got "+
+				"%v and %v", suit.Name, suit.ClueColors, suit.DisplayColors)
+			continue
+		}
+		for i, colorName := range suit.ClueColors {
+			if suit.DisplayColors[i] != colors[colorName].Fill {
+				t.Errorf("expected the %v suit to be displayed with %v, but got %v", suit.Name,
+					colors[colorName].Fill, suit.DisplayColors[i])
+			}
+		}
+	}
+}
+
+func TestCommandVariantInfoUnknownVariant(t *testing.T) {
+	s, conn := newTestWebsocket(t, 1, "Alice", 0)
+	commandVariantInfo(s, &CommandData{ // Manual invocation
+		VariantName: "Not A Variant",
+	})
+
+	expectTestWarningCode(t, conn, ErrVariantNotFound)
+}
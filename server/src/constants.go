@@ -44,6 +44,7 @@ const (
 	EndConditionCharacterSoftlock
 	EndConditionAllOrNothingFail
 	EndConditionAllOrNothingSoftlock
+	EndConditionConceded
 )
 
 // When in a shared replay, spectators can send certain types of "actions" to the server to
@@ -84,6 +85,9 @@ const (
 	ProjectName = "hanabi-live-server"
 	WebsiteName = "Hanab Live"
 
+	// The maximum amount of players that can be in a game
+	MaxPlayers = 6
+
 	// The maximum amount of clues (and the amount of clues that players start the game with)
 	MaxClueNum = 8
 
@@ -101,8 +105,17 @@ const (
 	// a server shutdown or restart is initiated
 	ShutdownTimeout = time.Minute * 30
 
-	// The amount of time that a game is inactive before it is killed by the server
-	IdleGameTimeout = time.Minute * 30
+	// The default amount of time that a game is inactive before it is killed by the server
+	// and the default point at which everyone at the table is warned about it
+	// (as a percentage of the idle timeout)
+	// Unstarted tables are killed sooner, since there is nothing to lose
+	DefaultIdleGameTimeout      = time.Minute * 30
+	DefaultIdleUnstartedTimeout = time.Minute * 10
+	DefaultIdleWarningPercent   = 80
+
+	// The amount of time to wait for ongoing WebSocket commands to finish when the WebSocket
+	// server is shut down
+	WebSocketShutdownTimeout = time.Second * 10
 
 	// We want to validate string inputs for too many consecutive diacritics
 	// This prevents the attack where messages can have a lot of diacritics and cause overflow
@@ -121,3 +134,51 @@ const (
 	NotStartedFail  = "The game has not started yet, so you cannot use that command."
 	NotOwnerFail    = "Only the table owner can use that command."
 )
+
+// Some warnings and errors are sent along with a code so that clients can react to them
+// programmatically instead of having to match the text of the message
+// These must never be changed, since clients rely on them
+const (
+	ErrNotYourTurn      = "ERR_NOT_YOUR_TURN"
+	ErrPaused           = "ERR_PAUSED"
+	ErrInvalidCard      = "ERR_INVALID_CARD"
+	ErrInvalidClue      = "ERR_INVALID_CLUE"
+	ErrClueRestricted   = "ERR_CLUE_RESTRICTED"
+	ErrTableFull        = "ERR_TABLE_FULL"
+	ErrTableNotFound    = "ERR_TABLE_NOT_FOUND"
+	ErrAlreadyAtTable   = "ERR_ALREADY_AT_TABLE"
+	ErrWrongPassword    = "ERR_WRONG_PASSWORD"
+	ErrNotOwner         = "ERR_NOT_OWNER"
+	ErrStarted          = "ERR_STARTED"
+	ErrNotStarted       = "ERR_NOT_STARTED"
+	ErrNotReplay        = "ERR_NOT_REPLAY"
+	ErrVariantNotFound  = "ERR_VARIANT_NOT_FOUND"
+	ErrServerAtCapacity = "ERR_SERVER_AT_CAPACITY"
+	ErrRateLimited      = "ERR_RATE_LIMITED"
+)
+
+// When the server closes a WebSocket connection, it sends one of these codes along with a reason
+// so that clients can show an appropriate message and decide whether or not to reconnect
+// (the WebSocket specification reserves 4000 through 4999 for applications)
+// These must never be changed, since clients rely on them
+const (
+	// The user logged in from somewhere else (clients should not reconnect)
+	CloseCodeDuplicateSession   = 4000
+	CloseReasonDuplicateSession = "Logged in from another location."
+
+	// An administrator logged the user out (clients should not reconnect)
+	CloseCodeKicked   = 4001
+	CloseReasonKicked = "Logged out by an administrator."
+
+	// The server is shutting down or restarting (clients can reconnect after a while)
+	CloseCodeShutdown   = 4002
+	CloseReasonShutdown = "The server is shutting down."
+
+	// The user sent too many commands too quickly (clients should not reconnect right away)
+	CloseCodeRateLimited   = 4003
+	CloseReasonRateLimited = "Sent commands too quickly."
+
+	// The user was not reading the messages from the server fast enough (clients can reconnect)
+	CloseCodeSendBufferFull   = 4004
+	CloseReasonSendBufferFull = "Too many messages were waiting to be sent."
+)
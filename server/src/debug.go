@@ -60,6 +60,7 @@ func debugPrint() {
 			"Players",
 			"Spectators",
 			"DisconSpectators",
+			"SpectatorIDs",
 
 			"Options",
 		}
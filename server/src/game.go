@@ -57,6 +57,8 @@ type Game struct {
 	Actions2              []*GameAction
 	InvalidActionOccurred bool // Used when emulating game actions in replays
 	EndCondition          int  // The values for this are listed in "constants.go"
+	// Set when the game was reported for having too many actions (see "game_actions_limit.go")
+	ActionsLimitReported bool
 	// The index of the player who ended the game, if any
 	// (needed for writing a "game over" terminate action to the database)
 	EndPlayer int
@@ -69,14 +71,27 @@ type Game struct {
 	Paused           bool
 	PausePlayerIndex int
 	PauseCount       int
+	// Set when the game was automatically paused because the active player disconnected
+	// (see "game_disconnect.go")
+	DisconnectPaused bool
+	// The last time that the table was serialized, so that the clock of the active player can be
+	// restored precisely (see "serialize_tables.go")
+	DatetimeSerialized time.Time
 
 	// Shared replay fields
 	EfficiencyMod int
 
+	// Shared review fields (for reviewing the past turns of an ongoing game together)
+	Reviewing    bool
+	ReviewTurn   int
+	ReviewPaused bool // True if the game was automatically paused when the review started
+
 	// Hypothetical-related fields
 	Hypothetical        bool // Whether or not we are in a post-game hypothetical
 	HypoActions         []string
 	HypoDrawnCardsShown bool // Whether or not drawn cards should be revealed (false by default)
+	// The segment that the "replayHypothetical" command started the hypothetical from
+	HypoStartSegment int
 
 	// Keep track of user-defined tags; they will be written to the database upon game completion
 	Tags map[string]int // Keys are the tags, values are the user ID that created it
@@ -135,37 +150,50 @@ func (g *Game) CheckTimer(turn int, pauseCount int, gp *GamePlayer) {
 	t := g.Table
 
 	// Sleep until the active player runs out of time
-	time.Sleep(gp.Time)
-
-	// Check to see if the table still exists
-	t2, exists := getTableAndLock(nil, t.ID, false)
-	if !exists || t != t2 {
-		return
-	}
+	// (their time can be changed by other commands, so it must be read while holding the table lock;
+	// the caller is usually still holding it and this will wait for them to finish)
 	t.Mutex.Lock()
-	defer t.Mutex.Unlock()
-
-	// Check to see if we have made a move in the meanwhile
-	if turn != g.Turn {
-		return
-	}
-
-	// Check to see if the game is currently paused
-	if g.Paused {
-		return
-	}
+	sleepTime := gp.Time
+	t.Mutex.Unlock()
+	for {
+		time.Sleep(sleepTime)
+
+		// Check to see if the table still exists
+		t2, exists := getTableAndLock(nil, t.ID, false)
+		if !exists || t != t2 {
+			return
+		}
+		t.Mutex.Lock()
+
+		// Check to see if we have made a move in the meanwhile
+		// or if the game is currently paused
+		// or if the game was paused while we were sleeping
+		// or if the game ended already
+		if turn != g.Turn ||
+			g.Paused ||
+			pauseCount != g.PauseCount ||
+			g.EndCondition > EndConditionInProgress {
+
+			t.Mutex.Unlock()
+			return
+		}
 
-	// Check to see if the game was paused while we were sleeping
-	if pauseCount != g.PauseCount {
-		return
-	}
+		// The player may have been given more time in the meantime
+		// (e.g. if they disconnected and the clock was stopped for them)
+		if timeLeft := g.GetTimeLeft(gp.Index); timeLeft > 0 {
+			t.Mutex.Unlock()
+			sleepTime = timeLeft
+			continue
+		}
 
-	// Check to see if the game ended already
-	if g.EndCondition > EndConditionInProgress {
+		if t.Options.TimeoutAction == TimeoutActionAutoDiscard {
+			g.TimeoutDiscard(gp)
+		} else {
+			g.EndTimer(gp)
+		}
+		t.Mutex.Unlock()
 		return
 	}
-
-	g.EndTimer(gp)
 }
 
 // EndTimer is called when a player has run out of time in a timed game, which will automatically
@@ -210,7 +238,8 @@ func (g *Game) CheckEnd() bool {
 	if g.EndCondition == EndConditionTimeout ||
 		g.EndCondition == EndConditionTerminated ||
 		g.EndCondition == EndConditionIdleTimeout ||
-		g.EndCondition == EndConditionCharacterSoftlock {
+		g.EndCondition == EndConditionCharacterSoftlock ||
+		g.EndCondition == EndConditionConceded {
 
 		return true
 	}
@@ -332,29 +361,39 @@ func (g *Game) GetHandSizeForNormalGame() int {
 // GetMaxScore calculates what the maximum score is,
 // accounting for stacks that cannot be completed due to discarded cards
 func (g *Game) GetMaxScore() int {
+	maxScore := 0
+	for _, stackMaxScore := range g.GetMaxScorePerStack() {
+		maxScore += stackMaxScore
+	}
+
+	return maxScore
+}
+
+// GetMaxScorePerStack is like "GetMaxScore()", but it returns the maximum score for each stack
+func (g *Game) GetMaxScorePerStack() []int {
 	// Local variables
 	variant := variants[g.Options.VariantName]
 
 	// Getting the maximum score is much more complicated if we are playing a
 	// "Reversed" or "Up or Down" variant
 	if variant.HasReversedSuits() {
-		return variantReversibleGetMaxScore(g)
+		return variantReversibleGetMaxScorePerStack(g)
 	}
 
-	maxScore := 0
+	maxScorePerStack := make([]int, len(g.Stacks))
 	for suit := range g.Stacks {
 		for rank := 1; rank <= 5; rank++ {
 			// Search through the deck to see if all the copies of this card are discarded already
 			total, discarded := g.GetSpecificCardNum(suit, rank)
 			if total > discarded {
-				maxScore++
+				maxScorePerStack[suit]++
 			} else {
 				break
 			}
 		}
 	}
 
-	return maxScore
+	return maxScorePerStack
 }
 
 // GetSpecificCardNum returns the total cards in the deck of the specified suit and rank
@@ -374,6 +413,23 @@ func (g *Game) GetSpecificCardNum(suitIndex int, rank int) (int, int) {
 	return total, discarded
 }
 
+// GetPlayableRanks returns the ranks that would successfully play on the stack of the given suit
+// (ignoring any "Detrimental Character Assignment" restrictions)
+func (g *Game) GetPlayableRanks(suitIndex int) []int {
+	// Local variables
+	variant := variants[g.Options.VariantName]
+
+	if variant.HasReversedSuits() {
+		return variantReversibleGetPlayableRanks(g, suitIndex)
+	}
+
+	if g.Stacks[suitIndex] >= PointsPerSuit {
+		return make([]int, 0)
+	}
+
+	return []int{g.Stacks[suitIndex] + 1}
+}
+
 func (g *Game) GetNotesSize() int {
 	// Local variables
 	variant := variants[g.Options.VariantName]
@@ -383,3 +439,88 @@ func (g *Game) GetNotesSize() int {
 	numSuits := len(variant.Suits)
 	return numCards + numSuits
 }
+
+// GetActionsUpToTurn returns all of the actions that are necessary to reconstruct the state of the
+// game at the beginning of a particular turn
+// (since a replay is already over, we do not need to scrub any of the actions)
+func (g *Game) GetActionsUpToTurn(turn int) []interface{} {
+	if turn >= g.EndTurn {
+		return g.Actions
+	}
+
+	if turn == 0 {
+		// The beginning of the game only consists of the cards being dealt to the players
+		for i, a := range g.Actions {
+			if _, ok := a.(ActionDraw); !ok {
+				return g.Actions[:i]
+			}
+		}
+		return g.Actions
+	}
+
+	// A "turn" action is appended after all of the other actions for that turn are performed
+	for i, a := range g.Actions {
+		if turnAction, ok := a.(ActionTurn); ok && turnAction.Num == turn {
+			return g.Actions[:i+1]
+		}
+	}
+
+	return g.Actions
+}
+
+// ClearPauseVotes resets all of the votes from the "tablePauseVote" command
+func (g *Game) ClearPauseVotes() {
+	for _, p := range g.Players {
+		p.VotedPause = false
+	}
+}
+
+// ClearConcedeVotes resets all of the votes from the "tableConcede" command
+// It returns true if there were any votes to clear
+func (g *Game) ClearConcedeVotes() bool {
+	cleared := false
+	for _, p := range g.Players {
+		if p.VotedConcede {
+			p.VotedConcede = false
+			cleared = true
+		}
+	}
+
+	return cleared
+}
+
+// GetExtensionRequester returns the player with an outstanding request from the
+// "tableRequestExtension" command (or nil if there is no request)
+func (g *Game) GetExtensionRequester() *GamePlayer {
+	for _, p := range g.Players {
+		if p.RequestedExtension {
+			return p
+		}
+	}
+
+	return nil
+}
+
+// ClearExtensionRequest cancels the outstanding request from the "tableRequestExtension" command,
+// if any
+func (g *Game) ClearExtensionRequest() {
+	for _, p := range g.Players {
+		p.RequestedExtension = false
+		p.ApprovedExtension = false
+	}
+}
+
+// GetTimeLeft returns the amount of time that a player has left on their clock
+// We could be in the middle of someone's turn, so the time taken so far is subtracted from the
+// active player's clock (unless the game is paused, since the time taken prior to the pause is
+// already subtracted when the game is paused)
+func (g *Game) GetTimeLeft(playerIndex int) time.Duration {
+	gp := g.Players[playerIndex]
+	timeLeft := gp.Time
+	if g.ActivePlayerIndex == playerIndex && !g.Paused {
+		timeLeft -= time.Since(g.DatetimeTurnBegin)
+		timeLeft += g.GetDisconnectCredit(gp)
+	}
+
+	return timeLeft
+}
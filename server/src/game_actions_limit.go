@@ -0,0 +1,55 @@
+package main
+
+import (
+	"os"
+	"strconv"
+)
+
+const (
+	// A normal game has a few hundred actions, so this should only ever be reached because of a bug
+	DefaultMaxGameActions = 5000
+)
+
+var (
+	// The number of actions after which a game is reported as abnormally long
+	// (0 means that games are never reported)
+	maxGameActions int
+)
+
+func maxGameActionsInit() {
+	maxGameActions = DefaultMaxGameActions
+	maxGameActionsString := os.Getenv("MAX_GAME_ACTIONS")
+	if len(maxGameActionsString) != 0 {
+		if v, err := strconv.Atoi(maxGameActionsString); err != nil {
+			logger.Fatal("Failed to convert the \"MAX_GAME_ACTIONS\" environment variable to a " +
+				"number.")
+			return
+		} else if v < 0 {
+			logger.Fatal("The \"MAX_GAME_ACTIONS\" environment variable cannot be negative.")
+			return
+		} else {
+			maxGameActions = v
+		}
+	}
+}
+
+// CheckActionsLimit reports a game that has more actions than the "MAX_GAME_ACTIONS" limit
+// The actions are still kept in memory (and serialized), since every one of them is needed for
+// replays and for writing the game to the database, so this only makes sure that we find out about
+// the game before it becomes a problem
+// The table mutex must be held when calling this function
+func (g *Game) CheckActionsLimit() {
+	// Local variables
+	t := g.Table
+
+	if maxGameActions == 0 || g.ActionsLimitReported || len(g.Actions) <= maxGameActions {
+		return
+	}
+
+	// Only report each game once so that we do not flood the logs
+	g.ActionsLimitReported = true
+	logger.Error(t.GetName() + "The game has " + strconv.Itoa(len(g.Actions)) + " actions, " +
+		"which is more than the limit of " + strconv.Itoa(maxGameActions) + ". " +
+		"(There are " + strconv.Itoa(len(g.Actions2)) + " moves on turn " +
+		strconv.Itoa(g.Turn) + ".)")
+}
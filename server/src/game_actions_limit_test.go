@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	logging "github.com/Zamiell/go-logging"
+)
+
+func useTestMaxGameActions(t *testing.T, limit int) {
+	oldMaxGameActions := maxGameActions
+	maxGameActions = limit
+	t.Cleanup(func() {
+		maxGameActions = oldMaxGameActions
+	})
+}
+
+// countTestLogs returns the number of log messages with the given level that contain the given
+// text
+func countTestLogs(backend *logging.MemoryBackend, level logging.Level, text string) int {
+	count := 0
+	for n := backend.Head(); n != nil; n = n.Next() {
+		if n.Record.Level == level && strings.Contains(n.Record.Message(), text) {
+			count++
+		}
+	}
+
+	return count
+}
+
+func TestCheckActionsLimit(t *testing.T) {
+	resetTestTables(t)
+	logs := captureTestLogs(t)
+	tb := newTestGame(t, 2)
+	g := tb.Game
+	useTestMaxGameActions(t, len(g.Actions)+1)
+
+	clueTestPlayer(t, tb)
+	if !g.ActionsLimitReported {
+		t.Fatal("the game was not reported after going over the limit")
+	}
+	if findTestLog(logs, logging.ERROR, "more than the limit of") == "" {
+		t.Error("the error for going over the limit was not logged")
+	}
+
+	// Each game is only reported once
+	clueTestPlayer(t, tb)
+	if v := countTestLogs(logs, logging.ERROR, "more than the limit of"); v != 1 {
+		t.Errorf("expected the game to be reported once, but it was reported %v times", v)
+	}
+}
+
+func TestCheckActionsLimitUnderLimit(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+	useTestMaxGameActions(t, len(tb.Game.Actions)+100)
+
+	clueTestPlayer(t, tb)
+	if tb.Game.ActionsLimitReported {
+		t.Error("the game was reported while under the limit")
+	}
+
+	// A limit of 0 disables the check
+	maxGameActions = 0
+	tb.Game.CheckActionsLimit()
+	if tb.Game.ActionsLimitReported {
+		t.Error("the game was reported with the limit disabled")
+	}
+}
+
+func TestActionsLimitReplaySeek(t *testing.T) {
+	resetTestTables(t)
+	useTestMaxGameActions(t, 1)
+	tb := newTestReplay(t)
+	g := tb.Game
+	if !g.ActionsLimitReported {
+		t.Fatal("the game was not reported after going over the limit")
+	}
+
+	// Every action is kept, so the replay can still go back to the beginning
+	sp, conn := newTestWebsocketSpectator(t, tb, 10, "Spectator")
+	for turn, expectedLength := range map[int]int{
+		0:         2 * g.GetHandSize(),
+		g.EndTurn: len(g.Actions),
+	} {
+		commandReplaySeek(sp.Session, &CommandData{ // Manual invocation
+			TableID: tb.ID,
+			Turn:    turn,
+		})
+
+		var msg testReplaySeekMessage
+		if err := json.Unmarshal([]byte(readTestCommand(t, conn, "replaySeek")), &msg); err != nil {
+			t.Fatal("failed to unmarshal the message:", err)
+		}
+		if len(msg.List) != expectedLength {
+			t.Errorf("expected %v actions for turn %v, but got %v", expectedLength, turn,
+				len(msg.List))
+		}
+	}
+}
+
+func TestMaxGameActionsInit(t *testing.T) {
+	useTestMaxGameActions(t, 0)
+
+	setTestEnv(t, "MAX_GAME_ACTIONS", "")
+	maxGameActionsInit()
+	if maxGameActions != DefaultMaxGameActions {
+		t.Errorf("expected the default limit of %v, but got %v", DefaultMaxGameActions,
+			maxGameActions)
+	}
+
+	setTestEnv(t, "MAX_GAME_ACTIONS", "100")
+	maxGameActionsInit()
+	if maxGameActions != 100 {
+		t.Errorf("expected a limit of 100, but got %v", maxGameActions)
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// How often ongoing games are checked for a state where the active player cannot do anything
+	DeadlockCheckInterval = time.Minute
+)
+
+// deadlockCheckLoop is meant to be run in a goroutine
+// A bug in the game logic could leave a game in a state where no legal move exists without the
+// game being over, which would otherwise cause it to hang until it is killed for being idle
+func deadlockCheckLoop() {
+	for {
+		time.Sleep(DeadlockCheckInterval)
+
+		tableIDs := make([]uint64, 0)
+		tablesMutex.RLock()
+		for _, t := range tables {
+			tableIDs = append(tableIDs, t.ID)
+		}
+		tablesMutex.RUnlock()
+
+		for _, tableID := range tableIDs {
+			t, exists := getTableAndLock(nil, tableID, true)
+			if !exists {
+				continue
+			}
+			checkDeadlock(t)
+			t.Mutex.Unlock()
+		}
+	}
+}
+
+// checkDeadlock ends the game if it is stuck
+// The table lock must be held when calling this function
+func checkDeadlock(t *Table) {
+	if !t.Running || t.Replay {
+		return
+	}
+
+	// Local variables
+	g := t.Game
+
+	if g.EndCondition != EndConditionInProgress || !detectDeadlock(g) {
+		return
+	}
+
+	// Log the state of the game so that the bug can be found later
+	p := g.Players[g.ActivePlayerIndex]
+	handSizes := make([]string, 0)
+	for _, gp := range g.Players {
+		handSizes = append(handSizes, strconv.Itoa(len(gp.Hand)))
+	}
+	logger.ErrorWithFields(LogFields{
+		"tableID":      t.ID,
+		"turn":         g.Turn,
+		"activePlayer": p.Name,
+		"clueTokens":   g.ClueTokens,
+		"deckIndex":    g.DeckIndex,
+		"deckSize":     len(g.Deck),
+		"handSizes":    strings.Join(handSizes, ","),
+		"endTurn":      g.EndTurn,
+	}, t.GetName()+"The active player has no legal moves; ending the game.")
+
+	// End the game as if it had finished normally so that the score is kept
+	g.EndCondition = EndConditionNormal
+	g.EndPlayer = -1
+	g.Actions = append(g.Actions, ActionGameOver{
+		Type:         "gameOver",
+		EndCondition: g.EndCondition,
+		PlayerIndex:  g.EndPlayer,
+	})
+	t.NotifyGameAction()
+	t.NotifyTurn()
+	g.End()
+}
+
+// detectDeadlock returns true if the active player does not have any legal move
+// (i.e. they have no cards to play and they cannot give any clue)
+// "Detrimental Character Assignments" restrictions are not considered,
+// since softlocks from those are already handled in the "characterCheckSoftlock()" function
+func detectDeadlock(g *Game) bool {
+	// Local variables
+	variant := variants[g.Options.VariantName]
+	p := g.Players[g.ActivePlayerIndex]
+
+	// A player can always play a card if they have one (even if it is a misplay)
+	if len(p.Hand) > 0 {
+		return false
+	}
+	if g.Options.DeckPlays && g.DeckIndex == len(g.Deck)-1 {
+		return false
+	}
+
+	// Check to see if they can give a clue to anyone
+	if g.ClueTokens < variant.GetAdjustedClueTokens(1) {
+		return true
+	}
+	for _, p2 := range g.Players {
+		if p2.Index == p.Index {
+			continue
+		}
+
+		// The index of each element corresponds to the clue type
+		for clueType, numValues := range []int{len(variant.ClueColors), len(variant.ClueRanks)} {
+			if variant.IsAlternatingClues() && clueType == g.LastClueTypeGiven {
+				continue
+			}
+			if !g.Options.IsClueTypeAllowed(clueType) {
+				continue
+			}
+			if g.Options.EmptyClues ||
+				(clueType == ClueTypeColor && variant.ColorCluesTouchNothing) ||
+				(clueType == ClueTypeRank && variant.RankCluesTouchNothing) {
+
+				if numValues > 0 {
+					return false
+				}
+				continue
+			}
+
+			for i := 0; i < numValues; i++ {
+				clue := Clue{
+					Type:  clueType,
+					Value: i,
+				}
+				if clueType == ClueTypeRank {
+					clue.Value = variant.ClueRanks[i]
+				}
+				for _, c := range p2.Hand {
+					if variantIsCardTouched(g.Options.VariantName, clue, c) {
+						return false
+					}
+				}
+			}
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"testing"
+
+	logging "github.com/Zamiell/go-logging"
+)
+
+// emptyTestHand removes every card from the hand of a player
+// (this is not possible in a normal game, which is the point of the deadlock check)
+func emptyTestHand(gp *GamePlayer) {
+	gp.Hand = make([]*Card, 0)
+}
+
+func TestDetectDeadlock(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 3)
+	g := tb.Game
+	active := g.Players[g.ActivePlayerIndex]
+
+	if detectDeadlock(g) {
+		t.Fatal("a deadlock was detected at the start of a game")
+	}
+
+	// They can still give a clue to a teammate
+	emptyTestHand(active)
+	if detectDeadlock(g) {
+		t.Error("a deadlock was detected when the active player can give a clue")
+	}
+
+	// There is nothing that they can do without clues
+	g.ClueTokens = 0
+	if !detectDeadlock(g) {
+		t.Error("a deadlock was not detected when the active player cannot do anything")
+	}
+
+	// Clues that touch no cards are not legal
+	g.ClueTokens = MaxClueNum
+	for _, gp := range g.Players {
+		emptyTestHand(gp)
+	}
+	if !detectDeadlock(g) {
+		t.Error("a deadlock was not detected when nobody has any cards to clue")
+	}
+
+	// ...unless the table allows them
+	g.Options.EmptyClues = true
+	if detectDeadlock(g) {
+		t.Error("a deadlock was detected when empty clues are allowed")
+	}
+}
+
+func TestCheckDeadlockEndsGame(t *testing.T) {
+	resetTestTables(t)
+	logs := captureTestLogs(t)
+	tb := newTestGame(t, 2)
+	g := tb.Game
+	g.Score = 2
+	emptyTestHand(g.Players[g.ActivePlayerIndex])
+	g.ClueTokens = 0
+
+	tb.Mutex.Lock()
+	checkDeadlock(tb)
+	tb.Mutex.Unlock()
+
+	// The game is finished normally (so that the score is kept)
+	if g.EndCondition != EndConditionNormal {
+		t.Fatalf("expected the game to end with an end condition of %v, but got %v",
+			EndConditionNormal, g.EndCondition)
+	}
+	if g.Score != 2 {
+		t.Errorf("expected the score of 2 to be kept, but it is %v", g.Score)
+	}
+	foundGameOver := false
+	for _, action := range g.Actions {
+		if gameOver, ok := action.(ActionGameOver); ok &&
+			gameOver.EndCondition == EndConditionNormal {
+
+			foundGameOver = true
+		}
+	}
+	if !foundGameOver {
+		t.Error("the players were not sent a game over action")
+	}
+
+	// The details are logged so that the bug can be found
+	if findTestLog(logs, logging.ERROR, "no legal moves") == "" {
+		t.Error("the deadlock was not logged")
+	}
+
+	// A game that is already over is left alone
+	numActions := len(g.Actions)
+	checkDeadlock(tb)
+	if len(g.Actions) != numActions {
+		t.Error("a finished game was ended again")
+	}
+}
+
+func TestCheckDeadlockIgnoresNormalGames(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+	checkDeadlock(tb)
+	if tb.Game.EndCondition != EndConditionInProgress {
+		t.Error("a game with legal moves was ended")
+	}
+
+	// Unstarted tables do not have a game to check
+	checkDeadlock(newTestTable(t, 2))
+}
@@ -76,3 +76,40 @@ func (g *Game) ShuffleDeck() {
 		g.CardIdentities[i], g.CardIdentities[j] = g.CardIdentities[j], g.CardIdentities[i]
 	}
 }
+
+// DeckMatchesSeed checks to see if shuffling a new deck with the game's seed results in the same
+// deck that the game is using (e.g. to verify the integrity of a table that was restored from disk)
+// Games that use a deck from arbitrary JSON data are not derived from a seed,
+// so they always match
+func (g *Game) DeckMatchesSeed() bool {
+	if g.Seed == "JSON" {
+		return true
+	}
+
+	if _, ok := variants[g.Options.VariantName]; !ok {
+		return false
+	}
+
+	// Custom decks are ignored when there is a seed, so we do not need to copy the extra options
+	g2 := &Game{
+		Options:      g.Options,
+		ExtraOptions: &ExtraOptions{},
+		Seed:         g.Seed,
+	}
+	g2.InitDeck()
+	setSeed(g2.Seed) // Seed the random number generator
+	g2.ShuffleDeck()
+
+	if len(g2.CardIdentities) != len(g.CardIdentities) {
+		return false
+	}
+	for i, cardIdentity := range g2.CardIdentities {
+		if cardIdentity.SuitIndex != g.CardIdentities[i].SuitIndex ||
+			cardIdentity.Rank != g.CardIdentities[i].Rank {
+
+			return false
+		}
+	}
+
+	return true
+}
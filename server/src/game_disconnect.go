@@ -0,0 +1,214 @@
+package main
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// What happens to the clock when the active player of a timed game disconnects during their turn
+const (
+	// The clock keeps running
+	ActiveDisconnectPolicyDrain = "drain"
+	// The game is paused until the player reconnects (or until the timeout)
+	ActiveDisconnectPolicyPause = "pause"
+	// The clock is frozen for the length of the timeout and then resumes
+	ActiveDisconnectPolicyGrace = "grace"
+)
+
+const (
+	DefaultActiveDisconnectSeconds = 60
+)
+
+var (
+	activeDisconnectPolicy  string
+	activeDisconnectTimeout time.Duration
+)
+
+func activeDisconnectInit() {
+	activeDisconnectPolicy = os.Getenv("ACTIVE_DISCONNECT_POLICY")
+	if len(activeDisconnectPolicy) == 0 {
+		activeDisconnectPolicy = ActiveDisconnectPolicyDrain
+	}
+	if activeDisconnectPolicy != ActiveDisconnectPolicyDrain &&
+		activeDisconnectPolicy != ActiveDisconnectPolicyPause &&
+		activeDisconnectPolicy != ActiveDisconnectPolicyGrace {
+
+		logger.Fatal("The \"ACTIVE_DISCONNECT_POLICY\" environment variable must be \"" +
+			ActiveDisconnectPolicyDrain + "\", \"" + ActiveDisconnectPolicyPause + "\", or \"" +
+			ActiveDisconnectPolicyGrace + "\".")
+		return
+	}
+
+	timeoutSeconds := DefaultActiveDisconnectSeconds
+	timeoutSecondsString := os.Getenv("ACTIVE_DISCONNECT_SECONDS")
+	if len(timeoutSecondsString) != 0 {
+		if v, err := strconv.Atoi(timeoutSecondsString); err != nil {
+			logger.Fatal("Failed to convert the \"ACTIVE_DISCONNECT_SECONDS\" " +
+				"environment variable to a number.")
+			return
+		} else {
+			timeoutSeconds = v
+		}
+	}
+	if timeoutSeconds <= 0 {
+		logger.Fatal("The \"ACTIVE_DISCONNECT_SECONDS\" environment variable must be positive.")
+		return
+	}
+	activeDisconnectTimeout = time.Duration(timeoutSeconds) * time.Second
+}
+
+// ActivePlayerDisconnected applies the "ACTIVE_DISCONNECT_POLICY" to a player who disconnected
+// from an ongoing game
+// The table mutex must be held when calling this function
+func (g *Game) ActivePlayerDisconnected(s *Session, playerIndex int) {
+	// Local variables
+	t := g.Table
+
+	// When the server is restarting, everyone is disconnected before the tables are serialized,
+	// so the clock of the active player must be left alone
+	if blockAllIncomingMessages.IsSet() {
+		return
+	}
+
+	// In untimed games, nobody cares how long the turn takes
+	if !t.Options.Timed || t.Replay || g.EndCondition > EndConditionInProgress {
+		return
+	}
+
+	// The policy only applies to the player whose clock is running
+	if g.ActivePlayerIndex != playerIndex || g.Paused {
+		return
+	}
+
+	if activeDisconnectPolicy == ActiveDisconnectPolicyPause {
+		commandPause(s, &CommandData{ // Manual invocation
+			TableID: t.ID,
+			Setting: "pause",
+			NoLock:  true,
+		})
+		if !g.Paused {
+			return
+		}
+		g.DisconnectPaused = true
+		chatServerSend("The game will be unpaused when "+s.Username()+" reconnects "+
+			"(or in "+strconv.Itoa(int(activeDisconnectTimeout.Seconds()))+" seconds).",
+			t.GetRoomName())
+		go g.CheckDisconnectPause(g.PauseCount, activeDisconnectTimeout)
+	} else if activeDisconnectPolicy == ActiveDisconnectPolicyGrace {
+		g.Players[playerIndex].DisconnectedAt = time.Now()
+		chatServerSend(s.Username()+" disconnected, so their clock is stopped for "+
+			strconv.Itoa(int(activeDisconnectTimeout.Seconds()))+" seconds.", t.GetRoomName())
+	}
+}
+
+// ActivePlayerReconnected undoes the effects of the "ActivePlayerDisconnected()" function
+// The table mutex must be held when calling this function
+func (g *Game) ActivePlayerReconnected(s *Session, playerIndex int) {
+	// Local variables
+	t := g.Table
+	gp := g.Players[playerIndex]
+
+	if g.DisconnectPaused && g.Paused && g.PausePlayerIndex == playerIndex {
+		g.DisconnectPaused = false
+		commandPause(s, &CommandData{ // Manual invocation
+			TableID: t.ID,
+			Setting: "unpause",
+			NoLock:  true,
+		})
+	}
+
+	// Keep the time that they were given while they were gone
+	if !gp.DisconnectedAt.IsZero() {
+		g.ApplyDisconnectCredit(gp)
+		gp.DisconnectedAt = time.Time{}
+	}
+}
+
+// CheckDisconnectPause is meant to be run in a new goroutine
+// It unpauses a game that was paused for a disconnected player who did not come back in time
+func (g *Game) CheckDisconnectPause(pauseCount int, timeout time.Duration) {
+	// Local variables
+	t := g.Table
+
+	time.Sleep(timeout)
+
+	// The server might have started restarting in the meantime
+	// (the game will stay paused until the player reconnects to the new server or until the
+	// timeout elapses again)
+	if blockAllIncomingMessages.IsSet() {
+		return
+	}
+
+	// Check to see if the table still exists
+	t2, exists := getTableAndLock(nil, t.ID, false)
+	if !exists || t != t2 {
+		return
+	}
+	t.Mutex.Lock()
+	defer t.Mutex.Unlock()
+
+	// Check to see if the game was unpaused (or paused again) in the meantime
+	if !g.Paused || !g.DisconnectPaused || g.PauseCount != pauseCount ||
+		g.EndCondition > EndConditionInProgress {
+
+		return
+	}
+
+	p := t.Players[g.PausePlayerIndex]
+	s := p.Session
+	if s == nil {
+		// A player's session should never be nil
+		// They might be in the process of reconnecting,
+		// so make a fake session that will represent them
+		s = newFakeSession(p.ID, p.Name)
+		logger.Info("Created a new fake session in the \"CheckDisconnectPause()\" function.")
+	}
+
+	g.DisconnectPaused = false
+	chatServerSend(p.Name+" did not reconnect in time.", t.GetRoomName())
+	commandPause(s, &CommandData{ // Manual invocation
+		TableID: t.ID,
+		Setting: "unpause",
+		NoLock:  true,
+	})
+}
+
+// GetDisconnectCredit returns the amount of time that should not count against a player's clock
+// because they disconnected during their turn and the policy is "grace"
+func (g *Game) GetDisconnectCredit(gp *GamePlayer) time.Duration {
+	if activeDisconnectPolicy != ActiveDisconnectPolicyGrace ||
+		gp.DisconnectedAt.IsZero() ||
+		g.ActivePlayerIndex != gp.Index ||
+		g.Paused {
+
+		return 0
+	}
+
+	// The clock only ran while they were disconnected since the (un)pause, if any
+	start := gp.DisconnectedAt
+	if g.DatetimeTurnBegin.After(start) {
+		start = g.DatetimeTurnBegin
+	}
+
+	// They cannot be given more than the grace period in total on the same turn
+	// (otherwise, they could stop their clock indefinitely by reconnecting over and over)
+	credit := time.Since(start)
+	maxCredit := activeDisconnectTimeout - gp.DisconnectCreditUsed
+	if maxCredit < 0 {
+		maxCredit = 0
+	}
+	if credit > maxCredit {
+		credit = maxCredit
+	}
+
+	return credit
+}
+
+// ApplyDisconnectCredit adds the time from the "GetDisconnectCredit()" function to the clock of
+// the player (this must be done before the clock is stopped or the time of the turn is subtracted)
+func (g *Game) ApplyDisconnectCredit(gp *GamePlayer) {
+	credit := g.GetDisconnectCredit(gp)
+	gp.Time += credit
+	gp.DisconnectCreditUsed += credit
+}
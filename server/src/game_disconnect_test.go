@@ -0,0 +1,276 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func useTestActiveDisconnectPolicy(t *testing.T, policy string, timeout time.Duration) {
+	oldActiveDisconnectPolicy := activeDisconnectPolicy
+	oldActiveDisconnectTimeout := activeDisconnectTimeout
+	activeDisconnectPolicy = policy
+	activeDisconnectTimeout = timeout
+	t.Cleanup(func() {
+		activeDisconnectPolicy = oldActiveDisconnectPolicy
+		activeDisconnectTimeout = oldActiveDisconnectTimeout
+	})
+}
+
+// disconnectTestPlayer applies the disconnect policy to the given player
+func disconnectTestPlayer(tb *Table, playerIndex int) {
+	tb.Mutex.Lock()
+	defer tb.Mutex.Unlock()
+	tb.Game.ActivePlayerDisconnected(tb.Players[playerIndex].Session, playerIndex)
+}
+
+func reconnectTestPlayer(tb *Table, playerIndex int) {
+	tb.Mutex.Lock()
+	defer tb.Mutex.Unlock()
+	tb.Game.ActivePlayerReconnected(tb.Players[playerIndex].Session, playerIndex)
+}
+
+func getTestTimeLeft(tb *Table, playerIndex int) time.Duration {
+	tb.Mutex.Lock()
+	defer tb.Mutex.Unlock()
+	return tb.Game.GetTimeLeft(playerIndex)
+}
+
+func isTestGamePaused(tb *Table) bool {
+	tb.Mutex.Lock()
+	defer tb.Mutex.Unlock()
+	return tb.Game.Paused
+}
+
+// waitForTestUnpause waits for the game to be unpaused by another goroutine
+func waitForTestUnpause(t *testing.T, tb *Table) {
+	deadline := time.Now().Add(time.Second)
+	for isTestGamePaused(tb) {
+		if time.Now().After(deadline) {
+			t.Fatal("the game was not unpaused")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// expectTestClockStopped fails the test if the clock of the player keeps running
+func expectTestClockStopped(t *testing.T, tb *Table, playerIndex int) {
+	timeLeft := getTestTimeLeft(tb, playerIndex)
+	time.Sleep(50 * time.Millisecond)
+	if v := getTestTimeLeft(tb, playerIndex); v < timeLeft-10*time.Millisecond {
+		t.Errorf("expected the clock to be stopped at %v, but it is at %v", timeLeft, v)
+	}
+}
+
+// expectTestClockRunning fails the test if the clock of the player does not run
+func expectTestClockRunning(t *testing.T, tb *Table, playerIndex int) {
+	timeLeft := getTestTimeLeft(tb, playerIndex)
+	time.Sleep(50 * time.Millisecond)
+	if v := getTestTimeLeft(tb, playerIndex); v > timeLeft-40*time.Millisecond {
+		t.Errorf("expected the clock to run from %v, but it is at %v", timeLeft, v)
+	}
+}
+
+func TestActiveDisconnectPolicyDrain(t *testing.T) {
+	resetTestTables(t)
+	useTestActiveDisconnectPolicy(t, ActiveDisconnectPolicyDrain, time.Minute)
+	tb := newTestTimedGame(t, 2)
+
+	disconnectTestPlayer(tb, 0)
+	if isTestGamePaused(tb) {
+		t.Fatal("the game was paused")
+	}
+	expectTestClockRunning(t, tb, 0)
+}
+
+func TestActiveDisconnectPolicyPause(t *testing.T) {
+	resetTestTables(t)
+	useTestActiveDisconnectPolicy(t, ActiveDisconnectPolicyPause, time.Minute)
+	tb := newTestTimedGame(t, 2)
+	g := tb.Game
+
+	// The policy only applies to the active player
+	disconnectTestPlayer(tb, 1)
+	if isTestGamePaused(tb) {
+		t.Fatal("the game was paused when a player who is not active disconnected")
+	}
+
+	disconnectTestPlayer(tb, 0)
+	if !isTestGamePaused(tb) || !g.DisconnectPaused {
+		t.Fatal("the game was not paused when the active player disconnected")
+	}
+	expectTestClockStopped(t, tb, 0)
+
+	// The game is unpaused once they come back
+	reconnectTestPlayer(tb, 0)
+	if isTestGamePaused(tb) || g.DisconnectPaused {
+		t.Fatal("the game was not unpaused when the active player reconnected")
+	}
+	expectTestClockRunning(t, tb, 0)
+}
+
+func TestActiveDisconnectPolicyPauseTimeout(t *testing.T) {
+	resetTestTables(t)
+	useTestActiveDisconnectPolicy(t, ActiveDisconnectPolicyPause, 50*time.Millisecond)
+	tb := newTestTimedGame(t, 2)
+
+	disconnectTestPlayer(tb, 0)
+	if !isTestGamePaused(tb) {
+		t.Fatal("the game was not paused when the active player disconnected")
+	}
+
+	// The game is unpaused if they do not come back in time
+	waitForTestUnpause(t, tb)
+	expectTestClockRunning(t, tb, 0)
+}
+
+func TestActiveDisconnectPolicyGrace(t *testing.T) {
+	resetTestTables(t)
+	useTestActiveDisconnectPolicy(t, ActiveDisconnectPolicyGrace, 100*time.Millisecond)
+	tb := newTestTimedGame(t, 2)
+	timeLeft := getTestTimeLeft(tb, 0)
+
+	// The clock is stopped for the length of the grace period without pausing the game
+	disconnectTestPlayer(tb, 0)
+	if isTestGamePaused(tb) {
+		t.Fatal("the game was paused")
+	}
+	expectTestClockStopped(t, tb, 0)
+
+	// Afterwards, it runs again
+	time.Sleep(60 * time.Millisecond)
+	expectTestClockRunning(t, tb, 0)
+
+	// They only lose the time that they took outside of the grace period
+	tb.Mutex.Lock()
+	turnTime := time.Since(tb.Game.DatetimeTurnBegin)
+	tb.Mutex.Unlock()
+	reconnectTestPlayer(tb, 0)
+	expected := timeLeft - (turnTime - activeDisconnectTimeout)
+	if v := getTestTimeLeft(tb, 0); v < expected-20*time.Millisecond || v > expected+time.Millisecond {
+		t.Errorf("expected the clock to be at about %v after reconnecting, but it is at %v",
+			expected, v)
+	}
+	if !tb.Game.Players[0].DisconnectedAt.IsZero() {
+		t.Error("the time that the player disconnected was not cleared")
+	}
+}
+
+func TestActiveDisconnectPolicyGraceCreditedOnAction(t *testing.T) {
+	resetTestTables(t)
+	useTestActiveDisconnectPolicy(t, ActiveDisconnectPolicyGrace, time.Minute)
+	tb := newTestTimedGame(t, 2)
+	timeLeft := getTestTimeLeft(tb, 0)
+
+	// The turn is taken for them while they are disconnected
+	// (e.g. by a bot or after they reconnect on another device)
+	disconnectTestPlayer(tb, 0)
+	time.Sleep(50 * time.Millisecond)
+	tb.Mutex.Lock()
+	clueTestPlayer(t, tb)
+	gp := tb.Game.Players[0]
+	if gp.Time < timeLeft-10*time.Millisecond {
+		t.Errorf("expected the time during the grace period to be credited, but the clock went "+
+			"from %v to %v", timeLeft, gp.Time)
+	}
+	if !gp.DisconnectedAt.IsZero() {
+		t.Error("the time that the player disconnected was not cleared")
+	}
+	tb.Mutex.Unlock()
+}
+
+func TestActiveDisconnectPolicyGraceOncePerTurn(t *testing.T) {
+	resetTestTables(t)
+	useTestActiveDisconnectPolicy(t, ActiveDisconnectPolicyGrace, 100*time.Millisecond)
+	tb := newTestTimedGame(t, 2)
+	timeLeft := getTestTimeLeft(tb, 0)
+
+	// Reconnecting does not give them a new grace period on the same turn
+	disconnectTestPlayer(tb, 0)
+	time.Sleep(60 * time.Millisecond)
+	reconnectTestPlayer(tb, 0)
+	disconnectTestPlayer(tb, 0)
+	time.Sleep(100 * time.Millisecond)
+	expectTestClockRunning(t, tb, 0)
+
+	tb.Mutex.Lock()
+	turnTime := time.Since(tb.Game.DatetimeTurnBegin)
+	tb.Mutex.Unlock()
+	reconnectTestPlayer(tb, 0)
+	expected := timeLeft - (turnTime - activeDisconnectTimeout)
+	if v := getTestTimeLeft(tb, 0); v < expected-20*time.Millisecond || v > expected+time.Millisecond {
+		t.Errorf("expected the clock to be at about %v after reconnecting, but it is at %v",
+			expected, v)
+	}
+
+	// The next turn has its own grace period
+	tb.Mutex.Lock()
+	clueTestPlayer(t, tb)
+	clueTestPlayer(t, tb)
+	used := tb.Game.Players[0].DisconnectCreditUsed
+	tb.Mutex.Unlock()
+	if used != 0 {
+		t.Errorf("expected the used grace period to be reset on the next turn, but it is %v", used)
+	}
+	disconnectTestPlayer(tb, 0)
+	expectTestClockStopped(t, tb, 0)
+}
+
+func TestActiveDisconnectPolicyGracePause(t *testing.T) {
+	resetTestTables(t)
+	useTestActiveDisconnectPolicy(t, ActiveDisconnectPolicyGrace, time.Minute)
+	tb := newTestTimedGame(t, 2)
+	timeLeft := getTestTimeLeft(tb, 0)
+
+	// Pausing during the grace period does not charge them for it
+	// (a shared review pauses the game on behalf of the active player)
+	disconnectTestPlayer(tb, 0)
+	time.Sleep(50 * time.Millisecond)
+	commandTableEnterSharedReview(tb.Players[0].Session, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+	})
+	if !isTestGamePaused(tb) {
+		t.Fatal("the game was not paused for the shared review")
+	}
+	if v := getTestTimeLeft(tb, 0); v < timeLeft-10*time.Millisecond {
+		t.Errorf("expected the time during the grace period to be credited, but the clock went "+
+			"from %v to %v", timeLeft, v)
+	}
+
+	// The rest of the grace period still applies after the game is unpaused
+	commandTableExitSharedReview(tb.Players[0].Session, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+	})
+	if isTestGamePaused(tb) {
+		t.Fatal("the game was not unpaused after the shared review")
+	}
+	expectTestClockStopped(t, tb, 0)
+}
+
+func TestActiveDisconnectPolicySkippedDuringShutdown(t *testing.T) {
+	resetTestTables(t)
+	useTestActiveDisconnectPolicy(t, ActiveDisconnectPolicyPause, time.Minute)
+	tb := newTestTimedGame(t, 2)
+
+	blockAllIncomingMessages.Set()
+	disconnectTestPlayer(tb, 0)
+	if isTestGamePaused(tb) || tb.Game.DisconnectPaused {
+		t.Error("the game was paused while the server is shutting down")
+	}
+}
+
+func TestRestoreTablesRearmsDisconnectPause(t *testing.T) {
+	resetTestTables(t)
+	useTestTableStore(t, false)
+	useTestActiveDisconnectPolicy(t, ActiveDisconnectPolicyPause, 100*time.Millisecond)
+	tb := newTestTimedGame(t, 2)
+
+	disconnectTestPlayer(tb, 0)
+	serializeAndRestoreTestTables(t)
+	restored := getTestTable(t, tb.ID)
+	if !isTestGamePaused(restored) || !restored.Game.DisconnectPaused {
+		t.Fatal("the restored game is not paused for the disconnected player")
+	}
+
+	// The player never reconnects, so the restored game is unpaused after the timeout
+	waitForTestUnpause(t, restored)
+}
@@ -11,7 +11,14 @@ func (g *Game) End() {
 	t := g.Table
 
 	g.DatetimeFinished = time.Now()
-	if g.EndCondition > EndConditionNormal {
+	g.ClearPauseVotes()
+	g.ClearConcedeVotes()
+	g.ClearExtensionRequest()
+	g.Reviewing = false
+	// Spectate tokens are only meant for the game itself
+	t.SpectateTokens = make(map[string]*SpectateToken)
+	// Conceded games keep the score that the team had when they stopped
+	if g.EndCondition > EndConditionNormal && g.EndCondition != EndConditionConceded {
 		g.Score = 0
 	}
 	logger.Info(t.GetName() + "Ended with a score of " + strconv.Itoa(g.Score) + ".")
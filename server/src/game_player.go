@@ -24,8 +24,28 @@ type GamePlayer struct {
 	Time              time.Duration
 	Notes             []string
 	RequestedPause    bool
+	VotedPause        bool // From the "tablePauseVote" command (to pause or unpause)
 	Character         string
 	CharacterMetadata int
+
+	// These relate to the "tableRequestExtension" command
+	// (each player can only be given one extension per game)
+	RequestedExtension bool
+	ApprovedExtension  bool
+	UsedExtension      bool
+
+	// While a player is away, their turns are automatically taken for them
+	// (from the "tableSetAway" command)
+	Away bool
+
+	// Set when the player disconnects during their turn in a timed game with the "grace" policy
+	// (see "game_disconnect.go")
+	DisconnectedAt time.Time `json:"-"`
+	// The grace period applies to the whole turn, so it cannot be extended by disconnecting again
+	DisconnectCreditUsed time.Duration `json:"-"`
+
+	// From the "tableConcede" command (all of the present players must vote to end the game)
+	VotedConcede bool
 }
 
 // GiveClue returns false if the clue is illegal
@@ -134,10 +154,14 @@ func (p *GamePlayer) PlayCard(c *Card) {
 		g.Strikes++
 
 		g.Actions = append(g.Actions, ActionStrike{
-			Type:  "strike",
-			Num:   g.Strikes,
-			Turn:  g.Turn,
-			Order: c.Order,
+			Type:        "strike",
+			Num:         g.Strikes,
+			Turn:        g.Turn,
+			Order:       c.Order,
+			PlayerIndex: p.Index,
+			SuitIndex:   c.SuitIndex,
+			Rank:        c.Rank,
+			Expected:    g.GetPlayableRanks(c.SuitIndex),
 		})
 		t.NotifyGameAction()
 
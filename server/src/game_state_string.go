@@ -0,0 +1,245 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// A game state string is a compact, copy-pasteable encoding of the state of a game
+// (similar to the FEN notation used in chess)
+// It consists of seven fields separated by spaces:
+// 1) The play stacks, as the value of each stack separated by commas (e.g. "3,0,5,1,2")
+// 2) The discard pile, as cards separated by commas (or "-" if it is empty)
+// 3) The number of clue tokens
+// 4) The number of strikes
+// 5) The number of cards left in the deck
+// 6) The index of the active player
+// 7) The hands, as cards separated by commas, with each hand separated by a slash
+//    (e.g. "03,14,xx/22,31,45" for a two-player game where one card is hidden)
+// A card is its suit index followed by its rank (e.g. "03" is a 3 of the first suit)
+// Cards and stacks that are hidden from the requester are replaced with "x" characters
+// (e.g. "xx" for a card) and are decoded as -1
+// For example: "1,0,0,0,0 21 7 0 33 1 03,14,22,31/xx,xx,xx,xx"
+
+const (
+	GameStateStringNumFields = 7
+	GameStateStringHidden    = "x"
+	GameStateStringEmpty     = "-"
+)
+
+// GameState is the information that is stored in a game state string
+type GameState struct {
+	Stacks            []int
+	Discards          []*GameStateCard
+	ClueTokens        int
+	Strikes           int
+	DeckSize          int
+	ActivePlayerIndex int
+	Hands             [][]*GameStateCard
+}
+
+// GameStateCard is a card in a game state string (both fields are -1 if the card is hidden)
+type GameStateCard struct {
+	SuitIndex int
+	Rank      int
+}
+
+// NewGameState gets the current state of a game from the perspective of the given player
+// If the player is nil, then every card is visible (e.g. for spectators)
+func NewGameState(g *Game, p *GamePlayer) *GameState {
+	// Local variables
+	variant := variants[g.Options.VariantName]
+
+	// In some variants, the played cards are hidden from the players
+	hidePlayedCards := p != nil && variant.IsThrowItInAHole()
+
+	gs := &GameState{
+		Stacks:            make([]int, 0, len(g.Stacks)),
+		Discards:          make([]*GameStateCard, 0),
+		ClueTokens:        g.ClueTokens,
+		Strikes:           g.Strikes,
+		DeckSize:          len(g.Deck) - g.DeckIndex,
+		ActivePlayerIndex: g.ActivePlayerIndex,
+		Hands:             make([][]*GameStateCard, 0, len(g.Players)),
+	}
+	for _, stack := range g.Stacks {
+		if hidePlayedCards {
+			stack = -1
+		}
+		gs.Stacks = append(gs.Stacks, stack)
+	}
+	for _, c := range g.Deck {
+		if !c.Discarded {
+			continue
+		}
+		if hidePlayedCards && c.Failed {
+			gs.Discards = append(gs.Discards, newHiddenGameStateCard())
+		} else {
+			gs.Discards = append(gs.Discards, newGameStateCard(c))
+		}
+	}
+	for _, p2 := range g.Players {
+		hand := make([]*GameStateCard, 0, len(p2.Hand))
+		for _, c := range p2.Hand {
+			if p != nil && (p2.Index == p.Index || !characterSeesCard(g, p, p2, c.Order)) {
+				hand = append(hand, newHiddenGameStateCard())
+			} else {
+				hand = append(hand, newGameStateCard(c))
+			}
+		}
+		gs.Hands = append(gs.Hands, hand)
+	}
+
+	return gs
+}
+
+func newGameStateCard(c *Card) *GameStateCard {
+	return &GameStateCard{
+		SuitIndex: c.SuitIndex,
+		Rank:      c.Rank,
+	}
+}
+
+func newHiddenGameStateCard() *GameStateCard {
+	return &GameStateCard{
+		SuitIndex: -1,
+		Rank:      -1,
+	}
+}
+
+// String encodes the game state (see the comment at the top of this file)
+func (gs *GameState) String() string {
+	stacks := make([]string, 0, len(gs.Stacks))
+	for _, stack := range gs.Stacks {
+		if stack == -1 {
+			stacks = append(stacks, GameStateStringHidden)
+		} else {
+			stacks = append(stacks, strconv.Itoa(stack))
+		}
+	}
+
+	discards := GameStateStringEmpty
+	if len(gs.Discards) > 0 {
+		discards = gameStateCardsToString(gs.Discards)
+	}
+
+	hands := make([]string, 0, len(gs.Hands))
+	for _, hand := range gs.Hands {
+		if len(hand) == 0 {
+			hands = append(hands, GameStateStringEmpty)
+		} else {
+			hands = append(hands, gameStateCardsToString(hand))
+		}
+	}
+
+	return strings.Join([]string{
+		strings.Join(stacks, ","),
+		discards,
+		strconv.Itoa(gs.ClueTokens),
+		strconv.Itoa(gs.Strikes),
+		strconv.Itoa(gs.DeckSize),
+		strconv.Itoa(gs.ActivePlayerIndex),
+		strings.Join(hands, "/"),
+	}, " ")
+}
+
+func gameStateCardsToString(cards []*GameStateCard) string {
+	cardStrings := make([]string, 0, len(cards))
+	for _, c := range cards {
+		if c.SuitIndex == -1 || c.Rank == -1 {
+			cardStrings = append(cardStrings, GameStateStringHidden+GameStateStringHidden)
+		} else {
+			cardStrings = append(cardStrings, strconv.Itoa(c.SuitIndex)+strconv.Itoa(c.Rank))
+		}
+	}
+
+	return strings.Join(cardStrings, ",")
+}
+
+// parseGameStateString is the inverse of the "GameState.String()" method
+func parseGameStateString(s string) (*GameState, error) {
+	fields := strings.Fields(s)
+	if len(fields) != GameStateStringNumFields {
+		return nil, errors.New("the game state string has " + strconv.Itoa(len(fields)) +
+			" fields instead of " + strconv.Itoa(GameStateStringNumFields))
+	}
+
+	gs := &GameState{
+		Stacks: make([]int, 0),
+		Hands:  make([][]*GameStateCard, 0),
+	}
+
+	for _, stackString := range strings.Split(fields[0], ",") {
+		if stackString == GameStateStringHidden {
+			gs.Stacks = append(gs.Stacks, -1)
+		} else if v, err := strconv.Atoi(stackString); err != nil || v < 0 {
+			return nil, errors.New("the stack of \"" + stackString + "\" is not valid")
+		} else {
+			gs.Stacks = append(gs.Stacks, v)
+		}
+	}
+
+	if v, err := parseGameStateCards(fields[1]); err != nil {
+		return nil, err
+	} else {
+		gs.Discards = v
+	}
+
+	numbers := make([]int, 0, 4)
+	for _, numberString := range fields[2:6] {
+		if v, err := strconv.Atoi(numberString); err != nil || v < 0 {
+			return nil, errors.New("the number of \"" + numberString + "\" is not valid")
+		} else {
+			numbers = append(numbers, v)
+		}
+	}
+	gs.ClueTokens = numbers[0]
+	gs.Strikes = numbers[1]
+	gs.DeckSize = numbers[2]
+	gs.ActivePlayerIndex = numbers[3]
+
+	for _, handString := range strings.Split(fields[6], "/") {
+		if v, err := parseGameStateCards(handString); err != nil {
+			return nil, err
+		} else {
+			gs.Hands = append(gs.Hands, v)
+		}
+	}
+	if gs.ActivePlayerIndex >= len(gs.Hands) {
+		return nil, errors.New("the active player index of " +
+			strconv.Itoa(gs.ActivePlayerIndex) + " is not valid for " +
+			strconv.Itoa(len(gs.Hands)) + " hands")
+	}
+
+	return gs, nil
+}
+
+func parseGameStateCards(s string) ([]*GameStateCard, error) {
+	cards := make([]*GameStateCard, 0)
+	if s == GameStateStringEmpty {
+		return cards, nil
+	}
+
+	for _, cardString := range strings.Split(s, ",") {
+		if cardString == GameStateStringHidden+GameStateStringHidden {
+			cards = append(cards, newHiddenGameStateCard())
+			continue
+		}
+
+		if len(cardString) != 2 {
+			return nil, errors.New("the card of \"" + cardString + "\" is not valid")
+		}
+		suitIndex, err1 := strconv.Atoi(cardString[0:1])
+		rank, err2 := strconv.Atoi(cardString[1:2])
+		if err1 != nil || err2 != nil {
+			return nil, errors.New("the card of \"" + cardString + "\" is not valid")
+		}
+		cards = append(cards, &GameStateCard{
+			SuitIndex: suitIndex,
+			Rank:      rank,
+		})
+	}
+
+	return cards, nil
+}
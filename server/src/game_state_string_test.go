@@ -0,0 +1,128 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newTestPlayedGame creates a game where a card was played, a card was discarded,
+// and a clue was given
+func newTestPlayedGame(t *testing.T) *Table {
+	tb := newTestGame(t, 3)
+	clueTestPlayer(t, tb)
+	discardTestCard(t, tb)
+	gp := tb.Game.Players[tb.Game.ActivePlayerIndex]
+	performTestAction(t, tb, ActionTypePlay, gp.Hand[0].Order, 0)
+	tb.Game.Strikes = 1
+
+	return tb
+}
+
+func TestGameStateStringRoundTrip(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestPlayedGame(t)
+	g := tb.Game
+
+	for _, p := range []*GamePlayer{nil, g.Players[0], g.Players[1]} {
+		gs := NewGameState(g, p)
+		gs2, err := parseGameStateString(gs.String())
+		if err != nil {
+			t.Fatalf("failed to parse the game state string \"%v\": %v", gs, err)
+		}
+		if !reflect.DeepEqual(gs, gs2) {
+			t.Errorf("the game state string \"%v\" was decoded as \"%v\"", gs, gs2)
+		}
+	}
+}
+
+func TestNewGameState(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestPlayedGame(t)
+	g := tb.Game
+
+	gs := NewGameState(g, nil)
+	if gs.ClueTokens != g.ClueTokens || gs.Strikes != 1 ||
+		gs.DeckSize != len(g.Deck)-g.DeckIndex || gs.ActivePlayerIndex != g.ActivePlayerIndex {
+
+		t.Errorf("the numbers of the game state are not correct: %+v", gs)
+	}
+	if !reflect.DeepEqual(gs.Stacks, g.Stacks) {
+		t.Errorf("expected the stacks to be %v, but got %v", g.Stacks, gs.Stacks)
+	}
+	numDiscards := 0
+	for _, c := range g.Deck {
+		if c.Discarded {
+			numDiscards++
+		}
+	}
+	if len(gs.Discards) != numDiscards {
+		t.Errorf("expected %v discarded cards, but got %v", numDiscards, len(gs.Discards))
+	}
+
+	// Every card is visible without a perspective
+	for i, gp := range g.Players {
+		for j, c := range gp.Hand {
+			if gsc := gs.Hands[i][j]; gsc.SuitIndex != c.SuitIndex || gsc.Rank != c.Rank {
+				t.Errorf("card %v of player %v was encoded as %+v", j, i, gsc)
+			}
+		}
+	}
+
+	// Players cannot see their own cards
+	gs = NewGameState(g, g.Players[1])
+	for i, hand := range gs.Hands {
+		for j, gsc := range hand {
+			if hidden := gsc.SuitIndex == -1 && gsc.Rank == -1; hidden != (i == 1) {
+				t.Errorf("card %v of player %v was encoded as %+v for player 1", j, i, gsc)
+			}
+		}
+	}
+	hands := strings.Fields(gs.String())[6]
+	if !strings.Contains(strings.Split(hands, "/")[1], "xx") {
+		t.Errorf("the hidden cards were not encoded as \"xx\": %v", hands)
+	}
+}
+
+func TestParseGameStateString(t *testing.T) {
+	gs, err := parseGameStateString("1,0,0,0,0 21 7 0 33 1 03,14,22,31/xx,xx,xx,xx")
+	if err != nil {
+		t.Fatal("failed to parse the game state string:", err)
+	}
+	expected := &GameState{
+		Stacks:            []int{1, 0, 0, 0, 0},
+		Discards:          []*GameStateCard{{2, 1}},
+		ClueTokens:        7,
+		Strikes:           0,
+		DeckSize:          33,
+		ActivePlayerIndex: 1,
+		Hands: [][]*GameStateCard{
+			{{0, 3}, {1, 4}, {2, 2}, {3, 1}},
+			{{-1, -1}, {-1, -1}, {-1, -1}, {-1, -1}},
+		},
+	}
+	if !reflect.DeepEqual(gs, expected) {
+		t.Errorf("expected %+v, but got %+v", expected, gs)
+	}
+
+	// An empty discard pile is encoded as a dash
+	if gs, err := parseGameStateString("0,0 - 8 0 40 0 03/14"); err != nil {
+		t.Error("failed to parse a game state string without discards:", err)
+	} else if len(gs.Discards) != 0 {
+		t.Errorf("expected no discards, but got %v", len(gs.Discards))
+	}
+
+	for _, s := range []string{
+		"",
+		"1,0,0,0,0 21 7 0 33 1",
+		"1,a,0,0,0 21 7 0 33 1 03/14",
+		"1,0,0,0,0 2 7 0 33 1 03/14",
+		"1,0,0,0,0 21 -7 0 33 1 03/14",
+		"1,0,0,0,0 21 7 0 33 2 03/14",
+		"1,0,0,0,0 21 7 0 33 1 03/1x",
+	} {
+		if _, err := parseGameStateString(s); err == nil {
+			t.Errorf("the invalid game state string \"%v\" was parsed", s)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// What happens when a player runs out of time in a timed game (from the "timeoutAction" option)
+const (
+	// The game ends with a score of 0 (this is the default)
+	TimeoutActionEndGame = "endGame"
+	// The oldest card of the player is discarded for them and the game continues
+	TimeoutActionAutoDiscard = "autoDiscard"
+)
+
+const (
+	DefaultTimeoutDiscardSeconds = 10
+)
+
+var (
+	// The amount of time that a player has left after their turn is taken for them
+	timeoutDiscardIncrement time.Duration
+)
+
+func timeoutDiscardInit() {
+	incrementSeconds := DefaultTimeoutDiscardSeconds
+	incrementSecondsString := os.Getenv("TIMEOUT_DISCARD_SECONDS")
+	if len(incrementSecondsString) != 0 {
+		if v, err := strconv.Atoi(incrementSecondsString); err != nil {
+			logger.Fatal("Failed to convert the \"TIMEOUT_DISCARD_SECONDS\" " +
+				"environment variable to a number.")
+			return
+		} else {
+			incrementSeconds = v
+		}
+	}
+	if incrementSeconds <= 0 {
+		logger.Fatal("The \"TIMEOUT_DISCARD_SECONDS\" environment variable must be positive.")
+		return
+	}
+	timeoutDiscardIncrement = time.Duration(incrementSeconds) * time.Second
+}
+
+// validateTimeoutAction returns true if the "timeoutAction" option is valid
+// (an empty value is replaced with the default)
+func validateTimeoutAction(s *Session, options *Options) bool {
+	if options.TimeoutAction == "" {
+		options.TimeoutAction = TimeoutActionEndGame
+	}
+
+	if options.TimeoutAction != TimeoutActionEndGame &&
+		options.TimeoutAction != TimeoutActionAutoDiscard {
+
+		s.Warning("The timeout action must be \"" + TimeoutActionEndGame + "\" or \"" +
+			TimeoutActionAutoDiscard + "\".")
+		return false
+	}
+
+	return true
+}
+
+// TimeoutDiscard is called when a player has run out of time in a timed game with the
+// "autoDiscard" timeout action
+// Their oldest card is discarded for them (or a clue is given if the team is at the maximum amount
+// of clues) and their clock is reset to a small increment
+// The table mutex must be held when calling this function
+func (g *Game) TimeoutDiscard(gp *GamePlayer) {
+	// Local variables
+	t := g.Table
+
+	d := g.GetAutomaticAction(gp, 0)
+	if d == nil {
+		// There is nothing that we can do for them, so we have to end the game
+		g.EndTimer(gp)
+		return
+	}
+
+	logger.Info(t.GetName() + "Time ran out for \"" + gp.Name + "\", " +
+		"so their turn is being taken for them.")
+
+	// Get the session of this player
+	p := t.Players[gp.Index]
+	s := p.Session
+	if s == nil {
+		// A player's session should never be nil
+		// They might be in the process of reconnecting,
+		// so make a fake session that will represent them
+		s = newFakeSession(p.ID, p.Name)
+		logger.Info("Created a new fake session in the \"TimeoutDiscard()\" function.")
+	}
+
+	// The action will subtract the time that the turn took and add the time per turn,
+	// so we account for both of those ahead of time
+	// (this way, they are left with exactly the increment)
+	gp.Time = timeoutDiscardIncrement - time.Duration(t.Options.TimePerTurn)*time.Second
+	gp.DisconnectedAt = time.Time{}
+	gp.DisconnectCreditUsed = 0
+	g.DatetimeTurnBegin = time.Now()
+
+	chatServerSend(gp.Name+" ran out of time, so their turn was taken for them.",
+		t.GetRoomName())
+	turn := g.Turn
+	commandAction(s, d)
+
+	// The action can still be rejected (e.g. by "Detrimental Character Assignment" restrictions)
+	if g.Turn == turn && g.EndCondition == EndConditionInProgress {
+		g.EndTimer(gp)
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// expireTestTimer makes the active player run out of time and waits for the timer to handle it
+func expireTestTimer(tb *Table) {
+	g := tb.Game
+	gp := g.Players[g.ActivePlayerIndex]
+	gp.Time = 0
+	g.CheckTimer(g.Turn, g.PauseCount, gp)
+}
+
+func TestTimeoutActionEndGame(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTimedGame(t, 2)
+	tb.Options.TimeoutAction = TimeoutActionEndGame
+
+	expireTestTimer(tb)
+	if v := getTestEndCondition(tb); v != EndConditionTimeout {
+		t.Errorf("expected the game to end with a timeout (%v), but the end condition is %v",
+			EndConditionTimeout, v)
+	}
+}
+
+func TestTimeoutActionAutoDiscard(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTimedGame(t, 2)
+	g := tb.Game
+
+	// The team has to be below the maximum amount of clues for a discard to be possible
+	clueTestPlayer(t, tb)
+	gp := g.Players[g.ActivePlayerIndex]
+	c := gp.Hand[0]
+	turn := g.Turn
+
+	expireTestTimer(tb)
+	tb.Mutex.Lock()
+	defer tb.Mutex.Unlock()
+	if g.EndCondition != EndConditionInProgress {
+		t.Fatalf("the game ended with end condition %v", g.EndCondition)
+	}
+	if g.Turn != turn+1 {
+		t.Fatal("the turn was not taken for the player who ran out of time")
+	}
+	if !c.Discarded {
+		t.Error("the oldest card of the player was not discarded")
+	}
+
+	// They are left with the increment
+	if gp.Time < timeoutDiscardIncrement-time.Second || gp.Time > timeoutDiscardIncrement {
+		t.Errorf("expected the player to have %v left, but they have %v",
+			timeoutDiscardIncrement, gp.Time)
+	}
+}
+
+func TestTimeoutActionAutoDiscardMaxClues(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestTimedGame(t, 2)
+	g := tb.Game
+
+	// At the maximum amount of clues, a clue is given instead
+	expireTestTimer(tb)
+	tb.Mutex.Lock()
+	defer tb.Mutex.Unlock()
+	if g.EndCondition != EndConditionInProgress || g.Turn != 1 {
+		t.Fatal("the turn was not taken for the player who ran out of time")
+	}
+	if g.ClueTokens >= MaxClueNum {
+		t.Error("a clue was not given for the player who ran out of time")
+	}
+	for _, c := range g.Players[0].Hand {
+		if c.Discarded {
+			t.Error("a card was discarded at the maximum amount of clues")
+		}
+	}
+}
+
+func TestValidateTimeoutAction(t *testing.T) {
+	s, conn := newTestWebsocket(t, 1, "Alice", 0)
+
+	// The default is to end the game
+	options := &Options{}
+	if !validateTimeoutAction(s, options) || options.TimeoutAction != TimeoutActionEndGame {
+		t.Errorf("expected an empty timeout action to be replaced with \"%v\", but got \"%v\"",
+			TimeoutActionEndGame, options.TimeoutAction)
+	}
+
+	options.TimeoutAction = TimeoutActionAutoDiscard
+	if !validateTimeoutAction(s, options) {
+		t.Errorf("the \"%v\" timeout action was rejected", TimeoutActionAutoDiscard)
+	}
+
+	options.TimeoutAction = "explode"
+	if validateTimeoutAction(s, options) {
+		t.Error("an invalid timeout action was accepted")
+	}
+	expectTestWarning(t, conn, "The timeout action must be")
+}
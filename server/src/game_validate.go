@@ -0,0 +1,123 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+)
+
+// validateGameState replays all of the actions of a game from scratch on a new (fake) table and
+// checks that the resulting state matches the state of the game
+// This is used to detect tables that were restored from disk with a corrupted (but still parseable)
+// list of actions, which would otherwise lead to a crash later on
+func validateGameState(g *Game) error {
+	if _, ok := variants[g.Options.VariantName]; !ok {
+		return errors.New("the variant of \"" + g.Options.VariantName + "\" does not exist")
+	}
+
+	// Create a fake table with the same options
+	// We use the exact deck from the game instead of the seed so that JSON games are also covered
+	// (the seed is checked separately in the "DeckMatchesSeed()" function)
+	// The table is never added to the tables map, so it does not need a real table ID
+	t := newTable(0, g.Table.Name, -1)
+	t.Mutex.Lock()
+	defer t.Mutex.Unlock()
+	options := *g.Options
+	t.Options = &options
+	t.ExtraOptions = &ExtraOptions{
+		NoWriteToDatabase: true,
+		JSONReplay:        true,
+		CustomNumPlayers:  len(g.Players),
+		CustomDeck:        g.CardIdentities,
+	}
+	if g.Options.DetrimentalCharacters {
+		characterAssignments := make([]*CharacterAssignment, 0)
+		for _, p := range g.Players {
+			characterAssignments = append(characterAssignments, &CharacterAssignment{
+				Name:     p.Character,
+				Metadata: p.CharacterMetadata,
+			})
+		}
+		t.ExtraOptions.CustomCharacterAssignments = characterAssignments
+	}
+
+	playerNames := make([]string, 0)
+	for _, p := range g.Players {
+		playerNames = append(playerNames, p.Name)
+	}
+	loadFakePlayers(t, playerNames)
+
+	// It is not visible, so nobody will be notified about it
+	// (and the idle timeouts and the action log are skipped in the same way as for restored actions)
+	t.Visible = false
+	t.RestoringActions = true
+
+	// Start the (fake) game
+	tableStart(nil, &CommandData{ // Manual invocation
+		TableID: t.ID,
+		NoLock:  true,
+	}, t)
+	g2 := t.Game
+	if g2 == nil {
+		return errors.New("failed to start the game")
+	}
+
+	// Emulate the actions
+	for i, action := range g.Actions2 {
+		p := t.Players[g2.ActivePlayerIndex]
+		if action.Type == ActionTypeEndGame &&
+			action.Target >= 0 && action.Target < len(t.Players) {
+
+			p = t.Players[action.Target]
+		}
+
+		tableAction(p.Session, &CommandData{ // Manual invocation
+			TableID: t.ID,
+			Type:    action.Type,
+			Target:  action.Target,
+			Value:   action.Value,
+			NoLock:  true,
+		}, t)
+
+		if g2.InvalidActionOccurred {
+			return errors.New("the action at index " + strconv.Itoa(i) + " was not valid")
+		}
+	}
+
+	// Compare the resulting state with the state of the game
+	if len(g2.Deck) != len(g.Deck) {
+		return errors.New("the deck has " + strconv.Itoa(len(g.Deck)) + " cards, " +
+			"but it should have " + strconv.Itoa(len(g2.Deck)) + " cards")
+	}
+	if g2.DeckIndex != g.DeckIndex {
+		return errors.New("the deck index is " + strconv.Itoa(g.DeckIndex) + ", " +
+			"but it should be " + strconv.Itoa(g2.DeckIndex))
+	}
+	if g2.Turn != g.Turn {
+		return errors.New("the turn is " + strconv.Itoa(g.Turn) + ", " +
+			"but it should be " + strconv.Itoa(g2.Turn))
+	}
+	if g2.ClueTokens != g.ClueTokens {
+		return errors.New("there are " + strconv.Itoa(g.ClueTokens) + " clue tokens, " +
+			"but there should be " + strconv.Itoa(g2.ClueTokens))
+	}
+	if g2.Strikes != g.Strikes {
+		return errors.New("there are " + strconv.Itoa(g.Strikes) + " strikes, " +
+			"but there should be " + strconv.Itoa(g2.Strikes))
+	}
+	for i, p2 := range g2.Players {
+		p := g.Players[i]
+		if len(p2.Hand) != len(p.Hand) {
+			return errors.New("player " + strconv.Itoa(i) + " has " + strconv.Itoa(len(p.Hand)) +
+				" cards in their hand, but they should have " + strconv.Itoa(len(p2.Hand)))
+		}
+		for j, c2 := range p2.Hand {
+			c := p.Hand[j]
+			if c2.Order != c.Order || c2.SuitIndex != c.SuitIndex || c2.Rank != c.Rank {
+				return errors.New("the card in slot " + strconv.Itoa(j) + " of player " +
+					strconv.Itoa(i) + "'s hand does not match")
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestValidateGameState(t *testing.T) {
+	resetTestTables(t)
+
+	tb := newTestGame(t, 2)
+	clueTestPlayer(t, tb)
+	discardTestCard(t, tb)
+	lastTableID := atomic.LoadUint64(&tableIDCounter)
+	if err := validateGameState(tb.Game); err != nil {
+		t.Error("a valid game failed validation:", err)
+	}
+
+	// The fake table used for the validation is never added to the tables map
+	// (this is also used when restoring tables, so it must not have any side effects)
+	if len(tables) != 1 {
+		t.Errorf("expected only the original table to exist, but there are %v tables", len(tables))
+	}
+	if v := atomic.LoadUint64(&tableIDCounter); v != lastTableID {
+		t.Errorf("expected the table ID counter to stay at %v, but it is %v", lastTableID, v)
+	}
+}
+
+func TestValidateGameStateDoesNotRegisterTable(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+	clueTestPlayer(t, tb)
+
+	// Hold the lock so that the validation would block if it tried to add the fake table
+	tablesMutex.Lock()
+	done := make(chan error, 1)
+	go func() {
+		done <- validateGameState(tb.Game)
+	}()
+	select {
+	case err := <-done:
+		tablesMutex.Unlock()
+		if err != nil {
+			t.Error("a valid game failed validation:", err)
+		}
+	case <-time.After(time.Second):
+		tablesMutex.Unlock()
+		<-done
+		t.Error("the validation tried to use the tables map")
+	}
+}
+
+func TestValidateGameStateInconsistent(t *testing.T) {
+	resetTestTables(t)
+
+	tests := []struct {
+		name   string
+		modify func(g *Game)
+		err    string
+	}{
+		{"clue tokens", func(g *Game) { g.ClueTokens++ }, "clue tokens"},
+		{"strikes", func(g *Game) { g.Strikes++ }, "strikes"},
+		{"deck index", func(g *Game) { g.DeckIndex++ }, "deck index"},
+		{"hand size", func(g *Game) {
+			g.Players[0].Hand = g.Players[0].Hand[1:]
+		}, "cards in their hand"},
+		{"hand order", func(g *Game) {
+			hand := g.Players[1].Hand
+			hand[0], hand[1] = hand[1], hand[0]
+		}, "does not match"},
+		{"invalid action", func(g *Game) {
+			// A player cannot clue themselves
+			g.Actions2[0].Target = 0
+		}, "was not valid"},
+		{"unknown variant", func(g *Game) {
+			g.Options.VariantName = "Not A Real Variant"
+		}, "does not exist"},
+	}
+
+	for _, test := range tests {
+		tb := newTestGame(t, 2)
+		clueTestPlayer(t, tb)
+		test.modify(tb.Game)
+
+		if err := validateGameState(tb.Game); err == nil {
+			t.Errorf("the game with an inconsistent %v passed validation", test.name)
+		} else if !strings.Contains(err.Error(), test.err) {
+			t.Errorf("the game with an inconsistent %v failed with the wrong error: %v",
+				test.name, err)
+		}
+	}
+}
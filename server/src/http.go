@@ -15,6 +15,7 @@ import (
 	gsessions "github.com/gin-contrib/sessions"
 	"github.com/gin-contrib/sessions/cookie"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 type TemplateData struct {
@@ -261,6 +262,14 @@ func httpInit() {
 	httpRouter.GET("/export", httpExport)
 	httpRouter.GET("/export/:game", httpExport)
 
+	// Path handlers for monitoring
+	httpRouter.GET("/healthz", httpHealthz)
+	httpRouter.GET("/readyz", httpReadyz)
+	// (unless the metrics are configured to be served from a dedicated address)
+	if metricsAddress == "" {
+		httpRouter.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	}
+
 	// Other
 	httpRouter.Static("/public", path.Join(projectPath, "public"))
 	httpRouter.StaticFile("/favicon.ico", path.Join(projectPath, "public", "img", "favicon.ico"))
@@ -137,19 +137,7 @@ func httpExport(c *gin.Context) {
 	}
 
 	// Trim off trailing empty notes
-	allPlayerNotesEmpty := true
-	for i, playerNotes := range notes {
-		for len(playerNotes) > 0 && playerNotes[len(playerNotes)-1] == "" {
-			playerNotes = playerNotes[:len(playerNotes)-1]
-		}
-		notes[i] = playerNotes
-		if len(playerNotes) > 0 {
-			allPlayerNotesEmpty = false
-		}
-	}
-	if allPlayerNotesEmpty {
-		notes = nil
-	}
+	notes = trimPlayerNotes(notes)
 
 	// If this was a game with the "Detrimental Characters" option turned on,
 	// make a list of the characters for each player
@@ -159,59 +147,7 @@ func httpExport(c *gin.Context) {
 	}
 
 	// Create JSON options
-	// (we want the pointers to remain nil if the option is the default value
-	// so that they are not added to the JSON object)
-	optionsJSON := &OptionsJSON{}
-	allDefaultOptions := true
-	if options.StartingPlayer != 0 {
-		optionsJSON.StartingPlayer = &options.StartingPlayer
-		allDefaultOptions = false
-	}
-	if options.VariantName != "No Variant" {
-		optionsJSON.Variant = &variant.Name
-		allDefaultOptions = false
-	}
-	if options.Timed {
-		optionsJSON.Timed = &options.Timed
-		optionsJSON.TimeBase = &options.TimeBase
-		optionsJSON.TimePerTurn = &options.TimePerTurn
-		allDefaultOptions = false
-	}
-	if options.Speedrun {
-		optionsJSON.Speedrun = &options.Speedrun
-		allDefaultOptions = false
-	}
-	if options.CardCycle {
-		optionsJSON.CardCycle = &options.CardCycle
-		allDefaultOptions = false
-	}
-	if options.DeckPlays {
-		optionsJSON.DeckPlays = &options.DeckPlays
-		allDefaultOptions = false
-	}
-	if options.EmptyClues {
-		optionsJSON.EmptyClues = &options.EmptyClues
-		allDefaultOptions = false
-	}
-	if options.OneExtraCard {
-		optionsJSON.OneExtraCard = &options.OneExtraCard
-		allDefaultOptions = false
-	}
-	if options.OneLessCard {
-		optionsJSON.OneLessCard = &options.OneLessCard
-		allDefaultOptions = false
-	}
-	if options.AllOrNothing {
-		optionsJSON.AllOrNothing = &options.AllOrNothing
-		allDefaultOptions = false
-	}
-	if options.DetrimentalCharacters {
-		optionsJSON.DetrimentalCharacters = &options.DetrimentalCharacters
-		allDefaultOptions = false
-	}
-	if allDefaultOptions {
-		optionsJSON = nil
-	}
+	optionsJSON := options.GetOptionsJSON()
 
 	// Create a JSON game
 	gameJSON := &GameJSON{
@@ -227,3 +163,23 @@ func httpExport(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gameJSON)
 }
+
+// trimPlayerNotes removes trailing empty notes for each player
+// It returns nil if none of the players wrote any notes
+func trimPlayerNotes(notes [][]string) [][]string {
+	allPlayerNotesEmpty := true
+	for i, playerNotes := range notes {
+		for len(playerNotes) > 0 && playerNotes[len(playerNotes)-1] == "" {
+			playerNotes = playerNotes[:len(playerNotes)-1]
+		}
+		notes[i] = playerNotes
+		if len(playerNotes) > 0 {
+			allPlayerNotesEmpty = false
+		}
+	}
+	if allPlayerNotesEmpty {
+		return nil
+	}
+
+	return notes
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tevino/abool"
+)
+
+var (
+	// Set once the ongoing tables from the last server restart have been restored
+	// (which happens before the HTTP server starts accepting WebSocket connections)
+	serverReady = abool.New()
+)
+
+// httpHealthz is a liveness probe
+// It succeeds as long as the process is running
+func httpHealthz(c *gin.Context) {
+	c.String(http.StatusOK, "ok\n")
+}
+
+// httpReadyz is a readiness probe
+// It only succeeds if the server is able to take on new users
+// (e.g. load balancers should not send traffic to a server that is restoring tables, draining,
+// or about to restart)
+func httpReadyz(c *gin.Context) {
+	// Local variables
+	w := c.Writer
+
+	if !serverReady.IsSet() ||
+		shuttingDown.IsSet() ||
+		restartingSoon.IsSet() ||
+		blockAllIncomingMessages.IsSet() {
+
+		http.Error(
+			w,
+			http.StatusText(http.StatusServiceUnavailable),
+			http.StatusServiceUnavailable,
+		)
+		return
+	}
+
+	c.String(http.StatusOK, "ok\n")
+}
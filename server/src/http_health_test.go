@@ -0,0 +1,86 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tevino/abool"
+)
+
+func getTestHealthCheck(handler gin.HandlerFunc, path string) int {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, path, nil)
+	handler(c)
+
+	return w.Code
+}
+
+// useTestFlag sets a flag for the duration of the test
+func useTestFlag(t *testing.T, flag *abool.AtomicBool) {
+	oldValue := flag.IsSet()
+	flag.Set()
+	t.Cleanup(func() {
+		flag.SetTo(oldValue)
+	})
+}
+
+func TestHttpHealthz(t *testing.T) {
+	if code := getTestHealthCheck(httpHealthz, "/healthz"); code != http.StatusOK {
+		t.Errorf("expected a status code of %v, but got %v", http.StatusOK, code)
+	}
+}
+
+func TestHttpReadyz(t *testing.T) {
+	oldServerReady := serverReady.IsSet()
+	t.Cleanup(func() {
+		serverReady.SetTo(oldServerReady)
+	})
+
+	// The tables have not been restored yet
+	serverReady.UnSet()
+	if code := getTestHealthCheck(httpReadyz, "/readyz"); code != http.StatusServiceUnavailable {
+		t.Errorf("expected a status code of %v before the restore, but got %v",
+			http.StatusServiceUnavailable, code)
+	}
+
+	// The restore has completed
+	serverReady.Set()
+	if code := getTestHealthCheck(httpReadyz, "/readyz"); code != http.StatusOK {
+		t.Errorf("expected a status code of %v after the restore, but got %v",
+			http.StatusOK, code)
+	}
+}
+
+func TestHttpReadyzNotReady(t *testing.T) {
+	tests := []struct {
+		name string
+		flag *abool.AtomicBool
+	}{
+		{"shutting down", shuttingDown},
+		{"restarting soon", restartingSoon},
+		{"blocking all incoming messages", blockAllIncomingMessages},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useTestFlag(t, serverReady)
+			useTestFlag(t, test.flag)
+
+			code := getTestHealthCheck(httpReadyz, "/readyz")
+			if code != http.StatusServiceUnavailable {
+				t.Errorf("expected a status code of %v, but got %v",
+					http.StatusServiceUnavailable, code)
+			}
+
+			// The process is still alive
+			if code := getTestHealthCheck(httpHealthz, "/healthz"); code != http.StatusOK {
+				t.Errorf("expected a liveness status code of %v, but got %v",
+					http.StatusOK, code)
+			}
+		})
+	}
+}
@@ -34,21 +34,32 @@ func httpLocalhostInit() {
 	httpRouter := gin.Default() // Has the "Logger" and "Recovery" middleware attached
 
 	// Path handlers
+	httpRouter.POST("/announce", httpLocalhostAnnounce)
 	httpRouter.POST("/ban", httpLocalhostUserAction)
 	httpRouter.GET("/cancel", httpLocalhostCancel)
 	httpRouter.GET("/clearEmptyTables", httpLocalhostClearEmptyTables)
 	httpRouter.GET("/debug", httpLocalhostDebug)
+	httpRouter.GET("/debug/sessions", httpLocalhostDebugSessions)
+	httpRouter.GET("/dumpTrace", httpLocalhostDumpTrace)
+	httpRouter.GET("/info", httpLocalhostInfo)
 	httpRouter.GET("/maintenance", httpLocalhostMaintenance)
+	httpRouter.GET("/maxTables", httpLocalhostMaxTables)
 	httpRouter.POST("/mute", httpLocalhostUserAction)
 	httpRouter.GET("/print", httpLocalhostPrint)
 	httpRouter.GET("/restart", httpLocalhostRestart)
+	httpRouter.GET("/restartCountdown", httpLocalhostRestartCountdown)
 	httpRouter.GET("/saveTables", httpLocalhostSaveTables)
 	httpRouter.POST("/sendWarning", httpLocalhostUserAction)
 	httpRouter.POST("/sendError", httpLocalhostUserAction)
 	httpRouter.GET("/shutdown", httpLocalhostShutdown)
 	httpRouter.GET("/terminate", httpLocalhostTerminate)
+	httpRouter.POST("/terminateTable", httpLocalhostTerminateTable)
+	httpRouter.POST("/transferOwner", httpLocalhostTransferOwner)
+	httpRouter.POST("/kickSpectator", httpLocalhostKickSpectator)
 	httpRouter.GET("/timeLeft", httpLocalhostTimeLeft)
+	httpRouter.POST("/traceSession", httpLocalhostTraceSession)
 	httpRouter.GET("/uptime", httpLocalhostUptime)
+	httpRouter.GET("/variantToggle", httpLocalhostVariantToggle)
 	httpRouter.GET("/version", httpLocalhostVersion)
 	httpRouter.GET("/unmaintenance", httpLocalhostUnmaintenance)
 
@@ -129,7 +140,7 @@ func logoutUser(userID int) {
 		return
 	}
 
-	if err := s.Close(); err != nil {
+	if err := s.CloseWithCode(CloseCodeKicked, CloseReasonKicked); err != nil {
 		logger.Error("Failed to manually close the WebSocket session for user "+
 			strconv.Itoa(userID)+":", err)
 	} else {
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// httpLocalhostAnnounce sends a warning to every user that is currently connected
+// (e.g. to let everyone know about an upcoming restart)
+// An optional "countdown" POST parameter (in seconds) will be passed along to the client
+func httpLocalhostAnnounce(c *gin.Context) {
+	// Local variables
+	w := c.Writer
+
+	// Validate that the admin sent a message
+	msg := c.PostForm("msg")
+	if msg == "" {
+		c.String(http.StatusOK, "You must send a \"msg\" POST parameter.\n")
+		return
+	}
+
+	countdown := 0
+	countdownString := c.PostForm("countdown")
+	if countdownString != "" {
+		if v, err := strconv.Atoi(countdownString); err != nil || v < 0 {
+			http.Error(w, "Error: The countdown must be a non-negative number.", http.StatusBadRequest)
+			return
+		} else {
+			countdown = v
+		}
+	}
+
+	numRecipients := notifyAllAnnouncement(msg, countdown)
+	logger.Info("Sent an announcement to " + strconv.Itoa(numRecipients) + " user(s): " + msg)
+	c.String(http.StatusOK, "success\n")
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	logging "github.com/Zamiell/go-logging"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+func announceTestFromLocalhost(form url.Values) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/announce", strings.NewReader(form.Encode()))
+	c.Request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	httpLocalhostAnnounce(c)
+
+	return w
+}
+
+func TestHttpLocalhostAnnounce(t *testing.T) {
+	resetTestTables(t)
+	logs := captureTestLogs(t)
+
+	conns := make([]*websocket.Conn, 0)
+	for i, name := range []string{"Alice", "Bob", "Cathy"} {
+		s, conn := newTestWebsocket(t, i+1, name, 0)
+		sessionsMutex.Lock()
+		sessions[s.UserID()] = s
+		sessionsMutex.Unlock()
+		conns = append(conns, conn)
+	}
+
+	form := url.Values{}
+	form.Set("msg", "The server will restart soon.")
+	form.Set("countdown", "60")
+	if w := announceTestFromLocalhost(form); w.Code != http.StatusOK {
+		t.Fatalf("expected a status code of %v, but got %v: %v", http.StatusOK, w.Code, w.Body)
+	}
+
+	for i, conn := range conns {
+		var announcement struct {
+			Warning   string `json:"warning"`
+			Countdown int    `json:"countdown"`
+		}
+		data := readTestCommand(t, conn, "warning")
+		if err := json.Unmarshal([]byte(data), &announcement); err != nil {
+			t.Fatal("failed to unmarshal the announcement:", err)
+		}
+		if announcement.Warning != "The server will restart soon." || announcement.Countdown != 60 {
+			t.Errorf("session %v received the wrong announcement: %v", i+1, data)
+		}
+	}
+
+	if findTestLog(logs, logging.INFO, "to 3 user(s)") == "" {
+		t.Error("the number of recipients was not logged")
+	}
+}
+
+func TestHttpLocalhostAnnounceInvalidCountdown(t *testing.T) {
+	form := url.Values{}
+	form.Set("msg", "The server will restart soon.")
+	form.Set("countdown", "-1")
+	if w := announceTestFromLocalhost(form); w.Code != http.StatusBadRequest {
+		t.Errorf("expected a status code of %v, but got %v", http.StatusBadRequest, w.Code)
+	}
+}
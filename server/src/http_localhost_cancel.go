@@ -10,10 +10,10 @@ func httpLocalhostCancel(c *gin.Context) {
 	// Local variables
 	w := c.Writer
 
-	if shuttingDown.IsNotSet() {
+	if shuttingDown.IsNotSet() && restartingSoon.IsNotSet() {
 		http.Error(
 			w,
-			"The server is not shutting down, so you cannot cancel it.",
+			"The server is not shutting down or restarting, so you cannot cancel it.",
 			http.StatusBadRequest,
 		)
 		return
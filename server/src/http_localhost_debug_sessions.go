@@ -0,0 +1,36 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DebugSession struct {
+	UserID      int       `json:"userID"`
+	Username    string    `json:"username"`
+	ConnectedAt time.Time `json:"connectedAt"`
+	RemoteAddr  string    `json:"remoteAddr"`
+	LastCommand string    `json:"lastCommand"`
+}
+
+// httpLocalhostDebugSessions dumps the connection metadata for every WebSocket session
+// (this is useful for tracking down "ghost" connections)
+func httpLocalhostDebugSessions(c *gin.Context) {
+	debugSessions := make([]*DebugSession, 0)
+
+	sessionsMutex.RLock()
+	for _, s := range sessions {
+		debugSessions = append(debugSessions, &DebugSession{
+			UserID:      s.UserID(),
+			Username:    s.Username(),
+			ConnectedAt: s.ConnectedAt(),
+			RemoteAddr:  s.RemoteAddr(),
+			LastCommand: s.LastCommand(),
+		})
+	}
+	sessionsMutex.RUnlock()
+
+	c.JSON(http.StatusOK, debugSessions)
+}
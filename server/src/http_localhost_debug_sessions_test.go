@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHttpLocalhostDebugSessions(t *testing.T) {
+	resetTestTables(t)
+
+	connectedAt := time.Date(2020, 8, 1, 12, 0, 0, 0, time.UTC)
+	s := newTestSession(1, "Alice")
+	s.Set("connectedAt", connectedAt)
+	s.Set("remoteAddr", "192.0.2.1")
+	sessionsMutex.Lock()
+	sessions[s.UserID()] = s
+	sessionsMutex.Unlock()
+
+	// Sending a command records it as the last command
+	websocketMessage(s.Session, []byte(`tableUnattend {"tableID":999}`))
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/debug/sessions", nil)
+	httpLocalhostDebugSessions(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected a status code of %v, but got %v", http.StatusOK, w.Code)
+	}
+	var debugSessions []*DebugSession
+	if err := json.Unmarshal(w.Body.Bytes(), &debugSessions); err != nil {
+		t.Fatal("failed to unmarshal the response:", err)
+	}
+	if len(debugSessions) != 1 {
+		t.Fatalf("expected 1 session, but got %v", len(debugSessions))
+	}
+
+	expected := &DebugSession{
+		UserID:      1,
+		Username:    "Alice",
+		ConnectedAt: connectedAt,
+		RemoteAddr:  "192.0.2.1",
+		LastCommand: "tableUnattend",
+	}
+	if actual := debugSessions[0]; *actual != *expected {
+		t.Errorf("expected %+v, but got %+v", expected, actual)
+	}
+}
@@ -0,0 +1,12 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// httpLocalhostInfo prints out the same information as the "serverInfo" command
+func httpLocalhostInfo(c *gin.Context) {
+	c.JSON(http.StatusOK, getServerInfo())
+}
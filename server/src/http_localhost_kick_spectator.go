@@ -0,0 +1,66 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// httpLocalhostKickSpectator removes a disruptive spectator from a table
+// If the "block" POST parameter is "true", they will not be able to spectate the table again
+func httpLocalhostKickSpectator(c *gin.Context) {
+	// Local variables
+	w := c.Writer
+
+	// Validate the table ID
+	tableIDString := c.PostForm("tableID")
+	if tableIDString == "" {
+		http.Error(w, "Error: You must specify a table ID.", http.StatusBadRequest)
+		return
+	}
+	var tableID uint64
+	if v, err := strconv.ParseUint(tableIDString, 10, 64); err != nil {
+		http.Error(w, "Error: The table ID must be a number.", http.StatusBadRequest)
+		return
+	} else {
+		tableID = v
+	}
+
+	// Validate the spectator
+	spectatorIDString := c.PostForm("spectatorID")
+	if spectatorIDString == "" {
+		http.Error(w, "Error: You must specify the user ID of the spectator.", http.StatusBadRequest)
+		return
+	}
+	var spectatorID int
+	if v, err := strconv.Atoi(spectatorIDString); err != nil {
+		http.Error(w, "Error: The user ID of the spectator must be a number.", http.StatusBadRequest)
+		return
+	} else {
+		spectatorID = v
+	}
+
+	block := c.PostForm("block") == "true"
+
+	// Get the corresponding table
+	t, exists := getTableAndLock(nil, tableID, true)
+	if !exists {
+		http.Error(w, "Error: Table "+strconv.FormatUint(tableID, 10)+" does not exist.",
+			http.StatusBadRequest)
+		return
+	}
+	defer t.Mutex.Unlock()
+
+	// Validate that the target is spectating the table
+	spectatorIndex := t.GetSpectatorIndexFromID(spectatorID)
+	if spectatorIndex == -1 {
+		http.Error(w, "Error: User "+strconv.Itoa(spectatorID)+" is not spectating table "+
+			strconv.FormatUint(tableID, 10)+".", http.StatusBadRequest)
+		return
+	}
+
+	tableKickSpectator(t, spectatorIndex, block, "An administrator")
+
+	c.String(http.StatusOK, "success\n")
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func httpLocalhostMaxTables(c *gin.Context) {
+	msg := "Current tables: " + strconv.Itoa(countNonReplayTables()) + "\n"
+	msg += "Maximum tables: "
+	if maxTables == 0 {
+		msg += "unlimited"
+	} else {
+		msg += strconv.Itoa(maxTables)
+	}
+	msg += "\n"
+
+	c.String(http.StatusOK, msg)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	DefaultRestartCountdownSeconds = 60
+)
+
+// httpLocalhostRestartCountdown schedules a graceful restart
+// The amount of seconds to wait can be specified with the "seconds" query parameter
+func httpLocalhostRestartCountdown(c *gin.Context) {
+	// Local variables
+	w := c.Writer
+
+	if shuttingDown.IsSet() {
+		http.Error(w, "The server is already shutting down.", http.StatusBadRequest)
+		return
+	}
+
+	if restartingSoon.IsSet() {
+		http.Error(w, "The server is already restarting.", http.StatusBadRequest)
+		return
+	}
+
+	seconds := DefaultRestartCountdownSeconds
+	secondsString := c.Query("seconds")
+	if secondsString != "" {
+		if v, err := strconv.Atoi(secondsString); err != nil || v < 0 {
+			http.Error(w, "Error: The seconds must be a non-negative number.", http.StatusBadRequest)
+			return
+		} else {
+			seconds = v
+		}
+	}
+
+	restartCountdown(seconds)
+	c.String(http.StatusOK, "success\n")
+}
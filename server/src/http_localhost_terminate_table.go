@@ -0,0 +1,85 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// httpLocalhostTerminateTable forcibly removes a table that is stuck in a bad state
+// Unlike the "/terminate" endpoint, the game is not ended normally
+// (e.g. it is not written to the database and nobody is put into a replay)
+// It works regardless of whether the table is started, a replay, or paused
+func httpLocalhostTerminateTable(c *gin.Context) {
+	// Local variables
+	w := c.Writer
+
+	// Validate the table ID
+	tableIDString := c.PostForm("tableID")
+	if tableIDString == "" {
+		http.Error(w, "Error: You must specify a table ID.", http.StatusBadRequest)
+		return
+	}
+	var tableID uint64
+	if v, err := strconv.ParseUint(tableIDString, 10, 64); err != nil {
+		http.Error(w, "Error: The table ID must be a number.", http.StatusBadRequest)
+		return
+	} else {
+		tableID = v
+	}
+
+	// The reason is optional
+	reason := c.PostForm("reason")
+
+	// Get the corresponding table
+	t, exists := getTableAndLock(nil, tableID, true)
+	if !exists {
+		http.Error(w, "Error: Table "+strconv.FormatUint(tableID, 10)+" does not exist.",
+			http.StatusBadRequest)
+		return
+	}
+	defer t.Mutex.Unlock()
+
+	terminateTable(t, reason)
+
+	c.String(http.StatusOK, "success\n")
+}
+
+func terminateTable(t *Table, reason string) {
+	logger.Info(t.GetName() + "Forcibly terminating the table: " + reason)
+
+	msg := "Table #" + strconv.FormatUint(t.ID, 10) + " was terminated by an administrator."
+	if reason != "" {
+		msg += " Reason: " + reason
+	}
+
+	// Send everyone at the table back to the lobby
+	sessions := make([]*Session, 0)
+	for _, p := range t.Players {
+		if p.Present && p.Session != nil {
+			sessions = append(sessions, p.Session)
+		}
+	}
+	for _, sp := range t.Spectators {
+		if sp.Session != nil {
+			sessions = append(sessions, sp.Session)
+		}
+	}
+	for _, s := range sessions {
+		s.NotifyBoot(t)
+		s.Warning(msg)
+		s.Set("status", StatusLobby)
+		s.Set("tableID", uint64(0))
+		notifyAllUser(s)
+	}
+
+	// Any timer or idle goroutines for this table will stop once it is marked as deleted
+	deleteTable(t)
+
+	// Also delete the serialized version of the table (if any),
+	// so that it is not restored on the next startup
+	if err := tableStore.Delete(t.ID); err != nil {
+		logger.Error("Failed to delete the saved table:", err)
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func terminateTableTestFromLocalhost(form url.Values) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(
+		http.MethodPost,
+		"/terminateTable",
+		strings.NewReader(form.Encode()),
+	)
+	c.Request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	httpLocalhostTerminateTable(c)
+
+	return w
+}
+
+func TestHttpLocalhostTerminateTable(t *testing.T) {
+	resetTestTables(t)
+	store := useTestTableStore(t, false)
+	tb := newTestGame(t, 2)
+	clueTestPlayer(t, tb)
+
+	s, conn := newTestWebsocket(t, 1, "Alice", 0)
+	tb.Players[0].Session = s
+	_, spectatorConn := newTestWebsocketSpectator(t, tb, 3, "Cathy")
+
+	if _, err := store.Save(tb.ID, []byte("{}")); err != nil {
+		t.Fatal("failed to save the test table:", err)
+	}
+
+	form := url.Values{}
+	form.Set("tableID", strconv.FormatUint(tb.ID, 10))
+	form.Set("reason", "The game is stuck.")
+	if w := terminateTableTestFromLocalhost(form); w.Code != http.StatusOK {
+		t.Fatalf("expected a status code of %v, but got %v: %v", http.StatusOK, w.Code, w.Body)
+	}
+
+	if !isTestTableDeleted(tb) {
+		t.Error("the table was not removed")
+	}
+	if ids, err := store.List(); err != nil {
+		t.Fatal("failed to list the saved tables:", err)
+	} else if len(ids) != 0 {
+		t.Errorf("the saved table was not deleted: %v", ids)
+	}
+
+	// Both the player and the spectator are told why the table is gone
+	readTestCommand(t, conn, "boot")
+	expectTestWarning(t, conn, "The game is stuck.")
+	readTestCommand(t, spectatorConn, "boot")
+	expectTestWarning(t, spectatorConn, "The game is stuck.")
+}
+
+func TestHttpLocalhostTerminateTableDoesNotExist(t *testing.T) {
+	resetTestTables(t)
+
+	form := url.Values{}
+	form.Set("tableID", "1000")
+	w := terminateTableTestFromLocalhost(form)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected a status code of %v, but got %v", http.StatusBadRequest, w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "Table 1000 does not exist.") {
+		t.Errorf("the error does not mention the missing table: %v", w.Body)
+	}
+}
@@ -0,0 +1,17 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestLogoutUserCloseCode(t *testing.T) {
+	resetTestTables(t)
+
+	s, conn := newTestWebsocket(t, 1, "Alice", 0)
+	sessionConnectMutex.Lock()
+	websocketConnectReplaceSession(s, &WebsocketConnectData{})
+	sessionConnectMutex.Unlock()
+
+	logoutUser(1)
+	expectTestCloseWithReason(t, conn, CloseCodeKicked, CloseReasonKicked)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// httpLocalhostTraceSession starts or stops recording all of the WebSocket messages for a user
+// (so that their problem can be debugged without enabling debug logging for everyone)
+// The recorded messages can be retrieved with the "/dumpTrace" endpoint
+// The trace is discarded when the user disconnects
+func httpLocalhostTraceSession(c *gin.Context) {
+	// Local variables
+	w := c.Writer
+
+	s, ok := httpLocalhostGetSession(c, c.PostForm("userID"))
+	if !ok {
+		return
+	}
+
+	var enabled bool
+	if v, err := strconv.ParseBool(c.PostForm("enabled")); err != nil {
+		http.Error(w, "Error: The enabled value must be \"true\" or \"false\".",
+			http.StatusBadRequest)
+		return
+	} else {
+		enabled = v
+	}
+
+	if enabled {
+		s.StartTrace()
+		logger.Info("Started tracing the session for user: " + s.Username())
+	} else {
+		s.StopTrace()
+		logger.Info("Stopped tracing the session for user: " + s.Username())
+	}
+
+	c.String(http.StatusOK, "success\n")
+}
+
+// httpLocalhostDumpTrace prints out the WebSocket messages that were recorded for a user
+func httpLocalhostDumpTrace(c *gin.Context) {
+	// Local variables
+	w := c.Writer
+
+	s, ok := httpLocalhostGetSession(c, c.Query("userID"))
+	if !ok {
+		return
+	}
+
+	trace := s.Trace()
+	if trace == nil {
+		http.Error(w, "Error: The session for user \""+s.Username()+"\" is not being traced.",
+			http.StatusBadRequest)
+		return
+	}
+
+	msg := ""
+	for _, entry := range trace.GetEntries() {
+		direction := "<"
+		if entry.Incoming {
+			direction = ">"
+		}
+		msg += entry.Datetime.Format("2006-01-02 15:04:05.000") + " " + direction + " " +
+			entry.Command + " " + entry.Data + "\n"
+	}
+	c.String(http.StatusOK, msg)
+}
+
+// httpLocalhostGetSession validates a user ID and returns the session for that user
+func httpLocalhostGetSession(c *gin.Context, userIDString string) (*Session, bool) {
+	// Local variables
+	w := c.Writer
+
+	if userIDString == "" {
+		http.Error(w, "Error: You must specify a user ID.", http.StatusBadRequest)
+		return nil, false
+	}
+	var userID int
+	if v, err := strconv.Atoi(userIDString); err != nil {
+		http.Error(w, "Error: The user ID must be a number.", http.StatusBadRequest)
+		return nil, false
+	} else {
+		userID = v
+	}
+
+	sessionsMutex.RLock()
+	s, ok := sessions[userID]
+	sessionsMutex.RUnlock()
+	if !ok {
+		http.Error(w, "Error: User "+strconv.Itoa(userID)+" is not online.", http.StatusBadRequest)
+		return nil, false
+	}
+
+	return s, true
+}
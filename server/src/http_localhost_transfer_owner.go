@@ -0,0 +1,70 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// httpLocalhostTransferOwner gives a table to another player when the owner has gone idle
+// Unlike the "tableTransferOwner" command, it also works on tables that have already started
+func httpLocalhostTransferOwner(c *gin.Context) {
+	// Local variables
+	w := c.Writer
+
+	// Validate the table ID
+	tableIDString := c.PostForm("tableID")
+	if tableIDString == "" {
+		http.Error(w, "Error: You must specify a table ID.", http.StatusBadRequest)
+		return
+	}
+	var tableID uint64
+	if v, err := strconv.ParseUint(tableIDString, 10, 64); err != nil {
+		http.Error(w, "Error: The table ID must be a number.", http.StatusBadRequest)
+		return
+	} else {
+		tableID = v
+	}
+
+	// Validate the new owner
+	newOwnerIDString := c.PostForm("newOwnerID")
+	if newOwnerIDString == "" {
+		http.Error(w, "Error: You must specify the user ID of the new owner.", http.StatusBadRequest)
+		return
+	}
+	var newOwnerID int
+	if v, err := strconv.Atoi(newOwnerIDString); err != nil {
+		http.Error(w, "Error: The user ID of the new owner must be a number.", http.StatusBadRequest)
+		return
+	} else {
+		newOwnerID = v
+	}
+
+	// Get the corresponding table
+	t, exists := getTableAndLock(nil, tableID, true)
+	if !exists {
+		http.Error(w, "Error: Table "+strconv.FormatUint(tableID, 10)+" does not exist.",
+			http.StatusBadRequest)
+		return
+	}
+	defer t.Mutex.Unlock()
+
+	if t.Replay {
+		http.Error(w, "Error: Table "+strconv.FormatUint(tableID, 10)+" is a replay.",
+			http.StatusBadRequest)
+		return
+	}
+
+	// Validate that the new owner is joined to the table
+	newOwnerIndex := t.GetPlayerIndexFromID(newOwnerID)
+	if newOwnerIndex == -1 {
+		http.Error(w, "Error: User "+strconv.Itoa(newOwnerID)+" is not joined to table "+
+			strconv.FormatUint(tableID, 10)+".", http.StatusBadRequest)
+		return
+	}
+
+	tableTransferOwner(t, newOwnerIndex, "An administrator")
+
+	c.String(http.StatusOK, "success\n")
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// httpLocalhostVariantToggle enables or disables a variant for new tables
+// e.g. "/variantToggle?name=Rainbow%20(6%20Suits)&enabled=false"
+func httpLocalhostVariantToggle(c *gin.Context) {
+	// Local variables
+	w := c.Writer
+
+	variantName := c.Query("name")
+	if _, ok := variants[variantName]; !ok {
+		http.Error(w, "Error: The variant of \""+variantName+"\" does not exist.", http.StatusBadRequest)
+		return
+	}
+
+	var enabled bool
+	enabledString := c.Query("enabled")
+	if enabledString == "true" {
+		enabled = true
+	} else if enabledString == "false" {
+		enabled = false
+	} else {
+		http.Error(w, "Error: \"enabled\" must be either \"true\" or \"false\".", http.StatusBadRequest)
+		return
+	}
+
+	if err := setVariantEnabled(variantName, enabled); err != nil {
+		logger.Error("Failed to save the disabled variants:", err)
+		http.Error(
+			w,
+			http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	if enabled {
+		logger.Info("Enabled variant: " + variantName)
+	} else {
+		logger.Info("Disabled variant: " + variantName)
+	}
+	c.String(http.StatusOK, "success\n")
+}
@@ -149,6 +149,7 @@ func httpWS(c *gin.Context) {
 	keys["friends"] = friendsMap
 	keys["reverseFriends"] = reverseFriendsMap
 	keys["hyphenated"] = hyphenated
+	keys["remoteAddr"] = ip
 
 	// Validation succeeded; establish the WebSocket connection
 	// "HandleRequestWithKeys()" will call the "websocketConnect()" function if successful;
@@ -206,10 +207,17 @@ func defaultSessionKeys() map[string]interface{} {
 	keys["reverseFriends"] = make(map[int]struct{})
 	keys["hyphenated"] = false
 	keys["inactive"] = false
+	keys["datetimeInactive"] = time.Time{}
 	keys["fakeUser"] = false
-	keys["rateLimitAllowance"] = RateLimitRate
+	keys["rateLimitAllowance"] = rateLimitBurst
 	keys["rateLimitLastCheck"] = time.Now()
+	keys["rateLimitViolations"] = 0
 	keys["banned"] = false
+	keys["connectedAt"] = time.Now()
+	keys["remoteAddr"] = ""
+	keys["lastCommand"] = ""
+	keys["showTouchedCards"] = true
+	keys["sendBufferFull"] = false
 
 	return keys
 }
@@ -0,0 +1,79 @@
+// Sessions that are subscribed to a compact feed of lobby changes
+// (from the "lobbySubscribe" command)
+
+package main
+
+import (
+	"sync"
+)
+
+const (
+	LobbyUpdateCreated = "created"
+	LobbyUpdateStarted = "started"
+	LobbyUpdateRemoved = "removed"
+)
+
+var (
+	// Indexed by user ID
+	lobbySubscribers      = make(map[int]*Session)
+	lobbySubscribersMutex = sync.RWMutex{}
+)
+
+type LobbyUpdateMessage struct {
+	Type    string `json:"type"`
+	TableID uint64 `json:"tableID"`
+	// The rest of the fields are omitted for removed tables
+	Name       string `json:"name,omitempty"`
+	Variant    string `json:"variant,omitempty"`
+	NumPlayers int    `json:"numPlayers,omitempty"`
+	Timed      bool   `json:"timed,omitempty"`
+	Password   bool   `json:"password,omitempty"`
+}
+
+func lobbySubscribe(s *Session) {
+	lobbySubscribersMutex.Lock()
+	defer lobbySubscribersMutex.Unlock()
+
+	lobbySubscribers[s.UserID()] = s
+}
+
+// lobbyUnsubscribe returns true if the session was subscribed
+func lobbyUnsubscribe(s *Session) bool {
+	lobbySubscribersMutex.Lock()
+	defer lobbySubscribersMutex.Unlock()
+
+	// The user might have already subscribed again from a new session (e.g. after a reconnect)
+	if s2, ok := lobbySubscribers[s.UserID()]; !ok || s2.SessionID() != s.SessionID() {
+		return false
+	}
+
+	delete(lobbySubscribers, s.UserID())
+	return true
+}
+
+// notifyLobbySubscribers sends a lobby update to every subscribed session
+// The table mutex must be held when calling this function
+func notifyLobbySubscribers(updateType string, t *Table) {
+	// Users that cannot see the table in the lobby should not hear about it either
+	if !t.Visible {
+		return
+	}
+
+	msg := &LobbyUpdateMessage{
+		Type:    updateType,
+		TableID: t.ID,
+	}
+	if updateType != LobbyUpdateRemoved {
+		msg.Name = t.Name
+		msg.Variant = t.Options.VariantName
+		msg.NumPlayers = len(t.Players)
+		msg.Timed = t.Options.Timed
+		msg.Password = len(t.PasswordHash) > 0
+	}
+
+	lobbySubscribersMutex.RLock()
+	for _, s := range lobbySubscribers {
+		s.Emit("lobbyUpdate", msg)
+	}
+	lobbySubscribersMutex.RUnlock()
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+func useTestLobbySubscribers(t *testing.T) {
+	reset := func() {
+		lobbySubscribersMutex.Lock()
+		lobbySubscribers = make(map[int]*Session)
+		lobbySubscribersMutex.Unlock()
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+func isTestLobbySubscriber(userID int) bool {
+	lobbySubscribersMutex.RLock()
+	defer lobbySubscribersMutex.RUnlock()
+	_, ok := lobbySubscribers[userID]
+	return ok
+}
+
+func readTestLobbyUpdate(t *testing.T, conn *websocket.Conn) *LobbyUpdateMessage {
+	var msg LobbyUpdateMessage
+	if err := json.Unmarshal([]byte(readTestCommand(t, conn, "lobbyUpdate")), &msg); err != nil {
+		t.Fatal("failed to unmarshal the lobby update:", err)
+	}
+
+	return &msg
+}
+
+// expectTestNoLobbyUpdate fails the test if the session was sent a lobby update
+// A warning is sent to the session as a marker, since it is received after any earlier messages
+func expectTestNoLobbyUpdate(t *testing.T, s *Session, conn *websocket.Conn) {
+	s.Warning("marker")
+	for {
+		command, data := readTestMessage(t, conn)
+		if command == "lobbyUpdate" {
+			t.Errorf("%v was sent a lobby update: %v", s.Username(), data)
+		} else if command == "warning" && strings.Contains(data, "marker") {
+			return
+		}
+	}
+}
+
+func TestLobbySubscribe(t *testing.T) {
+	resetTestTables(t)
+	useTestLobbySubscribers(t)
+	s, conn := newTestWebsocket(t, 10, "Dan", 0)
+	s2, conn2 := newTestWebsocket(t, 11, "Eve", 0)
+	s3, conn3 := newTestWebsocket(t, 12, "Frank", 0)
+	commandLobbySubscribe(s, &CommandData{})    // Manual invocation
+	commandLobbySubscribe(s2, &CommandData{})   // Manual invocation
+	commandLobbyUnsubscribe(s2, &CommandData{}) // Manual invocation
+
+	// This is what "tableCreate()" does after adding the table to the map
+	tb := newTestTable(t, 2)
+	tb.Options.Timed = true
+	notifyLobbySubscribers(LobbyUpdateCreated, tb)
+
+	expected := &LobbyUpdateMessage{
+		Type:       LobbyUpdateCreated,
+		TableID:    tb.ID,
+		Name:       tb.Name,
+		Variant:    "No Variant",
+		NumPlayers: 2,
+		Timed:      true,
+	}
+	if msg := readTestLobbyUpdate(t, conn); *msg != *expected {
+		t.Errorf("expected the lobby update to be %+v, but got %+v", expected, msg)
+	}
+	expectTestNoLobbyUpdate(t, s2, conn2)
+	expectTestNoLobbyUpdate(t, s3, conn3)
+
+	// Only the ID is sent for removed tables
+	notifyAllTableGone(tb)
+	expected = &LobbyUpdateMessage{
+		Type:    LobbyUpdateRemoved,
+		TableID: tb.ID,
+	}
+	if msg := readTestLobbyUpdate(t, conn); *msg != *expected {
+		t.Errorf("expected the lobby update to be %+v, but got %+v", expected, msg)
+	}
+	expectTestNoLobbyUpdate(t, s2, conn2)
+}
+
+func TestLobbySubscribeHiddenTable(t *testing.T) {
+	resetTestTables(t)
+	useTestLobbySubscribers(t)
+	s, conn := newTestWebsocket(t, 10, "Dan", 0)
+	commandLobbySubscribe(s, &CommandData{}) // Manual invocation
+
+	tb := newTestTable(t, 2)
+	tb.Visible = false
+	notifyLobbySubscribers(LobbyUpdateCreated, tb)
+	expectTestNoLobbyUpdate(t, s, conn)
+}
+
+func TestLobbySubscribeTableCreate(t *testing.T) {
+	if db == nil {
+		t.Skip("creating a table requires a database")
+	}
+	resetTestTables(t)
+	useTestLobbySubscribers(t)
+	s, conn := newTestWebsocket(t, 10, "Dan", 0)
+	s2, conn2 := newTestWebsocket(t, 11, "Eve", 0)
+	commandLobbySubscribe(s, &CommandData{}) // Manual invocation
+
+	commandTableCreate(s2, &CommandData{ // Manual invocation
+		Name: "Test Table",
+		Options: &Options{
+			VariantName: "No Variant",
+		},
+	})
+	if msg := readTestLobbyUpdate(t, conn); msg.Type != LobbyUpdateCreated ||
+		msg.Name != "Test Table" {
+
+		t.Errorf("expected a lobby update for the new table, but got %+v", msg)
+	}
+	expectTestNoLobbyUpdate(t, s2, conn2)
+}
+
+func TestLobbyUnsubscribe(t *testing.T) {
+	useTestLobbySubscribers(t)
+	s, conn := newTestWebsocket(t, 10, "Dan", 0)
+
+	commandLobbyUnsubscribe(s, &CommandData{}) // Manual invocation
+	expectTestWarning(t, conn, "You are not subscribed to lobby updates.")
+
+	// A session that was replaced by a new one cannot unsubscribe the new one
+	s2, _ := newTestWebsocket(t, 10, "Dan", 0)
+	commandLobbySubscribe(s, &CommandData{})  // Manual invocation
+	commandLobbySubscribe(s2, &CommandData{}) // Manual invocation
+	if lobbyUnsubscribe(s) {
+		t.Error("the old session unsubscribed the new one")
+	}
+	if !isTestLobbySubscriber(10) {
+		t.Error("the new session is no longer subscribed")
+	}
+}
+
+func TestLobbyUnsubscribeOnDisconnect(t *testing.T) {
+	resetTestTables(t)
+	useTestLobbySubscribers(t)
+	s, _ := newTestWebsocket(t, 10, "Dan", 0)
+	sessionsMutex.Lock()
+	sessions[s.UserID()] = s
+	sessionsMutex.Unlock()
+	commandLobbySubscribe(s, &CommandData{}) // Manual invocation
+
+	websocketDisconnect(s.Session)
+	if isTestLobbySubscriber(10) {
+		t.Error("the session is still subscribed after disconnecting")
+	}
+}
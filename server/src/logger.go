@@ -1,9 +1,17 @@
 package main
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	logging "github.com/Zamiell/go-logging"
 	sentry "github.com/getsentry/sentry-go"
@@ -31,6 +39,80 @@ func NewLogger() *Logger {
 	}
 }
 
+// SetFormat switches the output format of the logger
+// It is called after the ".env" file is loaded, since the format is specified with the
+// "LOG_FORMAT" environment variable
+func (l *Logger) SetFormat(format string) {
+	if format == "" || format == "text" {
+		// Keep the human-readable format from "NewLogger()"
+		return
+	}
+
+	if format != "json" {
+		l.Fatal("The \"LOG_FORMAT\" environment variable must be either \"text\" or \"json\".")
+		return
+	}
+
+	loggingBackend := logging.NewLogBackend(os.Stdout, "", 0)
+	loggingBackendFormatted := logging.NewBackendFormatter(loggingBackend, &JSONFormatter{})
+	logging.SetBackend(loggingBackendFormatted)
+}
+
+// LogFields provide context about what a log message relates to (e.g. the table ID)
+// They are passed as the final argument to one of the "WithFields" logging methods
+// In the human-readable format, they are printed as "key=value" pairs after the message
+// In the JSON format, they become top-level keys of the JSON object
+type LogFields map[string]interface{}
+
+func (fields LogFields) String() string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+fmt.Sprint(fields[k]))
+	}
+
+	return "(" + strings.Join(pairs, " ") + ")"
+}
+
+// JSONFormatter writes every log record as a single line of JSON
+type JSONFormatter struct{}
+
+func (f *JSONFormatter) Format(calldepth int, r *logging.Record, w io.Writer) error {
+	entry := make(map[string]interface{})
+	args := make([]interface{}, 0, len(r.Args))
+	for _, arg := range r.Args {
+		if fields, ok := arg.(LogFields); ok {
+			for k, v := range fields {
+				entry[k] = v
+			}
+		} else {
+			args = append(args, arg)
+		}
+	}
+
+	entry["time"] = r.Time.Format(time.RFC3339)
+	entry["level"] = r.Level.String()
+	// (this must match the call depth in the "%{shortfile}" verb of the text formatter)
+	if _, file, line, ok := runtime.Caller(calldepth + 2); ok {
+		entry["file"] = filepath.Base(file) + ":" + strconv.Itoa(line)
+	}
+	entry["msg"] = strings.TrimSuffix(fmt.Sprintln(args...), "\n")
+
+	var entryJSON []byte
+	if v, err := json.Marshal(entry); err != nil {
+		return err
+	} else {
+		entryJSON = v
+	}
+	_, err := w.Write(entryJSON)
+	return err
+}
+
 func (l *Logger) Debug(args ...interface{}) {
 	l.Logger.Debug(args...)
 }
@@ -73,3 +155,31 @@ func (l *Logger) Warning(args ...interface{}) {
 
 	l.Logger.Warning(args...)
 }
+
+func (l *Logger) InfoWithFields(fields LogFields, args ...interface{}) {
+	l.Logger.Info(append(args, fields)...)
+}
+
+func (l *Logger) WarningWithFields(fields LogFields, args ...interface{}) {
+	if usingSentry {
+		sentry.WithScope(func(scope *sentry.Scope) {
+			scope.SetLevel(sentry.LevelWarning)
+			scope.SetExtras(fields)
+			sentry.CaptureException(errors.New(fmt.Sprint(args...)))
+		})
+	}
+
+	l.Logger.Warning(append(args, fields)...)
+}
+
+func (l *Logger) ErrorWithFields(fields LogFields, args ...interface{}) {
+	if usingSentry {
+		sentry.WithScope(func(scope *sentry.Scope) {
+			scope.SetLevel(sentry.LevelError)
+			scope.SetExtras(fields)
+			sentry.CaptureException(errors.New(fmt.Sprint(args...)))
+		})
+	}
+
+	l.Logger.Error(append(args, fields)...)
+}
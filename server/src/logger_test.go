@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	logging "github.com/Zamiell/go-logging"
+)
+
+func TestLoggerJSONFormat(t *testing.T) {
+	var buf bytes.Buffer
+	backend := logging.NewLogBackend(&buf, "", 0)
+	logging.SetBackend(logging.NewBackendFormatter(backend, &JSONFormatter{}))
+	t.Cleanup(func() {
+		logger = NewLogger()
+	})
+
+	logger.WarningWithFields(LogFields{
+		"tableID": 123,
+		"userID":  5,
+		"command": "action",
+	}, "Something", "happened.")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("failed to unmarshal the log entry \"%s\": %v", buf.Bytes(), err)
+	}
+
+	expected := map[string]interface{}{
+		"tableID": float64(123),
+		"userID":  float64(5),
+		"command": "action",
+		"level":   "WARNING",
+		"msg":     "Something happened.",
+	}
+	for k, v := range expected {
+		if entry[k] != v {
+			t.Errorf("expected \"%v\" to be %v, but it was %v", k, v, entry[k])
+		}
+	}
+	if _, ok := entry["time"]; !ok {
+		t.Error("the log entry does not have a \"time\" field")
+	}
+	if file, _ := entry["file"].(string); !strings.HasPrefix(file, "logger_test.go:") {
+		t.Errorf("expected the file to be the caller of the logger, but it was: %v", file)
+	}
+}
+
+func TestLogFieldsString(t *testing.T) {
+	fields := LogFields{
+		"userID":  5,
+		"tableID": 123,
+	}
+	if s := fields.String(); s != "(tableID=123 userID=5)" {
+		t.Errorf("expected the fields to be sorted by key, but got: %v", s)
+	}
+}
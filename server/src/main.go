@@ -3,6 +3,7 @@ package main // In Go, executable commands must always use package main
 // This file contains the entry point for the server software
 
 import (
+	"flag"
 	"os"
 	"os/exec"
 	"path"
@@ -29,7 +30,33 @@ var (
 	datetimeStarted  time.Time
 )
 
+// These are set when the server is compiled with linker flags (see the "build_server.sh" script)
+// e.g. go build -ldflags "-X main.buildCommit=abc123 -X main.buildTime=2020-08-01T00:00:00Z"
+var (
+	buildCommit string
+	buildTime   string
+)
+
 func main() {
+	// Parse the command-line flags
+	validateTablesFlag := flag.Bool(
+		"validate-tables",
+		false,
+		"Check that all of the serialized tables can be restored and then exit",
+	)
+	exportTablesFlag := flag.String(
+		"export-tables",
+		"",
+		"Write all of the serialized tables to the given file and then exit",
+	)
+	importTablesFlag := flag.String(
+		"import-tables",
+		"",
+		"Add the serialized tables from a file created with \"-export-tables\" and then exit "+
+			"(the server must not be running)",
+	)
+	flag.Parse()
+
 	// Initialize logging (in "logger.go")
 	logger = NewLogger()
 
@@ -125,6 +152,35 @@ func main() {
 		return
 	}
 
+	// Optionally switch to machine-readable logs (in "logger.go")
+	logger.SetFormat(os.Getenv("LOG_FORMAT"))
+
+	// Check to see if the serialized tables can be restored without actually starting the server
+	// (this does not require a database connection so that it can easily be run in CI)
+	if *validateTablesFlag {
+		validateTablesInit() // (in "serialize_tables.go")
+		if validateTables() > 0 {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Move the serialized tables to or from another server without actually starting the server
+	if *exportTablesFlag != "" || *importTablesFlag != "" {
+		// (in "serialize_tables_bundle.go")
+		store := NewFileTableStore(tablesPath, os.Getenv("SERIALIZE_COMPRESS") == "true")
+		if *exportTablesFlag != "" {
+			if err := exportTables(store, *exportTablesFlag); err != nil {
+				logger.Fatal("Failed to export the tables:", err)
+			}
+		} else {
+			if err := importTables(store, *importTablesFlag); err != nil {
+				logger.Fatal("Failed to import the tables:", err)
+			}
+		}
+		os.Exit(0)
+	}
+
 	if os.Getenv("DOMAIN") == "" ||
 		os.Getenv("DOMAIN") == "localhost" ||
 		strings.HasPrefix(os.Getenv("DOMAIN"), "192.168.") ||
@@ -167,6 +223,9 @@ func main() {
 	suitsInit()    // (in "suits.go")
 	variantsInit() // (in "variants.go")
 
+	// Load the variants that were disabled by an administrator (in "variants_disabled.go")
+	disabledVariantsInit()
+
 	// Initialize the action functions command map (in "command_action.go")
 	actionsFunctionsInit()
 
@@ -182,9 +241,27 @@ func main() {
 	// Start the Discord bot (in "discord.go")
 	discordInit()
 
+	// Initialize the Prometheus metrics (in "metrics.go")
+	metricsInit()
+
+	// Read the idle timeouts for tables (in "tables.go")
+	idleTimeoutInit()
+
+	// Read the maximum number of tables (in "tables.go")
+	maxTablesInit()
+
+	// Read the bonus time for extensions in timed games (in "command_table_request_extension.go")
+	extensionInit()
+
+	// Read the limit on the length of games (in "game_actions_limit.go")
+	maxGameActionsInit()
+
 	// Initialize a WebSocket router using the Melody framework (in "websocket.go")
 	websocketInit()
 
+	// Start periodically saving ongoing tables to disk (in "serialize_tables.go")
+	serializeTablesInit()
+
 	// Initialize chat commands (in "chatCommand.go")
 	chatCommandInit()
 
@@ -193,6 +270,10 @@ func main() {
 
 	// Restore tables that were ongoing at the time of the last server restart
 	restoreTables()
+	serverReady.Set()
+
+	// Start periodically checking for games that are stuck (in "game_deadlock.go")
+	go deadlockCheckLoop()
 
 	// Initialize an HTTP router that will only listen locally for maintenance-related commands
 	// (in "httpLocalhost.go")
@@ -0,0 +1,461 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	logging "github.com/Zamiell/go-logging"
+	"github.com/gorilla/websocket"
+	melody "gopkg.in/olahol/melody.v1"
+)
+
+var testPlayerNames = []string{"Alice", "Bob", "Cathy", "Donner", "Emily", "Frank"}
+
+// TestMain performs the same initialization as the "main()" function,
+// minus everything that requires a database or a network connection
+func TestMain(tm *testing.M) {
+	logger = NewLogger()
+	dataPath = path.Join("..", "..", "data")
+	tablesPath = path.Join(os.TempDir(), "hanabi-test-tables-"+strconv.Itoa(os.Getpid()))
+
+	// Idle timeouts are disabled in development, so the tests do not leave idle goroutines behind
+	isDev = true
+
+	colorsInit()
+	suitsInit()
+	variantsInit()
+	actionsFunctionsInit()
+	replayActionsFunctionsInit()
+	charactersInit()
+	metricsInit()
+	idleTimeoutInit()
+	maxTablesInit()
+	extensionInit()
+	maxGameActionsInit()
+	websocketInit()
+	chatCommandInit()
+
+	// The periodic serialization is never started, so these are set to their defaults manually
+	serializeChatLimit = DefaultSerializeChatLimit
+	serializeLockTimeout = DefaultSerializeLockTimeout * time.Second
+	serializeBaseInterval = DefaultSerializeBaseInterval * time.Minute
+
+	os.Exit(tm.Run())
+}
+
+// useTestTableStore points the table store at an empty temporary directory for the duration of
+// the test
+func useTestTableStore(t *testing.T, compress bool) *FileTableStore {
+	oldTableStore := tableStore
+	oldTablesPath := tablesPath
+	oldSerializeCompress := serializeCompress
+
+	tablesPath = newTestDir(t)
+	serializeCompress = compress
+	store := NewFileTableStore(tablesPath, compress)
+	tableStore = store
+
+	t.Cleanup(func() {
+		tableStore = oldTableStore
+		tablesPath = oldTablesPath
+		serializeCompress = oldSerializeCompress
+	})
+
+	return store
+}
+
+// newTestDir creates an empty directory that is removed after the test finishes
+func newTestDir(t *testing.T) string {
+	var dirPath string
+	if v, err := ioutil.TempDir("", "hanabi-test-"); err != nil {
+		t.Fatal("failed to create a temporary directory:", err)
+	} else {
+		dirPath = v
+	}
+	t.Cleanup(func() {
+		os.RemoveAll(dirPath)
+	})
+
+	return dirPath
+}
+
+// captureTestLogs collects the log messages for the duration of the test so that they can be
+// inspected with "findTestLog()"
+func captureTestLogs(t *testing.T) *logging.MemoryBackend {
+	backend := logging.NewMemoryBackend(1024)
+	logging.SetBackend(backend)
+	t.Cleanup(func() {
+		logger = NewLogger()
+	})
+
+	return backend
+}
+
+// findTestLog returns the first log message with the given level that contains the given text
+// (or an empty string if there is no such message)
+func findTestLog(backend *logging.MemoryBackend, level logging.Level, text string) string {
+	for n := backend.Head(); n != nil; n = n.Next() {
+		if msg := n.Record.Message(); n.Record.Level == level && strings.Contains(msg, text) {
+			return msg
+		}
+	}
+
+	return ""
+}
+
+// setTestEnv sets an environment variable for the duration of the test
+func setTestEnv(t *testing.T, key string, value string) {
+	oldValue, existed := os.LookupEnv(key)
+	os.Setenv(key, value)
+	t.Cleanup(func() {
+		if existed {
+			os.Setenv(key, oldValue)
+		} else {
+			os.Unsetenv(key)
+		}
+	})
+}
+
+// resetTestTables removes every table (and every session) before the test and after it finishes
+func resetTestTables(t *testing.T) {
+	reset := func() {
+		tablesMutex.Lock()
+		tables = make(map[uint64]*Table)
+		tablesMutex.Unlock()
+
+		sessionsMutex.Lock()
+		sessions = make(map[int]*Session)
+		sessionsMutex.Unlock()
+
+		restoreTablesCalled.UnSet()
+		blockAllIncomingMessages.UnSet()
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+// newTestTable creates an unstarted table owned by the first of the given number of fake players
+// The user IDs of the players start at 1
+// ("resetTestTables()" must be called at the beginning of the test)
+func newTestTable(t *testing.T, numPlayers int) *Table {
+	tb := NewTable("Test Table", 1)
+	tb.Options = &Options{
+		VariantName: "No Variant",
+	}
+	tb.ExtraOptions = &ExtraOptions{
+		DatabaseID: -1,
+		// A fixed seed prevents the players from being shuffled,
+		// so the first player is always the active player
+		CustomSeed:        "p" + strconv.Itoa(numPlayers) + "v0s1",
+		NoWriteToDatabase: true,
+	}
+	for i := 0; i < numPlayers; i++ {
+		tb.Players = append(tb.Players, newTestPlayer(i+1, testPlayerNames[i]))
+	}
+
+	tablesMutex.Lock()
+	tables[tb.ID] = tb
+	tablesMutex.Unlock()
+
+	return tb
+}
+
+// newTestSession creates a session that is not connected to anything
+// (messages that are sent to it are discarded)
+func newTestSession(id int, name string) *Session {
+	s := newFakeSession(id, name)
+	s.Set("sessionID", uint64(id))
+	s.Set("fakeUser", false)
+	return s
+}
+
+func newTestPlayer(id int, name string) *Player {
+	s := newTestSession(id, name)
+	return &Player{
+		ID:      id,
+		Name:    name,
+		Session: s,
+		Present: true,
+	}
+}
+
+// newTestGame is the same as "newTestTable()", but the game is also started
+func newTestGame(t *testing.T, numPlayers int) *Table {
+	tb := newTestTable(t, numPlayers)
+	startTestGame(t, tb)
+	return tb
+}
+
+// newTestTimedGame is the same as "newTestGame()", but the game is timed
+// (the turn of a player that runs out of time is taken for them)
+func newTestTimedGame(t *testing.T, numPlayers int) *Table {
+	tb := newTestTable(t, numPlayers)
+	tb.Options.Timed = true
+	tb.Options.TimeBase = 120
+	tb.Options.TimePerTurn = 10
+	tb.Options.TimeoutAction = TimeoutActionAutoDiscard
+	startTestGame(t, tb)
+
+	// Normally, the clock starts once the first player has loaded the game
+	tb.Game.DatetimeTurnBegin = time.Now()
+	return tb
+}
+
+func startTestGame(t *testing.T, tb *Table) {
+	tableStart(nil, &CommandData{}, tb)
+	if !tb.Running {
+		t.Fatal("failed to start the test game")
+	}
+
+	// Normally, the players are marked as present once they have loaded the game
+	for _, p := range tb.Players {
+		p.Present = true
+	}
+}
+
+// serializeAndRestoreTestTables simulates a server restart
+// ("useTestTableStore()" must be called at the beginning of the test)
+func serializeAndRestoreTestTables(t *testing.T) {
+	if !serializeTables() {
+		t.Fatal("failed to serialize the tables")
+	}
+	resetTestTables(t)
+	restoreTables()
+}
+
+// performTestAction performs an action for the active player of the game
+func performTestAction(t *testing.T, tb *Table, actionType int, target int, value int) {
+	g := tb.Game
+	p := tb.Players[g.ActivePlayerIndex]
+	commandAction(p.Session, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		Type:    actionType,
+		Target:  target,
+		Value:   value,
+		NoLock:  true,
+	})
+	if g.InvalidActionOccurred {
+		t.Fatal("the action of type " + strconv.Itoa(actionType) + " was not valid")
+	}
+}
+
+// discardTestCard makes the active player discard their oldest card
+// (the team must not be at the maximum amount of clues)
+func discardTestCard(t *testing.T, tb *Table) {
+	gp := tb.Game.Players[tb.Game.ActivePlayerIndex]
+	performTestAction(t, tb, ActionTypeDiscard, gp.Hand[0].Order, 0)
+}
+
+// clueTestPlayer makes the active player give a rank clue to the next player that touches their
+// oldest card
+func clueTestPlayer(t *testing.T, tb *Table) {
+	g := tb.Game
+	target := (g.ActivePlayerIndex + 1) % len(g.Players)
+	performTestAction(t, tb, ActionTypeRankClue, target, g.Players[target].Hand[0].Rank)
+}
+
+// newTestWebsocket connects a real WebSocket client to a Melody router so that the messages sent
+// to a session can be inspected
+// If the buffer size is 0, the default send buffer size is used
+func newTestWebsocket(t *testing.T, id int, name string, bufferSize int) (*Session, *websocket.Conn) {
+	router := melody.New()
+	router.Config.MaxMessageSize = m.Config.MaxMessageSize
+	if bufferSize > 0 {
+		router.Config.MessageBufferSize = bufferSize
+	}
+	router.HandleError(websocketError)
+	t.Cleanup(func() {
+		router.Close() // nolint: errcheck
+	})
+
+	return connectTestWebsocket(t, router, id, name)
+}
+
+// connectTestWebsocket connects a real WebSocket client to the given Melody router
+// (the caller is responsible for closing the router)
+func connectTestWebsocket(
+	t *testing.T,
+	router *melody.Melody,
+	id int,
+	name string,
+) (*Session, *websocket.Conn) {
+	connected := make(chan *melody.Session, 1)
+	router.HandleConnect(func(ms *melody.Session) {
+		connected <- ms
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys := defaultSessionKeys()
+		keys["sessionID"] = atomic.AddUint64(&sessionIDCounter, 1)
+		keys["userID"] = id
+		keys["username"] = name
+		router.HandleRequestWithKeys(w, r, keys) // nolint: errcheck
+	}))
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	var conn *websocket.Conn
+	if v, _, err := websocket.DefaultDialer.Dial(url, nil); err != nil {
+		server.Close()
+		t.Fatal("failed to connect to the test WebSocket server:", err)
+	} else {
+		conn = v
+	}
+
+	t.Cleanup(func() {
+		conn.Close()
+		server.Close()
+	})
+
+	select {
+	case ms := <-connected:
+		return &Session{Session: ms}, conn
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out while waiting for the test WebSocket session to connect")
+		return nil, nil
+	}
+}
+
+// readTestMessage returns the command and the data of the next message that the client receives
+func readTestMessage(t *testing.T, conn *websocket.Conn) (string, string) {
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second)) // nolint: errcheck
+	var msg []byte
+	if _, v, err := conn.ReadMessage(); err != nil {
+		t.Fatal("failed to read a message from the test WebSocket session:", err)
+	} else {
+		msg = v
+	}
+
+	parts := strings.SplitN(string(msg), " ", 2)
+	if len(parts) != 2 {
+		t.Fatal("received a message without any data: " + string(msg))
+	}
+
+	return parts[0], parts[1]
+}
+
+// readTestCommand skips messages until it finds one with the given command and returns its data
+func readTestCommand(t *testing.T, conn *websocket.Conn, command string) string {
+	for {
+		if c, data := readTestMessage(t, conn); c == command {
+			return data
+		}
+	}
+}
+
+// getTestTableResponse sends a command for the table from a new connection for the given user and
+// unmarshals the data of the response into the given pointer
+// (it also fails the test if the response is not for the same table)
+func getTestTableResponse(
+	t *testing.T,
+	tb *Table,
+	id int,
+	name string,
+	handler func(*Session, *CommandData),
+	command string,
+	v interface{},
+) {
+	s, conn := newTestWebsocket(t, id, name, 0)
+	handler(s, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		NoLock:  true,
+	})
+
+	data := []byte(readTestCommand(t, conn, command))
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("failed to unmarshal the \"%v\" message: %v", command, err)
+	}
+	var msg struct {
+		TableID uint64 `json:"tableID"`
+	}
+	if err := json.Unmarshal(data, &msg); err != nil || msg.TableID != tb.ID {
+		t.Errorf("expected the \"%v\" message to be for table %v, but got table %v", command,
+			tb.ID, msg.TableID)
+	}
+}
+
+// connectTestPlayers replaces the session of every player at the table with one that is connected
+// with a real WebSocket client (so that the messages sent to them can be inspected)
+func connectTestPlayers(t *testing.T, tb *Table) []*websocket.Conn {
+	conns := make([]*websocket.Conn, 0)
+	for _, p := range tb.Players {
+		s, conn := newTestWebsocket(t, p.ID, p.Name, 0)
+		p.Session = s
+		conns = append(conns, conn)
+	}
+
+	return conns
+}
+
+// newTestWebsocketSpectator adds a spectator to the table that is connected with a real WebSocket
+// client (so that the messages sent to them can be inspected)
+func newTestWebsocketSpectator(t *testing.T, tb *Table, id int, name string) (*Spectator, *websocket.Conn) {
+	s, conn := newTestWebsocket(t, id, name, 0)
+	sp := &Spectator{
+		ID:                   id,
+		Name:                 name,
+		Session:              s,
+		ShadowingPlayerIndex: -1,
+	}
+	tb.Spectators = append(tb.Spectators, sp)
+
+	return sp, conn
+}
+
+// expectTestWarning reads messages until it finds a warning and fails the test if the warning
+// does not contain the given text
+func expectTestWarning(t *testing.T, conn *websocket.Conn, text string) {
+	data := readTestCommand(t, conn, "warning")
+	if !strings.Contains(data, text) {
+		t.Errorf("expected a warning containing \"%v\", but got: %v", text, data)
+	}
+}
+
+// expectTestError is the same as "expectTestWarning()", but for errors
+func expectTestError(t *testing.T, conn *websocket.Conn, text string) {
+	data := readTestCommand(t, conn, "error")
+	if !strings.Contains(data, text) {
+		t.Errorf("expected an error containing \"%v\", but got: %v", text, data)
+	}
+}
+
+// expectTestClose reads messages until the connection is closed and fails the test if it was not
+// closed with the given close code
+func expectTestClose(t *testing.T, conn *websocket.Conn, code int) {
+	if closeErr := readTestClose(t, conn); closeErr != nil && closeErr.Code != code {
+		t.Errorf("expected a close code of %v, but got %v", code, closeErr.Code)
+	}
+}
+
+// expectTestCloseWithReason is the same as "expectTestClose()", but also checks the reason
+func expectTestCloseWithReason(t *testing.T, conn *websocket.Conn, code int, reason string) {
+	if closeErr := readTestClose(t, conn); closeErr != nil &&
+		(closeErr.Code != code || closeErr.Text != reason) {
+
+		t.Errorf("expected a close code of %v with a reason of \"%v\", but got %v with \"%v\"",
+			code, reason, closeErr.Code, closeErr.Text)
+	}
+}
+
+// readTestClose reads messages until the connection is closed and returns the close frame
+// (or nil if the connection was not closed properly)
+func readTestClose(t *testing.T, conn *websocket.Conn) *websocket.CloseError {
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second)) // nolint: errcheck
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			if closeErr, ok := err.(*websocket.CloseError); ok {
+				return closeErr
+			}
+			t.Errorf("expected the connection to be closed, but got: %v", err)
+			return nil
+		}
+	}
+}
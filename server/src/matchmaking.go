@@ -0,0 +1,165 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// The time controls of the timed tables that are created by the matchmaking queue
+	MatchmakingTimeBase    = 120 // In seconds
+	MatchmakingTimePerTurn = 20  // In seconds
+)
+
+// QueueEntry is a user that is waiting in the matchmaking queue (from the "queueJoin" command)
+type QueueEntry struct {
+	Session        *Session
+	VariantName    string
+	NumPlayers     int
+	Timed          bool
+	DatetimeJoined time.Time
+}
+
+var (
+	// The users that are waiting for a game, in the order that they joined the queue
+	matchmakingQueue      = make([]*QueueEntry, 0)
+	matchmakingQueueMutex = sync.Mutex{}
+)
+
+// IsCompatible returns true if the two entries want the same kind of game
+func (e *QueueEntry) IsCompatible(e2 *QueueEntry) bool {
+	return e.VariantName == e2.VariantName &&
+		e.NumPlayers == e2.NumPlayers &&
+		e.Timed == e2.Timed
+}
+
+// matchmakingGetEntry returns the queue entry for the given user (or nil if they are not queued)
+// The matchmaking queue mutex must be held when calling this function
+func matchmakingGetEntry(userID int) *QueueEntry {
+	for _, entry := range matchmakingQueue {
+		if entry.Session.UserID() == userID {
+			return entry
+		}
+	}
+
+	return nil
+}
+
+// matchmakingRemove takes the given user out of the matchmaking queue, if they are in it
+// It returns true if they were in the queue
+func matchmakingRemove(userID int) bool {
+	matchmakingQueueMutex.Lock()
+	defer matchmakingQueueMutex.Unlock()
+
+	for i, entry := range matchmakingQueue {
+		if entry.Session.UserID() == userID {
+			matchmakingQueue = append(matchmakingQueue[:i], matchmakingQueue[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchmakingTakeMatch removes and returns the entries for a game if there are enough users
+// waiting for the same kind of game as the given entry (or nil if there are not)
+// The matchmaking queue mutex must be held when calling this function
+func matchmakingTakeMatch(entry *QueueEntry) []*QueueEntry {
+	matchedEntries := make([]*QueueEntry, 0, entry.NumPlayers)
+	for _, entry2 := range matchmakingQueue {
+		if entry.IsCompatible(entry2) {
+			matchedEntries = append(matchedEntries, entry2)
+			if len(matchedEntries) == entry.NumPlayers {
+				break
+			}
+		}
+	}
+	if len(matchedEntries) < entry.NumPlayers {
+		return nil
+	}
+
+	remainingEntries := make([]*QueueEntry, 0, len(matchmakingQueue))
+	for _, entry2 := range matchmakingQueue {
+		matched := false
+		for _, matchedEntry := range matchedEntries {
+			if entry2 == matchedEntry {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			remainingEntries = append(remainingEntries, entry2)
+		}
+	}
+	matchmakingQueue = remainingEntries
+
+	return matchedEntries
+}
+
+// matchmakingCreateTable creates a table for the matched users and seats all of them
+// The user that has been waiting the longest becomes the owner of the table
+// The matchmaking queue mutex must not be held when calling this function
+// (since it acquires the table locks)
+func matchmakingCreateTable(entries []*QueueEntry) {
+	// Local variables
+	owner := entries[0]
+
+	options := &Options{
+		VariantName: owner.VariantName,
+		Timed:       owner.Timed,
+	}
+	if options.Timed {
+		options.TimeBase = MatchmakingTimeBase
+		options.TimePerTurn = MatchmakingTimePerTurn
+	}
+	commandTableCreate(owner.Session, &CommandData{ // Manual invocation
+		Name:    getName(),
+		Options: options,
+	})
+
+	t := owner.Session.GetJoinedTable()
+	if t == nil {
+		// The owner was already warned about why the table could not be created
+		for _, entry := range entries[1:] {
+			entry.Session.Warning("Failed to create the table for your match. " +
+				"Please join the queue again.")
+		}
+		return
+	}
+	logger.Info(t.GetName() + "Created the table from the matchmaking queue.")
+
+	for _, entry := range entries[1:] {
+		commandTableJoin(entry.Session, &CommandData{ // Manual invocation
+			TableID: t.ID,
+		})
+	}
+
+	type QueueMatchedMessage struct {
+		TableID uint64 `json:"tableID"`
+	}
+	for _, entry := range entries {
+		entry.Session.Emit("queueMatched", &QueueMatchedMessage{
+			TableID: t.ID,
+		})
+	}
+}
+
+// NotifyQueue tells the user whether or not they are in the matchmaking queue
+// (an entry of nil means that they are no longer in the queue)
+func (s *Session) NotifyQueue(entry *QueueEntry) {
+	type QueueMessage struct {
+		Queued     bool   `json:"queued"`
+		Variant    string `json:"variant,omitempty"`
+		NumPlayers int    `json:"players,omitempty"`
+		Timed      bool   `json:"timed,omitempty"`
+	}
+	msg := &QueueMessage{
+		Queued: entry != nil,
+	}
+	if entry != nil {
+		msg.Variant = entry.VariantName
+		msg.NumPlayers = entry.NumPlayers
+		msg.Timed = entry.Timed
+	}
+	s.Emit("queue", msg)
+}
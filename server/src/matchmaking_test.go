@@ -0,0 +1,225 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func useTestMatchmakingQueue(t *testing.T) {
+	reset := func() {
+		matchmakingQueueMutex.Lock()
+		matchmakingQueue = make([]*QueueEntry, 0)
+		matchmakingQueueMutex.Unlock()
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+func getTestQueueLength() int {
+	matchmakingQueueMutex.Lock()
+	defer matchmakingQueueMutex.Unlock()
+	return len(matchmakingQueue)
+}
+
+func newTestQueueEntry(id int, variantName string, numPlayers int, timed bool) *QueueEntry {
+	return &QueueEntry{
+		Session:     newTestSession(id, testPlayerNames[id-1]),
+		VariantName: variantName,
+		NumPlayers:  numPlayers,
+		Timed:       timed,
+	}
+}
+
+func TestMatchmakingTakeMatch(t *testing.T) {
+	useTestMatchmakingQueue(t)
+
+	// Only users who want the same kind of game are matched with each other
+	entries := []*QueueEntry{
+		newTestQueueEntry(1, "No Variant", 3, false),
+		newTestQueueEntry(2, "No Variant", 3, true),
+		newTestQueueEntry(3, "No Variant", 2, false),
+		newTestQueueEntry(4, "Rainbow (6 Suits)", 3, false),
+		newTestQueueEntry(5, "No Variant", 3, false),
+	}
+	matchmakingQueue = append(matchmakingQueue, entries...)
+	if v := matchmakingTakeMatch(entries[4]); v != nil {
+		t.Fatalf("a match was made with only 2 compatible users: %v", v)
+	}
+	if len(matchmakingQueue) != len(entries) {
+		t.Fatalf("the queue was changed without a match")
+	}
+
+	// The third compatible user completes the match
+	entry := newTestQueueEntry(6, "No Variant", 3, false)
+	matchmakingQueue = append(matchmakingQueue, entry)
+	matchedEntries := matchmakingTakeMatch(entry)
+	if len(matchedEntries) != 3 || matchedEntries[0] != entries[0] ||
+		matchedEntries[1] != entries[4] || matchedEntries[2] != entry {
+
+		t.Fatalf("expected the 3 compatible users to be matched in the order that they joined, "+
+			"but got %v", matchedEntries)
+	}
+	if len(matchmakingQueue) != 3 {
+		t.Fatalf("expected 3 users to remain in the queue, but there are %v",
+			len(matchmakingQueue))
+	}
+	for i, entry2 := range matchmakingQueue {
+		if entry2 != entries[i+1] {
+			t.Errorf("expected user %v to remain in the queue at position %v",
+				entries[i+1].Session.UserID(), i)
+		}
+	}
+}
+
+func TestQueueJoinWaitsForEnoughPlayers(t *testing.T) {
+	resetTestTables(t)
+	useTestMatchmakingQueue(t)
+
+	for i := 1; i <= 2; i++ {
+		s, conn := newTestWebsocket(t, i, testPlayerNames[i-1], 0)
+		commandQueueJoin(s, &CommandData{ // Manual invocation
+			Variant:    "No Variant",
+			NumPlayers: 3,
+		})
+
+		var msg struct {
+			Queued     bool   `json:"queued"`
+			Variant    string `json:"variant"`
+			NumPlayers int    `json:"players"`
+		}
+		if err := json.Unmarshal([]byte(readTestCommand(t, conn, "queue")), &msg); err != nil {
+			t.Fatal("failed to unmarshal the queue message:", err)
+		}
+		if !msg.Queued || msg.Variant != "No Variant" || msg.NumPlayers != 3 {
+			t.Errorf("expected %v to be queued for a 3-player game, but got %+v", s.Username(),
+				msg)
+		}
+
+		// Users cannot join the queue twice
+		commandQueueJoin(s, &CommandData{ // Manual invocation
+			Variant:    "No Variant",
+			NumPlayers: 3,
+		})
+		expectTestWarning(t, conn, "You are already in the queue.")
+	}
+
+	if v := getTestQueueLength(); v != 2 {
+		t.Errorf("expected 2 users in the queue, but there are %v", v)
+	}
+	tablesMutex.RLock()
+	numTables := len(tables)
+	tablesMutex.RUnlock()
+	if numTables != 0 {
+		t.Error("a table was created without enough players")
+	}
+}
+
+func TestQueueJoinFormsTable(t *testing.T) {
+	if db == nil {
+		t.Skip("creating a table requires a database")
+	}
+	resetTestTables(t)
+	useTestMatchmakingQueue(t)
+
+	sessionsList := make([]*Session, 0)
+	for i := 1; i <= 3; i++ {
+		s, _ := newTestWebsocket(t, i, testPlayerNames[i-1], 0)
+		sessionsMutex.Lock()
+		sessions[s.UserID()] = s
+		sessionsMutex.Unlock()
+		sessionsList = append(sessionsList, s)
+		commandQueueJoin(s, &CommandData{ // Manual invocation
+			Variant:    "No Variant",
+			NumPlayers: 3,
+			Timed:      true,
+		})
+	}
+
+	if v := getTestQueueLength(); v != 0 {
+		t.Errorf("expected the matched users to leave the queue, but there are %v left", v)
+	}
+	tb := sessionsList[0].GetJoinedTable()
+	if tb == nil {
+		t.Fatal("the table was not created")
+	}
+	if tb.Owner != sessionsList[0].UserID() || !tb.Options.Timed {
+		t.Errorf("the table was not created by the first user with a timed game")
+	}
+	for _, s := range sessionsList {
+		if tb.GetPlayerIndexFromID(s.UserID()) == -1 {
+			t.Errorf("%v was not seated at the table", s.Username())
+		}
+	}
+}
+
+func TestQueueLeave(t *testing.T) {
+	useTestMatchmakingQueue(t)
+	s, conn := newTestWebsocket(t, 1, "Alice", 0)
+
+	commandQueueLeave(s, &CommandData{}) // Manual invocation
+	expectTestWarning(t, conn, "You are not in the queue.")
+
+	commandQueueJoin(s, &CommandData{ // Manual invocation
+		Variant:    "No Variant",
+		NumPlayers: 3,
+	})
+	readTestCommand(t, conn, "queue")
+	commandQueueLeave(s, &CommandData{}) // Manual invocation
+	if data := readTestCommand(t, conn, "queue"); data != `{"queued":false}` {
+		t.Errorf("expected the user to be told that they are no longer queued, but got: %v",
+			data)
+	}
+	if v := getTestQueueLength(); v != 0 {
+		t.Errorf("expected the queue to be empty, but there are %v users in it", v)
+	}
+}
+
+func TestQueueDequeueOnDisconnect(t *testing.T) {
+	resetTestTables(t)
+	useTestMatchmakingQueue(t)
+	s, _ := newTestWebsocket(t, 1, "Alice", 0)
+	s2, _ := newTestWebsocket(t, 2, "Bob", 0)
+	sessionsMutex.Lock()
+	sessions[s.UserID()] = s
+	sessions[s2.UserID()] = s2
+	sessionsMutex.Unlock()
+	for _, s3 := range []*Session{s, s2} {
+		commandQueueJoin(s3, &CommandData{ // Manual invocation
+			Variant:    "No Variant",
+			NumPlayers: 3,
+		})
+	}
+
+	websocketDisconnect(s.Session)
+	matchmakingQueueMutex.Lock()
+	defer matchmakingQueueMutex.Unlock()
+	if matchmakingGetEntry(s.UserID()) != nil {
+		t.Error("the user who disconnected is still in the queue")
+	}
+	if matchmakingGetEntry(s2.UserID()) == nil {
+		t.Error("the user who is still connected was removed from the queue")
+	}
+}
+
+func TestQueueJoinValidation(t *testing.T) {
+	useTestMatchmakingQueue(t)
+	s, conn := newTestWebsocket(t, 1, "Alice", 0)
+
+	commandQueueJoin(s, &CommandData{ // Manual invocation
+		Variant:    "Not A Variant",
+		NumPlayers: 3,
+	})
+	expectTestWarningCode(t, conn, ErrVariantNotFound)
+
+	for _, numPlayers := range []int{1, MaxPlayers + 1} {
+		commandQueueJoin(s, &CommandData{ // Manual invocation
+			Variant:    "No Variant",
+			NumPlayers: numPlayers,
+		})
+		expectTestWarning(t, conn, "Games must have between 2 and")
+	}
+
+	if v := getTestQueueLength(); v != 0 {
+		t.Errorf("expected the queue to be empty, but there are %v users in it", v)
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"net/http"
+	"os"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	// The address for a dedicated metrics HTTP server
+	// If blank, the metrics will be served from the main HTTP server instead
+	metricsAddress string
+
+	// This is incremented in the "websocketMessage()" function
+	metricsCommandsProcessed = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "hanabi_websocket_commands_total",
+		Help: "The total number of WebSocket commands processed.",
+	})
+)
+
+// metricsInit registers all of the Prometheus metrics
+// The gauges are calculated on demand whenever the metrics are scraped
+func metricsInit() {
+	metricsAddress = os.Getenv("METRICS_ADDRESS")
+
+	prometheus.MustRegister(metricsCommandsProcessed)
+	prometheus.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "hanabi_websocket_sessions",
+		Help: "The number of connected WebSocket sessions.",
+	}, func() float64 {
+		sessionsMutex.RLock()
+		defer sessionsMutex.RUnlock()
+		return float64(len(sessions))
+	}))
+	prometheus.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "hanabi_running_tables",
+		Help: "The number of ongoing games.",
+	}, func() float64 {
+		return float64(countActiveTables())
+	}))
+	prometheus.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "hanabi_replays",
+		Help: "The number of solo replays and shared replays.",
+	}, func() float64 {
+		tablesMutex.RLock()
+		defer tablesMutex.RUnlock()
+		numReplays := 0
+		for _, t := range tables {
+			if t.Replay {
+				numReplays++
+			}
+		}
+		return float64(numReplays)
+	}))
+	prometheus.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "hanabi_timed_games_running",
+		Help: "The number of ongoing timed games with a clock that is counting down.",
+	}, func() float64 {
+		return float64(countRunningTimedGames())
+	}))
+
+	if metricsAddress != "" {
+		// ListenAndServe is blocking, so we need to start listening in a new goroutine
+		go func() {
+			serveMux := http.NewServeMux()
+			serveMux.Handle("/metrics", promhttp.Handler())
+			metricsServerWithTimeout := &http.Server{
+				Addr:         metricsAddress,
+				Handler:      serveMux,
+				ReadTimeout:  HTTPReadTimeout,
+				WriteTimeout: HTTPWriteTimeout,
+			}
+			if err := metricsServerWithTimeout.ListenAndServe(); err != nil {
+				logger.Fatal("ListenAndServe failed (for metrics):", err)
+				return
+			}
+			logger.Fatal("ListenAndServe ended prematurely (for metrics).")
+		}()
+	}
+}
+
+func countRunningTimedGames() int {
+	// Make a copy of the tables so that we do not hold the tables lock while acquiring the lock
+	// for each individual table
+	tableList := make([]*Table, 0)
+	tablesMutex.RLock()
+	for _, t := range tables {
+		tableList = append(tableList, t)
+	}
+	tablesMutex.RUnlock()
+
+	numGames := 0
+	for _, t := range tableList {
+		t.Mutex.Lock()
+		if !t.Deleted && t.Running && !t.Replay && t.Options.Timed && !t.Game.Paused {
+			numGames++
+		}
+		t.Mutex.Unlock()
+	}
+
+	return numGames
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetrics(t *testing.T) {
+	resetTestTables(t)
+
+	newTestGame(t, 2)
+	newTestTimedGame(t, 2)
+	paused := newTestTimedGame(t, 2)
+	paused.Game.Paused = true
+	replay := newTestGame(t, 2)
+	replay.Replay = true
+	newTestTable(t, 2)
+
+	s := newTestSession(10, "Alice")
+	sessionsMutex.Lock()
+	sessions[s.UserID()] = s
+	sessionsMutex.Unlock()
+
+	w := httptest.NewRecorder()
+	promhttp.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected a status code of %v, but got %v", http.StatusOK, w.Code)
+	}
+
+	body := w.Body.String()
+	for _, line := range []string{
+		"hanabi_websocket_sessions 1",
+		"hanabi_running_tables 3",
+		"hanabi_replays 1",
+		"hanabi_timed_games_running 1",
+	} {
+		if !strings.Contains(body, "\n"+line+"\n") {
+			t.Errorf("the metrics do not contain \"%v\"", line)
+		}
+	}
+}
+
+func TestMetricsCommandsProcessed(t *testing.T) {
+	resetTestTables(t)
+
+	s := newTestSession(10, "Alice")
+	before := testutil.ToFloat64(metricsCommandsProcessed)
+	websocketMessage(s.Session, []byte(`tableUnattend {"tableID":999}`))
+	websocketMessage(s.Session, []byte(`notACommand {}`))
+
+	// Invalid commands are not counted
+	if after := testutil.ToFloat64(metricsCommandsProcessed); after != before+1 {
+		t.Errorf("expected the counter to increase by 1, but it went from %v to %v", before, after)
+	}
+}
@@ -48,6 +48,8 @@ func notifyAllTableGone(t *Table) {
 		s.NotifyTableGone(t)
 	}
 	sessionsMutex.RUnlock()
+
+	notifyLobbySubscribers(LobbyUpdateRemoved, t)
 }
 
 func notifyAllShutdown() {
@@ -58,6 +60,18 @@ func notifyAllShutdown() {
 	sessionsMutex.RUnlock()
 }
 
+// notifyAllAnnouncement returns the number of users that the announcement was sent to
+func notifyAllAnnouncement(msg string, countdown int) int {
+	sessionsMutex.RLock()
+	for _, s := range sessions {
+		s.NotifyAnnouncement(msg, countdown)
+	}
+	numRecipients := len(sessions)
+	sessionsMutex.RUnlock()
+
+	return numRecipients
+}
+
 func notifyAllMaintenance() {
 	sessionsMutex.RLock()
 	for _, s := range sessions {
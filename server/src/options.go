@@ -20,10 +20,35 @@ type Options struct {
 	OneLessCard           bool   `json:"oneLessCard"`
 	AllOrNothing          bool   `json:"allOrNothing"`
 	DetrimentalCharacters bool   `json:"detrimentalCharacters"`
+	// TimeoutAction is what happens when a player runs out of time in a timed game
+	// (see "game_timeout.go")
+	// It is not stored in the database, since the discards that it causes are recorded as normal
+	// actions
+	TimeoutAction string `json:"timeoutAction"`
+	// NoColorClues and NoRankClues prevent a type of clue from being given in any variant
+	// (e.g. for custom setups)
+	// They are not stored in the database, since a replay only has the clues that were given
+	NoColorClues bool `json:"noColorClues"`
+	NoRankClues  bool `json:"noRankClues"`
+}
+
+// IsClueTypeAllowed returns false if the table was created without the given type of clue
+// (the variant can also prevent a type of clue from being given; see "Variant.IsClueTypeAllowed()")
+func (o *Options) IsClueTypeAllowed(clueType int) bool {
+	if clueType == ClueTypeColor && o.NoColorClues {
+		return false
+	}
+	if clueType == ClueTypeRank && o.NoRankClues {
+		return false
+	}
+
+	return true
 }
 
 // ExtraOptions are extra specifications for the game; they are not recorded in the database
 // Similar to Options, a pointer to ExtraOptions is copied into the Game struct for convenience
+// Every field is needed to write a game to the database once it ends, so none of them can be
+// skipped when a table is serialized (see "validateDatabaseFields()")
 type ExtraOptions struct {
 	// -1 if an ongoing game, 0 if a JSON replay,
 	// a positive number if a database replay (or a "!replay" table)
@@ -65,6 +90,66 @@ type OptionsJSON struct {
 	DetrimentalCharacters *bool   `json:"detrimentalCharacters,omitempty"`
 }
 
+// GetOptionsJSON converts the options to the format used in game exports
+// (we want the pointers to remain nil if the option is the default value
+// so that they are not added to the JSON object)
+// It returns nil if all of the options are the default values
+func (o *Options) GetOptionsJSON() *OptionsJSON {
+	optionsJSON := &OptionsJSON{}
+	allDefaultOptions := true
+	if o.StartingPlayer != 0 {
+		optionsJSON.StartingPlayer = &o.StartingPlayer
+		allDefaultOptions = false
+	}
+	if o.VariantName != "No Variant" {
+		optionsJSON.Variant = &o.VariantName
+		allDefaultOptions = false
+	}
+	if o.Timed {
+		optionsJSON.Timed = &o.Timed
+		optionsJSON.TimeBase = &o.TimeBase
+		optionsJSON.TimePerTurn = &o.TimePerTurn
+		allDefaultOptions = false
+	}
+	if o.Speedrun {
+		optionsJSON.Speedrun = &o.Speedrun
+		allDefaultOptions = false
+	}
+	if o.CardCycle {
+		optionsJSON.CardCycle = &o.CardCycle
+		allDefaultOptions = false
+	}
+	if o.DeckPlays {
+		optionsJSON.DeckPlays = &o.DeckPlays
+		allDefaultOptions = false
+	}
+	if o.EmptyClues {
+		optionsJSON.EmptyClues = &o.EmptyClues
+		allDefaultOptions = false
+	}
+	if o.OneExtraCard {
+		optionsJSON.OneExtraCard = &o.OneExtraCard
+		allDefaultOptions = false
+	}
+	if o.OneLessCard {
+		optionsJSON.OneLessCard = &o.OneLessCard
+		allDefaultOptions = false
+	}
+	if o.AllOrNothing {
+		optionsJSON.AllOrNothing = &o.AllOrNothing
+		allDefaultOptions = false
+	}
+	if o.DetrimentalCharacters {
+		optionsJSON.DetrimentalCharacters = &o.DetrimentalCharacters
+		allDefaultOptions = false
+	}
+	if allDefaultOptions {
+		return nil
+	}
+
+	return optionsJSON
+}
+
 // GetModifier computes the integer modifier for the game options,
 // corresponding to the "ScoreModifier" constants in "constants.go"
 func (o *Options) GetModifier() Bitmask {
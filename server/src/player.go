@@ -18,6 +18,10 @@ type Player struct {
 	Stats     PregameStats
 	Typing    bool
 	LastTyped time.Time
+	// Set with the "tableSetReady" command before the game starts
+	Ready bool
+	// The time that "Present" was last set to false (used by the "tableAbsentPlayers" command)
+	DatetimeAbsent time.Time
 }
 type PregameStats struct {
 	NumGames int           `json:"numGames"`
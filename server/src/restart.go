@@ -2,8 +2,54 @@ package main
 
 import (
 	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/tevino/abool"
+)
+
+var (
+	// Set when a restart has been scheduled with "restartCountdown()"
+	restartingSoon  = abool.New()
+	datetimeRestart time.Time
 )
 
+// restartCountdown schedules a graceful restart after the given amount of seconds
+// In the meantime, ongoing games can continue (they will be saved to disk),
+// but no new games can be started
+func restartCountdown(seconds int) {
+	restartingSoon.Set()
+	datetimeRestart = time.Now().Add(time.Duration(seconds) * time.Second)
+
+	logger.Info("Scheduling a server restart in " + strconv.Itoa(seconds) + " seconds.")
+	msg := "The server will restart in " + strconv.Itoa(seconds) + " seconds. " +
+		"Ongoing games will be saved, but no new games can be started in the meantime."
+	notifyAllAnnouncement(msg, seconds)
+	chatServerSendAll(msg)
+
+	go restartCountdownWait(datetimeRestart)
+}
+
+func restartCountdownWait(datetimeScheduled time.Time) {
+	time.Sleep(time.Until(datetimeScheduled))
+
+	// Do nothing if the restart was canceled (or canceled and then rescheduled)
+	if restartingSoon.IsNotSet() || !datetimeRestart.Equal(datetimeScheduled) {
+		logger.Info("The scheduled restart was aborted.")
+		return
+	}
+
+	restart()
+}
+
+func restartSecondsLeft() int {
+	secondsLeft := int(time.Until(datetimeRestart).Seconds())
+	if secondsLeft < 0 {
+		return 0
+	}
+	return secondsLeft
+}
+
 // We want to record all of the ongoing games to a flat file on the disk
 // This allows the server to restart without waiting for ongoing games to finish
 func restart() {
@@ -25,14 +71,7 @@ func restart() {
 		}
 	}
 
-	waitForAllWebSocketCommandsToFinish()
-
-	logger.Info("Serializing the tables and writing all tables to disk...")
-	if !serializeTables() {
-		return
-	}
-	logger.Info("Finished writing all tables to disk.")
-
+	// Notify everyone before we close all of the WebSocket connections
 	sessionsMutex.RLock()
 	for _, s := range sessions {
 		// The sound has to be before the error, since the latter will cause a disconnect
@@ -47,6 +86,20 @@ func restart() {
 	msg += "(" + gitCommitOnStart + ")"
 	chatServerSend(msg, "lobby")
 
+	// Ensure that no commands are halfway finished before we write the game state to disk
+	websocketShutdown()
+
+	logger.Info("Serializing the tables and writing all tables to disk...")
+	if !serializeTables() {
+		// Everyone has already been disconnected and the WebSocket server cannot be reopened,
+		// so there is nothing left to do except to exit without restarting
+		// (the tables that failed to save are kept from the previous serialization)
+		logger.Fatal("Failed to write all of the tables to disk, so the server will not be " +
+			"restarted. Check the logs above for the tables that were not saved.")
+		return
+	}
+	logger.Info("Finished writing all tables to disk.")
+
 	if runtime.GOOS != "windows" {
 		logger.Info("Restarting...")
 		if err := executeScript("restart_service_only.sh"); err != nil {
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRestartCountdown(t *testing.T) {
+	resetTestTables(t)
+
+	game := newTestGame(t, 2)
+	s, conn := newTestWebsocket(t, 3, "Cathy", 0)
+
+	// "restartCountdown()" announces the restart in the lobby (which requires a database),
+	// so we schedule the restart manually
+	restartingSoon.Set()
+	datetimeRestart = time.Now().Add(time.Hour)
+	t.Cleanup(restartingSoon.UnSet)
+
+	// New tables cannot be created
+	commandTableCreate(s, &CommandData{ // Manual invocation
+		Name: "New Table",
+		Options: &Options{
+			VariantName: "No Variant",
+		},
+	})
+	expectTestWarning(t, conn, "The server is restarting in")
+	if len(tables) != 1 {
+		t.Errorf("expected only the running game to exist, but there are %v tables", len(tables))
+	}
+
+	// But games that are already running can continue
+	clueTestPlayer(t, game)
+	if game.Game.Turn != 1 {
+		t.Errorf("expected the running game to be on turn 1, but it is on turn %v", game.Game.Turn)
+	}
+}
+
+func TestHttpLocalhostRestartCountdownAlreadyRestarting(t *testing.T) {
+	restartingSoon.Set()
+	t.Cleanup(restartingSoon.UnSet)
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/restartCountdown?seconds=60", nil)
+	httpLocalhostRestartCountdown(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected a status code of %v, but got %v", http.StatusBadRequest, w.Code)
+	}
+}
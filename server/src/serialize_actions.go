@@ -0,0 +1,190 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// Marshaling an entire table is expensive for long games, so it is only done every so often
+// (see "SERIALIZE_BASE_INTERVAL")
+// In between, each action is appended to the action log of the table
+// When the table is restored, the actions in the log are performed again on top of the last full
+// serialization
+
+// LoggedAction is one entry of the action log
+type LoggedAction struct {
+	// The index of the action in "Game.Actions2"
+	// (entries that are already part of the full serialization are skipped when restoring)
+	Index  int
+	Action *GameAction
+	// The clocks cannot be recomputed when the action is performed again,
+	// so we store their values from after the action
+	PlayerTimes       []time.Duration
+	DatetimeTurnBegin time.Time
+}
+
+// appendToActionLog records the most recent action of a table
+// The table mutex must be held when calling this function
+func appendToActionLog(t *Table) {
+	// Local variables
+	g := t.Game
+
+	// Without a full serialization, there would be nothing to perform the actions on top of
+	if t.DatetimeBaseSerialized.IsZero() {
+		return
+	}
+
+	actionLog, ok := tableStore.(TableActionLog)
+	if !ok {
+		return
+	}
+
+	loggedAction := &LoggedAction{
+		Index:             len(g.Actions2) - 1,
+		Action:            g.Actions2[len(g.Actions2)-1],
+		PlayerTimes:       make([]time.Duration, 0, len(g.Players)),
+		DatetimeTurnBegin: g.DatetimeTurnBegin,
+	}
+	for _, gp := range g.Players {
+		loggedAction.PlayerTimes = append(loggedAction.PlayerTimes, gp.Time)
+	}
+
+	entry, err := json.Marshal(loggedAction)
+	if err == nil {
+		err = actionLog.AppendAction(t.ID, entry)
+	}
+	if err != nil {
+		// Any later entries would leave a gap in the log, so we stop appending to it until the
+		// next full serialization (which will then happen on the next interval)
+		logger.Error("Failed to append to the action log of table "+
+			strconv.FormatUint(t.ID, 10)+":", err)
+		t.DatetimeBaseSerialized = time.Time{}
+	}
+}
+
+// truncateActionLog removes the entries of the action log that are already part of the full
+// serialization of a table
+// The table mutex must be held when calling this function
+func truncateActionLog(store TableStore, tableID uint64, numActions int) error {
+	actionLog, ok := store.(TableActionLog)
+	if !ok {
+		return nil
+	}
+
+	var entries [][]byte
+	if v, err := actionLog.LoadActions(tableID); err != nil {
+		return err
+	} else {
+		entries = v
+	}
+
+	// Actions that were taken after the table was marshaled must be kept
+	remainingEntries := make([][]byte, 0)
+	for _, entry := range entries {
+		var loggedAction LoggedAction
+		if err := json.Unmarshal(entry, &loggedAction); err != nil {
+			continue
+		}
+		if loggedAction.Index >= numActions {
+			remainingEntries = append(remainingEntries, entry)
+		}
+	}
+	if len(remainingEntries) == len(entries) {
+		return nil
+	}
+
+	return actionLog.ReplaceActions(tableID, remainingEntries)
+}
+
+// restoreActionLog performs the actions from the action log of a table that was just loaded
+// It does not modify any global state, so it can be called from the "loadTable()" function
+func restoreActionLog(store TableStore, tableID uint64, t *Table) error {
+	// Local variables
+	g := t.Game
+
+	actionLog, ok := store.(TableActionLog)
+	if !ok {
+		return nil
+	}
+
+	var entries [][]byte
+	if v, err := actionLog.LoadActions(tableID); err != nil {
+		return err
+	} else {
+		entries = v
+	}
+
+	// There are no connected players yet, so they must not be sent any messages
+	for _, p := range t.Players {
+		p.Present = false
+	}
+
+	t.RestoringActions = true
+	defer func() {
+		t.RestoringActions = false
+	}()
+
+	numRestored := 0
+	for i, entry := range entries {
+		// If the server crashed while an entry was being written, the final entry may be
+		// incomplete; it is still better to restore the table as of the previous action
+		var loggedAction LoggedAction
+		if err := json.Unmarshal(entry, &loggedAction); err != nil || loggedAction.Action == nil {
+			logger.Warning("Ignoring the rest of the action log of table " +
+				strconv.FormatUint(t.ID, 10) + " because entry " + strconv.Itoa(i) +
+				" is corrupted.")
+			break
+		}
+
+		// Skip the actions that are already part of the full serialization
+		if loggedAction.Index < len(g.Actions2) {
+			continue
+		}
+		if loggedAction.Index > len(g.Actions2) {
+			logger.Warning("Ignoring the rest of the action log of table " +
+				strconv.FormatUint(t.ID, 10) + " because it is missing the action at index " +
+				strconv.Itoa(len(g.Actions2)) + ".")
+			break
+		}
+
+		// Validate that the game is still in the same state as when the action was taken
+		if g.EndCondition != EndConditionInProgress {
+			return errors.New("action " + strconv.Itoa(loggedAction.Index) + " was taken " +
+				"after the game ended")
+		}
+		if len(loggedAction.PlayerTimes) != len(g.Players) {
+			return errors.New("action " + strconv.Itoa(loggedAction.Index) + " has " +
+				strconv.Itoa(len(loggedAction.PlayerTimes)) + " player times, but the game has " +
+				strconv.Itoa(len(g.Players)) + " players")
+		}
+
+		gp := g.Players[g.ActivePlayerIndex]
+		action(nil, &CommandData{ // Manual invocation
+			TableID: t.ID,
+			Type:    loggedAction.Action.Type,
+			Target:  loggedAction.Action.Target,
+			Value:   loggedAction.Action.Value,
+			NoLock:  true,
+		}, t, gp)
+		// (an invalid action does not add anything to "Game.Actions2")
+		if len(g.Actions2) != loggedAction.Index+1 {
+			return errors.New("action " + strconv.Itoa(loggedAction.Index) + " could not be " +
+				"performed again")
+		}
+
+		for j, gp2 := range g.Players {
+			gp2.Time = loggedAction.PlayerTimes[j]
+		}
+		g.DatetimeTurnBegin = loggedAction.DatetimeTurnBegin
+		numRestored++
+	}
+
+	if numRestored > 0 {
+		logger.Info("Restored " + strconv.Itoa(numRestored) + " action(s) from the action log " +
+			"of table " + strconv.FormatUint(t.ID, 10) + ".")
+	}
+
+	return nil
+}
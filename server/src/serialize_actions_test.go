@@ -0,0 +1,200 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// restoreTestTableFrom restores the tables from the given store and returns the given table
+func restoreTestTableFrom(t *testing.T, store TableStore, tableID uint64) *Table {
+	tableStore = store
+	resetTestTables(t)
+	restoreTables()
+
+	return getTestTable(t, tableID)
+}
+
+// marshalTestRestoredGame returns the state of a restored game as JSON
+// (aside from the time that it was serialized at)
+func marshalTestRestoredGame(t *testing.T, tb *Table) string {
+	g := tb.Game
+	g.DatetimeSerialized = time.Time{}
+	data, err := json.Marshal(g)
+	if err != nil {
+		t.Fatal("failed to marshal the game:", err)
+	}
+
+	return string(data)
+}
+
+func loadTestActionLog(t *testing.T, store *FileTableStore, tableID uint64) [][]byte {
+	entries, err := store.LoadActions(tableID)
+	if err != nil {
+		t.Fatal("failed to load the action log:", err)
+	}
+
+	return entries
+}
+
+func TestActionLogRestoresSameStateAsFullSerialization(t *testing.T) {
+	resetTestTables(t)
+	baseStore := useTestTableStore(t, false)
+	tb := newTestGame(t, 3)
+	clueTestPlayer(t, tb)
+	if !serializeTables() {
+		t.Fatal("failed to serialize the tables")
+	}
+
+	// The actions after the full serialization only go to the action log
+	baseData, err := baseStore.Load(tb.ID)
+	if err != nil {
+		t.Fatal("failed to load the table:", err)
+	}
+	discardTestCard(t, tb)
+	clueTestPlayer(t, tb)
+	discardTestCard(t, tb)
+	if v := loadTestActionLog(t, baseStore, tb.ID); len(v) != 3 {
+		t.Fatalf("expected 3 entries in the action log, but got %v", len(v))
+	}
+	if data, err := baseStore.Load(tb.ID); err != nil {
+		t.Fatal("failed to load the table:", err)
+	} else if string(data) != string(baseData) {
+		t.Fatal("the table was written in full after an action")
+	}
+
+	// Write the same state in full to a separate store
+	fullStore := NewFileTableStore(newTestDir(t), false)
+	tableStore = fullStore
+	if !serializeTables() {
+		t.Fatal("failed to serialize the tables")
+	}
+
+	incremental := restoreTestTableFrom(t, baseStore, tb.ID)
+	full := restoreTestTableFrom(t, fullStore, tb.ID)
+	if len(incremental.Game.Actions2) != len(tb.Game.Actions2) {
+		t.Fatalf("expected %v actions to be restored, but got %v", len(tb.Game.Actions2),
+			len(incremental.Game.Actions2))
+	}
+	v1 := marshalTestRestoredGame(t, incremental)
+	if v2 := marshalTestRestoredGame(t, full); v1 != v2 {
+		t.Errorf("the base serialization and the action log restored a different state than "+
+			"a full serialization:\n%v\n%v", v1, v2)
+	}
+
+	// The restored cards in the hands are the same as the ones in the deck
+	for _, gp := range incremental.Game.Players {
+		for _, c := range gp.Hand {
+			if c != incremental.Game.Deck[c.Order] {
+				t.Errorf("card %v in the hand of %v is not the card from the deck", c.Order,
+					gp.Name)
+			}
+		}
+	}
+}
+
+func TestSerializeTablesTruncatesActionLog(t *testing.T) {
+	resetTestTables(t)
+	store := useTestTableStore(t, false)
+	tb := newTestGame(t, 2)
+
+	// There is nothing to append the actions to before the first full serialization
+	clueTestPlayer(t, tb)
+	if v := loadTestActionLog(t, store, tb.ID); len(v) != 0 {
+		t.Fatalf("expected the action log to be empty, but it has %v entries", len(v))
+	}
+
+	if !serializeTables() {
+		t.Fatal("failed to serialize the tables")
+	}
+	discardTestCard(t, tb)
+	clueTestPlayer(t, tb)
+
+	// Tables that were written recently are skipped by the periodic serialization
+	if !serializeStaleTables(time.Hour) {
+		t.Fatal("failed to serialize the tables")
+	}
+	if v := loadTestActionLog(t, store, tb.ID); len(v) != 2 {
+		t.Fatalf("expected 2 entries in the action log, but got %v", len(v))
+	}
+
+	// A full serialization makes the entries redundant
+	if !serializeStaleTables(0) {
+		t.Fatal("failed to serialize the tables")
+	}
+	if v := loadTestActionLog(t, store, tb.ID); len(v) != 0 {
+		t.Errorf("expected the action log to be truncated, but it has %v entries", len(v))
+	}
+	actionLogPath := path.Join(store.Path, store.getActionLogFilename(tb.ID))
+	if _, err := os.Stat(actionLogPath); !os.IsNotExist(err) {
+		t.Error("the empty action log was not removed")
+	}
+}
+
+func TestRestoreActionLogIgnoresCorruptEntries(t *testing.T) {
+	resetTestTables(t)
+	store := useTestTableStore(t, false)
+	tb := newTestGame(t, 2)
+	if !serializeTables() {
+		t.Fatal("failed to serialize the tables")
+	}
+	clueTestPlayer(t, tb)
+	discardTestCard(t, tb)
+	turn := tb.Game.Turn
+
+	// A crash while an entry is being written leaves an incomplete entry at the end of the log
+	if err := store.AppendAction(tb.ID, []byte(`{"Index":2,"Act`)); err != nil {
+		t.Fatal("failed to append to the action log:", err)
+	}
+
+	restored := restoreTestTableFrom(t, store, tb.ID)
+	if restored.Game.Turn != turn || len(restored.Game.Actions2) != 2 {
+		t.Errorf("expected to restore 2 actions up to turn %v, but restored %v actions up to "+
+			"turn %v", turn, len(restored.Game.Actions2), restored.Game.Turn)
+	}
+}
+
+func TestFileTableStoreActionLog(t *testing.T) {
+	store := NewFileTableStore(newTestDir(t), false)
+	if _, err := store.Save(1, []byte("{}")); err != nil {
+		t.Fatal("failed to save the table:", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := store.AppendAction(1, []byte(`{"Index":`+strconv.Itoa(i)+`}`)); err != nil {
+			t.Fatal("failed to append to the action log:", err)
+		}
+	}
+	entries := loadTestActionLog(t, store, 1)
+	if len(entries) != 3 || string(entries[2]) != `{"Index":2}` {
+		t.Fatalf("the entries were not loaded in order: %q", entries)
+	}
+
+	// The action log is not listed as a separate table
+	if ids, err := store.List(); err != nil {
+		t.Fatal("failed to list the tables:", err)
+	} else if len(ids) != 1 || ids[0] != 1 {
+		t.Errorf("expected only table 1 to be listed, but got %v", ids)
+	}
+
+	if err := store.ReplaceActions(1, entries[1:]); err != nil {
+		t.Fatal("failed to replace the action log:", err)
+	}
+	if v := loadTestActionLog(t, store, 1); len(v) != 2 || string(v[0]) != `{"Index":1}` {
+		t.Errorf("the action log was not replaced: %q", v)
+	}
+
+	// Deleting the table also deletes its action log
+	if err := store.Delete(1); err != nil {
+		t.Fatal("failed to delete the table:", err)
+	}
+	if files, err := ioutil.ReadDir(store.Path); err != nil {
+		t.Fatal("failed to read the directory:", err)
+	} else if len(files) != 0 {
+		t.Errorf("expected the directory to be empty, but it has %v files", len(files))
+	}
+}
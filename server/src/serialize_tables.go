@@ -1,218 +1,728 @@
 package main
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
 	"encoding/json"
+	"errors"
 	"io/ioutil"
-	"log"
 	"os"
-	"path"
+	"sort"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/mitchellh/mapstructure"
+	"github.com/tevino/abool"
 )
 
-// serializeTables saves any ongoing tables to disk as JSON files so that they can be restored later
+const (
+	// By default, ongoing tables are periodically written to disk every 5 minutes so that they
+	// can be recovered if the server crashes
+	DefaultSerializeTablesInterval = 5 // In minutes
+
+	// By default, the periodic serialization only writes a table in full if it has not been
+	// written in the last 30 minutes (its actions are appended to its action log in the meantime)
+	DefaultSerializeBaseInterval = 30 // In minutes
+
+	// By default, only the 200 most recent chat messages of each table are saved
+	DefaultSerializeChatLimit = 200
+
+	// By default, writing a table file is retried 3 times (waiting 100 milliseconds before the
+	// first retry and doubling the wait each time after that)
+	DefaultSerializeWriteRetries    = 3
+	DefaultSerializeWriteRetryDelay = 100 // In milliseconds
+
+	// By default, a table is skipped if its lock cannot be acquired within 10 seconds
+	DefaultSerializeLockTimeout = 10 // In seconds
+
+	// By default, the active player in a restored timed game gets 20 extra seconds
+	DefaultRestoreGraceSeconds = 20
+
+	// By default, 4 table files are read at the same time when restoring tables
+	DefaultRestoreWorkers = 4
+
+	// This must be incremented whenever the serialized format of a table changes in a way that
+	// older versions of the server cannot read (along with a new case in "migrateTable()")
+	// Files written before versioning was introduced are treated as version 0
+	TableSchemaVersion = 1
+)
+
+// SerializedTable is the envelope that wraps a table when it is written to disk
+type SerializedTable struct {
+	SchemaVersion int
+	Table         json.RawMessage
+}
+
+var (
+	// Whether or not to write the serialized tables as gzipped files
+	// (gzipped files are always restored, regardless of this setting)
+	serializeCompress bool
+
+	// Whether or not to indent the JSON of the serialized tables so that they are easier to read
+	serializePretty bool
+
+	// The maximum number of chat messages saved for each table (0 means that there is no limit)
+	serializeChatLimit int
+
+	// How many times to retry writing a table file after a failure (e.g. a transient disk error)
+	serializeWriteRetries    int
+	serializeWriteRetryDelay time.Duration
+
+	// How long to wait for the lock of a table before giving up on serializing it
+	// (so that a table that is stuck cannot prevent every other table from being saved)
+	serializeLockTimeout time.Duration
+
+	// How often the ongoing tables are periodically written to disk
+	// (0 means that the periodic serialization is disabled)
+	serializeTablesInterval time.Duration
+
+	// How often the periodic serialization writes each table in full (see "serialize_actions.go")
+	serializeBaseInterval time.Duration
+
+	// Restoring the tables more than once would start duplicate timer and idle goroutines
+	restoreTablesCalled = abool.New()
+)
+
+// serializeTablesInit starts a goroutine that periodically saves all of the ongoing tables to disk
+// (in addition to when the server is restarted)
+func serializeTablesInit() {
+	serializeCompress = os.Getenv("SERIALIZE_COMPRESS") == "true"
+	tableStore = NewFileTableStore(tablesPath, serializeCompress)
+	serializePretty = os.Getenv("SERIALIZE_PRETTY") == "true"
+
+	serializeChatLimit = DefaultSerializeChatLimit
+	chatLimitString := os.Getenv("SERIALIZE_CHAT_LIMIT")
+	if len(chatLimitString) != 0 {
+		if v, err := strconv.Atoi(chatLimitString); err != nil {
+			logger.Fatal("Failed to convert the \"SERIALIZE_CHAT_LIMIT\" " +
+				"environment variable to a number.")
+			return
+		} else {
+			serializeChatLimit = v
+		}
+	}
+	if serializeChatLimit < 0 {
+		logger.Fatal("The \"SERIALIZE_CHAT_LIMIT\" environment variable cannot be negative.")
+		return
+	}
+
+	serializeWriteRetries = DefaultSerializeWriteRetries
+	writeRetriesString := os.Getenv("SERIALIZE_WRITE_RETRIES")
+	if len(writeRetriesString) != 0 {
+		if v, err := strconv.Atoi(writeRetriesString); err != nil {
+			logger.Fatal("Failed to convert the \"SERIALIZE_WRITE_RETRIES\" " +
+				"environment variable to a number.")
+			return
+		} else {
+			serializeWriteRetries = v
+		}
+	}
+	if serializeWriteRetries < 0 {
+		logger.Fatal("The \"SERIALIZE_WRITE_RETRIES\" environment variable cannot be negative.")
+		return
+	}
+
+	writeRetryDelayMilliseconds := DefaultSerializeWriteRetryDelay
+	writeRetryDelayString := os.Getenv("SERIALIZE_WRITE_RETRY_DELAY")
+	if len(writeRetryDelayString) != 0 {
+		if v, err := strconv.Atoi(writeRetryDelayString); err != nil {
+			logger.Fatal("Failed to convert the \"SERIALIZE_WRITE_RETRY_DELAY\" " +
+				"environment variable to a number.")
+			return
+		} else {
+			writeRetryDelayMilliseconds = v
+		}
+	}
+	if writeRetryDelayMilliseconds < 0 {
+		logger.Fatal("The \"SERIALIZE_WRITE_RETRY_DELAY\" environment variable cannot be " +
+			"negative.")
+		return
+	}
+	serializeWriteRetryDelay = time.Duration(writeRetryDelayMilliseconds) * time.Millisecond
+
+	lockTimeoutSeconds := DefaultSerializeLockTimeout
+	lockTimeoutString := os.Getenv("SERIALIZE_LOCK_TIMEOUT")
+	if len(lockTimeoutString) != 0 {
+		if v, err := strconv.Atoi(lockTimeoutString); err != nil {
+			logger.Fatal("Failed to convert the \"SERIALIZE_LOCK_TIMEOUT\" " +
+				"environment variable to a number.")
+			return
+		} else {
+			lockTimeoutSeconds = v
+		}
+	}
+	if lockTimeoutSeconds <= 0 {
+		logger.Fatal("The \"SERIALIZE_LOCK_TIMEOUT\" environment variable must be positive.")
+		return
+	}
+	serializeLockTimeout = time.Duration(lockTimeoutSeconds) * time.Second
+
+	baseIntervalMinutes := DefaultSerializeBaseInterval
+	baseIntervalString := os.Getenv("SERIALIZE_BASE_INTERVAL")
+	if len(baseIntervalString) != 0 {
+		if v, err := strconv.Atoi(baseIntervalString); err != nil {
+			logger.Fatal("Failed to convert the \"SERIALIZE_BASE_INTERVAL\" " +
+				"environment variable to a number.")
+			return
+		} else {
+			baseIntervalMinutes = v
+		}
+	}
+	if baseIntervalMinutes < 0 {
+		logger.Fatal("The \"SERIALIZE_BASE_INTERVAL\" environment variable cannot be negative.")
+		return
+	}
+	serializeBaseInterval = time.Duration(baseIntervalMinutes) * time.Minute
+
+	intervalString := os.Getenv("SERIALIZE_TABLES_INTERVAL")
+	var intervalMinutes int
+	if len(intervalString) == 0 {
+		intervalMinutes = DefaultSerializeTablesInterval
+	} else {
+		if v, err := strconv.Atoi(intervalString); err != nil {
+			logger.Fatal("Failed to convert the \"SERIALIZE_TABLES_INTERVAL\" " +
+				"environment variable to a number.")
+			return
+		} else {
+			intervalMinutes = v
+		}
+	}
+
+	if intervalMinutes <= 0 {
+		serializeTablesInterval = 0
+		logger.Info("Periodic table serialization is disabled.")
+		return
+	}
+	serializeTablesInterval = time.Duration(intervalMinutes) * time.Minute
+
+	go serializeTablesLoop(serializeTablesInterval)
+}
+
+// serializeTablesLoop is meant to be run in a goroutine
+func serializeTablesLoop(interval time.Duration) {
+	for {
+		time.Sleep(interval)
+
+		// The tables are serialized one last time as part of the restart process,
+		// so we do not want to clobber those files
+		if blockAllIncomingMessages.IsSet() {
+			return
+		}
+
+		serializeStaleTables(serializeBaseInterval)
+	}
+}
+
+// serializeTables saves any ongoing tables to the table store so that they can be restored later
 func serializeTables() bool {
-	tablesMutex.RLock()
-	defer tablesMutex.RUnlock()
+	return serializeStaleTables(0)
+}
+
+// serializeStaleTables is the same as "serializeTables()", but it skips the tables that were
+// written in full more recently than the given duration
+// (the actions of those tables since then are already in their action logs)
+func serializeStaleTables(maxAge time.Duration) bool {
+	// Keep track of which tables we save so that we can clean up the tables that have ended since
+	// the last time we serialized
+	savedTableIDs := make(map[uint64]struct{})
+	allSucceeded := true
+	numTablesSaved := 0
+	numTablesSkipped := 0
+	totalBytes := 0
+	totalUncompressedBytes := 0
 
+	// Serialize the tables in order of their IDs so that the output is reproducible
+	// (the JSON encoder already sorts the keys of every map, including nested ones,
+	// so the same game state will always produce the same bytes)
+	// We make a copy of the list of tables so that we do not hold the tables lock while waiting
+	// for the lock of each table and while writing the files
+	// (otherwise, no tables could be created or deleted until the serialization is finished)
+	tablesMutex.RLock()
+	tableList := make([]*Table, 0, len(tables))
 	for _, t := range tables {
+		tableList = append(tableList, t)
+	}
+	tablesMutex.RUnlock()
+	sort.Slice(tableList, func(i, j int) bool {
+		return tableList[i].ID < tableList[j].ID
+	})
+
+	for _, t := range tableList {
+		// Every field that is serialized (including the nested maps and slices) is only ever
+		// modified while holding the table lock, so we hold it until the marshal is finished in
+		// order to get a consistent snapshot
+		// The resulting bytes are not shared with the table, so the lock is released before the
+		// file is written
+		// If a command is stuck while holding the lock, we skip the table instead of waiting
+		// forever (the file from the last serialization of the table is kept)
+		if !lockWithTimeout(&t.Mutex, serializeLockTimeout) {
+			logger.Warning("Skipping table " + strconv.FormatUint(t.ID, 10) + " because its " +
+				"lock could not be acquired within " +
+				strconv.Itoa(int(serializeLockTimeout.Seconds())) + " seconds.")
+			savedTableIDs[t.ID] = struct{}{}
+			numTablesSkipped++
+			continue
+		}
+
 		// Only serialize ongoing games
-		if !t.Running || t.Replay {
+		if !t.Running || t.Replay || t.Deleted {
+			t.Mutex.Unlock()
 			logger.Info("Skipping due to it being unstarted or a replay.")
 			continue
 		}
 
-		logger.Info("Serializing table:", t.ID)
+		// Even if this fails, we do not want to delete the table from the last serialization
+		// (since it is better to restore an old version of the table than none at all)
+		savedTableIDs[t.ID] = struct{}{}
+
+		if !t.DatetimeBaseSerialized.IsZero() && time.Since(t.DatetimeBaseSerialized) < maxAge {
+			t.Mutex.Unlock()
+			numTablesSkipped++
+			continue
+		}
+
+		logFields := LogFields{
+			"tableID": t.ID,
+		}
+		logger.InfoWithFields(logFields, "Serializing table.")
 
 		// Several fields on the Table object and the Game object are set with `json:"-"` to prevent
 		// the JSON encoder from serializing them
 		// Otherwise, we would have to explicitly unset some fields here to avoid circular
 		// references, session data, and so forth
-		t.Mutex.Lock()
-		var tableJSON []byte
-		if v, err := json.Marshal(t); err != nil {
-			logger.Error("Failed to marshal table "+strconv.FormatUint(t.ID, 10)+":", err)
-			return false
+		// Marshaling can fail if a value cannot be represented in JSON
+		// (e.g. a NaN float or a channel that was added to one of the actions)
+		// A problem with one table should not prevent the rest of the tables from being saved,
+		// so we skip it
+		tableJSON, err := marshalTable(t)
+		numActions := len(t.Game.Actions2)
+		if err == nil {
+			// Actions that are taken while the file is being written must go to the action log
+			t.DatetimeBaseSerialized = time.Now()
+		}
+		t.Mutex.Unlock()
+		if err != nil {
+			logger.ErrorWithFields(logFields, "Failed to marshal the table:", err)
+			allSucceeded = false
+			continue
+		}
+
+		if v, err := marshalSerializedTable(&SerializedTable{
+			SchemaVersion: TableSchemaVersion,
+			Table:         tableJSON,
+		}); err != nil {
+			logger.ErrorWithFields(logFields, "Failed to marshal the envelope for the table:", err)
+			allSucceeded = false
+			resetBaseSerialized(t)
+			continue
 		} else {
 			tableJSON = v
 		}
-		t.Mutex.Unlock()
 
-		tableFilename := strconv.FormatUint(t.ID, 10) + ".json"
-		tablePath := path.Join(tablesPath, tableFilename)
-		if err := ioutil.WriteFile(tablePath, tableJSON, 0600); err != nil {
-			logger.Error("Failed to write \""+tablePath+"\":", err)
-			return false
+		var numBytes int
+		if v, err := saveTable(t.ID, tableJSON); err != nil {
+			logger.ErrorWithFields(logFields, "Failed to save the table after "+
+				strconv.Itoa(serializeWriteRetries)+" retries:", err)
+			allSucceeded = false
+			resetBaseSerialized(t)
+			continue
+		} else {
+			numBytes = v
+		}
+
+		// The actions that are now part of the table file do not need to be in the action log
+		// anymore
+		// (we must hold the table lock so that an action is not appended in the meantime)
+		// The log is still valid if it is not truncated, since the entries from before the table
+		// was written are skipped when it is restored
+		if lockWithTimeout(&t.Mutex, serializeLockTimeout) {
+			if err := truncateActionLog(tableStore, t.ID, numActions); err != nil {
+				logger.ErrorWithFields(logFields, "Failed to truncate the action log:", err)
+			}
+			t.Mutex.Unlock()
+		} else {
+			logger.WarningWithFields(logFields, "Failed to acquire the lock to truncate the "+
+				"action log.")
+		}
+
+		// Report the size of each table so that disk usage can be planned for
+		logFields["bytes"] = numBytes
+		if serializeCompress {
+			logFields["uncompressedBytes"] = len(tableJSON)
+			logFields["compressionRatio"] = getCompressionRatio(numBytes, len(tableJSON))
+		}
+		logger.InfoWithFields(logFields, "Serialized table.")
+		numTablesSaved++
+		totalBytes += numBytes
+		totalUncompressedBytes += len(tableJSON)
+	}
+
+	removeStaleTables(savedTableIDs)
+
+	totalLogFields := LogFields{
+		"tables":  numTablesSaved,
+		"skipped": numTablesSkipped,
+		"bytes":   totalBytes,
+	}
+	if serializeCompress {
+		totalLogFields["uncompressedBytes"] = totalUncompressedBytes
+		totalLogFields["compressionRatio"] = getCompressionRatio(totalBytes, totalUncompressedBytes)
+	}
+	logger.InfoWithFields(totalLogFields, "Finished serializing the tables.")
+
+	return allSucceeded
+}
+
+// lockWithTimeout acquires a mutex and returns true,
+// or returns false if it could not be acquired before the timeout
+// A "Lock()" call cannot be canceled, so if we give up, the mutex is unlocked again as soon as the
+// pending call acquires it
+func lockWithTimeout(mutex *sync.Mutex, timeout time.Duration) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	locked := make(chan struct{})
+	go func() {
+		mutex.Lock()
+		select {
+		case locked <- struct{}{}:
+		case <-ctx.Done():
+			mutex.Unlock()
 		}
+	}()
+
+	select {
+	case <-locked:
+		return true
+	case <-ctx.Done():
+		return false
 	}
+}
 
-	return true
+// resetBaseSerialized makes the next periodic serialization write a table in full after the
+// current one failed
+// (it also stops actions from being appended to an action log that might not have a table file)
+func resetBaseSerialized(t *Table) {
+	t.Mutex.Lock()
+	t.DatetimeBaseSerialized = time.Time{}
+	t.Mutex.Unlock()
 }
 
-// restoreTables recreates tables that were ongoing at the time of the last server restart
-// Tables were serialized to flat files in the "tablesPath" directory
-func restoreTables() {
-	var files []os.FileInfo
-	if v, err := ioutil.ReadDir(tablesPath); err != nil {
-		log.Fatal("Failed to get the files in the \""+tablesPath+"\" directory: ", err)
+// saveTable writes a serialized table to the table store,
+// retrying with an exponential backoff if it fails
+// It returns the number of bytes that were stored
+func saveTable(tableID uint64, tableJSON []byte) (int, error) {
+	delay := serializeWriteRetryDelay
+
+	var err error
+	for i := 0; i <= serializeWriteRetries; i++ {
+		if i > 0 {
+			logger.Warning("Failed to save table "+strconv.FormatUint(tableID, 10)+
+				" (retrying in "+delay.String()+"):", err)
+			time.Sleep(delay)
+			delay *= 2
+		}
+
+		var numBytes int
+		if numBytes, err = tableStore.Save(tableID, tableJSON); err == nil {
+			return numBytes, nil
+		}
+	}
+
+	return 0, err
+}
+
+// getCompressionRatio returns the compressed size as a fraction of the uncompressed size
+// (e.g. "0.25" if the compressed data is a quarter of the size of the original data)
+func getCompressionRatio(compressedBytes int, uncompressedBytes int) string {
+	if uncompressedBytes == 0 {
+		return "0"
+	}
+
+	ratio := float64(compressedBytes) / float64(uncompressedBytes)
+	return strconv.FormatFloat(ratio, 'f', 2, 64)
+}
+
+// removeStaleTables deletes any tables from a previous serialization that are not ongoing anymore
+// (otherwise, a game that ended after a periodic snapshot would be restored on the next startup)
+func removeStaleTables(savedTableIDs map[uint64]struct{}) {
+	var tableIDs []uint64
+	if v, err := tableStore.List(); err != nil {
+		logger.Error("Failed to get the list of the saved tables:", err)
 		return
 	} else {
-		files = v
+		tableIDs = v
 	}
 
-	if len(files) == 0 {
-		logger.Info("No previously running tables to restore.")
+	for _, tableID := range tableIDs {
+		if _, ok := savedTableIDs[tableID]; ok {
+			continue
+		}
+
+		if err := tableStore.Delete(tableID); err != nil {
+			logger.Error("Failed to delete table "+strconv.FormatUint(tableID, 10)+":", err)
+		}
+	}
+}
+
+// marshalSerializedTable converts the envelope (and the table inside of it) to JSON
+// Indenting the JSON does not change how it is unmarshaled, so the tables are restored in the same
+// way regardless of the "SERIALIZE_PRETTY" setting
+func marshalSerializedTable(serializedTable *SerializedTable) ([]byte, error) {
+	if serializePretty {
+		return json.MarshalIndent(serializedTable, "", "  ")
+	}
+
+	return json.Marshal(serializedTable)
+}
+
+// marshalTable converts a table to JSON
+// The table mutex must be held when calling this function
+func marshalTable(t *Table) ([]byte, error) {
+	if t.Game != nil {
+		t.Game.DatetimeSerialized = time.Now()
+	}
+
+	// Spectators who are currently disconnected are included so that they are still put back into
+	// the game if they reconnect after the table is restored
+	t.SpectatorIDs = make([]int, 0, len(t.Spectators)+len(t.DisconSpectators))
+	for _, sp := range t.Spectators {
+		t.SpectatorIDs = append(t.SpectatorIDs, sp.ID)
+	}
+	// (they are sorted, since the order of a map is random and the output must be reproducible)
+	disconSpectatorIDs := make([]int, 0, len(t.DisconSpectators))
+	for id := range t.DisconSpectators {
+		if t.GetSpectatorIndexFromID(id) == -1 {
+			disconSpectatorIDs = append(disconSpectatorIDs, id)
+		}
+	}
+	sort.Ints(disconSpectatorIDs)
+	t.SpectatorIDs = append(t.SpectatorIDs, disconSpectatorIDs...)
+
+	// Only the most recent chat messages are saved so that the size of the file is bounded
+	// (the full chat history is put back after the table is marshaled)
+	if serializeChatLimit > 0 && len(t.Chat) > serializeChatLimit {
+		chat := t.Chat
+		chatRead := t.ChatRead
+		defer func() {
+			t.Chat = chat
+			t.ChatRead = chatRead
+		}()
+
+		numDropped := len(chat) - serializeChatLimit
+		t.Chat = chat[numDropped:]
+		t.ChatRead = make(map[int]int)
+		for userID, numRead := range chatRead {
+			numRead -= numDropped
+			if numRead < 0 {
+				numRead = 0
+			}
+			t.ChatRead[userID] = numRead
+		}
+	}
+
+	return json.Marshal(t)
+}
+
+// restoreTables recreates tables that were ongoing at the time of the last server restart
+// Tables were saved to the table store (see "table_store.go")
+func restoreTables() {
+	if !restoreTablesCalled.SetToIf(false, true) {
+		logger.Error("The \"restoreTables()\" function was called more than once; ignoring.")
 		return
 	}
 
-	for _, f := range files {
-		if f.Name() == ".gitignore" {
-			continue
+	// The active player in a timed game is given some extra time to make up for the fact that
+	// they are forced to refresh
+	graceSecondsString := os.Getenv("RESTORE_GRACE_SECONDS")
+	var graceSeconds int
+	if len(graceSecondsString) == 0 {
+		graceSeconds = DefaultRestoreGraceSeconds
+	} else {
+		if v, err := strconv.Atoi(graceSecondsString); err != nil {
+			logger.Fatal("Failed to convert the \"RESTORE_GRACE_SECONDS\" " +
+				"environment variable to a number.")
+			return
+		} else {
+			graceSeconds = v
 		}
+	}
+	if graceSeconds < 0 {
+		logger.Fatal("The \"RESTORE_GRACE_SECONDS\" environment variable cannot be negative.")
+		return
+	}
+	restoreGracePeriod := time.Duration(graceSeconds) * time.Second
+
+	// Some servers prefer that the clocks of restored games are exactly the same as they were
+	// before the restart
+	restoreTimeBonusEnabled := os.Getenv("RESTORE_TIME_BONUS_ENABLED") != "false"
 
-		tablePath := path.Join(tablesPath, f.Name())
-		var tableJSON []byte
-		if v, err := ioutil.ReadFile(tablePath); err != nil {
-			log.Fatal("Failed to read \""+tablePath+"\":", err)
+	restoreWorkers := DefaultRestoreWorkers
+	restoreWorkersString := os.Getenv("RESTORE_WORKERS")
+	if len(restoreWorkersString) != 0 {
+		if v, err := strconv.Atoi(restoreWorkersString); err != nil {
+			logger.Fatal("Failed to convert the \"RESTORE_WORKERS\" " +
+				"environment variable to a number.")
 			return
 		} else {
-			tableJSON = v
+			restoreWorkers = v
 		}
+	}
+	if restoreWorkers <= 0 {
+		logger.Fatal("The \"RESTORE_WORKERS\" environment variable must be positive.")
+		return
+	}
+
+	// In read-only mode, the table files are left untouched after they are restored
+	// This allows a server to be pointed at a backup directory
+	// (e.g. a copy of the production tables for a staging server) without modifying it
+	restorePath := tablesPath
+	readOnly := os.Getenv("TABLES_RESTORE_READONLY") == "true"
+	if readOnly {
+		if v := os.Getenv("TABLES_RESTORE_PATH"); len(v) != 0 {
+			restorePath = v
+		}
+		logger.Info("Restoring tables in read-only mode from: " + restorePath)
+	}
+
+	store := tableStore
+	if readOnly {
+		store = NewFileTableStore(restorePath, false)
+	}
 
-		t := &Table{} // We must initialize the table for "Unmarshal()" to work
-		if err := json.Unmarshal(tableJSON, t); err != nil {
-			logger.Fatal("Failed to unmarshal \""+tablePath+"\":", err)
+	var tableIDs []uint64
+	if v, err := store.List(); os.IsNotExist(err) {
+		// This is expected on a fresh deployment, so there is simply nothing to restore
+		// (we do not create the directory in read-only mode, since it may be a mistyped path)
+		if readOnly {
+			logger.Warning("The \"" + restorePath + "\" directory does not exist, " +
+				"so there are no tables to restore.")
 			return
 		}
-		t.Spectators = make([]*Spectator, 0)
-		t.KickedPlayers = make(map[int]struct{})
-		t.DisconSpectators = make(map[int]struct{})
-		if t.ChatRead == nil {
-			t.ChatRead = make(map[int]int)
+		logger.Info("The \"" + restorePath + "\" directory does not exist; creating it.")
+		if err2 := os.MkdirAll(restorePath, 0755); err2 != nil {
+			logger.Fatal("Failed to create the \""+restorePath+"\" directory:", err2)
 		}
+		return
+	} else if err != nil {
+		// Other errors (e.g. permission errors) mean that tables may be lost, so we must fail loudly
+		logger.Fatal("Failed to get the list of the saved tables:", err)
+		return
+	} else {
+		tableIDs = v
+	}
 
-		// Restore the circular references that could not be represented in JSON
-		g := t.Game
-		g.Table = t
-		g.Options = t.Options
-		g.ExtraOptions = t.ExtraOptions
-		for _, gp := range g.Players {
-			gp.Game = g
-		}
-
-		// Restore the types of the actions
-		for i, a := range g.Actions {
-			var action map[string]interface{}
-			if v, ok := a.(map[string]interface{}); !ok {
-				logger.Fatal("Failed to convert the action " + strconv.Itoa(i) + " of table " +
-					strconv.FormatUint(t.ID, 10) + " to a map.")
-			} else {
-				action = v
-			}
+	if len(tableIDs) == 0 {
+		logger.Info("No previously running tables to restore.")
+		return
+	}
 
-			actionType := action["type"].(string)
+	// Tables are restored (and their timers are started) in order of their IDs so that restores
+	// are deterministic
+	// (every store should already list them in this order,
+	// but we do not want to rely on the implementation of the store)
+	sort.Slice(tableIDs, func(i, j int) bool {
+		return tableIDs[i] < tableIDs[j]
+	})
 
-			if actionType == "cardIdentity" {
-				actionCardIdentity := ActionCardIdentity{}
-				if err := mapstructure.Decode(a, &actionCardIdentity); err != nil {
-					logger.Fatal("Failed to convert the action " + strconv.Itoa(i) + " of table " +
-						strconv.FormatUint(t.ID, 10) + " to a \"cardIdentity\" action.")
-				}
-				g.Actions[i] = actionCardIdentity
-			} else if actionType == "clue" {
-				actionClue := ActionClue{}
-				if err := mapstructure.Decode(a, &actionClue); err != nil {
-					logger.Fatal("Failed to convert the action " + strconv.Itoa(i) + " of table " +
-						strconv.FormatUint(t.ID, 10) + " to a \"clue\" action.")
-				}
-				g.Actions[i] = actionClue
-			} else if actionType == "discard" {
-				actionDiscard := ActionDiscard{}
-				if err := mapstructure.Decode(a, &actionDiscard); err != nil {
-					logger.Fatal("Failed to convert the action " + strconv.Itoa(i) + " of table " +
-						strconv.FormatUint(t.ID, 10) + " to a \"discard\" action.")
-				}
-				g.Actions[i] = actionDiscard
-			} else if actionType == "draw" {
-				actionDraw := ActionDraw{}
-				if err := mapstructure.Decode(a, &actionDraw); err != nil {
-					logger.Fatal("Failed to convert the action " + strconv.Itoa(i) + " of table " +
-						strconv.FormatUint(t.ID, 10) + " to a \"draw\" action.")
-				}
-				g.Actions[i] = actionDraw
-			} else if actionType == "gameOver" {
-				actionGameOver := ActionGameOver{}
-				if err := mapstructure.Decode(a, &actionGameOver); err != nil {
-					logger.Fatal("Failed to convert the action " + strconv.Itoa(i) + " of table " +
-						strconv.FormatUint(t.ID, 10) + " to a \"gameOver\" action.")
-				}
-				g.Actions[i] = actionGameOver
-			} else if actionType == "play" {
-				actionPlay := ActionPlay{}
-				if err := mapstructure.Decode(a, &actionPlay); err != nil {
-					logger.Fatal("Failed to convert the action " + strconv.Itoa(i) + " of table " +
-						strconv.FormatUint(t.ID, 10) + " to a \"play\" action.")
-				}
-				g.Actions[i] = actionPlay
-			} else if actionType == "playerTimes" {
-				actionDraw := ActionDraw{}
-				if err := mapstructure.Decode(a, &actionDraw); err != nil {
-					logger.Fatal("Failed to convert the action " + strconv.Itoa(i) + " of table " +
-						strconv.FormatUint(t.ID, 10) + " to a \"playerTimes\" action.")
-				}
-				g.Actions[i] = actionDraw
-			} else if actionType == "strike" {
-				actionStrike := ActionStrike{}
-				if err := mapstructure.Decode(a, &actionStrike); err != nil {
-					logger.Fatal("Failed to convert the action " + strconv.Itoa(i) + " of table " +
-						strconv.FormatUint(t.ID, 10) + " to a \"strike\" action.")
-				}
-				g.Actions[i] = actionStrike
-			} else if actionType == "status" {
-				actionStatus := ActionStatus{}
-				if err := mapstructure.Decode(a, &actionStatus); err != nil {
-					logger.Fatal("Failed to convert the action " + strconv.Itoa(i) + " of table " +
-						strconv.FormatUint(t.ID, 10) + " to a \"status\" action.")
-				}
-				g.Actions[i] = actionStatus
-			} else if actionType == "turn" {
-				actionTurn := ActionTurn{}
-				if err := mapstructure.Decode(a, &actionTurn); err != nil {
-					logger.Fatal("Failed to convert the action " + strconv.Itoa(i) + " of table " +
-						strconv.FormatUint(t.ID, 10) + " to a \"turn\" action.")
-				}
-				g.Actions[i] = actionTurn
-			} else {
-				logger.Fatal("Table " + strconv.FormatUint(t.ID, 10) +
-					" had an unknown action type of \"" + actionType + "\".")
+	// Reading and parsing the tables is the slowest part of the restore process,
+	// so it is done in parallel
+	// (everything else is still done one table at a time)
+	loadedTables := loadTables(store, tableIDs, restoreWorkers)
+
+	for i, tableID := range tableIDs {
+		logFields := LogFields{
+			"tableID": tableID,
+		}
+
+		var t *Table
+		if err := loadedTables[i].Err; err != nil {
+			logger.ErrorWithFields(logFields, "Failed to load the table:", err)
+			if !readOnly {
+				quarantineTable(store, tableID)
 			}
+			continue
+		} else {
+			t = loadedTables[i].Table
+		}
+		g := t.Game
+
+		if err := validateLoadedTable(store, t, tableID, logFields); err != nil {
+			logger.ErrorWithFields(logFields, "Failed to validate the table:", err)
+			if !readOnly {
+				quarantineTable(store, tableID)
+			}
+			continue
+		}
+
+		// Validate that this table does not already exist
+		// (otherwise, we would start a second set of timer and idle goroutines for it)
+		tablesMutex.RLock()
+		_, exists := tables[t.ID]
+		tablesMutex.RUnlock()
+		if exists {
+			logger.WarningWithFields(logFields, "Skipping the restore of the table because it "+
+				"already exists.")
+			continue
 		}
 
 		// Ensure that all of the players are not present
 		// (they were presumably present and connected when the table serialization happened)
 		for _, p := range t.Players {
 			p.Present = false
+			p.DatetimeAbsent = time.Now()
 		}
 
-		if g.Options.Timed {
-			// Give the current player some additional seconds to make up for the fact that they are
-			// forced to refresh
-			g.Players[g.ActivePlayerIndex].Time += 20 * time.Second
+		// If the game is paused, the clock is not running, so we do not have to do anything
+		// (the timer will be started by the unpause logic in the "commandPause()" function)
+		if g.Options.Timed && !g.Paused {
+			if restoreTimeBonusEnabled {
+				// Give the current player some additional seconds to make up for the fact that
+				// they are forced to refresh
+				g.Players[g.ActivePlayerIndex].Time += restoreGracePeriod
+			} else {
+				// The time that the server was down does not count against the current player
+				lastKnown := g.DatetimeSerialized
+				if g.DatetimeTurnBegin.After(lastKnown) {
+					lastKnown = g.DatetimeTurnBegin
+				}
+				g.DatetimeTurnBegin = g.DatetimeTurnBegin.Add(time.Since(lastKnown))
+			}
+		}
+
+		// The table must be added to the map before any of the goroutines below are started,
+		// since they stop as soon as they cannot find the table
+		// (we lock "tablesMutex" even though we are still in the synchronous phase of startup,
+		// because the goroutines of the previously restored tables might already be running)
+		tablesMutex.Lock()
+		tables[t.ID] = t
+		tablesMutex.Unlock()
+		logger.InfoWithFields(logFields, t.GetName()+"Restored table.")
 
-			// Players will never run out of time on restored tables because the "CheckTimer()"
-			// function was never initiated; manually do this
+		if !readOnly {
+			if err := store.Delete(t.ID); err != nil {
+				logger.Fatal("Failed to delete table "+strconv.FormatUint(t.ID, 10)+":", err)
+			}
+		}
+
+		// Players will never run out of time on restored tables because the "CheckTimer()"
+		// function was never initiated; manually do this
+		if g.Options.Timed && !g.Paused {
 			go g.CheckTimer(g.Turn, g.PauseCount, g.Players[g.ActivePlayerIndex])
 		}
 
-		tables[t.ID] = t
-		// (we don't need to lock "tablesMutex" because we are still in the synchronous phase of
-		// startup)
-		logger.Info(t.GetName() + "Restored table.")
+		// Similarly, a game that was paused for a disconnected player would never be unpaused if
+		// they do not reconnect
+		if g.Paused && g.DisconnectPaused {
+			go g.CheckDisconnectPause(g.PauseCount, activeDisconnectTimeout)
+		}
 
-		if err := os.Remove(tablePath); err != nil {
-			logger.Fatal("Failed to delete \""+tablePath+"\":", err)
+		// Similarly, the turn of an away player would never be automatically taken
+		if gp := g.Players[g.ActivePlayerIndex]; gp.Away {
+			go g.CheckAway(g.Turn, g.PauseCount, gp)
 		}
 
 		// Restored tables will never be automatically terminated due to idleness because the
@@ -220,13 +730,415 @@ func restoreTables() {
 		go t.CheckIdle()
 	}
 
-	// (we do not need to adjust the "tableIDCounter" variable because
-	// we have logic to not allow duplicate game IDs)
+	// New tables should be given IDs that are higher than all of the restored tables
+	// ("NewTable()" also checks for conflicting IDs, but we do not want to rely on that)
+	// (the counter is always accessed atomically)
+	tablesMutex.RLock()
+	numTables := len(tables)
+	var maxTableID uint64
+	for tableID := range tables {
+		if tableID > maxTableID {
+			maxTableID = tableID
+		}
+	}
+	tablesMutex.RUnlock()
+	if maxTableID > atomic.LoadUint64(&tableIDCounter) {
+		atomic.StoreUint64(&tableIDCounter, maxTableID)
+	}
 
-	msg := "Restored " + strconv.Itoa(len(tables)) + " previously running table"
-	if len(tables) >= 2 {
+	msg := "Restored " + strconv.Itoa(numTables) + " previously running table"
+	if numTables >= 2 {
 		msg += "s"
 	}
 	msg += "."
 	logger.Info(msg)
 }
+
+// LoadedTable is the result of loading one table in the "loadTables()" function
+type LoadedTable struct {
+	Table *Table
+	Err   error
+}
+
+// loadTables loads the given tables from the store using a pool of worker goroutines
+// The results are in the same order as the table IDs
+func loadTables(store TableStore, tableIDs []uint64, numWorkers int) []*LoadedTable {
+	loadedTables := make([]*LoadedTable, len(tableIDs))
+
+	tableIndexes := make(chan int, len(tableIDs))
+	for i := range tableIDs {
+		tableIndexes <- i
+	}
+	close(tableIndexes)
+
+	var wg sync.WaitGroup
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			// Each worker writes to different indexes of the slice, so we do not need a mutex
+			for j := range tableIndexes {
+				t, err := loadTable(store, tableIDs[j])
+				loadedTables[j] = &LoadedTable{
+					Table: t,
+					Err:   err,
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	return loadedTables
+}
+
+// loadTable reads a serialized table from the store and reconstructs it
+// It does not modify any global state, so it can also be used to validate the saved tables
+func loadTable(store TableStore, tableID uint64) (*Table, error) {
+	var tableJSON []byte
+	if v, err := store.Load(tableID); err != nil {
+		return nil, err
+	} else {
+		tableJSON = v
+	}
+
+	// Unwrap the envelope and upgrade the table from older versions of the server, if necessary
+	var serializedTable SerializedTable
+	if err := json.Unmarshal(tableJSON, &serializedTable); err != nil {
+		return nil, err
+	}
+	version := serializedTable.SchemaVersion
+	if serializedTable.Table == nil {
+		// This file was written before the envelope existed, so the whole file is the table
+		version = 0
+	} else {
+		tableJSON = serializedTable.Table
+	}
+	if version > TableSchemaVersion {
+		return nil, errors.New("the table has a schema version of " + strconv.Itoa(version) +
+			", but this server only understands versions up to " +
+			strconv.Itoa(TableSchemaVersion))
+	}
+	for ; version < TableSchemaVersion; version++ {
+		if v, err := migrateTable(version, tableJSON); err != nil {
+			return nil, err
+		} else {
+			tableJSON = v
+		}
+	}
+
+	t := &Table{} // We must initialize the table for "Unmarshal()" to work
+	if err := json.Unmarshal(tableJSON, t); err != nil {
+		return nil, err
+	}
+	if t.Game == nil || t.Options == nil || t.ExtraOptions == nil {
+		return nil, errors.New("the table is missing the game or the options")
+	}
+	t.Spectators = make([]*Spectator, 0)
+	t.KickedPlayers = make(map[int]struct{})
+	t.KickedSpectators = make(map[int]struct{})
+	t.DisconSpectators = make(map[int]struct{})
+	t.AllowedSpectators = make(map[int]struct{})
+	t.ReservedSeats = make(map[int]int)
+	t.SpectateTokens = make(map[string]*SpectateToken)
+	// The spectators will be automatically put back into the game if/when they reconnect
+	// (they already had access to the table, so they do not need the password again)
+	for _, id := range t.SpectatorIDs {
+		t.DisconSpectators[id] = struct{}{}
+		t.AllowedSpectators[id] = struct{}{}
+	}
+	t.SpectatorIDs = nil
+	if t.ChatRead == nil {
+		t.ChatRead = make(map[int]int)
+	}
+
+	// Restore the circular references that could not be represented in JSON
+	g := t.Game
+	g.Table = t
+	g.Options = t.Options
+	g.ExtraOptions = t.ExtraOptions
+	for _, gp := range g.Players {
+		gp.Game = g
+
+		// The cards in the hands are the same objects as the cards in the deck,
+		// but JSON turns them into separate copies
+		// (each new action performed from the action log would only update one of them)
+		for i, c := range gp.Hand {
+			if c.Order < 0 || c.Order >= len(g.Deck) {
+				return nil, errors.New("player " + strconv.Itoa(gp.Index) + " has a card with " +
+					"an invalid order of " + strconv.Itoa(c.Order))
+			}
+			gp.Hand[i] = g.Deck[c.Order]
+		}
+	}
+
+	// Restore the types of the actions
+	for i, a := range g.Actions {
+		if v, err := deserializeAction(a); err != nil {
+			return nil, errors.New("failed to restore action " + strconv.Itoa(i) + ": " +
+				err.Error())
+		} else {
+			g.Actions[i] = v
+		}
+	}
+
+	// Perform the actions that were taken after the table was last written in full
+	if err := restoreActionLog(store, tableID, t); err != nil {
+		return nil, errors.New("failed to restore the action log: " + err.Error())
+	}
+
+	return t, nil
+}
+
+// validateLoadedTable checks a table that was returned by "loadTable()" before it is restored
+// (this is shared with "validateTables()" so that a table passes validation if and only if it
+// would be restored)
+// A deck that does not match the seed is only logged as a warning with the given log fields
+func validateLoadedTable(
+	store TableStore,
+	t *Table,
+	tableID uint64,
+	logFields LogFields,
+) error {
+	g := t.Game
+
+	// Validate that the table is stored under its own ID
+	// (e.g. if the files were manually copied from a backup)
+	if t.ID != tableID {
+		return errors.New("the table in \"" + getTableFilename(store, tableID) + "\" has a " +
+			"different table ID of " + strconv.FormatUint(t.ID, 10) + ", so it collides with \"" +
+			getTableFilename(store, t.ID) + "\"")
+	}
+
+	// The deck is stored directly, but it should always be the same as the one generated from the
+	// seed (the seed must be correct so that the same deal can be played again later)
+	if !g.DeckMatchesSeed() {
+		logger.WarningWithFields(logFields, "The deck for the table does not match the deck "+
+			"generated from the seed of \""+g.Seed+"\".")
+	}
+
+	// Validate that the actions are consistent with the rest of the game state
+	if err := validateGameState(g); err != nil {
+		return errors.New("the game state is not valid: " + err.Error())
+	}
+
+	return validateDatabaseFields(t)
+}
+
+// getTableFilename returns the name of the file that the table is stored in for log messages
+// (or just the ID of the table if the store does not use files)
+func getTableFilename(store TableStore, tableID uint64) string {
+	if namer, ok := store.(TableFileNamer); ok {
+		return namer.GetPath(tableID)
+	}
+
+	return "table " + strconv.FormatUint(tableID, 10)
+}
+
+// validateDatabaseFields checks the fields that are used to write a restored game to the
+// database when it finishes
+// All of these are serialized, but if they are wrong (e.g. because a variant was renamed in the
+// meantime), the game would be recorded with bad stats or fail to be recorded at all
+func validateDatabaseFields(t *Table) error {
+	g := t.Game
+
+	if variant, ok := variants[t.Options.VariantName]; !ok {
+		return errors.New("the variant of \"" + t.Options.VariantName + "\" does not exist")
+	} else if variant.ID != t.Options.VariantID {
+		return errors.New("the variant ID of " + strconv.Itoa(t.Options.VariantID) +
+			" does not match the ID of " + strconv.Itoa(variant.ID) + " for \"" +
+			t.Options.VariantName + "\"")
+	}
+
+	// Replays are never written to the database
+	if t.Replay || t.ExtraOptions.NoWriteToDatabase {
+		return nil
+	}
+
+	// Ongoing games do not have a database ID until they are written to the database
+	if t.ExtraOptions.DatabaseID != -1 {
+		return errors.New("the ongoing game has a database ID of " +
+			strconv.Itoa(t.ExtraOptions.DatabaseID))
+	}
+
+	if len(t.Players) != len(g.Players) {
+		return errors.New("the table has " + strconv.Itoa(len(t.Players)) + " players, " +
+			"but the game has " + strconv.Itoa(len(g.Players)) + " players")
+	}
+	for i, p := range t.Players {
+		if p.ID <= 0 {
+			return errors.New("player " + strconv.Itoa(i) + " has an invalid user ID of " +
+				strconv.Itoa(p.ID))
+		}
+		if p.Name != g.Players[i].Name {
+			return errors.New("player " + strconv.Itoa(i) + " is named \"" + p.Name + "\" " +
+				"on the table, but \"" + g.Players[i].Name + "\" in the game")
+		}
+	}
+
+	return nil
+}
+
+// validateTablesInit initializes everything that "validateTables()" needs
+// (the rest of the server is not initialized when the "--validate-tables" flag is used)
+func validateTablesInit() {
+	colorsInit()
+	suitsInit()
+	variantsInit()
+	// The actions of each table are replayed to validate the game state
+	actionsFunctionsInit()
+	charactersInit()
+}
+
+// validateTables checks that every serialized table file can be restored without actually
+// restoring anything (this is invoked with the "--validate-tables" command-line flag)
+// It returns the number of files that failed to load
+func validateTables() int {
+	store := NewFileTableStore(tablesPath, false)
+
+	var tableIDs []uint64
+	if v, err := store.List(); err != nil {
+		logger.Fatal("Failed to get the list of the saved tables:", err)
+		return 0
+	} else {
+		tableIDs = v
+	}
+
+	numSucceeded := 0
+	numFailed := 0
+	for _, tableID := range tableIDs {
+		logFields := LogFields{
+			"tableID": tableID,
+		}
+		if t, err := loadTable(store, tableID); err != nil {
+			logger.ErrorWithFields(logFields, "Failed to load the table:", err)
+			numFailed++
+		} else if err := validateLoadedTable(store, t, tableID, logFields); err != nil {
+			logger.ErrorWithFields(logFields, "Failed to validate the table:", err)
+			numFailed++
+		} else {
+			numSucceeded++
+		}
+	}
+
+	logger.Info("Tables that would restore successfully: " + strconv.Itoa(numSucceeded))
+	logger.Info("Tables that would fail to restore: " + strconv.Itoa(numFailed))
+
+	return numFailed
+}
+
+// migrateTable converts the JSON of a table from the given schema version to the next one
+func migrateTable(version int, raw []byte) ([]byte, error) {
+	switch version {
+	case 0:
+		// Version 1 only introduced the envelope, so the table itself is unchanged
+		return raw, nil
+	default:
+		return nil, errors.New("there is no migration for schema version " + strconv.Itoa(version))
+	}
+}
+
+// deserializeAction converts an action that was unmarshaled from JSON as a generic map back into
+// the typed action struct that corresponds to its "type" field
+// Any actions added to the "actions.go" file must also be added here
+func deserializeAction(raw interface{}) (interface{}, error) {
+	var action map[string]interface{}
+	if v, ok := raw.(map[string]interface{}); !ok {
+		return nil, errors.New("the action is not a map")
+	} else {
+		action = v
+	}
+
+	var actionType string
+	if v, ok := action["type"].(string); !ok {
+		return nil, errors.New("the action does not have a valid type")
+	} else {
+		actionType = v
+	}
+
+	switch actionType {
+	case "cardIdentity":
+		typedAction := ActionCardIdentity{}
+		err := mapstructure.Decode(action, &typedAction)
+		return typedAction, err
+	case "clue":
+		typedAction := ActionClue{}
+		err := mapstructure.Decode(action, &typedAction)
+		return typedAction, err
+	case "discard":
+		typedAction := ActionDiscard{}
+		err := mapstructure.Decode(action, &typedAction)
+		return typedAction, err
+	case "draw":
+		typedAction := ActionDraw{}
+		err := mapstructure.Decode(action, &typedAction)
+		return typedAction, err
+	case "gameOver":
+		typedAction := ActionGameOver{}
+		err := mapstructure.Decode(action, &typedAction)
+		return typedAction, err
+	case "play":
+		typedAction := ActionPlay{}
+		err := mapstructure.Decode(action, &typedAction)
+		return typedAction, err
+	case "playerTimes":
+		typedAction := ActionPlayerTimes{}
+		err := mapstructure.Decode(action, &typedAction)
+		return typedAction, err
+	case "strike":
+		typedAction := ActionStrike{}
+		err := mapstructure.Decode(action, &typedAction)
+		return typedAction, err
+	case "status":
+		typedAction := ActionStatus{}
+		err := mapstructure.Decode(action, &typedAction)
+		return typedAction, err
+	case "turn":
+		typedAction := ActionTurn{}
+		err := mapstructure.Decode(action, &typedAction)
+		return typedAction, err
+	default:
+		return nil, errors.New("unknown action type of \"" + actionType + "\"")
+	}
+}
+
+func gzipBytes(data []byte) ([]byte, error) {
+	var buffer bytes.Buffer
+	// We do not set a modification time or a name in the header,
+	// so the same data will always produce the same bytes
+	gzipWriter := gzip.NewWriter(&buffer)
+	if _, err := gzipWriter.Write(data); err != nil {
+		return nil, err
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func gunzipBytes(data []byte) ([]byte, error) {
+	var gzipReader *gzip.Reader
+	if v, err := gzip.NewReader(bytes.NewReader(data)); err != nil {
+		return nil, err
+	} else {
+		gzipReader = v
+	}
+	defer gzipReader.Close()
+
+	return ioutil.ReadAll(gzipReader)
+}
+
+// quarantineTable sets aside a table that could not be restored (if the store supports it)
+// so that one corrupted table does not prevent the rest of the tables from being restored
+// (it is kept around so that it can be manually inspected later)
+func quarantineTable(store TableStore, tableID uint64) {
+	quarantiner, ok := store.(TableQuarantiner)
+	if !ok {
+		return
+	}
+
+	if err := quarantiner.Quarantine(tableID); err != nil {
+		logger.Error("Failed to quarantine table "+strconv.FormatUint(tableID, 10)+":", err)
+	}
+}
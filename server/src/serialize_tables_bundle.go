@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"strconv"
+)
+
+const (
+	// The version of the format of the file created by the "-export-tables" flag
+	// This is separate from the schema version of the tables inside of it
+	// Version 2 added the action logs
+	TableBundleVersion = 2
+)
+
+// TableBundle is used to move all of the ongoing tables to another server
+// (e.g. when migrating to new hardware)
+type TableBundle struct {
+	BundleVersion int
+	Tables        []*BundledTable
+}
+
+type BundledTable struct {
+	ID uint64
+	// The exact contents of the table in the store (i.e. the JSON envelope)
+	// This is a byte slice instead of a "json.RawMessage" so that the contents are not reformatted
+	Data []byte
+	// The entries of the action log of the table, if any (see "serialize_actions.go")
+	Actions [][]byte
+}
+
+// exportTables writes every table in the store to a single file
+func exportTables(store TableStore, filePath string) error {
+	var tableIDs []uint64
+	if v, err := store.List(); err != nil {
+		return err
+	} else {
+		tableIDs = v
+	}
+
+	bundle := &TableBundle{
+		BundleVersion: TableBundleVersion,
+		Tables:        make([]*BundledTable, 0),
+	}
+	for _, tableID := range tableIDs {
+		var data []byte
+		if v, err := store.Load(tableID); err != nil {
+			return errors.New("failed to load table " + strconv.FormatUint(tableID, 10) + ": " +
+				err.Error())
+		} else {
+			data = v
+		}
+
+		actions := make([][]byte, 0)
+		if actionLog, ok := store.(TableActionLog); ok {
+			if v, err := actionLog.LoadActions(tableID); err != nil {
+				return errors.New("failed to load the action log of table " +
+					strconv.FormatUint(tableID, 10) + ": " + err.Error())
+			} else {
+				actions = v
+			}
+		}
+
+		bundle.Tables = append(bundle.Tables, &BundledTable{
+			ID:      tableID,
+			Data:    data,
+			Actions: actions,
+		})
+	}
+
+	var bundleJSON []byte
+	if v, err := json.Marshal(bundle); err != nil {
+		return err
+	} else {
+		bundleJSON = v
+	}
+
+	if err := ioutil.WriteFile(filePath, bundleJSON, 0600); err != nil {
+		return err
+	}
+
+	logger.Info("Exported " + strconv.Itoa(len(bundle.Tables)) + " table(s) to: " + filePath)
+	return nil
+}
+
+// importTables writes every table from a file created by the "exportTables()" function to the
+// store
+// It should only be used when the server is not running, since the server will overwrite the
+// directory with its own tables the next time that it serializes
+// Nothing is written unless every table in the bundle is compatible with this server
+func importTables(store TableStore, filePath string) error {
+	var bundleJSON []byte
+	if v, err := ioutil.ReadFile(filePath); err != nil {
+		return err
+	} else {
+		bundleJSON = v
+	}
+
+	var bundle TableBundle
+	if err := json.Unmarshal(bundleJSON, &bundle); err != nil {
+		return err
+	}
+	// Older bundles simply do not have any action logs
+	if bundle.BundleVersion < 1 || bundle.BundleVersion > TableBundleVersion {
+		return errors.New("the bundle has a version of " + strconv.Itoa(bundle.BundleVersion) +
+			", but this server only understands versions up to " +
+			strconv.Itoa(TableBundleVersion))
+	}
+
+	actionLog, supportsActionLog := store.(TableActionLog)
+
+	// Validate all of the tables before writing any of them
+	for _, bundledTable := range bundle.Tables {
+		tableIDString := strconv.FormatUint(bundledTable.ID, 10)
+
+		// Older schema versions are fine, since they are migrated when the table is restored
+		var serializedTable SerializedTable
+		if err := json.Unmarshal(bundledTable.Data, &serializedTable); err != nil {
+			return errors.New("table " + tableIDString + " is not valid JSON: " + err.Error())
+		}
+		if serializedTable.SchemaVersion > TableSchemaVersion {
+			return errors.New("table " + tableIDString + " has a schema version of " +
+				strconv.Itoa(serializedTable.SchemaVersion) + ", but this server only " +
+				"understands versions up to " + strconv.Itoa(TableSchemaVersion))
+		}
+
+		if len(bundledTable.Actions) != 0 && !supportsActionLog {
+			return errors.New("table " + tableIDString + " has an action log, but the store " +
+				"does not support action logs")
+		}
+
+		// Do not clobber the tables that are already on this server
+		if _, err := store.Load(bundledTable.ID); err == nil {
+			return errors.New("table " + tableIDString + " already exists")
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	for _, bundledTable := range bundle.Tables {
+		if _, err := store.Save(bundledTable.ID, bundledTable.Data); err != nil {
+			return errors.New("failed to save table " + strconv.FormatUint(bundledTable.ID, 10) +
+				": " + err.Error())
+		}
+		if supportsActionLog {
+			if err := actionLog.ReplaceActions(bundledTable.ID, bundledTable.Actions); err != nil {
+				return errors.New("failed to save the action log of table " +
+					strconv.FormatUint(bundledTable.ID, 10) + ": " + err.Error())
+			}
+		}
+	}
+
+	logger.Info("Imported " + strconv.Itoa(len(bundle.Tables)) + " table(s) from: " + filePath)
+	return nil
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path"
+	"strings"
+	"testing"
+)
+
+// readTestDir returns the contents of every file in a directory by name
+func readTestDir(t *testing.T, dirPath string) map[string][]byte {
+	files := make(map[string][]byte)
+	if infos, err := ioutil.ReadDir(dirPath); err != nil {
+		t.Fatal("failed to read the directory:", err)
+	} else {
+		for _, info := range infos {
+			if data, err := ioutil.ReadFile(path.Join(dirPath, info.Name())); err != nil {
+				t.Fatal("failed to read the file:", err)
+			} else {
+				files[info.Name()] = data
+			}
+		}
+	}
+
+	return files
+}
+
+func TestExportImportTables(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		resetTestTables(t)
+		store := useTestTableStore(t, compress)
+		newTestGame(t, 2)
+		tb := newTestGame(t, 3)
+		if !serializeTables() {
+			t.Fatal("failed to serialize the tables")
+		}
+
+		// The actions that are taken after the table is saved go to the action log
+		clueTestPlayer(t, tb)
+		discardTestCard(t, tb)
+
+		original := readTestDir(t, store.Path)
+		if len(original) < 3 {
+			t.Fatalf("expected at least 2 tables and an action log, but got %v files",
+				len(original))
+		}
+
+		bundlePath := path.Join(newTestDir(t), "tables.json")
+		if err := exportTables(store, bundlePath); err != nil {
+			t.Fatal("failed to export the tables:", err)
+		}
+		store2 := NewFileTableStore(newTestDir(t), compress)
+		if err := importTables(store2, bundlePath); err != nil {
+			t.Fatal("failed to import the tables:", err)
+		}
+
+		imported := readTestDir(t, store2.Path)
+		if len(imported) != len(original) {
+			t.Errorf("expected %v files to be imported, but got %v", len(original), len(imported))
+		}
+		for name, data := range original {
+			if !bytes.Equal(imported[name], data) {
+				t.Errorf("the file \"%v\" was not imported byte-for-byte (compress: %v)",
+					name, compress)
+			}
+		}
+
+		// Importing the same tables again would clobber them
+		if err := importTables(store2, bundlePath); err == nil ||
+			!strings.Contains(err.Error(), "already exists") {
+
+			t.Errorf("expected an error about the tables already existing, but got: %v", err)
+		}
+	}
+}
+
+func writeTestBundle(t *testing.T, bundle *TableBundle) string {
+	bundlePath := path.Join(newTestDir(t), "tables.json")
+	if data, err := json.Marshal(bundle); err != nil {
+		t.Fatal("failed to marshal the bundle:", err)
+	} else if err := ioutil.WriteFile(bundlePath, data, 0600); err != nil {
+		t.Fatal("failed to write the bundle:", err)
+	}
+
+	return bundlePath
+}
+
+func TestImportTablesRejectsIncompatibleVersions(t *testing.T) {
+	newTable := func(id uint64, schemaVersion int) *BundledTable {
+		data, err := json.Marshal(&SerializedTable{
+			SchemaVersion: schemaVersion,
+			Table:         []byte(`{}`),
+		})
+		if err != nil {
+			t.Fatal("failed to marshal the table:", err)
+		}
+		return &BundledTable{
+			ID:   id,
+			Data: data,
+		}
+	}
+
+	tests := []struct {
+		name   string
+		bundle *TableBundle
+		text   string
+	}{
+		{
+			name: "newer bundle version",
+			bundle: &TableBundle{
+				BundleVersion: TableBundleVersion + 1,
+				Tables:        []*BundledTable{newTable(1, TableSchemaVersion)},
+			},
+			text: "the bundle has a version of",
+		},
+		{
+			name: "newer schema version",
+			bundle: &TableBundle{
+				BundleVersion: TableBundleVersion,
+				Tables: []*BundledTable{
+					newTable(1, TableSchemaVersion),
+					newTable(2, TableSchemaVersion+1),
+				},
+			},
+			text: "has a schema version of",
+		},
+	}
+	for _, test := range tests {
+		store := NewFileTableStore(newTestDir(t), false)
+		err := importTables(store, writeTestBundle(t, test.bundle))
+		if err == nil || !strings.Contains(err.Error(), test.text) {
+			t.Errorf("%v: expected an error containing \"%v\", but got: %v", test.name, test.text,
+				err)
+		}
+
+		// Nothing is written if any of the tables are incompatible
+		if ids, err := store.List(); err != nil {
+			t.Fatal("failed to list the tables:", err)
+		} else if len(ids) != 0 {
+			t.Errorf("%v: expected no tables to be imported, but got %v", test.name, ids)
+		}
+	}
+}
@@ -0,0 +1,1896 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	logging "github.com/Zamiell/go-logging"
+)
+
+func TestRestoreTablesSkipsPartialWrites(t *testing.T) {
+	resetTestTables(t)
+	store := useTestTableStore(t, false)
+
+	t1 := newTestGame(t, 2)
+	t2 := newTestGame(t, 3)
+	if !serializeTables() {
+		t.Fatal("failed to serialize the tables")
+	}
+
+	// Simulate the server being killed while a third table was being written
+	partialPath := path.Join(store.Path, "999.json.tmp")
+	if err := ioutil.WriteFile(partialPath, []byte(`{"SchemaVersion":1,"Ta`), 0600); err != nil {
+		t.Fatal("failed to write the partial table file:", err)
+	}
+
+	if ids, err := store.List(); err != nil {
+		t.Fatal("failed to list the tables:", err)
+	} else if len(ids) != 2 {
+		t.Fatalf("expected the store to list 2 tables, but it listed: %v", ids)
+	}
+
+	resetTestTables(t)
+	restoreTables()
+
+	for _, id := range []uint64{t1.ID, t2.ID} {
+		if _, ok := tables[id]; !ok {
+			t.Errorf("table %v was not restored", id)
+		}
+	}
+	if len(tables) != 2 {
+		t.Errorf("expected 2 restored tables, but got %v", len(tables))
+	}
+}
+
+func TestRestoreTablesQuarantinesCorruptFiles(t *testing.T) {
+	resetTestTables(t)
+	store := useTestTableStore(t, false)
+
+	t1 := newTestGame(t, 2)
+	t2 := newTestGame(t, 2)
+	if !serializeTables() {
+		t.Fatal("failed to serialize the tables")
+	}
+
+	// Write a garbage file between the two valid tables
+	garbagePath := path.Join(store.Path, "1000.json")
+	if err := ioutil.WriteFile(garbagePath, []byte("this is not JSON"), 0600); err != nil {
+		t.Fatal("failed to write the garbage table file:", err)
+	}
+
+	resetTestTables(t)
+	restoreTables()
+
+	for _, id := range []uint64{t1.ID, t2.ID} {
+		if _, ok := tables[id]; !ok {
+			t.Errorf("table %v was not restored", id)
+		}
+	}
+	if _, ok := tables[1000]; ok {
+		t.Error("the garbage table was restored")
+	}
+
+	if _, err := os.Stat(garbagePath); !os.IsNotExist(err) {
+		t.Error("the garbage table file was not moved out of the tables directory")
+	}
+	if _, err := os.Stat(path.Join(store.Path, "failed", "1000.json")); err != nil {
+		t.Error("the garbage table file was not quarantined:", err)
+	}
+}
+
+func TestDeserializeAction(t *testing.T) {
+	// Each action is converted to JSON and back so that the input is in the same form as the
+	// actions of a table that was read from disk
+	actions := []interface{}{
+		ActionCardIdentity{Type: "cardIdentity", PlayerIndex: 1, Order: 2, SuitIndex: 3, Rank: 4},
+		ActionClue{
+			Type:   "clue",
+			Clue:   Clue{Type: ClueTypeRank, Value: 5},
+			Giver:  0,
+			List:   []int{1, 3},
+			Target: 1,
+			Turn:   4,
+		},
+		ActionDiscard{Type: "discard", PlayerIndex: 1, Order: 7, SuitIndex: 2, Rank: 3, Failed: true},
+		ActionDraw{Type: "draw", PlayerIndex: 0, Order: 10, SuitIndex: 4, Rank: 1},
+		ActionGameOver{Type: "gameOver", EndCondition: EndConditionStrikeout, PlayerIndex: 1},
+		ActionPlay{Type: "play", PlayerIndex: 0, Order: 3, SuitIndex: 0, Rank: 1},
+		ActionPlayerTimes{Type: "playerTimes", PlayerTimes: []int64{-5000, 120000}, Duration: 9000},
+		ActionStrike{
+			Type:        "strike",
+			Num:         2,
+			Turn:        6,
+			Order:       8,
+			PlayerIndex: 1,
+			SuitIndex:   3,
+			Rank:        4,
+			Expected:    []int{2},
+		},
+		ActionStatus{Type: "status", Clues: 6, Score: 3, MaxScore: 25},
+		ActionTurn{Type: "turn", Num: 5, CurrentPlayerIndex: 1},
+	}
+
+	for _, expected := range actions {
+		var raw interface{}
+		if actionJSON, err := json.Marshal(expected); err != nil {
+			t.Fatal("failed to marshal the action:", err)
+		} else if err := json.Unmarshal(actionJSON, &raw); err != nil {
+			t.Fatal("failed to unmarshal the action:", err)
+		}
+
+		if actual, err := deserializeAction(raw); err != nil {
+			t.Errorf("failed to deserialize the %T action: %v", expected, err)
+		} else if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("expected %#v, but got %#v", expected, actual)
+		}
+	}
+}
+
+func TestDeserializeActionErrors(t *testing.T) {
+	inputs := map[string]interface{}{
+		"not a map":    []interface{}{"draw"},
+		"missing type": map[string]interface{}{"order": 1.0},
+		"invalid type": map[string]interface{}{"type": 1.0},
+		"unknown type": map[string]interface{}{"type": "teleport"},
+	}
+
+	for name, raw := range inputs {
+		if _, err := deserializeAction(raw); err == nil {
+			t.Errorf("expected an error for the input that is: %v", name)
+		}
+	}
+}
+
+func TestSerializeTablesLoop(t *testing.T) {
+	resetTestTables(t)
+	store := useTestTableStore(t, false)
+	tb := newTestGame(t, 2)
+
+	done := make(chan struct{})
+	go func() {
+		serializeTablesLoop(200 * time.Millisecond)
+		close(done)
+	}()
+
+	// Nothing should be written until the first interval has elapsed
+	time.Sleep(50 * time.Millisecond)
+	if ids, err := store.List(); err != nil {
+		t.Fatal("failed to list the tables:", err)
+	} else if len(ids) != 0 {
+		t.Error("the tables were serialized before the interval elapsed")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if ids, err := store.List(); err != nil {
+			t.Fatal("failed to list the tables:", err)
+		} else if len(ids) == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the tables were not periodically serialized")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Unlike a restart, the periodic snapshot leaves the tables alone
+	tablesMutex.RLock()
+	_, ok := tables[tb.ID]
+	tablesMutex.RUnlock()
+	if !ok {
+		t.Error("the table was removed after the periodic snapshot")
+	}
+
+	// The loop stops once the server starts to shut down
+	blockAllIncomingMessages.Set()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the loop did not stop after the server started shutting down")
+	}
+}
+
+func TestSerializeTablesIntervalZeroDisables(t *testing.T) {
+	useTestTableStore(t, false)
+	setTestEnv(t, "SERIALIZE_TABLES_INTERVAL", "0")
+
+	serializeTablesInterval = time.Minute
+	serializeTablesInit()
+	if serializeTablesInterval != 0 {
+		t.Errorf("expected the periodic serialization to be disabled, but the interval is %v",
+			serializeTablesInterval)
+	}
+}
+
+func useTestSerializeLockTimeout(t *testing.T, timeout time.Duration) {
+	oldSerializeLockTimeout := serializeLockTimeout
+	serializeLockTimeout = timeout
+	t.Cleanup(func() {
+		serializeLockTimeout = oldSerializeLockTimeout
+	})
+}
+
+func TestSerializeTablesDoesNotBlockTheTablesLock(t *testing.T) {
+	resetTestTables(t)
+	useTestTableStore(t, false)
+	tb := newTestGame(t, 2)
+
+	useTestSerializeLockTimeout(t, 2*time.Second)
+
+	// Simulate a command that is holding the lock of the table
+	tb.Mutex.Lock()
+	done := make(chan struct{})
+	go func() {
+		serializeTables()
+		close(done)
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	// While the serialization is waiting for the table, other tables can still be created
+	locked := make(chan struct{})
+	go func() {
+		tablesMutex.Lock()
+		tablesMutex.Unlock()
+		close(locked)
+	}()
+	select {
+	case <-locked:
+	case <-time.After(time.Second):
+		t.Error("the tables lock was held while waiting for the lock of a table")
+	}
+
+	tb.Mutex.Unlock()
+	<-done
+	<-locked
+}
+
+func TestSerializeTablesSkipsLockedTable(t *testing.T) {
+	resetTestTables(t)
+	logs := captureTestLogs(t)
+	store := useTestTableStore(t, false)
+	useTestSerializeLockTimeout(t, 100*time.Millisecond)
+	tb1 := newTestGame(t, 2)
+	tb2 := newTestGame(t, 2)
+	if !serializeTables() {
+		t.Fatal("failed to serialize the tables")
+	}
+	var oldData []byte
+	if v, err := store.Load(tb1.ID); err != nil {
+		t.Fatal("failed to load the table:", err)
+	} else {
+		oldData = v
+	}
+
+	// Simulate a command that is stuck while holding the lock of the first table
+	clueTestPlayer(t, tb1)
+	clueTestPlayer(t, tb2)
+	tb1.Mutex.Lock()
+	defer tb1.Mutex.Unlock()
+	done := make(chan bool)
+	go func() {
+		done <- serializeTables()
+	}()
+	select {
+	case success := <-done:
+		// A skipped table does not count as a failure
+		if !success {
+			t.Error("the serialization failed because of the locked table")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the serialization is stuck on the locked table")
+	}
+
+	skippedMsg := "Skipping table " + strconv.FormatUint(tb1.ID, 10)
+	if findTestLog(logs, logging.WARNING, skippedMsg) == "" {
+		t.Error("the skipped table was not logged")
+	}
+
+	// The file from the last serialization of the skipped table is kept
+	if v, err := store.Load(tb1.ID); err != nil {
+		t.Error("the file of the skipped table was deleted:", err)
+	} else if !bytes.Equal(v, oldData) {
+		t.Error("the file of the skipped table was changed")
+	}
+
+	// The other table is still saved
+	if v, err := loadTable(store, tb2.ID); err != nil {
+		t.Error("the other table was not saved:", err)
+	} else if v.Game.Turn != 1 {
+		t.Errorf("expected the other table to be saved on turn 1, but it was saved on turn %v",
+			v.Game.Turn)
+	}
+}
+
+func TestLockWithTimeout(t *testing.T) {
+	var mutex sync.Mutex
+	if !lockWithTimeout(&mutex, time.Second) {
+		t.Fatal("an unlocked mutex could not be acquired")
+	}
+
+	// The mutex is already locked
+	if lockWithTimeout(&mutex, 50*time.Millisecond) {
+		t.Fatal("a locked mutex was acquired")
+	}
+
+	// The attempt that timed out must not keep the mutex locked once it gets it
+	mutex.Unlock()
+	if !lockWithTimeout(&mutex, time.Second) {
+		t.Error("the mutex was left locked by an attempt that timed out")
+	}
+	mutex.Unlock()
+}
+
+func TestRestoreTablesRejectsDuplicateTableIDs(t *testing.T) {
+	resetTestTables(t)
+	store := useTestTableStore(t, false)
+
+	tb := newTestGame(t, 2)
+	if !serializeTables() {
+		t.Fatal("failed to serialize the tables")
+	}
+
+	// Simulate a botched merge of a backup, where a copy of the table was put under another ID
+	var tableJSON []byte
+	tableJSONPath := path.Join(store.Path, strconv.FormatUint(tb.ID, 10)+".json")
+	duplicateID := strconv.FormatUint(tb.ID+1, 10)
+	if v, err := ioutil.ReadFile(tableJSONPath); err != nil {
+		t.Fatal("failed to read the table file:", err)
+	} else {
+		tableJSON = v
+	}
+	duplicatePath := path.Join(store.Path, duplicateID+".json")
+	if err := ioutil.WriteFile(duplicatePath, tableJSON, 0600); err != nil {
+		t.Fatal("failed to write the duplicate table file:", err)
+	}
+
+	backend := captureTestLogs(t)
+	resetTestTables(t)
+	restoreTables()
+
+	if len(tables) != 1 {
+		t.Fatalf("expected 1 restored table, but got %v", len(tables))
+	}
+	if _, ok := tables[tb.ID]; !ok {
+		t.Error("the original table was not restored")
+	}
+	collision := "the table in \"" + duplicatePath + "\" has a different table ID of " +
+		strconv.FormatUint(tb.ID, 10) + ", so it collides with \"" + tableJSONPath + "\""
+	if findTestLog(backend, logging.ERROR, collision) == "" {
+		t.Error("the collision was not logged with both filenames")
+	}
+	if _, err := os.Stat(path.Join(store.Path, "failed", duplicateID+".json")); err != nil {
+		t.Error("the duplicate table file was not quarantined:", err)
+	}
+}
+
+// getTestTable returns the table with the given ID (e.g. after the tables have been restored)
+func getTestTable(t *testing.T, tableID uint64) *Table {
+	tablesMutex.RLock()
+	tb, ok := tables[tableID]
+	tablesMutex.RUnlock()
+	if !ok {
+		t.Fatalf("table %v does not exist", tableID)
+	}
+
+	return tb
+}
+
+// getTestTurn returns the current turn of the game while holding the table lock
+// (since a timer goroutine might be modifying it)
+func getTestTurn(tb *Table) int {
+	tb.Mutex.Lock()
+	defer tb.Mutex.Unlock()
+	return tb.Game.Turn
+}
+
+func TestRestoreTablesStartsTimerForUnpausedGame(t *testing.T) {
+	resetTestTables(t)
+	useTestTableStore(t, false)
+	setTestEnv(t, "RESTORE_GRACE_SECONDS", "0")
+
+	tb := newTestTimedGame(t, 2)
+	tb.Game.Players[0].Time = 100 * time.Millisecond
+	serializeAndRestoreTestTables(t)
+
+	// The active player runs out of time, so their turn is taken for them
+	restored := getTestTable(t, tb.ID)
+	deadline := time.Now().Add(5 * time.Second)
+	for getTestTurn(restored) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("the timer was not started for the restored game")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRestoreTablesDoesNotStartTimerForPausedGame(t *testing.T) {
+	resetTestTables(t)
+	useTestTableStore(t, false)
+	setTestEnv(t, "RESTORE_GRACE_SECONDS", "30")
+
+	tb := newTestTimedGame(t, 2)
+	tb.Game.Players[0].Time = 100 * time.Millisecond
+	tb.Game.Paused = true
+	tb.Game.PauseCount++
+	serializeAndRestoreTestTables(t)
+
+	restored := getTestTable(t, tb.ID)
+	time.Sleep(300 * time.Millisecond)
+	if getTestTurn(restored) != 0 {
+		t.Error("the timer ran for a paused game")
+	}
+
+	// Paused players are not losing any time, so they do not get the grace period
+	restored.Mutex.Lock()
+	defer restored.Mutex.Unlock()
+	if restored.Game.Players[0].Time != 100*time.Millisecond {
+		t.Errorf("expected the time of the active player to be unchanged, but it is %v",
+			restored.Game.Players[0].Time)
+	}
+}
+
+func TestRestoreTablesGracePeriod(t *testing.T) {
+	resetTestTables(t)
+	useTestTableStore(t, false)
+	setTestEnv(t, "RESTORE_GRACE_SECONDS", "30")
+
+	tb := newTestTimedGame(t, 2)
+	serializeAndRestoreTestTables(t)
+
+	restored := getTestTable(t, tb.ID)
+	restored.Mutex.Lock()
+	defer restored.Mutex.Unlock()
+	base := time.Duration(tb.Options.TimeBase) * time.Second
+	if timeLeft := restored.Game.Players[0].Time; timeLeft != base+30*time.Second {
+		t.Errorf("expected the active player to have %v, but they have %v",
+			base+30*time.Second, timeLeft)
+	}
+	if timeLeft := restored.Game.Players[1].Time; timeLeft != base {
+		t.Errorf("expected the other player to have %v, but they have %v", base, timeLeft)
+	}
+}
+
+func TestRestoreTablesTimeBonusEnabled(t *testing.T) {
+	resetTestTables(t)
+	useTestTableStore(t, false)
+	setTestEnv(t, "RESTORE_GRACE_SECONDS", "30")
+	setTestEnv(t, "RESTORE_TIME_BONUS_ENABLED", "true")
+
+	tb := newTestTimedGame(t, 2)
+	tb.Game.DatetimeTurnBegin = time.Now().Add(-10 * time.Second)
+	serializeAndRestoreTestTables(t)
+
+	// The time that the active player already took still counts against them
+	restored := getTestTable(t, tb.ID)
+	base := time.Duration(tb.Options.TimeBase) * time.Second
+	expected := base + 30*time.Second - 10*time.Second
+	if v := getTestTimeLeft(restored, 0); v > expected || v < expected-time.Second {
+		t.Errorf("expected the active player to have about %v left, but they have %v",
+			expected, v)
+	}
+}
+
+func TestRestoreTablesTimeBonusDisabled(t *testing.T) {
+	resetTestTables(t)
+	useTestTableStore(t, false)
+	setTestEnv(t, "RESTORE_GRACE_SECONDS", "30")
+	setTestEnv(t, "RESTORE_TIME_BONUS_ENABLED", "false")
+
+	tb := newTestTimedGame(t, 2)
+	tb.Game.DatetimeTurnBegin = time.Now().Add(-10 * time.Second)
+	if !serializeTables() {
+		t.Fatal("failed to serialize the tables")
+	}
+	resetTestTables(t)
+
+	// The time that the server is down does not count against the active player
+	time.Sleep(200 * time.Millisecond)
+	restoreTables()
+
+	restored := getTestTable(t, tb.ID)
+	base := time.Duration(tb.Options.TimeBase) * time.Second
+	restored.Mutex.Lock()
+	if v := restored.Game.Players[0].Time; v != base {
+		t.Errorf("expected the active player to not be given a bonus, but they have %v", v)
+	}
+	if restored.Game.DatetimeSerialized.IsZero() {
+		t.Error("the time that the table was serialized was not saved")
+	}
+	restored.Mutex.Unlock()
+
+	expected := base - 10*time.Second
+	if v := getTestTimeLeft(restored, 0); v > expected || v < expected-100*time.Millisecond {
+		t.Errorf("expected the active player to have about %v left, but they have %v",
+			expected, v)
+	}
+
+	// The other player is not affected
+	if v := getTestTimeLeft(restored, 1); v != base {
+		t.Errorf("expected the other player to have %v, but they have %v", base, v)
+	}
+}
+
+func TestSerializeTablesCompressionRoundTrip(t *testing.T) {
+	resetTestTables(t)
+	store := useTestTableStore(t, true)
+
+	tb := newTestGame(t, 3)
+	clueTestPlayer(t, tb)
+	discardTestCard(t, tb)
+	if !serializeTables() {
+		t.Fatal("failed to serialize the tables")
+	}
+
+	gzPath := path.Join(store.Path, strconv.FormatUint(tb.ID, 10)+".json.gz")
+	if _, err := os.Stat(gzPath); err != nil {
+		t.Fatal("the table was not written as a gzipped file:", err)
+	}
+
+	resetTestTables(t)
+	restoreTables()
+	restored := getTestTable(t, tb.ID)
+
+	if restored.Name != tb.Name || restored.Owner != tb.Owner {
+		t.Error("the name or the owner of the table was not restored")
+	}
+	if !reflect.DeepEqual(restored.Options, tb.Options) {
+		t.Errorf("expected the options to be %+v, but got %+v", tb.Options, restored.Options)
+	}
+	g := tb.Game
+	g2 := restored.Game
+	if g2.Seed != g.Seed || g2.Turn != g.Turn || g2.ClueTokens != g.ClueTokens ||
+		g2.Score != g.Score || g2.ActivePlayerIndex != g.ActivePlayerIndex {
+
+		t.Error("the state of the game was not restored")
+	}
+	if !reflect.DeepEqual(g2.Actions, g.Actions) {
+		t.Error("the actions were not restored")
+	}
+	for i, c := range g.Deck {
+		c2 := g2.Deck[i]
+		if c2.SuitIndex != c.SuitIndex || c2.Rank != c.Rank || c2.Order != c.Order {
+			t.Errorf("card %v of the deck was not restored", i)
+		}
+	}
+	for i, gp := range g.Players {
+		gp2 := g2.Players[i]
+		if gp2.Name != gp.Name || len(gp2.Hand) != len(gp.Hand) {
+			t.Errorf("player %v was not restored", i)
+			continue
+		}
+		for j, c := range gp.Hand {
+			if gp2.Hand[j].Order != c.Order {
+				t.Errorf("card %v in the hand of player %v was not restored", j, i)
+			}
+		}
+	}
+}
+
+func TestGzipBytesRoundTrip(t *testing.T) {
+	data := []byte(`{"SchemaVersion":1,"Table":{"ID":5}}`)
+
+	var compressed []byte
+	if v, err := gzipBytes(data); err != nil {
+		t.Fatal("failed to compress the data:", err)
+	} else {
+		compressed = v
+	}
+
+	// The same data must always produce the same bytes
+	if v, err := gzipBytes(data); err != nil {
+		t.Fatal("failed to compress the data:", err)
+	} else if !reflect.DeepEqual(v, compressed) {
+		t.Error("compressing the same data twice produced different bytes")
+	}
+
+	if v, err := gunzipBytes(compressed); err != nil {
+		t.Fatal("failed to decompress the data:", err)
+	} else if string(v) != string(data) {
+		t.Errorf("expected the decompressed data to be %s, but got %s", data, v)
+	}
+
+	if _, err := gunzipBytes(data); err == nil {
+		t.Error("decompressing data that is not gzipped did not fail")
+	}
+}
+
+func TestValidateTables(t *testing.T) {
+	resetTestTables(t)
+	store := useTestTableStore(t, false)
+
+	newTestGame(t, 2)
+	clueTestPlayer(t, newTestGame(t, 2))
+
+	// The actions do not match the rest of the game state
+	badState := newTestGame(t, 2)
+	badState.Game.ClueTokens = 3
+
+	// The game would be written to the database with the wrong variant
+	badVariant := newTestGame(t, 2)
+	badVariant.ExtraOptions.NoWriteToDatabase = false
+	badVariant.Options.VariantID = 999
+
+	if !serializeTables() {
+		t.Fatal("failed to serialize the tables")
+	}
+	if err := ioutil.WriteFile(path.Join(store.Path, "1000.json"), []byte("{"), 0600); err != nil {
+		t.Fatal("failed to write the garbage table file:", err)
+	}
+
+	var idsBefore []uint64
+	if v, err := store.List(); err != nil {
+		t.Fatal("failed to list the tables:", err)
+	} else {
+		idsBefore = v
+	}
+
+	resetTestTables(t)
+	if numFailed := validateTables(); numFailed != 3 {
+		t.Errorf("expected 3 tables to fail validation, but %v failed", numFailed)
+	}
+
+	// Nothing is restored, deleted, or quarantined
+	if len(tables) != 0 {
+		t.Errorf("expected no tables to be restored, but there are %v", len(tables))
+	}
+	if ids, err := store.List(); err != nil {
+		t.Fatal("failed to list the tables:", err)
+	} else if !reflect.DeepEqual(ids, idsBefore) {
+		t.Errorf("expected the store to still have the tables %v, but it has %v", idsBefore, ids)
+	}
+	if _, err := os.Stat(path.Join(store.Path, "failed")); !os.IsNotExist(err) {
+		t.Error("the tables that failed validation were quarantined")
+	}
+
+	// Every table that fails validation must also fail to restore (and vice versa)
+	restoreTables()
+	if len(tables) != 2 {
+		t.Errorf("expected 2 tables to be restored, but %v were", len(tables))
+	}
+}
+
+func TestValidateTablesWithoutServerInit(t *testing.T) {
+	resetTestTables(t)
+	useTestTableStore(t, false)
+	clueTestPlayer(t, newTestGame(t, 2))
+	if !serializeTables() {
+		t.Fatal("failed to serialize the tables")
+	}
+
+	// The "--validate-tables" flag skips the rest of the initialization of the server,
+	// so only the things that "validateTablesInit()" sets up are available
+	oldActionFunctions := actionFunctions
+	oldCharacters := characters
+	oldCharacterIDMap := characterIDMap
+	oldCharacterNames := characterNames
+	actionFunctions = nil
+	characters = nil
+	characterIDMap = nil
+	characterNames = nil
+	t.Cleanup(func() {
+		actionFunctions = oldActionFunctions
+		characters = oldCharacters
+		characterIDMap = oldCharacterIDMap
+		characterNames = oldCharacterNames
+	})
+
+	resetTestTables(t)
+	validateTablesInit()
+	if numFailed := validateTables(); numFailed != 0 {
+		t.Errorf("expected every table to pass validation, but %v failed", numFailed)
+	}
+}
+
+func TestRestoreTablesKeepsSpectators(t *testing.T) {
+	resetTestTables(t)
+	useTestTableStore(t, false)
+
+	tb := newTestGame(t, 2)
+	newTestSpectator(tb, 7, "Grace")
+	newTestSpectator(tb, 8, "Heidi")
+	tb.DisconSpectators[9] = struct{}{} // Ivan was spectating but is currently disconnected
+
+	serializeAndRestoreTestTables(t)
+	restored := getTestTable(t, tb.ID)
+
+	if len(restored.Spectators) != 0 {
+		t.Errorf("expected no connected spectators, but there are %v", len(restored.Spectators))
+	}
+	if restored.SpectatorIDs != nil {
+		t.Error("the spectator IDs were not cleared after the table was restored")
+	}
+	expected := map[int]struct{}{7: {}, 8: {}, 9: {}}
+	if !reflect.DeepEqual(restored.DisconSpectators, expected) {
+		t.Errorf("expected the disconnected spectators to be %v, but they are %v",
+			expected, restored.DisconSpectators)
+	}
+
+	// When the spectators reconnect, they are sent back to the restored table
+	for _, id := range []int{7, 8, 9} {
+		data := &WebsocketConnectData{}
+		websocketConnectGetTableData(newTestSession(id, "Spectator"+strconv.Itoa(id)), data)
+		if !data.SpectatingTable || data.SpectatingTableID != tb.ID {
+			t.Errorf("spectator %v was not put back into the restored table", id)
+		}
+	}
+}
+
+func TestRestoreTablesSchemaVersions(t *testing.T) {
+	resetTestTables(t)
+	store := useTestTableStore(t, false)
+	logs := captureTestLogs(t)
+
+	current := newTestGame(t, 2)
+	future := newTestGame(t, 2)
+	old := newTestGame(t, 2)
+	if !serializeTables() {
+		t.Fatal("failed to serialize the tables")
+	}
+
+	readEnvelope := func(tableID uint64) (string, *SerializedTable) {
+		tablePath := path.Join(store.Path, strconv.FormatUint(tableID, 10)+".json")
+		var serializedTable SerializedTable
+		if v, err := ioutil.ReadFile(tablePath); err != nil {
+			t.Fatal("failed to read the table file:", err)
+		} else if err := json.Unmarshal(v, &serializedTable); err != nil {
+			t.Fatal("failed to unmarshal the table file:", err)
+		}
+		if serializedTable.SchemaVersion != TableSchemaVersion {
+			t.Fatalf("expected a schema version of %v, but got %v",
+				TableSchemaVersion, serializedTable.SchemaVersion)
+		}
+		return tablePath, &serializedTable
+	}
+	readEnvelope(current.ID)
+
+	// A file that was written by a newer version of the server
+	futurePath, futureTable := readEnvelope(future.ID)
+	futureTable.SchemaVersion = TableSchemaVersion + 1
+	if v, err := json.Marshal(futureTable); err != nil {
+		t.Fatal("failed to marshal the envelope:", err)
+	} else if err := ioutil.WriteFile(futurePath, v, 0600); err != nil {
+		t.Fatal("failed to write the table file:", err)
+	}
+
+	// A file that was written before the envelope existed (i.e. version 0)
+	oldPath, oldTable := readEnvelope(old.ID)
+	if err := ioutil.WriteFile(oldPath, oldTable.Table, 0600); err != nil {
+		t.Fatal("failed to write the table file:", err)
+	}
+
+	resetTestTables(t)
+	restoreTables()
+
+	for _, id := range []uint64{current.ID, old.ID} {
+		if _, ok := tables[id]; !ok {
+			t.Errorf("table %v was not restored", id)
+		}
+	}
+	if _, ok := tables[future.ID]; ok {
+		t.Error("the table with an unknown schema version was restored")
+	}
+	if findTestLog(logs, logging.ERROR, "schema version") == "" {
+		t.Error("the unknown schema version was not logged")
+	}
+	futureFile := strconv.FormatUint(future.ID, 10) + ".json"
+	if _, err := os.Stat(path.Join(store.Path, "failed", futureFile)); err != nil {
+		t.Error("the table with an unknown schema version was not quarantined:", err)
+	}
+}
+
+func TestMigrateTable(t *testing.T) {
+	raw := []byte(`{"ID":1}`)
+	if v, err := migrateTable(0, raw); err != nil {
+		t.Error("failed to migrate a version 0 table:", err)
+	} else if string(v) != string(raw) {
+		t.Errorf("expected the version 0 table to be unchanged, but got: %s", v)
+	}
+
+	if _, err := migrateTable(TableSchemaVersion, raw); err == nil {
+		t.Error("a table with the current schema version was migrated")
+	}
+}
+
+func TestRestoreTablesReadOnly(t *testing.T) {
+	resetTestTables(t)
+	backup := useTestTableStore(t, false)
+
+	t1 := newTestGame(t, 2)
+	t2 := newTestGame(t, 3)
+	if !serializeTables() {
+		t.Fatal("failed to serialize the tables")
+	}
+	garbagePath := path.Join(backup.Path, "1000.json")
+	if err := ioutil.WriteFile(garbagePath, []byte("this is not JSON"), 0600); err != nil {
+		t.Fatal("failed to write the garbage table file:", err)
+	}
+
+	var idsBefore []uint64
+	if v, err := backup.List(); err != nil {
+		t.Fatal("failed to list the tables:", err)
+	} else {
+		idsBefore = v
+	}
+
+	// The server is configured to read from the backup directory, which cannot be modified
+	live := useTestTableStore(t, false)
+	setTestEnv(t, "TABLES_RESTORE_READONLY", "true")
+	setTestEnv(t, "TABLES_RESTORE_PATH", backup.Path)
+	if err := os.Chmod(backup.Path, 0555); err != nil {
+		t.Fatal("failed to make the backup directory read-only:", err)
+	}
+	t.Cleanup(func() {
+		os.Chmod(backup.Path, 0755) // nolint: errcheck
+	})
+
+	// Restoring twice works, since nothing is removed from the backup directory
+	for i := 0; i < 2; i++ {
+		resetTestTables(t)
+		restoreTables()
+
+		for _, id := range []uint64{t1.ID, t2.ID} {
+			if _, ok := tables[id]; !ok {
+				t.Errorf("table %v was not restored", id)
+			}
+		}
+		if ids, err := backup.List(); err != nil {
+			t.Fatal("failed to list the tables:", err)
+		} else if !reflect.DeepEqual(ids, idsBefore) {
+			t.Errorf("expected the backup to still have the tables %v, but it has %v",
+				idsBefore, ids)
+		}
+	}
+	if _, err := os.Stat(path.Join(backup.Path, "failed")); !os.IsNotExist(err) {
+		t.Error("the garbage table file was quarantined")
+	}
+
+	// The live directory is not used
+	if ids, err := live.List(); err != nil && !os.IsNotExist(err) {
+		t.Fatal("failed to list the tables:", err)
+	} else if len(ids) != 0 {
+		t.Errorf("expected no tables in the live directory, but it has %v", ids)
+	}
+}
+
+func TestRestoreTablesDeletesFiles(t *testing.T) {
+	resetTestTables(t)
+	store := useTestTableStore(t, false)
+
+	newTestGame(t, 2)
+	serializeAndRestoreTestTables(t)
+
+	if ids, err := store.List(); err != nil {
+		t.Fatal("failed to list the tables:", err)
+	} else if len(ids) != 0 {
+		t.Errorf("expected the restored tables to be deleted, but the store has %v", ids)
+	}
+}
+
+func TestRestoreTablesKeepsSeedAndDeck(t *testing.T) {
+	resetTestTables(t)
+	useTestTableStore(t, false)
+	logs := captureTestLogs(t)
+
+	tb := newTestGame(t, 2)
+	clueTestPlayer(t, tb)
+	seed := tb.Game.Seed
+	deck := make([]*CardIdentity, len(tb.Game.CardIdentities))
+	copy(deck, tb.Game.CardIdentities)
+
+	serializeAndRestoreTestTables(t)
+	g := getTestTable(t, tb.ID).Game
+
+	if g.Seed != seed {
+		t.Errorf("expected the seed to be \"%v\", but it was \"%v\"", seed, g.Seed)
+	}
+	if !reflect.DeepEqual(g.CardIdentities, deck) {
+		t.Error("the restored deck does not match the original deck")
+	}
+
+	// The deck can be reconstructed from the restored seed
+	if !g.DeckMatchesSeed() {
+		t.Error("the deck generated from the restored seed does not match the original deck")
+	}
+	if findTestLog(logs, logging.WARNING, "does not match the deck") != "" {
+		t.Error("a deck mismatch was logged for a valid table")
+	}
+}
+
+func TestRestoreTablesWarnsOnDeckMismatch(t *testing.T) {
+	resetTestTables(t)
+	useTestTableStore(t, false)
+	logs := captureTestLogs(t)
+
+	// Swap the last two cards of the deck (which have not been drawn yet)
+	tb := newTestGame(t, 2)
+	cardIdentities := tb.Game.CardIdentities
+	last := len(cardIdentities) - 1
+	for i := last - 1; i >= 0; i-- {
+		if !reflect.DeepEqual(cardIdentities[i], cardIdentities[last]) {
+			cardIdentities[i], cardIdentities[last] = cardIdentities[last], cardIdentities[i]
+			break
+		}
+	}
+	if tb.Game.DeckMatchesSeed() {
+		t.Fatal("the modified deck still matches the seed")
+	}
+
+	serializeAndRestoreTestTables(t)
+	if findTestLog(logs, logging.WARNING, "does not match the deck generated from the seed") == "" {
+		t.Error("the deck mismatch was not logged")
+	}
+}
+
+func TestRestoreTablesAdvancesTableIDCounter(t *testing.T) {
+	resetTestTables(t)
+	useTestTableStore(t, false)
+	oldTableIDCounter := atomic.LoadUint64(&tableIDCounter)
+	t.Cleanup(func() {
+		atomic.StoreUint64(&tableIDCounter, oldTableIDCounter)
+	})
+
+	for _, id := range []uint64{5, 10, 3} {
+		tb := newTestGame(t, 2)
+		tablesMutex.Lock()
+		delete(tables, tb.ID)
+		tb.ID = id
+		tables[tb.ID] = tb
+		tablesMutex.Unlock()
+	}
+
+	// Simulate a fresh server, where the counter starts at 0
+	if !serializeTables() {
+		t.Fatal("failed to serialize the tables")
+	}
+	resetTestTables(t)
+	atomic.StoreUint64(&tableIDCounter, 0)
+	restoreTables()
+	if len(tables) != 3 {
+		t.Fatalf("expected 3 tables to be restored, but %v were", len(tables))
+	}
+
+	if tb := NewTable("New Table", 1); tb.ID != 11 {
+		t.Errorf("expected the next table to have an ID of 11, but it has an ID of %v", tb.ID)
+	}
+}
+
+func TestRestoreTablesKeepsRecentChat(t *testing.T) {
+	resetTestTables(t)
+	useTestTableStore(t, false)
+	oldSerializeChatLimit := serializeChatLimit
+	serializeChatLimit = 5
+	t.Cleanup(func() {
+		serializeChatLimit = oldSerializeChatLimit
+	})
+
+	tb := newTestGame(t, 2)
+	datetime := time.Date(2020, 8, 1, 12, 0, 0, 0, time.UTC)
+	tb.Chat = nil
+	for i := 0; i < 8; i++ {
+		tb.Chat = append(tb.Chat, &TableChatMessage{
+			UserID:   1,
+			Username: "Alice",
+			Msg:      "message " + strconv.Itoa(i),
+			Datetime: datetime.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	tb.ChatRead[1] = 8
+	tb.ChatRead[2] = 2
+	expectedChat := tb.Chat[3:]
+
+	serializeAndRestoreTestTables(t)
+	restored := getTestTable(t, tb.ID)
+
+	if !reflect.DeepEqual(restored.Chat, expectedChat) {
+		t.Errorf("expected the last 5 chat messages to be restored, but got %v", restored.Chat)
+	}
+
+	// The number of read messages is adjusted for the messages that were dropped
+	expectedChatRead := map[int]int{1: 5, 2: 0}
+	if !reflect.DeepEqual(restored.ChatRead, expectedChatRead) {
+		t.Errorf("expected the read messages to be %v, but they are %v",
+			expectedChatRead, restored.ChatRead)
+	}
+
+	// The full history is kept for the table that is still running
+	if len(tb.Chat) != 8 || tb.ChatRead[1] != 8 {
+		t.Error("the chat history of the original table was modified")
+	}
+}
+
+func TestRestoreTablesTwiceDoesNotDuplicateTimers(t *testing.T) {
+	resetTestTables(t)
+	useTestTableStore(t, false)
+
+	tb := newTestTimedGame(t, 2)
+	serializeAndRestoreTestTables(t)
+	restored := getTestTable(t, tb.ID)
+	numGoroutines := runtime.NumGoroutine()
+
+	// Simulate a startup bug where the tables are restored a second time
+	if !serializeTables() {
+		t.Fatal("failed to serialize the tables")
+	}
+	backend := captureTestLogs(t)
+	restoreTables()
+
+	if findTestLog(backend, logging.ERROR, "called more than once") == "" {
+		t.Error("the second restore was not logged")
+	}
+	if getTestTable(t, tb.ID) != restored {
+		t.Error("the restored table was replaced by the second restore")
+	}
+	if v := runtime.NumGoroutine(); v > numGoroutines {
+		t.Errorf("expected at most %v goroutines after the second restore, but got %v",
+			numGoroutines, v)
+	}
+}
+
+func TestRestoreTablesSkipsExistingTables(t *testing.T) {
+	resetTestTables(t)
+	useTestTableStore(t, false)
+
+	tb := newTestTimedGame(t, 2)
+	serializeAndRestoreTestTables(t)
+	restored := getTestTable(t, tb.ID)
+	numGoroutines := runtime.NumGoroutine()
+
+	// Even if the restore is allowed to run again,
+	// a table that is already running must not get a second set of goroutines
+	if !serializeTables() {
+		t.Fatal("failed to serialize the tables")
+	}
+	restoreTablesCalled.UnSet()
+	backend := captureTestLogs(t)
+	restoreTables()
+
+	if findTestLog(backend, logging.WARNING, "already exists") == "" {
+		t.Error("skipping the existing table was not logged")
+	}
+	if getTestTable(t, tb.ID) != restored {
+		t.Error("the existing table was replaced by the second restore")
+	}
+	if v := runtime.NumGoroutine(); v > numGoroutines {
+		t.Errorf("expected at most %v goroutines after the second restore, but got %v",
+			numGoroutines, v)
+	}
+}
+
+// flakyTableStore is a table store that fails to save a table a certain amount of times before
+// succeeding
+type flakyTableStore struct {
+	*FileTableStore
+	failures map[uint64]int
+	attempts map[uint64]int
+}
+
+func (s *flakyTableStore) Save(id uint64, data []byte) (int, error) {
+	s.attempts[id]++
+	if s.attempts[id] <= s.failures[id] {
+		return 0, errors.New("no space left on device")
+	}
+
+	return s.FileTableStore.Save(id, data)
+}
+
+func useTestFlakyTableStore(t *testing.T, retries int, delay time.Duration) *flakyTableStore {
+	store := &flakyTableStore{
+		FileTableStore: useTestTableStore(t, false),
+		failures:       make(map[uint64]int),
+		attempts:       make(map[uint64]int),
+	}
+	tableStore = store
+
+	oldSerializeWriteRetries := serializeWriteRetries
+	oldSerializeWriteRetryDelay := serializeWriteRetryDelay
+	serializeWriteRetries = retries
+	serializeWriteRetryDelay = delay
+	t.Cleanup(func() {
+		serializeWriteRetries = oldSerializeWriteRetries
+		serializeWriteRetryDelay = oldSerializeWriteRetryDelay
+	})
+
+	return store
+}
+
+func TestSerializeTablesRetriesFailedWrites(t *testing.T) {
+	resetTestTables(t)
+	store := useTestFlakyTableStore(t, 3, 20*time.Millisecond)
+	tb := newTestGame(t, 2)
+	store.failures[tb.ID] = 2
+
+	start := time.Now()
+	if !serializeTables() {
+		t.Fatal("the tables were not serialized")
+	}
+
+	if store.attempts[tb.ID] != 3 {
+		t.Errorf("expected 3 attempts to save the table, but got %v", store.attempts[tb.ID])
+	}
+	if _, err := store.Load(tb.ID); err != nil {
+		t.Error("the table was not saved:", err)
+	}
+
+	// The delay doubles after every failure
+	if elapsed := time.Since(start); elapsed < 60*time.Millisecond {
+		t.Errorf("expected the retries to take at least 60ms, but they took %v", elapsed)
+	}
+}
+
+func TestSerializeTablesContinuesAfterFailedWrites(t *testing.T) {
+	resetTestTables(t)
+	store := useTestFlakyTableStore(t, 2, time.Millisecond)
+	tb1 := newTestGame(t, 2)
+	tb2 := newTestGame(t, 2)
+	store.failures[tb1.ID] = 100
+
+	if serializeTables() {
+		t.Error("the serialization succeeded even though a table could not be saved")
+	}
+
+	// The first table is given up on once the retries are exhausted
+	if store.attempts[tb1.ID] != 3 {
+		t.Errorf("expected 3 attempts to save the failing table, but got %v",
+			store.attempts[tb1.ID])
+	}
+	if _, err := store.Load(tb1.ID); !os.IsNotExist(err) {
+		t.Error("the failing table was saved")
+	}
+
+	// The rest of the tables are still saved
+	if _, err := store.Load(tb2.ID); err != nil {
+		t.Error("the other table was not saved:", err)
+	}
+}
+
+// slowTableStore is a table store that takes a while to load each table and keeps track of how
+// many tables were being loaded at the same time
+type slowTableStore struct {
+	*FileTableStore
+	numLoading    int32
+	maxNumLoading int32
+}
+
+func (s *slowTableStore) Load(id uint64) ([]byte, error) {
+	numLoading := atomic.AddInt32(&s.numLoading, 1)
+	defer atomic.AddInt32(&s.numLoading, -1)
+	for {
+		maxNumLoading := atomic.LoadInt32(&s.maxNumLoading)
+		if numLoading <= maxNumLoading ||
+			atomic.CompareAndSwapInt32(&s.maxNumLoading, maxNumLoading, numLoading) {
+
+			break
+		}
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	return s.FileTableStore.Load(id)
+}
+
+func TestRestoreTablesInParallel(t *testing.T) {
+	resetTestTables(t)
+	store := &slowTableStore{
+		FileTableStore: useTestTableStore(t, false),
+	}
+	tableStore = store
+	setTestEnv(t, "RESTORE_WORKERS", "4")
+
+	const numTables = 16
+	originals := make([]*Table, 0)
+	for i := 0; i < numTables; i++ {
+		tb := newTestGame(t, 2+i%4)
+		for j := 0; j < i%3; j++ {
+			clueTestPlayer(t, tb)
+		}
+		originals = append(originals, tb)
+	}
+	serializeAndRestoreTestTables(t)
+
+	// Every table is restored as it was
+	if len(tables) != numTables {
+		t.Fatalf("expected %v restored tables, but got %v", numTables, len(tables))
+	}
+	for _, original := range originals {
+		restored := getTestTable(t, original.ID)
+		if len(restored.Players) != len(original.Players) {
+			t.Errorf("expected table %v to have %v players, but it has %v",
+				original.ID, len(original.Players), len(restored.Players))
+		}
+		if restored.Game.Turn != original.Game.Turn {
+			t.Errorf("expected table %v to be on turn %v, but it is on turn %v",
+				original.ID, original.Game.Turn, restored.Game.Turn)
+		}
+	}
+
+	// The tables were loaded at the same time, but never by more than the number of workers
+	if store.maxNumLoading < 2 {
+		t.Error("the tables were not loaded in parallel")
+	}
+	if store.maxNumLoading > 4 {
+		t.Errorf("expected at most 4 tables to be loaded at the same time, but %v were",
+			store.maxNumLoading)
+	}
+}
+
+func TestLoadTablesKeepsOrder(t *testing.T) {
+	resetTestTables(t)
+	store := useTestTableStore(t, false)
+
+	tableIDs := make([]uint64, 0)
+	for i := 0; i < 10; i++ {
+		tableIDs = append(tableIDs, newTestGame(t, 2).ID)
+	}
+	if !serializeTables() {
+		t.Fatal("failed to serialize the tables")
+	}
+
+	// A table that cannot be loaded does not affect the others
+	tableIDs = append(tableIDs[:5], append([]uint64{1000}, tableIDs[5:]...)...)
+
+	loadedTables := loadTables(store, tableIDs, 3)
+	for i, loadedTable := range loadedTables {
+		if tableIDs[i] == 1000 {
+			if loadedTable.Err == nil {
+				t.Error("loading a table that does not exist did not fail")
+			}
+			continue
+		}
+		if loadedTable.Err != nil {
+			t.Errorf("failed to load table %v: %v", tableIDs[i], loadedTable.Err)
+		} else if loadedTable.Table.ID != tableIDs[i] {
+			t.Errorf("expected loaded table %v to be table %v, but it is table %v",
+				i, tableIDs[i], loadedTable.Table.ID)
+		}
+	}
+}
+
+// newTestDatabaseGame creates a game that will be written to the database once it ends
+func newTestDatabaseGame(t *testing.T) *Table {
+	tb := newTestGame(t, 3)
+	tb.ExtraOptions.NoWriteToDatabase = false
+	tb.ExtraOptions.Restarted = true
+	tb.ExtraOptions.SetSeedSuffix = "1"
+	tb.ExtraOptions.CustomNumPlayers = 3
+
+	return tb
+}
+
+func TestRestoreTablesKeepsDatabaseFields(t *testing.T) {
+	resetTestTables(t)
+	useTestTableStore(t, false)
+
+	tb := newTestDatabaseGame(t)
+	clueTestPlayer(t, tb)
+	extraOptions := *tb.ExtraOptions
+	serializeAndRestoreTestTables(t)
+	restored := getTestTable(t, tb.ID)
+	g := restored.Game
+
+	if !reflect.DeepEqual(*restored.ExtraOptions, extraOptions) {
+		t.Errorf("expected the extra options to be %+v, but got %+v",
+			extraOptions, *restored.ExtraOptions)
+	}
+
+	// The game must use the same options as the table,
+	// since the game writes the table to the database when it ends
+	if g.Table != restored || g.ExtraOptions != restored.ExtraOptions {
+		t.Error("the restored game is not linked to the restored table")
+	}
+	if variant := variants[restored.Options.VariantName]; variant.ID != restored.Options.VariantID {
+		t.Errorf("expected a variant ID of %v, but got %v", variant.ID, restored.Options.VariantID)
+	}
+
+	// Every player in the game must map to a seated user
+	for i, gp := range g.Players {
+		p := restored.Players[gp.Index]
+		if p.ID != tb.Players[i].ID || p.Name != gp.Name {
+			t.Errorf("game player %v (%v) is not linked to user %v (%v)",
+				i, gp.Name, tb.Players[i].ID, tb.Players[i].Name)
+		}
+	}
+	if err := validateDatabaseFields(restored); err != nil {
+		t.Error("the restored table has invalid database fields:", err)
+	}
+}
+
+func TestRestoreTablesWritesFinishedGameToDatabase(t *testing.T) {
+	if db == nil {
+		t.Skip("writing a finished game requires a database")
+	}
+	resetTestTables(t)
+	useTestTableStore(t, false)
+
+	tb := newTestDatabaseGame(t)
+	clueTestPlayer(t, tb)
+	serializeAndRestoreTestTables(t)
+	restored := getTestTable(t, tb.ID)
+
+	commandTableTerminate(restored.Players[0].Session, &CommandData{
+		TableID: restored.ID,
+		NoLock:  true,
+	})
+
+	if restored.ExtraOptions.DatabaseID <= 0 {
+		t.Errorf("expected the finished game to have a database ID, but it has %v",
+			restored.ExtraOptions.DatabaseID)
+	}
+}
+
+func TestValidateDatabaseFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(tb *Table)
+	}{
+		{"variant ID", func(tb *Table) { tb.Options.VariantID = 1000 }},
+		{"variant name", func(tb *Table) { tb.Options.VariantName = "Not a Variant" }},
+		{"database ID", func(tb *Table) { tb.ExtraOptions.DatabaseID = 5 }},
+		{"missing player", func(tb *Table) { tb.Players = tb.Players[1:] }},
+		{"user ID", func(tb *Table) { tb.Players[1].ID = 0 }},
+		{"player name", func(tb *Table) { tb.Players[1].Name = "Zelda" }},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resetTestTables(t)
+			tb := newTestDatabaseGame(t)
+			if err := validateDatabaseFields(tb); err != nil {
+				t.Fatal("the table was not valid before it was modified:", err)
+			}
+
+			test.modify(tb)
+			if err := validateDatabaseFields(tb); err == nil {
+				t.Error("the table was valid after it was modified")
+			}
+		})
+	}
+}
+
+func TestValidateDatabaseFieldsSkipsReplays(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestDatabaseGame(t)
+	tb.ExtraOptions.DatabaseID = 5
+	tb.Replay = true
+
+	// Replays are never written to the database
+	if err := validateDatabaseFields(tb); err != nil {
+		t.Error("a replay was not valid:", err)
+	}
+}
+
+// marshalTestTableWithoutTime marshals a table and replaces the time that it was serialized at
+// (which is the only part of the output that changes when the game state does not)
+func marshalTestTableWithoutTime(t *testing.T, tb *Table) []byte {
+	var tableJSON []byte
+	if v, err := marshalTable(tb); err != nil {
+		t.Fatal("failed to marshal the table:", err)
+	} else {
+		tableJSON = v
+	}
+
+	var datetimeJSON []byte
+	if v, err := json.Marshal(tb.Game.DatetimeSerialized); err != nil {
+		t.Fatal("failed to marshal the serialization time:", err)
+	} else {
+		datetimeJSON = v
+	}
+	if !bytes.Contains(tableJSON, datetimeJSON) {
+		t.Fatal("the serialization time is not in the output")
+	}
+
+	return bytes.Replace(tableJSON, datetimeJSON, []byte(`"serialized"`), 1)
+}
+
+func TestMarshalTableIsReproducible(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 3)
+	clueTestPlayer(t, tb)
+	discardTestCard(t, tb)
+
+	// Fill in some of the maps, since their order is random
+	for i := 10; i < 20; i++ {
+		tb.DisconSpectators[i] = struct{}{}
+		tb.ChatRead[i] = i
+		tb.Game.Tags["tag"+strconv.Itoa(i)] = i
+	}
+
+	first := marshalTestTableWithoutTime(t, tb)
+	for i := 0; i < 20; i++ {
+		if tableJSON := marshalTestTableWithoutTime(t, tb); !bytes.Equal(tableJSON, first) {
+			t.Fatalf("serializing the same table twice produced different output:\n%s\n%s",
+				first, tableJSON)
+		}
+	}
+}
+
+func TestGzipBytesIsReproducible(t *testing.T) {
+	data := []byte(strings.Repeat("The same data. ", 100))
+
+	var first []byte
+	if v, err := gzipBytes(data); err != nil {
+		t.Fatal("failed to gzip the data:", err)
+	} else {
+		first = v
+	}
+	if second, err := gzipBytes(data); err != nil {
+		t.Fatal("failed to gzip the data:", err)
+	} else if !bytes.Equal(first, second) {
+		t.Error("gzipping the same data twice produced different output")
+	}
+}
+
+// orderedTableStore is a table store that keeps track of the order that the tables were saved in
+type orderedTableStore struct {
+	*FileTableStore
+	savedIDs []uint64
+}
+
+func (s *orderedTableStore) Save(id uint64, data []byte) (int, error) {
+	s.savedIDs = append(s.savedIDs, id)
+	return s.FileTableStore.Save(id, data)
+}
+
+func TestSerializeTablesInOrder(t *testing.T) {
+	resetTestTables(t)
+	store := &orderedTableStore{
+		FileTableStore: useTestTableStore(t, false),
+	}
+	tableStore = store
+	for i := 0; i < 10; i++ {
+		newTestGame(t, 2)
+	}
+
+	if !serializeTables() {
+		t.Fatal("failed to serialize the tables")
+	}
+
+	if len(store.savedIDs) != 10 {
+		t.Fatalf("expected 10 tables to be saved, but got %v", len(store.savedIDs))
+	}
+	for i := 1; i < len(store.savedIDs); i++ {
+		if store.savedIDs[i-1] >= store.savedIDs[i] {
+			t.Fatalf("the tables were not saved in order of their IDs: %v", store.savedIDs)
+		}
+	}
+}
+
+// reversedTableStore is a table store that lists the tables in descending order and keeps track of
+// the order that the tables were deleted in (which is the order that they were restored in)
+type reversedTableStore struct {
+	*FileTableStore
+	deletedIDs []uint64
+}
+
+func (s *reversedTableStore) List() ([]uint64, error) {
+	tableIDs, err := s.FileTableStore.List()
+	for i, j := 0, len(tableIDs)-1; i < j; i, j = i+1, j-1 {
+		tableIDs[i], tableIDs[j] = tableIDs[j], tableIDs[i]
+	}
+
+	return tableIDs, err
+}
+
+func (s *reversedTableStore) Delete(id uint64) error {
+	s.deletedIDs = append(s.deletedIDs, id)
+	return s.FileTableStore.Delete(id)
+}
+
+func TestRestoreTablesInOrder(t *testing.T) {
+	resetTestTables(t)
+	logs := captureTestLogs(t)
+	store := &reversedTableStore{
+		FileTableStore: useTestTableStore(t, false),
+	}
+	setTestEnv(t, "RESTORE_GRACE_SECONDS", "30")
+	tableIDs := make([]uint64, 0)
+	for i := 0; i < 5; i++ {
+		tableIDs = append(tableIDs, newTestTimedGame(t, 2).ID)
+	}
+	if !serializeTables() {
+		t.Fatal("failed to serialize the tables")
+	}
+	tableStore = store
+	resetTestTables(t)
+	restoreTables()
+
+	if !reflect.DeepEqual(store.deletedIDs, tableIDs) {
+		t.Errorf("expected the tables to be restored in the order of %v, but got %v", tableIDs,
+			store.deletedIDs)
+	}
+
+	// The logs are in the same order
+	i := 0
+	for n := logs.Head(); n != nil; n = n.Next() {
+		msg := n.Record.Message()
+		if !strings.Contains(msg, "Restored table.") {
+			continue
+		}
+		if i >= len(tableIDs) {
+			t.Fatal("there are more restored tables than saved tables")
+		}
+		if v := getTestLogField(msg, "tableID"); v != strconv.FormatUint(tableIDs[i], 10) {
+			t.Errorf("expected restored table %v to be table %v, but got table %v", i,
+				tableIDs[i], v)
+		}
+		i++
+	}
+	if i != len(tableIDs) {
+		t.Errorf("expected %v restored tables to be logged, but got %v", len(tableIDs), i)
+	}
+
+	// Every active player got the grace period exactly once
+	for _, tableID := range tableIDs {
+		restored := getTestTable(t, tableID)
+		restored.Mutex.Lock()
+		base := time.Duration(restored.Options.TimeBase) * time.Second
+		if timeLeft := restored.Game.Players[0].Time; timeLeft != base+30*time.Second {
+			t.Errorf("expected the active player of table %v to have %v, but they have %v",
+				tableID, base+30*time.Second, timeLeft)
+		}
+		restored.Mutex.Unlock()
+	}
+}
+
+func TestRestoreTablesCreatesMissingDirectory(t *testing.T) {
+	resetTestTables(t)
+	store := useTestTableStore(t, false)
+	missingPath := path.Join(store.Path, "does", "not", "exist")
+	tablesPath = missingPath
+	tableStore = NewFileTableStore(missingPath, false)
+
+	restoreTables()
+
+	if len(tables) != 0 {
+		t.Errorf("expected no restored tables, but got %v", len(tables))
+	}
+	if info, err := os.Stat(missingPath); err != nil {
+		t.Error("the missing directory was not created:", err)
+	} else if !info.IsDir() {
+		t.Error("the created path is not a directory")
+	}
+}
+
+// expectTestRestoreTablesFatal restores the tables from the given path in a separate process
+// (since a fatal error exits the process) and checks that it fails with the given message
+func expectTestRestoreTablesFatal(t *testing.T, testName string, tablesDir string, msg string) {
+	if os.Getenv("TEST_RESTORE_TABLES_PATH") != "" {
+		tablesPath = os.Getenv("TEST_RESTORE_TABLES_PATH")
+		tableStore = NewFileTableStore(tablesPath, false)
+		restoreTables()
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^"+testName+"$") // nolint: gosec
+	cmd.Env = append(os.Environ(), "TEST_RESTORE_TABLES_PATH="+tablesDir)
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatal("restoring the tables did not fail")
+	}
+	if !strings.Contains(string(output), msg) {
+		t.Errorf("the output does not contain \"%v\":\n%s", msg, output)
+	}
+}
+
+func TestRestoreTablesFailsOnPermissionDenied(t *testing.T) {
+	if os.Getenv("TEST_RESTORE_TABLES_PATH") == "" && os.Geteuid() == 0 {
+		t.Skip("the root user can read every directory")
+	}
+	dirPath := newTestDir(t)
+	if err := os.Chmod(dirPath, 0); err != nil {
+		t.Fatal("failed to make the directory unreadable:", err)
+	}
+	t.Cleanup(func() {
+		os.Chmod(dirPath, 0755) // nolint: errcheck
+	})
+
+	expectTestRestoreTablesFatal(t, "TestRestoreTablesFailsOnPermissionDenied", dirPath,
+		"Failed to get the list of the saved tables")
+}
+
+func TestRestoreTablesFailsOnUnreadableDirectory(t *testing.T) {
+	// The path exists, but it is not a directory
+	filePath := path.Join(newTestDir(t), "tables")
+	if err := ioutil.WriteFile(filePath, []byte("this is not a directory"), 0600); err != nil {
+		t.Fatal("failed to write the file:", err)
+	}
+
+	expectTestRestoreTablesFatal(t, "TestRestoreTablesFailsOnUnreadableDirectory", filePath,
+		"Failed to get the list of the saved tables")
+}
+
+func TestSerializeTablesContinuesAfterMarshalFailure(t *testing.T) {
+	resetTestTables(t)
+	store := useTestTableStore(t, false)
+	tb1 := newTestGame(t, 2)
+	tb2 := newTestGame(t, 2)
+
+	// A channel cannot be represented in JSON
+	tb1.Game.Actions = append(tb1.Game.Actions, make(chan struct{}))
+	if serializeTables() {
+		t.Error("the serialization succeeded even though a table could not be marshaled")
+	}
+
+	if _, err := store.Load(tb1.ID); !os.IsNotExist(err) {
+		t.Error("the table that failed to marshal was saved")
+	}
+	if !tb1.DatetimeBaseSerialized.IsZero() {
+		t.Error("the table that failed to marshal was marked as serialized")
+	}
+	if _, err := store.Load(tb2.ID); err != nil {
+		t.Error("the other table was not saved:", err)
+	}
+
+	// The lock of the table that failed must have been released
+	if !lockWithTimeout(&tb1.Mutex, time.Second) {
+		t.Fatal("the table that failed to marshal was left locked")
+	}
+	tb1.Mutex.Unlock()
+}
+
+func TestSerializeTablesPretty(t *testing.T) {
+	resetTestTables(t)
+	store := useTestMemoryTableStore(t)
+	oldSerializePretty := serializePretty
+	t.Cleanup(func() {
+		serializePretty = oldSerializePretty
+	})
+
+	tb := newTestGame(t, 3)
+	clueTestPlayer(t, tb)
+	discardTestCard(t, tb)
+
+	loaded := make([]*Table, 0)
+	for _, pretty := range []bool{false, true} {
+		serializePretty = pretty
+		tb.DatetimeBaseSerialized = time.Time{}
+		if !serializeTables() {
+			t.Fatal("failed to serialize the tables")
+		}
+
+		data := store.tables[tb.ID]
+		if isPretty := bytes.Contains(data, []byte("\n  ")); isPretty != pretty {
+			t.Errorf("expected the table to be indented to be %v, but it was %v: %s",
+				pretty, isPretty, data)
+		}
+
+		if v, err := loadTable(store, tb.ID); err != nil {
+			t.Fatal("failed to load the table:", err)
+		} else {
+			v.Game.DatetimeSerialized = time.Time{}
+			loaded = append(loaded, v)
+		}
+	}
+
+	// Both formats are restored to the same table
+	var compactJSON []byte
+	var prettyJSON []byte
+	if v, err := json.Marshal(loaded[0]); err != nil {
+		t.Fatal("failed to marshal the table:", err)
+	} else {
+		compactJSON = v
+	}
+	if v, err := json.Marshal(loaded[1]); err != nil {
+		t.Fatal("failed to marshal the table:", err)
+	} else {
+		prettyJSON = v
+	}
+	if !bytes.Equal(compactJSON, prettyJSON) {
+		t.Errorf("the indented table was not restored in the same way as the compact table:\n"+
+			"%s\n%s", compactJSON, prettyJSON)
+	}
+	if len(loaded[1].Game.Actions) != len(tb.Game.Actions) {
+		t.Errorf("expected %v actions to be restored, but got %v",
+			len(tb.Game.Actions), len(loaded[1].Game.Actions))
+	}
+}
+
+// mutateTestTable takes game actions, adds spectators, and sends chat messages until the game is
+// over or the given number of actions have been taken
+// (it is meant to be run in a separate goroutine, so it takes the table lock in the same way as
+// the real commands do)
+func mutateTestTable(tb *Table, numActions int) error {
+	for i := 0; i < numActions; i++ {
+		tb.Mutex.Lock()
+		g := tb.Game
+		if g.EndCondition > EndConditionInProgress {
+			tb.Mutex.Unlock()
+			return nil
+		}
+		gp := g.Players[g.ActivePlayerIndex]
+		target := (gp.Index + 1) % len(g.Players)
+		d := &CommandData{ // Manual invocation
+			TableID: tb.ID,
+			Type:    ActionTypeDiscard,
+			Target:  gp.Hand[0].Order,
+			NoLock:  true,
+		}
+		if i%2 == 0 {
+			d.Type = ActionTypeRankClue
+			d.Target = target
+			d.Value = g.Players[target].Hand[0].Rank
+		}
+		commandAction(tb.Players[gp.Index].Session, d)
+		invalid := g.InvalidActionOccurred
+
+		// Add a spectator (the spectator map is one of the nested fields that is serialized)
+		sp := &Spectator{
+			ID:      100 + i,
+			Name:    "Spectator " + strconv.Itoa(i),
+			Session: newTestSession(100+i, "Spectator "+strconv.Itoa(i)),
+		}
+		tb.Spectators = append(tb.Spectators, sp)
+		chatServerSend("Message "+strconv.Itoa(i), tb.GetRoomName())
+		tb.Mutex.Unlock()
+
+		if invalid {
+			return errors.New("action " + strconv.Itoa(i) + " was not valid")
+		}
+	}
+
+	return nil
+}
+
+func TestSerializeTablesWhileMutating(t *testing.T) {
+	resetTestTables(t)
+	store := useTestMemoryTableStore(t)
+	tb := newTestGame(t, 3)
+
+	done := make(chan error)
+	go func() {
+		done <- mutateTestTable(tb, 30)
+	}()
+
+	// The race detector will catch any field that is not covered by the table lock
+	// (e.g. "go test -race -run TestSerializeTablesWhileMutating")
+	mutating := true
+	for mutating {
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Error("failed to mutate the table:", err)
+			}
+			mutating = false
+		default:
+			if !serializeTables() {
+				t.Fatal("failed to serialize the tables")
+			}
+		}
+	}
+
+	// The last snapshot is a consistent copy of the final state of the table
+	if !serializeTables() {
+		t.Fatal("failed to serialize the tables")
+	}
+	restored, err := loadTable(store, tb.ID)
+	if err != nil {
+		t.Fatal("failed to load the table:", err)
+	}
+	if restored.Game.Turn != tb.Game.Turn || len(restored.Game.Actions) != len(tb.Game.Actions) ||
+		len(restored.DisconSpectators) != len(tb.Spectators) || len(restored.Chat) != len(tb.Chat) {
+
+		t.Error("the serialized table does not match the final state of the table")
+	}
+}
+
+// getTestLogField returns the value of a field of a log message
+// (or an empty string if the message does not have the field)
+func getTestLogField(msg string, key string) string {
+	start := strings.Index(msg, key+"=")
+	if start == -1 {
+		return ""
+	}
+	value := msg[start+len(key)+1:]
+	if end := strings.IndexAny(value, " )"); end != -1 {
+		value = value[:end]
+	}
+
+	return value
+}
+
+func TestSerializeTablesLogsSizes(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		resetTestTables(t)
+		store := useTestTableStore(t, compress)
+		tb1 := newTestGame(t, 2)
+		tb2 := newTestGame(t, 3)
+		clueTestPlayer(t, tb2)
+
+		logs := captureTestLogs(t)
+		if !serializeTables() {
+			t.Fatal("failed to serialize the tables")
+		}
+
+		totalBytes := 0
+		totalUncompressedBytes := 0
+		for _, tb := range []*Table{tb1, tb2} {
+			msg := ""
+			for n := logs.Head(); n != nil; n = n.Next() {
+				if v := n.Record.Message(); strings.Contains(v, "Serialized table.") &&
+					getTestLogField(v, "tableID") == strconv.FormatUint(tb.ID, 10) {
+
+					msg = v
+				}
+			}
+
+			var data []byte
+			tablePath := path.Join(store.Path, store.getFilename(tb.ID, compress))
+			if v, err := ioutil.ReadFile(tablePath); err != nil {
+				t.Fatal("failed to read the serialized table:", err)
+			} else {
+				data = v
+			}
+			if v := getTestLogField(msg, "bytes"); v != strconv.Itoa(len(data)) {
+				t.Errorf("expected the size of table %v to be logged as %v bytes, but got: %v",
+					tb.ID, len(data), msg)
+			}
+			totalBytes += len(data)
+
+			// Compressed tables also report the uncompressed size and the ratio between them
+			if !compress {
+				if v := getTestLogField(msg, "uncompressedBytes"); v != "" {
+					t.Errorf("an uncompressed size was logged for an uncompressed table: %v", msg)
+				}
+				continue
+			}
+			uncompressed, err := gunzipBytes(data)
+			if err != nil {
+				t.Fatal("failed to decompress the serialized table:", err)
+			}
+			uncompressedBytes := strconv.Itoa(len(uncompressed))
+			if v := getTestLogField(msg, "uncompressedBytes"); v != uncompressedBytes {
+				t.Errorf("expected the uncompressed size of table %v to be logged as %v bytes, "+
+					"but got: %v", tb.ID, uncompressedBytes, msg)
+			}
+			ratio := getCompressionRatio(len(data), len(uncompressed))
+			if v := getTestLogField(msg, "compressionRatio"); v != ratio {
+				t.Errorf("expected the compression ratio of table %v to be %v, but got: %v",
+					tb.ID, ratio, msg)
+			}
+			totalUncompressedBytes += len(uncompressed)
+		}
+
+		msg := findTestLog(logs, logging.INFO, "Finished serializing the tables.")
+		if getTestLogField(msg, "tables") != "2" ||
+			getTestLogField(msg, "bytes") != strconv.Itoa(totalBytes) {
+
+			t.Errorf("expected a total of 2 tables and %v bytes to be logged, but got: %v",
+				totalBytes, msg)
+		}
+		if compress &&
+			getTestLogField(msg, "uncompressedBytes") != strconv.Itoa(totalUncompressedBytes) {
+
+			t.Errorf("expected a total of %v uncompressed bytes to be logged, but got: %v",
+				totalUncompressedBytes, msg)
+		}
+	}
+}
+
+func TestGetCompressionRatio(t *testing.T) {
+	for _, c := range []struct {
+		compressedBytes   int
+		uncompressedBytes int
+		expected          string
+	}{
+		{25, 100, "0.25"},
+		{100, 100, "1.00"},
+		{1, 3, "0.33"},
+		{0, 0, "0"},
+	} {
+		if v := getCompressionRatio(c.compressedBytes, c.uncompressedBytes); v != c.expected {
+			t.Errorf("expected a ratio of %v for %v / %v bytes, but got %v", c.expected,
+				c.compressedBytes, c.uncompressedBytes, v)
+		}
+	}
+}
@@ -4,12 +4,27 @@ package main
 
 import (
 	"encoding/json"
+	"strconv"
 
 	melody "gopkg.in/olahol/melody.v1"
 )
 
 type Session struct {
 	*melody.Session
+
+	// If this is set, messages are collected instead of being sent immediately
+	// (see the "NewBatch()" function)
+	Batch *MessageBatch
+}
+
+// MessageBatch is a list of messages that will be sent to a client all at once
+type MessageBatch struct {
+	Messages []*BatchedMessage
+}
+
+type BatchedMessage struct {
+	Command string          `json:"command"`
+	Data    json.RawMessage `json:"data"`
 }
 
 // Emit sends a message to a client using the Golem-style protocol described above
@@ -27,16 +42,127 @@ func (s *Session) Emit(command string, d interface{}) {
 		ds = string(dj)
 	}
 
+	// If we are batching messages, send it later
+	if s.Batch != nil {
+		s.Batch.Messages = append(s.Batch.Messages, &BatchedMessage{
+			Command: command,
+			Data:    json.RawMessage(ds),
+		})
+		return
+	}
+
+	s.emitRaw(command, ds)
+}
+
+func (s *Session) emitRaw(command string, ds string) {
+	// This client is not keeping up and is being disconnected,
+	// so there is no point in sending them anything else
+	if s.SendBufferFull() {
+		return
+	}
+
+	s.RecordTrace(false, command, ds)
+
 	// Send the message as bytes
 	msg := command + " " + ds
 	bytes := []byte(msg)
+
+	// Melody only enforces the maximum message size on incoming messages,
+	// so log oversized outgoing messages to make truncation problems easier to diagnose
+	if int64(len(bytes)) > websocketMaxMessageSize {
+		logger.WarningWithFields(LogFields{
+			"command": command,
+			"bytes":   len(bytes),
+		}, "The outgoing \""+command+"\" message for user \""+s.Username()+"\" is "+
+			strconv.Itoa(len(bytes))+" bytes, which exceeds the maximum WebSocket message "+
+			"size of "+strconv.FormatInt(websocketMaxMessageSize, 10)+" bytes.")
+	}
 	if err := s.Write(bytes); err != nil {
 		// This can routinely fail if the session is closed, so just return
 		return
 	}
 }
 
+// NewBatch returns a copy of the session that collects every message emitted to it
+// Call "EmitBatch()" on the copy to send all of the collected messages to the client
+func (s *Session) NewBatch() *Session {
+	return &Session{
+		Session: s.Session,
+		Batch: &MessageBatch{
+			Messages: make([]*BatchedMessage, 0),
+		},
+	}
+}
+
+// EmitBatch sends all of the collected messages to the client as one "initBatch" message so that
+// joining a game does not take many round-trips on high-latency connections
+// If the combined message would exceed the maximum WebSocket message size,
+// the messages are split up over multiple "initBatch" messages
+// (with "final" only being true on the last one)
+func (s *Session) EmitBatch(tableID uint64) {
+	if s.Batch == nil {
+		return
+	}
+	messages := s.Batch.Messages
+	s.Batch = nil
+
+	type InitBatchMessage struct {
+		TableID  uint64            `json:"tableID"`
+		Messages []*BatchedMessage `json:"messages"`
+		Final    bool              `json:"final"`
+	}
+
+	// Leave some room for the rest of the message
+	// (e.g. the command name, the table ID, and the JSON syntax)
+	maxSize := int(websocketMaxMessageSize) - 128
+
+	chunk := make([]*BatchedMessage, 0)
+	chunkSize := 0
+	for _, message := range messages {
+		// Measure the message as it will actually be sent,
+		// since marshaling escapes HTML characters (which can make the data larger)
+		var messageSize int
+		if mj, err := json.Marshal(message); err != nil {
+			logger.Error("Failed to marshal a batched \""+message.Command+"\" message:", err)
+			continue
+		} else {
+			messageSize = len(mj) + 1 // The comma between the messages
+		}
+		if chunkSize+messageSize > maxSize && len(chunk) > 0 {
+			s.Emit("initBatch", &InitBatchMessage{
+				TableID:  tableID,
+				Messages: chunk,
+				Final:    false,
+			})
+			chunk = make([]*BatchedMessage, 0)
+			chunkSize = 0
+		}
+
+		// A message that is too large to fit in a batch is sent by itself
+		// (the "emitRaw()" function will log an error about it)
+		if messageSize > maxSize {
+			s.emitRaw(message.Command, string(message.Data))
+			continue
+		}
+
+		chunk = append(chunk, message)
+		chunkSize += messageSize
+	}
+
+	s.Emit("initBatch", &InitBatchMessage{
+		TableID:  tableID,
+		Messages: chunk,
+		Final:    true,
+	})
+}
+
 func (s *Session) Warning(message string) {
+	s.WarningWithCode("", message)
+}
+
+// WarningWithCode is the same as "Warning()", but the client is also sent one of the error codes
+// listed in "constants.go"
+func (s *Session) WarningWithCode(code string, message string) {
 	// Specify a default warning message
 	if message == "" {
 		message = DefaultErrorMsg
@@ -46,14 +172,28 @@ func (s *Session) Warning(message string) {
 
 	type WarningMessage struct {
 		Warning string `json:"warning"`
+		Code    string `json:"code,omitempty"`
 	}
 	s.Emit("warning", &WarningMessage{
 		message,
+		code,
 	})
 }
 
+// CloseWithCode closes the WebSocket connection with one of the close codes listed in
+// "constants.go"
+func (s *Session) CloseWithCode(code int, reason string) error {
+	return s.CloseWithMsg(melody.FormatCloseMessage(code, reason))
+}
+
 // Sent to the client if either their command was unsuccessful or something else went wrong
 func (s *Session) Error(message string) {
+	s.ErrorWithCode("", message)
+}
+
+// ErrorWithCode is the same as "Error()", but the client is also sent one of the error codes
+// listed in "constants.go"
+func (s *Session) ErrorWithCode(code string, message string) {
 	// Specify a default error message
 	if message == "" {
 		message = DefaultErrorMsg
@@ -63,9 +203,11 @@ func (s *Session) Error(message string) {
 
 	type ErrorMessage struct {
 		Error string `json:"error"`
+		Code  string `json:"code,omitempty"`
 	}
 	s.Emit("error", &ErrorMessage{
 		message,
+		code,
 	})
 }
 
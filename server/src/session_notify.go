@@ -176,6 +176,20 @@ func (s *Session) NotifyShutdown() {
 	})
 }
 
+// NotifyAnnouncement sends a server-wide announcement from an administrator
+// It is sent as a warning so that the client will display it in the same way,
+// along with an optional countdown (in seconds) that the client can display
+func (s *Session) NotifyAnnouncement(msg string, countdown int) {
+	type AnnouncementMessage struct {
+		Warning   string `json:"warning"`
+		Countdown int    `json:"countdown,omitempty"`
+	}
+	s.Emit("warning", &AnnouncementMessage{
+		Warning:   msg,
+		Countdown: countdown,
+	})
+}
+
 func (s *Session) NotifyMaintenance() {
 	type MaintenanceMessage struct {
 		MaintenanceMode bool `json:"maintenanceMode"`
@@ -219,7 +233,7 @@ func (s *Session) NotifyConnected(t *Table) {
 }
 
 func (s *Session) NotifyGameAction(t *Table, action interface{}) {
-	scrubbedAction := CheckScrub(t, action, s.UserID())
+	scrubbedAction := CheckScrubForSession(s, t, action)
 
 	type GameActionMessage struct {
 		TableID uint64      `json:"tableID"`
@@ -236,13 +250,9 @@ func (s *Session) NotifyTime(t *Table) {
 
 	// Create the clock message
 	times := make([]int64, 0)
-	for i, p := range g.Players {
+	for i := range g.Players {
 		// We could be sending the message in the middle of someone's turn, so account for this
-		timeLeft := p.Time
-		if g.ActivePlayerIndex == i {
-			elapsedTime := time.Since(g.DatetimeTurnBegin)
-			timeLeft -= elapsedTime
-		}
+		timeLeft := g.GetTimeLeft(i)
 
 		// JavaScript expects time in milliseconds
 		milliseconds := int64(timeLeft / time.Millisecond)
@@ -279,6 +289,21 @@ func (s *Session) NotifyPause(t *Table) {
 	})
 }
 
+func (s *Session) NotifySharedReview(t *Table) {
+	g := t.Game
+
+	type SharedReviewMessage struct {
+		TableID uint64 `json:"tableID"`
+		Active  bool   `json:"active"`
+		Turn    int    `json:"turn"`
+	}
+	s.Emit("sharedReview", &SharedReviewMessage{
+		TableID: t.ID,
+		Active:  g.Reviewing,
+		Turn:    g.ReviewTurn,
+	})
+}
+
 func (s *Session) NotifySpectators(t *Table) {
 	type SpectatorsMessage struct {
 		TableID    uint64       `json:"tableID"`
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	logging "github.com/Zamiell/go-logging"
+	"github.com/gorilla/websocket"
+)
+
+func TestEmitWarnsAboutOversizedMessages(t *testing.T) {
+	s, conn := newTestWebsocket(t, 1, "Alice", 0)
+	backend := captureTestLogs(t)
+
+	msg := strings.Repeat("a", int(websocketMaxMessageSize))
+	s.Emit("chat", msg)
+
+	// The message is still sent, since the client might be able to handle it
+	if data := readTestCommand(t, conn, "chat"); len(data) != len(msg)+2 {
+		t.Errorf("expected the client to receive %v bytes of data, but got %v",
+			len(msg)+2, len(data))
+	}
+
+	size := strconv.Itoa(len("chat ") + len(msg) + 2)
+	if log := findTestLog(backend, logging.WARNING, "\"chat\""); log == "" {
+		t.Error("a warning was not logged for the oversized message")
+	} else if !strings.Contains(log, size) {
+		t.Errorf("the warning does not contain the size of %v bytes: %v", size, log)
+	}
+	if log := findTestLog(backend, logging.ERROR, "\"chat\""); log != "" {
+		t.Error("the oversized message was logged as an error: " + log)
+	}
+}
+
+func TestEmitDoesNotWarnAboutSmallMessages(t *testing.T) {
+	s, conn := newTestWebsocket(t, 1, "Alice", 0)
+	backend := captureTestLogs(t)
+
+	s.Emit("chat", "hello")
+	readTestCommand(t, conn, "chat")
+
+	if log := findTestLog(backend, logging.WARNING, "\"chat\""); log != "" {
+		t.Error("a warning was logged for a small message: " + log)
+	}
+}
+
+func TestEmitBatchChunksEscapedMessages(t *testing.T) {
+	s, conn := newTestWebsocket(t, 1, "Alice", 0)
+	oldWebsocketMaxMessageSize := websocketMaxMessageSize
+	websocketMaxMessageSize = 1000
+	t.Cleanup(func() {
+		websocketMaxMessageSize = oldWebsocketMaxMessageSize
+	})
+
+	// Every "<" becomes "\u003c" when the batch is marshaled,
+	// so the messages are much larger when they are sent than when they are collected
+	const numMessages = 10
+	bs := s.NewBatch()
+	for i := 0; i < numMessages; i++ {
+		bs.Batch.Messages = append(bs.Batch.Messages, &BatchedMessage{
+			Command: "chat",
+			Data:    json.RawMessage("\"" + strings.Repeat("<", 100) + "\""),
+		})
+	}
+	bs.EmitBatch(1)
+
+	received := 0
+	for {
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second)) // nolint: errcheck
+		var msg []byte
+		if _, v, err := conn.ReadMessage(); err != nil {
+			t.Fatal("failed to read a message from the test WebSocket session:", err)
+		} else {
+			msg = v
+		}
+		if int64(len(msg)) > websocketMaxMessageSize {
+			t.Errorf("a batch of %v bytes exceeds the maximum message size of %v bytes",
+				len(msg), websocketMaxMessageSize)
+		}
+
+		parts := strings.SplitN(string(msg), " ", 2)
+		if parts[0] != "initBatch" {
+			t.Fatalf("expected an \"initBatch\" message, but got: %v", parts[0])
+		}
+		var batch struct {
+			Messages []*BatchedMessage `json:"messages"`
+			Final    bool              `json:"final"`
+		}
+		if err := json.Unmarshal([]byte(parts[1]), &batch); err != nil {
+			t.Fatal("failed to unmarshal the \"initBatch\" message:", err)
+		}
+		received += len(batch.Messages)
+		if batch.Final {
+			break
+		}
+	}
+
+	if received != numMessages {
+		t.Errorf("expected %v batched messages, but got %v", numMessages, received)
+	}
+}
+
+// expectTestWarningCode reads messages until it finds a warning and fails the test if the warning
+// does not have the given code
+func expectTestWarningCode(t *testing.T, conn *websocket.Conn, code string) {
+	data := readTestCommand(t, conn, "warning")
+	var msg struct {
+		Warning string `json:"warning"`
+		Code    string `json:"code"`
+	}
+	if err := json.Unmarshal([]byte(data), &msg); err != nil {
+		t.Fatal("failed to unmarshal the warning:", err)
+	}
+	if msg.Code != code {
+		t.Errorf("expected a warning with the code of \"%v\", but got: %v", code, data)
+	}
+	if msg.Warning == "" {
+		t.Errorf("the warning does not have a message: %v", data)
+	}
+}
+
+func TestWarningWithCode(t *testing.T) {
+	s, conn := newTestWebsocket(t, 1, "Alice", 0)
+
+	s.WarningWithCode(ErrTableFull, "That table is already full.")
+	expectTestWarningCode(t, conn, ErrTableFull)
+
+	// Warnings without a code do not have the field at all
+	s.Warning("Something happened.")
+	if data := readTestCommand(t, conn, "warning"); strings.Contains(data, "code") {
+		t.Errorf("a warning without a code was sent with one: %v", data)
+	}
+}
+
+func TestErrorWithCode(t *testing.T) {
+	s, conn := newTestWebsocket(t, 1, "Alice", 0)
+
+	s.ErrorWithCode(ErrRateLimited, "Slow down.")
+	data := readTestCommand(t, conn, "error")
+	var msg struct {
+		Error string `json:"error"`
+		Code  string `json:"code"`
+	}
+	if err := json.Unmarshal([]byte(data), &msg); err != nil {
+		t.Fatal("failed to unmarshal the error:", err)
+	}
+	if msg.Code != ErrRateLimited || msg.Error != "Slow down." {
+		t.Errorf("the error was sent with the wrong data: %v", data)
+	}
+}
+
+func TestRejectionCodes(t *testing.T) {
+	resetTestTables(t)
+
+	// Acting out of turn
+	tb := newTestGame(t, 2)
+	conns := connectTestPlayers(t, tb)
+	commandAction(tb.Players[1].Session, &CommandData{
+		TableID: tb.ID,
+		Type:    ActionTypeColorClue,
+		Target:  0,
+		Value:   0,
+		NoLock:  true,
+	})
+	expectTestWarningCode(t, conns[1], ErrNotYourTurn)
+
+	// Giving a clue to yourself
+	commandAction(tb.Players[0].Session, &CommandData{
+		TableID: tb.ID,
+		Type:    ActionTypeColorClue,
+		Target:  0,
+		Value:   0,
+		NoLock:  true,
+	})
+	expectTestWarningCode(t, conns[0], ErrInvalidClue)
+
+	// Joining a table that does not exist
+	s, conn := newTestWebsocket(t, 10, "Dan", 0)
+	commandTableJoin(s, &CommandData{
+		TableID: 1000,
+	})
+	expectTestWarningCode(t, conn, ErrTableNotFound)
+
+	// Joining a full table
+	full := newTestTable(t, 6)
+	commandTableJoin(s, &CommandData{
+		TableID: full.ID,
+		NoLock:  true,
+	})
+	expectTestWarningCode(t, conn, ErrTableFull)
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// The maximum number of messages that are kept for a traced session
+	// (older messages are overwritten once the limit is reached)
+	MaxTraceEntries = 500
+
+	// The data of each traced message is truncated to this many characters
+	MaxTraceDataLength = 2000
+)
+
+var (
+	// The number of sessions that are currently being traced
+	// This allows "RecordTrace()" to return immediately in the normal case where nobody is being
+	// traced (without having to look up any session values)
+	numTracedSessions int32
+
+	// Used to prevent races when starting and stopping traces
+	sessionTraceMutex sync.Mutex
+)
+
+// SessionTrace is a ring buffer of the messages sent to and from a specific session
+// (for administrators to debug problems that a specific user is having)
+type SessionTrace struct {
+	Mutex     sync.Mutex
+	Entries   []*TraceEntry
+	NextIndex int // The index that will be written to once the buffer is full
+}
+
+type TraceEntry struct {
+	Datetime time.Time
+	Incoming bool // True if the message was sent by the client, false if it was sent by the server
+	Command  string
+	Data     string
+}
+
+// StartTrace begins recording the messages for the session
+// It has no effect if the session is already being traced
+func (s *Session) StartTrace() {
+	sessionTraceMutex.Lock()
+	defer sessionTraceMutex.Unlock()
+
+	if s.Trace() != nil {
+		return
+	}
+
+	s.Set("trace", &SessionTrace{
+		Entries: make([]*TraceEntry, 0),
+	})
+	atomic.AddInt32(&numTracedSessions, 1)
+}
+
+// StopTrace stops recording the messages for the session and discards the recorded messages
+func (s *Session) StopTrace() {
+	sessionTraceMutex.Lock()
+	defer sessionTraceMutex.Unlock()
+
+	if s.Trace() == nil {
+		return
+	}
+
+	s.Set("trace", (*SessionTrace)(nil))
+	atomic.AddInt32(&numTracedSessions, -1)
+}
+
+// Trace returns nil if the session is not being traced
+func (s *Session) Trace() *SessionTrace {
+	if v, exists := s.Get("trace"); !exists {
+		return nil
+	} else if trace, ok := v.(*SessionTrace); !ok {
+		return nil
+	} else {
+		return trace
+	}
+}
+
+// RecordTrace adds a message to the trace of the session, if the session is being traced
+func (s *Session) RecordTrace(incoming bool, command string, data string) {
+	if atomic.LoadInt32(&numTracedSessions) == 0 {
+		return
+	}
+
+	trace := s.Trace()
+	if trace == nil {
+		return
+	}
+
+	if len(data) > MaxTraceDataLength {
+		data = data[:MaxTraceDataLength] + "..."
+	}
+	trace.Add(&TraceEntry{
+		Datetime: time.Now(),
+		Incoming: incoming,
+		Command:  command,
+		Data:     data,
+	})
+}
+
+func (st *SessionTrace) Add(entry *TraceEntry) {
+	st.Mutex.Lock()
+	defer st.Mutex.Unlock()
+
+	if len(st.Entries) < MaxTraceEntries {
+		st.Entries = append(st.Entries, entry)
+		return
+	}
+
+	st.Entries[st.NextIndex] = entry
+	st.NextIndex = (st.NextIndex + 1) % MaxTraceEntries
+}
+
+// GetEntries returns a copy of the recorded messages, from oldest to newest
+func (st *SessionTrace) GetEntries() []*TraceEntry {
+	st.Mutex.Lock()
+	defer st.Mutex.Unlock()
+
+	entries := make([]*TraceEntry, 0, len(st.Entries))
+	entries = append(entries, st.Entries[st.NextIndex:]...)
+	entries = append(entries, st.Entries[:st.NextIndex]...)
+
+	return entries
+}
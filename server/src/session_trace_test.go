@@ -0,0 +1,170 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func startTestTrace(t *testing.T, s *Session) *SessionTrace {
+	s.StartTrace()
+	t.Cleanup(s.StopTrace)
+
+	trace := s.Trace()
+	if trace == nil {
+		t.Fatal("the session is not being traced")
+	}
+	return trace
+}
+
+func TestSessionTraceRecordsMessages(t *testing.T) {
+	s, conn := newTestWebsocket(t, 1, "Alice", 0)
+	trace := startTestTrace(t, s)
+
+	websocketMessage(s.Session, []byte(`testTrace {"tableID":5}`))
+	s.Warning("Something happened.")
+	readTestCommand(t, conn, "warning")
+
+	entries := trace.GetEntries()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 traced messages, but got %v", len(entries))
+	}
+	if !entries[0].Incoming || entries[0].Command != "testTrace" ||
+		entries[0].Data != `{"tableID":5}` {
+
+		t.Errorf("the incoming message was not traced correctly: %+v", entries[0])
+	}
+	if entries[1].Incoming || entries[1].Command != "warning" ||
+		!strings.Contains(entries[1].Data, "Something happened.") {
+
+		t.Errorf("the outgoing message was not traced correctly: %+v", entries[1])
+	}
+}
+
+func TestSessionTraceDisabled(t *testing.T) {
+	s, conn := newTestWebsocket(t, 1, "Alice", 0)
+	if atomic.LoadInt32(&numTracedSessions) != 0 {
+		t.Fatal("a session is already being traced")
+	}
+
+	s.Warning("Something happened.")
+	readTestCommand(t, conn, "warning")
+	if s.Trace() != nil {
+		t.Fatal("a trace was created for a session that is not being traced")
+	}
+
+	// Stopping a trace discards the recorded messages
+	startTestTrace(t, s)
+	s.RecordTrace(true, "testTrace", "{}")
+	s.StopTrace()
+	if s.Trace() != nil {
+		t.Error("the trace was not discarded")
+	}
+	if v := atomic.LoadInt32(&numTracedSessions); v != 0 {
+		t.Errorf("expected no sessions to be traced, but %v are", v)
+	}
+
+	// Only the traced session records anything
+	s2, _ := newTestWebsocket(t, 2, "Bob", 0)
+	trace2 := startTestTrace(t, s2)
+	s.RecordTrace(true, "testTrace", "{}")
+	if len(trace2.GetEntries()) != 0 {
+		t.Error("a message for another session was recorded")
+	}
+}
+
+func TestSessionTraceWraps(t *testing.T) {
+	s, _ := newTestWebsocket(t, 1, "Alice", 0)
+	trace := startTestTrace(t, s)
+
+	for i := 0; i < MaxTraceEntries+10; i++ {
+		s.RecordTrace(true, "testTrace", strconv.Itoa(i))
+	}
+
+	// Only the newest messages are kept, from oldest to newest
+	entries := trace.GetEntries()
+	if len(entries) != MaxTraceEntries {
+		t.Fatalf("expected %v traced messages, but got %v", MaxTraceEntries, len(entries))
+	}
+	for i, entry := range entries {
+		if expected := strconv.Itoa(i + 10); entry.Data != expected {
+			t.Fatalf("expected traced message %v to be %v, but got %v", i, expected, entry.Data)
+		}
+	}
+
+	// Long messages are truncated
+	s.RecordTrace(true, "testTrace", strings.Repeat("a", MaxTraceDataLength*2))
+	entries = trace.GetEntries()
+	if v := entries[len(entries)-1].Data; len(v) != MaxTraceDataLength+len("...") {
+		t.Errorf("expected the message to be truncated, but it has a length of %v", len(v))
+	}
+}
+
+func TestHttpLocalhostTraceSession(t *testing.T) {
+	resetTestTables(t)
+	gin.SetMode(gin.TestMode)
+	s, _ := newTestWebsocket(t, 1, "Alice", 0)
+	sessionsMutex.Lock()
+	sessions[s.UserID()] = s
+	sessionsMutex.Unlock()
+	t.Cleanup(s.StopTrace)
+
+	traceSession := func(userID string, enabled string) *httptest.ResponseRecorder {
+		form := url.Values{}
+		form.Set("userID", userID)
+		form.Set("enabled", enabled)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(
+			http.MethodPost,
+			"/traceSession",
+			strings.NewReader(form.Encode()),
+		)
+		c.Request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		httpLocalhostTraceSession(c)
+		return w
+	}
+	dumpTrace := func(userID string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/dumpTrace?userID="+userID, nil)
+		httpLocalhostDumpTrace(c)
+		return w
+	}
+
+	if w := dumpTrace("1"); w.Code != http.StatusBadRequest {
+		t.Errorf("expected a status code of %v for a session that is not being traced, "+
+			"but got %v", http.StatusBadRequest, w.Code)
+	}
+	if w := traceSession("2", "true"); w.Code != http.StatusBadRequest {
+		t.Errorf("expected a status code of %v for a user who is not online, but got %v",
+			http.StatusBadRequest, w.Code)
+	}
+	if w := traceSession("1", "maybe"); w.Code != http.StatusBadRequest {
+		t.Errorf("expected a status code of %v for an invalid enabled value, but got %v",
+			http.StatusBadRequest, w.Code)
+	}
+
+	if w := traceSession("1", "true"); w.Code != http.StatusOK {
+		t.Fatalf("expected a status code of %v, but got %v: %v", http.StatusOK, w.Code, w.Body)
+	}
+	websocketMessage(s.Session, []byte(`testTrace {"tableID":5}`))
+	if w := dumpTrace("1"); w.Code != http.StatusOK {
+		t.Fatalf("expected a status code of %v, but got %v: %v", http.StatusOK, w.Code, w.Body)
+	} else if !strings.Contains(w.Body.String(), `> testTrace {"tableID":5}`) {
+		t.Errorf("the traced message was not dumped: %v", w.Body)
+	}
+
+	if w := traceSession("1", "false"); w.Code != http.StatusOK {
+		t.Fatalf("expected a status code of %v, but got %v: %v", http.StatusOK, w.Code, w.Body)
+	}
+	if s.Trace() != nil {
+		t.Error("the trace was not stopped")
+	}
+}
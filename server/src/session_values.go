@@ -147,6 +147,20 @@ func (s *Session) Inactive() bool {
 	}
 }
 
+func (s *Session) DatetimeInactive() time.Time {
+	if s == nil {
+		logger.Error("The \"DatetimeInactive\" method was called for a nil session.")
+		return time.Now()
+	}
+
+	if v, exists := s.Get("datetimeInactive"); !exists {
+		logger.Error("Failed to get \"datetimeInactive\" from a session.")
+		return time.Now()
+	} else {
+		return v.(time.Time)
+	}
+}
+
 func (s *Session) FakeUser() bool {
 	if s == nil {
 		logger.Error("The \"FakeUser\" method was called for a nil session.")
@@ -164,7 +178,7 @@ func (s *Session) FakeUser() bool {
 func (s *Session) RateLimitAllowance() float64 {
 	if s == nil {
 		logger.Error("The \"RateLimitAllowance\" method was called for a nil session.")
-		return RateLimitRate
+		return rateLimitBurst
 	}
 
 	if v, exists := s.Get("rateLimitAllowance"); !exists {
@@ -189,6 +203,20 @@ func (s *Session) RateLimitLastCheck() time.Time {
 	}
 }
 
+func (s *Session) RateLimitViolations() int {
+	if s == nil {
+		logger.Error("The \"RateLimitViolations\" method was called for a nil session.")
+		return 0
+	}
+
+	if v, exists := s.Get("rateLimitViolations"); !exists {
+		logger.Error("Failed to get \"rateLimitViolations\" from a session.")
+		return 0
+	} else {
+		return v.(int)
+	}
+}
+
 func (s *Session) Banned() bool {
 	if s == nil {
 		logger.Error("The \"Banned\" method was called for a nil session.")
@@ -202,3 +230,73 @@ func (s *Session) Banned() bool {
 		return v.(bool)
 	}
 }
+
+func (s *Session) ConnectedAt() time.Time {
+	if s == nil {
+		logger.Error("The \"ConnectedAt\" method was called for a nil session.")
+		return time.Time{}
+	}
+
+	if v, exists := s.Get("connectedAt"); !exists {
+		logger.Error("Failed to get \"connectedAt\" from a session.")
+		return time.Time{}
+	} else {
+		return v.(time.Time)
+	}
+}
+
+func (s *Session) RemoteAddr() string {
+	if s == nil {
+		logger.Error("The \"RemoteAddr\" method was called for a nil session.")
+		return ""
+	}
+
+	if v, exists := s.Get("remoteAddr"); !exists {
+		logger.Error("Failed to get \"remoteAddr\" from a session.")
+		return ""
+	} else {
+		return v.(string)
+	}
+}
+
+func (s *Session) LastCommand() string {
+	if s == nil {
+		logger.Error("The \"LastCommand\" method was called for a nil session.")
+		return ""
+	}
+
+	if v, exists := s.Get("lastCommand"); !exists {
+		logger.Error("Failed to get \"lastCommand\" from a session.")
+		return ""
+	} else {
+		return v.(string)
+	}
+}
+
+func (s *Session) ShowTouchedCards() bool {
+	if s == nil {
+		logger.Error("The \"ShowTouchedCards\" method was called for a nil session.")
+		return true
+	}
+
+	if v, exists := s.Get("showTouchedCards"); !exists {
+		logger.Error("Failed to get \"showTouchedCards\" from a session.")
+		return true
+	} else {
+		return v.(bool)
+	}
+}
+
+func (s *Session) SendBufferFull() bool {
+	if s == nil {
+		logger.Error("The \"SendBufferFull\" method was called for a nil session.")
+		return false
+	}
+
+	if v, exists := s.Get("sendBufferFull"); !exists {
+		logger.Error("Failed to get \"sendBufferFull\" from a session.")
+		return false
+	} else {
+		return v.(bool)
+	}
+}
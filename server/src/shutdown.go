@@ -178,11 +178,19 @@ func shutdownImmediate() {
 
 func cancel() {
 	shuttingDown.UnSet()
+	restartingSoon.UnSet()
 	notifyAllShutdown()
 	chatServerSendAll("Server shutdown has been canceled.")
 }
 
 func checkImminentShutdown(s *Session) bool {
+	// Ongoing games are saved to disk during a restart, but unstarted games are not
+	if restartingSoon.IsSet() {
+		s.Warning("The server is restarting in " + strconv.Itoa(restartSecondsLeft()) +
+			" seconds. You cannot start any new games for the time being.")
+		return true
+	}
+
 	if shuttingDown.IsNotSet() {
 		return false
 	}
@@ -15,3 +15,15 @@ type Suit struct {
 	NoClueColors  bool `json:"noClueColors"`
 	NoClueRanks   bool `json:"noClueRanks"`
 }
+
+// IsClueTypeRestricted returns true if cards of this suit can never be touched by the given type
+// of clue (e.g. a "mute" suit cannot be touched by color clues)
+func (s *Suit) IsClueTypeRestricted(clueType int) bool {
+	if clueType == ClueTypeColor {
+		return s.NoClueColors
+	}
+	if clueType == ClueTypeRank {
+		return s.NoClueRanks
+	}
+	return false
+}
@@ -20,9 +20,28 @@ type Table struct {
 	// We keep track of players who have been kicked from the game
 	// so that we can prevent them from rejoining
 	KickedPlayers map[int]struct{} `json:"-"`
+	// Similarly, we keep track of spectators who were kicked and blocked from spectating again
+	KickedSpectators map[int]struct{} `json:"-"`
 	// We also keep track of spectators who have disconnected
 	// so that we can automatically put them back into the shared replay
 	DisconSpectators map[int]struct{} `json:"-"`
+	// For password-protected tables, we keep track of the users who have entered the password
+	// (or used a spectate token) so that they do not have to do it again
+	// (e.g. when they reconnect or when they switch which player they are shadowing)
+	AllowedSpectators map[int]struct{} `json:"-"`
+	// The table owner can reserve seats for specific users (from seat index to user ID)
+	// before the game starts
+	ReservedSeats map[int]int `json:"-"`
+	// The table owner can give out tokens that let specific users spectate the game
+	// (see "commandTableGenerateSpectateToken")
+	SpectateTokens map[string]*SpectateToken `json:"-"`
+	// The table owner can also require a minimum amount of experience from the users that join
+	// (see "commandTableSetRequirements")
+	MinGames  int     `json:"-"`
+	MinRating float64 `json:"-"`
+	// Sessions cannot be serialized, so we store the user IDs of the current spectators when the
+	// table is saved to disk and then convert them to disconnected spectators when it is restored
+	SpectatorIDs []int
 
 	// This is the user ID of the person who started the table
 	// or the current leader of the shared replay
@@ -35,6 +54,16 @@ type Table struct {
 	Replay         bool
 	AutomaticStart int // See "chatTable.go"
 	Progress       int // Displayed as a percentage on the main lobby screen
+	// Set when the owner has manually arranged the seats, so that the players are not shuffled
+	// when the game starts
+	SeatsArranged bool
+	// Set when the owner wants the game to start as soon as every player is ready
+	// (see "commandTableSetReady")
+	AutoStartWhenReady bool
+	// Set when the owner does not want the table to be ended for being idle
+	// (e.g. for tournament games where the players are discussing something elsewhere)
+	// (see "commandTableDisableIdleCheck")
+	IdleCheckDisabled bool
 
 	DatetimeCreated      time.Time
 	DatetimeLastJoined   time.Time
@@ -56,6 +85,13 @@ type Table struct {
 	// Each table has its own mutex to ensure that only one action can occur at the same time
 	Mutex   sync.Mutex `json:"-"`
 	Deleted bool       `json:"-"` // Used to prevent race conditions
+
+	// Between full serializations, each action is appended to the action log of the table
+	// (see "serialize_actions.go")
+	// This is zero if there is no full serialization for the actions to be appended to
+	DatetimeBaseSerialized time.Time `json:"-"`
+	// Set while the actions from the action log are being performed again on a restored table
+	RestoringActions bool `json:"-"`
 }
 
 type TableChatMessage struct {
@@ -87,15 +123,25 @@ func NewTable(name string, owner int) *Table {
 	}
 	tablesMutex.RUnlock()
 
-	// Create the table object
+	return newTable(newTableID, name, owner)
+}
+
+// newTable creates the table object with the given ID
+// (this is separate from "NewTable()" so that tables that are never added to the tables map do not
+// use up a table ID, e.g. in "validateGameState()")
+func newTable(id uint64, name string, owner int) *Table {
 	return &Table{
-		ID:   newTableID,
+		ID:   id,
 		Name: name,
 
-		Players:          make([]*Player, 0),
-		Spectators:       make([]*Spectator, 0),
-		KickedPlayers:    make(map[int]struct{}),
-		DisconSpectators: make(map[int]struct{}),
+		Players:           make([]*Player, 0),
+		Spectators:        make([]*Spectator, 0),
+		KickedPlayers:     make(map[int]struct{}),
+		KickedSpectators:  make(map[int]struct{}),
+		DisconSpectators:  make(map[int]struct{}),
+		AllowedSpectators: make(map[int]struct{}),
+		ReservedSeats:     make(map[int]int),
+		SpectateTokens:    make(map[string]*SpectateToken),
 
 		Owner:   owner,
 		Visible: true, // Tables are visible by default
@@ -119,13 +165,30 @@ func (t *Table) CheckIdle() {
 		return
 	}
 
+	t.WatchIdle()
+}
+
+// WatchIdle waits for the idle timeout to elapse and then ends the table
+// if there has not been any activity in the meantime
+func (t *Table) WatchIdle() {
 	// Set the last action
 	t.Mutex.Lock()
 	t.DatetimeLastAction = time.Now()
+	lastAction := t.DatetimeLastAction
+	idleTimeout, idleWarningTimeout := t.GetIdleTimeouts()
 	t.Mutex.Unlock()
 
 	// We want to clean up idle games, so sleep for a reasonable amount of time
-	time.Sleep(IdleGameTimeout)
+	// (and give everyone at the table a chance to do something before it is ended)
+	if idleWarningTimeout > 0 {
+		time.Sleep(idleWarningTimeout)
+		if !t.WarnIdle(lastAction) {
+			return
+		}
+		time.Sleep(idleTimeout - idleWarningTimeout)
+	} else {
+		time.Sleep(idleTimeout)
+	}
 
 	// Check to see if the table still exists
 	t2, exists := getTableAndLock(nil, t.ID, false)
@@ -136,13 +199,63 @@ func (t *Table) CheckIdle() {
 	defer t.Mutex.Unlock()
 
 	// Don't do anything if there has been an action in the meantime
-	if time.Since(t.DatetimeLastAction) < IdleGameTimeout {
+	// (starting the game counts as an action, so the timeout cannot have changed in the meantime)
+	if time.Since(t.DatetimeLastAction) < idleTimeout {
+		return
+	}
+
+	// Don't do anything if the owner turned off the idle check in the meantime
+	// (a new check is started when it is turned back on)
+	if t.IdleCheckDisabled {
 		return
 	}
 
 	t.EndIdle()
 }
 
+// WarnIdle lets everyone at the table know that it will be ended soon
+// It returns false if the table no longer exists or if there has been an action since the given
+// time (in which case a newer "CheckIdle()" goroutine will take care of the table)
+func (t *Table) WarnIdle(lastAction time.Time) bool {
+	// Check to see if the table still exists
+	t2, exists := getTableAndLock(nil, t.ID, false)
+	if !exists || t != t2 {
+		return false
+	}
+	t.Mutex.Lock()
+	defer t.Mutex.Unlock()
+
+	// Don't do anything if there has been an action in the meantime
+	if !t.DatetimeLastAction.Equal(lastAction) {
+		return false
+	}
+
+	// Don't do anything if the owner turned off the idle check in the meantime
+	if t.IdleCheckDisabled {
+		return false
+	}
+
+	logger.Info(t.GetName() + " Idle warning threshold has elapsed; warning the table.")
+	idleTimeout, idleWarningTimeout := t.GetIdleTimeouts()
+	minutesLeft := int((idleTimeout - idleWarningTimeout).Minutes())
+	msg := "This table has been idle for a while and will be automatically ended in " +
+		strconv.Itoa(minutesLeft) + " minute(s) unless there is some activity."
+	chatServerSend(msg, t.GetRoomName())
+
+	return true
+}
+
+// GetIdleTimeouts returns the amount of time that the table can be idle before it is ended and
+// the amount of time before everyone at the table is warned about it
+// Restored tables are always running, so they use the running timeouts
+func (t *Table) GetIdleTimeouts() (time.Duration, time.Duration) {
+	if t.Running {
+		return idleRunningTimeout, idleRunningWarningTimeout
+	}
+
+	return idleUnstartedTimeout, idleUnstartedWarningTimeout
+}
+
 // EndIdle is called when a table has been idle for a while and should be automatically ended
 func (t *Table) EndIdle() {
 	logger.Info(t.GetName() + " Idle timeout has elapsed; ending the game.")
@@ -198,6 +311,55 @@ func (t *Table) EndIdle() {
 	}
 }
 
+// GetNumReservedSeats returns the number of reserved seats that have not been claimed yet,
+// not counting the seat reserved for the specified user (if any)
+func (t *Table) GetNumReservedSeats(userID int) int {
+	numReservedSeats := 0
+	for _, reservedUserID := range t.ReservedSeats {
+		if reservedUserID != userID && t.GetPlayerIndexFromID(reservedUserID) == -1 {
+			numReservedSeats++
+		}
+	}
+
+	return numReservedSeats
+}
+
+// ResetReady marks every player as not ready (e.g. because the set of players changed)
+// It returns true if any of the players were ready
+func (t *Table) ResetReady() bool {
+	anyReady := false
+	for _, p := range t.Players {
+		if p.Ready {
+			p.Ready = false
+			anyReady = true
+		}
+	}
+
+	return anyReady
+}
+
+// HasRequirementsFor returns true if the specified user must meet the experience requirements
+// of this table in order to join it
+// (the owner and the users that have a reserved seat are exempt)
+func (t *Table) HasRequirementsFor(userID int) bool {
+	if t.MinGames == 0 && t.MinRating == 0 {
+		return false
+	}
+
+	return userID != t.Owner && !t.IsSeatReservedFor(userID)
+}
+
+// IsSeatReservedFor returns true if the specified user has a seat reserved at this table
+func (t *Table) IsSeatReservedFor(userID int) bool {
+	for _, reservedUserID := range t.ReservedSeats {
+		if reservedUserID == userID {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (t *Table) GetName() string {
 	g := t.Game
 	name := "Table #" + strconv.FormatUint(t.ID, 10) + " (" + t.Name + ") - "
@@ -57,6 +57,7 @@ func (t *Table) NotifyPlayerChange() {
 			Name    string       `json:"name"`
 			You     bool         `json:"you"`
 			Present bool         `json:"present"`
+			Ready   bool         `json:"ready"`
 			Stats   PregameStats `json:"stats"`
 		}
 		gamePlayers := make([]*GamePlayerMessage, 0)
@@ -66,6 +67,7 @@ func (t *Table) NotifyPlayerChange() {
 				Name:    p2.Name,
 				You:     p.ID == p2.ID,
 				Present: p2.Present,
+				Ready:   p2.Ready,
 				Stats:   p2.Stats,
 			}
 			gamePlayers = append(gamePlayers, gamePlayer)
@@ -238,6 +240,18 @@ func (t *Table) NotifyPause() {
 	}
 }
 
+func (t *Table) NotifySharedReview() {
+	for _, p := range t.Players {
+		if p.Present {
+			p.Session.NotifySharedReview(t)
+		}
+	}
+
+	for _, sp := range t.Spectators {
+		sp.Session.NotifySharedReview(t)
+	}
+}
+
 func (t *Table) NotifyReplayLeader() {
 	for _, sp := range t.Spectators {
 		sp.Session.NotifyReplayLeader(t)
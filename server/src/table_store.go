@@ -0,0 +1,319 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TableStore is where the serialized ongoing tables are kept between restarts
+// The data is opaque to the store (it is the JSON envelope created in the "serializeTables()"
+// function), so other backends (e.g. object storage) can be added without changing the
+// serialization logic
+type TableStore interface {
+	// Save returns the number of bytes that were stored
+	// (which is smaller than the size of the data if the store compresses it)
+	Save(id uint64, data []byte) (int, error)
+	// Load returns an error that satisfies "os.IsNotExist()" if there is no table with the given ID
+	Load(id uint64) ([]byte, error)
+	// List returns the IDs of all of the stored tables in ascending order
+	List() ([]uint64, error)
+	// Delete does not return an error if there is no table with the given ID
+	Delete(id uint64) error
+}
+
+// TableQuarantiner is implemented by stores that can set aside a table that could not be
+// restored so that it can be manually inspected later
+type TableQuarantiner interface {
+	Quarantine(id uint64) error
+}
+
+// TableFileNamer is implemented by stores that keep each table in a file,
+// so that the file can be named in log messages
+type TableFileNamer interface {
+	GetPath(id uint64) string
+}
+
+// TableActionLog is implemented by stores that can keep an append-only log of the actions that
+// were taken since a table was last saved (see "serialize_actions.go")
+// Like the tables, each entry is opaque to the store
+type TableActionLog interface {
+	AppendAction(id uint64, entry []byte) error
+	// LoadActions returns the entries in the order that they were appended
+	// (or no entries if there is no log for the table)
+	LoadActions(id uint64) ([][]byte, error)
+	// ReplaceActions atomically overwrites the log with the given entries
+	ReplaceActions(id uint64, entries [][]byte) error
+}
+
+var (
+	// The store that the ongoing tables are saved to (set in the "serializeTablesInit()" function)
+	tableStore TableStore
+)
+
+const (
+	// The action log of each table is kept next to it with one JSON entry per line
+	// (it is never compressed, since it is appended to)
+	ActionLogSuffix = ".actions.jsonl"
+)
+
+// FileTableStore keeps each table in a separate file in a directory
+// (e.g. "123.json" or "123.json.gz")
+type FileTableStore struct {
+	Path string
+	// Whether or not to write the tables as gzipped files
+	// (gzipped files are always loaded, regardless of this setting)
+	Compress bool
+}
+
+func NewFileTableStore(dirPath string, compress bool) *FileTableStore {
+	return &FileTableStore{
+		Path:     dirPath,
+		Compress: compress,
+	}
+}
+
+func (s *FileTableStore) getFilename(id uint64, compressed bool) string {
+	filename := strconv.FormatUint(id, 10) + ".json"
+	if compressed {
+		filename += ".gz"
+	}
+
+	return filename
+}
+
+func (s *FileTableStore) Save(id uint64, data []byte) (int, error) {
+	if s.Compress {
+		if v, err := gzipBytes(data); err != nil {
+			return 0, err
+		} else {
+			data = v
+		}
+	}
+
+	// Write to a temporary file first and then rename it into place
+	// Renaming is atomic on the same filesystem, so if the server is killed in the middle of a
+	// write, we will be left with a partial temporary file instead of a truncated table file
+	tablePath := path.Join(s.Path, s.getFilename(id, s.Compress))
+	tempPath := tablePath + ".tmp"
+	if err := ioutil.WriteFile(tempPath, data, 0600); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tempPath, tablePath); err != nil {
+		return 0, err
+	}
+
+	// Remove the file from before the compression setting was changed, if any
+	// (otherwise, the table would be restored from whichever file is found first)
+	otherPath := path.Join(s.Path, s.getFilename(id, !s.Compress))
+	if err := os.Remove(otherPath); err != nil && !os.IsNotExist(err) {
+		return 0, err
+	}
+
+	return len(data), nil
+}
+
+func (s *FileTableStore) Load(id uint64) ([]byte, error) {
+	for _, compressed := range []bool{s.Compress, !s.Compress} {
+		tablePath := path.Join(s.Path, s.getFilename(id, compressed))
+		var data []byte
+		if v, err := ioutil.ReadFile(tablePath); os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		} else {
+			data = v
+		}
+
+		if compressed {
+			return gunzipBytes(data)
+		}
+		return data, nil
+	}
+
+	return nil, os.ErrNotExist
+}
+
+// GetPath returns the path of the file that "Load()" would read for the table
+// (or the path that it would be saved to, if there is no file for it)
+func (s *FileTableStore) GetPath(id uint64) string {
+	for _, compressed := range []bool{s.Compress, !s.Compress} {
+		tablePath := path.Join(s.Path, s.getFilename(id, compressed))
+		if _, err := os.Stat(tablePath); err == nil {
+			return tablePath
+		}
+	}
+
+	return path.Join(s.Path, s.getFilename(id, s.Compress))
+}
+
+func (s *FileTableStore) List() ([]uint64, error) {
+	var files []os.FileInfo
+	if v, err := ioutil.ReadDir(s.Path); err != nil {
+		return nil, err
+	} else {
+		files = v
+	}
+
+	idMap := make(map[uint64]struct{})
+	for _, f := range files {
+		if f.Name() == ".gitignore" || f.IsDir() {
+			continue
+		}
+
+		// Temporary files are left behind when the server is killed in the middle of serialization
+		if strings.HasSuffix(f.Name(), ".tmp") {
+			logger.Warning("Skipping partially written table file: " + path.Join(s.Path, f.Name()))
+			continue
+		}
+
+		// Action logs are only used along with their table
+		if strings.HasSuffix(f.Name(), ActionLogSuffix) {
+			continue
+		}
+
+		idString := strings.TrimSuffix(strings.TrimSuffix(f.Name(), ".gz"), ".json")
+		if id, err := strconv.ParseUint(idString, 10, 64); err != nil ||
+			f.Name() != s.getFilename(id, strings.HasSuffix(f.Name(), ".gz")) {
+
+			logger.Warning("Skipping a file that is not named after a table ID: " +
+				path.Join(s.Path, f.Name()))
+		} else {
+			idMap[id] = struct{}{}
+		}
+	}
+
+	ids := make([]uint64, 0, len(idMap))
+	for id := range idMap {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+
+	return ids, nil
+}
+
+func (s *FileTableStore) Delete(id uint64) error {
+	for _, compressed := range []bool{false, true} {
+		tablePath := path.Join(s.Path, s.getFilename(id, compressed))
+		if err := os.Remove(tablePath); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	actionLogPath := path.Join(s.Path, s.getActionLogFilename(id))
+	if err := os.Remove(actionLogPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// Quarantine moves the files for a table to the "failed" subdirectory
+func (s *FileTableStore) Quarantine(id uint64) error {
+	failedPath := path.Join(s.Path, "failed")
+	if err := os.MkdirAll(failedPath, 0755); err != nil {
+		return err
+	}
+
+	moved := false
+	for _, compressed := range []bool{false, true} {
+		filename := s.getFilename(id, compressed)
+		tablePath := path.Join(s.Path, filename)
+		newPath := path.Join(failedPath, filename)
+		if err := os.Rename(tablePath, newPath); os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		logger.Info("Moved the unrestorable table file to: " + newPath)
+		moved = true
+	}
+	if !moved {
+		return errors.New("there is no file for table " + strconv.FormatUint(id, 10))
+	}
+
+	// The action log is needed to inspect the most recent state of the table
+	actionLogFilename := s.getActionLogFilename(id)
+	actionLogPath := path.Join(s.Path, actionLogFilename)
+	if err := os.Rename(actionLogPath, path.Join(failedPath, actionLogFilename)); err != nil &&
+		!os.IsNotExist(err) {
+
+		return err
+	}
+
+	return nil
+}
+
+func (s *FileTableStore) getActionLogFilename(id uint64) string {
+	return strconv.FormatUint(id, 10) + ActionLogSuffix
+}
+
+func (s *FileTableStore) AppendAction(id uint64, entry []byte) error {
+	actionLogPath := path.Join(s.Path, s.getActionLogFilename(id))
+	var file *os.File
+	if v, err := os.OpenFile(actionLogPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600); err != nil {
+		return err
+	} else {
+		file = v
+	}
+
+	// The entry and the newline are written together so that a crash cannot leave an entry
+	// without a newline in the middle of the file
+	if _, err := file.Write(append(entry, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+func (s *FileTableStore) LoadActions(id uint64) ([][]byte, error) {
+	actionLogPath := path.Join(s.Path, s.getActionLogFilename(id))
+	var data []byte
+	if v, err := ioutil.ReadFile(actionLogPath); os.IsNotExist(err) {
+		return [][]byte{}, nil
+	} else if err != nil {
+		return nil, err
+	} else {
+		data = v
+	}
+
+	entries := make([][]byte, 0)
+	for _, line := range bytes.Split(data, []byte{'\n'}) {
+		if len(line) != 0 {
+			entries = append(entries, line)
+		}
+	}
+
+	return entries, nil
+}
+
+func (s *FileTableStore) ReplaceActions(id uint64, entries [][]byte) error {
+	actionLogPath := path.Join(s.Path, s.getActionLogFilename(id))
+	if len(entries) == 0 {
+		if err := os.Remove(actionLogPath); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	var data []byte
+	for _, entry := range entries {
+		data = append(data, entry...)
+		data = append(data, '\n')
+	}
+
+	// Same as in the "Save()" method, we write to a temporary file first
+	tempPath := actionLogPath + ".tmp"
+	if err := ioutil.WriteFile(tempPath, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tempPath, actionLogPath)
+}
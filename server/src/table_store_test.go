@@ -0,0 +1,198 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestFileTableStoreSaveDoesNotLeaveTemporaryFiles(t *testing.T) {
+	store := NewFileTableStore(newTestDir(t), false)
+	if _, err := store.Save(1, []byte(`{}`)); err != nil {
+		t.Fatal("failed to save the table:", err)
+	}
+
+	var names []string
+	if files, err := ioutil.ReadDir(store.Path); err != nil {
+		t.Fatal("failed to read the directory:", err)
+	} else {
+		for _, f := range files {
+			names = append(names, f.Name())
+		}
+	}
+	if len(names) != 1 || names[0] != "1.json" {
+		t.Errorf("expected only \"1.json\" to exist, but found: %v", names)
+	}
+}
+
+// memoryTableStore is a table store that keeps the tables in memory
+// (it does not implement any of the optional interfaces)
+type memoryTableStore struct {
+	tables map[uint64][]byte
+}
+
+func newMemoryTableStore() *memoryTableStore {
+	return &memoryTableStore{
+		tables: make(map[uint64][]byte),
+	}
+}
+
+func (s *memoryTableStore) Save(id uint64, data []byte) (int, error) {
+	s.tables[id] = append([]byte(nil), data...)
+	return len(data), nil
+}
+
+func (s *memoryTableStore) Load(id uint64) ([]byte, error) {
+	if data, ok := s.tables[id]; ok {
+		return data, nil
+	}
+	return nil, os.ErrNotExist
+}
+
+func (s *memoryTableStore) List() ([]uint64, error) {
+	ids := make([]uint64, 0, len(s.tables))
+	for id := range s.tables {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+
+	return ids, nil
+}
+
+func (s *memoryTableStore) Delete(id uint64) error {
+	delete(s.tables, id)
+	return nil
+}
+
+func useTestMemoryTableStore(t *testing.T) *memoryTableStore {
+	oldTableStore := tableStore
+	store := newMemoryTableStore()
+	tableStore = store
+	t.Cleanup(func() {
+		tableStore = oldTableStore
+	})
+
+	return store
+}
+
+func TestRestoreTablesFromMemoryTableStore(t *testing.T) {
+	resetTestTables(t)
+	store := useTestMemoryTableStore(t)
+
+	tb1 := newTestGame(t, 2)
+	tb2 := newTestGame(t, 3)
+	clueTestPlayer(t, tb2)
+	if !serializeTables() {
+		t.Fatal("failed to serialize the tables")
+	}
+	if ids, _ := store.List(); !reflect.DeepEqual(ids, []uint64{tb1.ID, tb2.ID}) {
+		t.Fatalf("expected the store to have tables %v and %v, but it has %v",
+			tb1.ID, tb2.ID, ids)
+	}
+
+	resetTestTables(t)
+	restoreTables()
+
+	for _, tb := range []*Table{tb1, tb2} {
+		restored := getTestTable(t, tb.ID)
+		if restored.Name != tb.Name || restored.Game.Turn != tb.Game.Turn {
+			t.Errorf("table %v was not restored", tb.ID)
+		}
+		if !reflect.DeepEqual(restored.Game.Actions, tb.Game.Actions) {
+			t.Errorf("the actions of table %v were not restored", tb.ID)
+		}
+	}
+
+	// The tables are deleted from the store once they are restored
+	if len(store.tables) != 0 {
+		t.Errorf("expected the store to be empty, but it has %v tables", len(store.tables))
+	}
+}
+
+func TestRestoreTablesFromMemoryTableStoreSkipsCorruptTables(t *testing.T) {
+	resetTestTables(t)
+	store := useTestMemoryTableStore(t)
+
+	tb := newTestGame(t, 2)
+	if !serializeTables() {
+		t.Fatal("failed to serialize the tables")
+	}
+	store.tables[tb.ID+1] = []byte(`{"corrupt`)
+
+	resetTestTables(t)
+	restoreTables()
+
+	getTestTable(t, tb.ID)
+	if _, ok := tables[tb.ID+1]; ok {
+		t.Error("the corrupt table was restored")
+	}
+}
+
+func TestFileTableStoreLoadMissingTable(t *testing.T) {
+	store := NewFileTableStore(newTestDir(t), false)
+	if _, err := store.Load(1); !os.IsNotExist(err) {
+		t.Errorf("expected a missing table to return a \"not exist\" error, but got: %v", err)
+	}
+	if err := store.Delete(1); err != nil {
+		t.Error("failed to delete a missing table:", err)
+	}
+}
+
+func TestFileTableStoreCompressionSetting(t *testing.T) {
+	dirPath := newTestDir(t)
+	data := []byte(`{"id":1}`)
+
+	// A table that was saved before compression was enabled is still loaded
+	if _, err := NewFileTableStore(dirPath, false).Save(1, data); err != nil {
+		t.Fatal("failed to save the table:", err)
+	}
+	store := NewFileTableStore(dirPath, true)
+	if v, err := store.Load(1); err != nil {
+		t.Fatal("failed to load the table:", err)
+	} else if !bytes.Equal(v, data) {
+		t.Errorf("expected the table to be %s, but got %s", data, v)
+	}
+
+	// Saving it again replaces the uncompressed file
+	if _, err := store.Save(1, data); err != nil {
+		t.Fatal("failed to save the table:", err)
+	}
+	if _, err := os.Stat(path.Join(dirPath, "1.json")); !os.IsNotExist(err) {
+		t.Error("the uncompressed file was not removed")
+	}
+	if v, err := store.Load(1); err != nil {
+		t.Fatal("failed to load the table:", err)
+	} else if !bytes.Equal(v, data) {
+		t.Errorf("expected the table to be %s, but got %s", data, v)
+	}
+}
+
+func TestFileTableStoreList(t *testing.T) {
+	dirPath := newTestDir(t)
+	for _, name := range []string{
+		"10.json",
+		"2.json.gz",
+		"3.json.tmp",
+		"4" + ActionLogSuffix,
+		"notes.txt",
+		"05.json",
+		".gitignore",
+	} {
+		if err := ioutil.WriteFile(path.Join(dirPath, name), []byte(`{}`), 0600); err != nil {
+			t.Fatal("failed to write the file:", err)
+		}
+	}
+
+	store := NewFileTableStore(dirPath, false)
+	if ids, err := store.List(); err != nil {
+		t.Fatal("failed to list the tables:", err)
+	} else if !reflect.DeepEqual(ids, []uint64{2, 10}) {
+		t.Errorf("expected the tables to be [2 10], but got %v", ids)
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// useTestIdleTimeouts shortens the idle timeouts of unstarted tables for the duration of the test
+func useTestIdleTimeouts(t *testing.T, timeout time.Duration, warningTimeout time.Duration) {
+	oldTimeout := idleUnstartedTimeout
+	oldWarningTimeout := idleUnstartedWarningTimeout
+	idleUnstartedTimeout = timeout
+	idleUnstartedWarningTimeout = warningTimeout
+	t.Cleanup(func() {
+		idleUnstartedTimeout = oldTimeout
+		idleUnstartedWarningTimeout = oldWarningTimeout
+	})
+}
+
+// useTestRunningIdleTimeouts is the same as "useTestIdleTimeouts()", but for running tables
+func useTestRunningIdleTimeouts(t *testing.T, timeout time.Duration, warningTimeout time.Duration) {
+	oldTimeout := idleRunningTimeout
+	oldWarningTimeout := idleRunningWarningTimeout
+	idleRunningTimeout = timeout
+	idleRunningWarningTimeout = warningTimeout
+	t.Cleanup(func() {
+		idleRunningTimeout = oldTimeout
+		idleRunningWarningTimeout = oldWarningTimeout
+	})
+}
+
+// getTestEndCondition returns the end condition of the game while holding the table lock
+// (since an idle goroutine might be ending it)
+func getTestEndCondition(tb *Table) int {
+	tb.Mutex.Lock()
+	defer tb.Mutex.Unlock()
+	return tb.Game.EndCondition
+}
+
+// countTestIdleWarnings returns the number of idle warnings that were sent to the table
+func countTestIdleWarnings(tb *Table) int {
+	tb.Mutex.Lock()
+	defer tb.Mutex.Unlock()
+
+	numWarnings := 0
+	for _, chatMsg := range tb.Chat {
+		if strings.Contains(chatMsg.Msg, "will be automatically ended") {
+			numWarnings++
+		}
+	}
+	return numWarnings
+}
+
+// isTestTableDeleted returns whether the table has been removed from the table map
+func isTestTableDeleted(tb *Table) bool {
+	tablesMutex.RLock()
+	defer tablesMutex.RUnlock()
+	_, ok := tables[tb.ID]
+	return !ok
+}
+
+func TestTableWatchIdleWarnsOnce(t *testing.T) {
+	resetTestTables(t)
+	useTestIdleTimeouts(t, 400*time.Millisecond, 200*time.Millisecond)
+	tb := newTestTable(t, 2)
+
+	go tb.WatchIdle()
+
+	time.Sleep(300 * time.Millisecond)
+	if numWarnings := countTestIdleWarnings(tb); numWarnings != 1 {
+		t.Errorf("expected 1 idle warning, but got %v", numWarnings)
+	}
+	if isTestTableDeleted(tb) {
+		t.Error("the table was ended before the idle timeout elapsed")
+	}
+
+	time.Sleep(300 * time.Millisecond)
+	if numWarnings := countTestIdleWarnings(tb); numWarnings != 1 {
+		t.Errorf("expected 1 idle warning, but got %v", numWarnings)
+	}
+	if !isTestTableDeleted(tb) {
+		t.Error("the table was not ended after the idle timeout elapsed")
+	}
+}
+
+func TestTableWatchIdleActivityCancelsWarning(t *testing.T) {
+	resetTestTables(t)
+	useTestIdleTimeouts(t, 400*time.Millisecond, 200*time.Millisecond)
+	tb := newTestTable(t, 2)
+
+	go tb.WatchIdle()
+
+	// Every command that touches the table starts a new idle check,
+	// which resets the time of the last action
+	time.Sleep(100 * time.Millisecond)
+	go tb.WatchIdle()
+
+	// The first check does not warn the table since there was activity in the meantime
+	time.Sleep(150 * time.Millisecond)
+	if numWarnings := countTestIdleWarnings(tb); numWarnings != 0 {
+		t.Errorf("expected no idle warnings, but got %v", numWarnings)
+	}
+
+	// The newer check takes over
+	time.Sleep(100 * time.Millisecond)
+	if numWarnings := countTestIdleWarnings(tb); numWarnings != 1 {
+		t.Errorf("expected 1 idle warning, but got %v", numWarnings)
+	}
+
+	// Turning off the idle check stops the newer check as well
+	tb.Mutex.Lock()
+	tb.IdleCheckDisabled = true
+	tb.Mutex.Unlock()
+	time.Sleep(300 * time.Millisecond)
+	if isTestTableDeleted(tb) {
+		t.Error("the table was ended after the idle check was disabled")
+	}
+}
+
+func TestTableWatchIdleUsesSeparateTimeouts(t *testing.T) {
+	resetTestTables(t)
+	useTestIdleTimeouts(t, 200*time.Millisecond, 0)
+	useTestRunningIdleTimeouts(t, 600*time.Millisecond, 0)
+	unstarted := newTestTable(t, 2)
+	running := newTestGame(t, 2)
+
+	go unstarted.WatchIdle()
+	go running.WatchIdle()
+
+	// The unstarted table times out first
+	time.Sleep(400 * time.Millisecond)
+	if !isTestTableDeleted(unstarted) {
+		t.Error("the unstarted table was not ended after the unstarted idle timeout elapsed")
+	}
+	if getTestEndCondition(running) != EndConditionInProgress {
+		t.Error("the running game was ended before the running idle timeout elapsed")
+	}
+
+	time.Sleep(400 * time.Millisecond)
+	if endCondition := getTestEndCondition(running); endCondition != EndConditionIdleTimeout {
+		t.Errorf("expected the running game to end due to idleness, but the end condition is %v",
+			endCondition)
+	}
+}
+
+func TestTableGetIdleTimeouts(t *testing.T) {
+	resetTestTables(t)
+	useTestTableStore(t, false)
+	useTestIdleTimeouts(t, time.Minute, 30*time.Second)
+	useTestRunningIdleTimeouts(t, time.Hour, 30*time.Minute)
+
+	tb := newTestTable(t, 2)
+	if timeout, warningTimeout := tb.GetIdleTimeouts(); timeout != time.Minute ||
+		warningTimeout != 30*time.Second {
+
+		t.Errorf("expected the unstarted timeouts for an unstarted table, but got %v and %v",
+			timeout, warningTimeout)
+	}
+
+	// Restored tables are always running
+	startTestGame(t, tb)
+	serializeAndRestoreTestTables(t)
+	restored := getTestTable(t, tb.ID)
+	if timeout, warningTimeout := restored.GetIdleTimeouts(); timeout != time.Hour ||
+		warningTimeout != 30*time.Minute {
+
+		t.Errorf("expected the running timeouts for a restored table, but got %v and %v",
+			timeout, warningTimeout)
+	}
+}
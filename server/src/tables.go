@@ -1,8 +1,10 @@
 package main
 
 import (
+	"os"
 	"strconv"
 	"sync"
+	"time"
 )
 
 var (
@@ -12,8 +14,106 @@ var (
 	// The counter is atomically incremented before assignment,
 	// so the first ID will be 1 and will increase from there
 	tableIDCounter uint64 = 0
+
+	// The amount of time that a table is inactive before it is killed by the server
+	// and the amount of time that a table is inactive before everyone at it is warned
+	// (a warning timeout of 0 means that no warning is sent)
+	// Unstarted tables use a separate (shorter) timeout from games that are in progress
+	idleRunningTimeout          time.Duration
+	idleRunningWarningTimeout   time.Duration
+	idleUnstartedTimeout        time.Duration
+	idleUnstartedWarningTimeout time.Duration
+
+	// The maximum number of unstarted and ongoing tables (0 means that there is no limit)
+	maxTables int
 )
 
+func idleTimeoutInit() {
+	// "IDLE_TIMEOUT" is the old name of "IDLE_TIMEOUT_RUNNING" and is still respected
+	idleRunningTimeout = getIdleTimeoutEnv("IDLE_TIMEOUT", DefaultIdleGameTimeout)
+	idleRunningTimeout = getIdleTimeoutEnv("IDLE_TIMEOUT_RUNNING", idleRunningTimeout)
+	idleUnstartedTimeout = getIdleTimeoutEnv("IDLE_TIMEOUT_UNSTARTED", DefaultIdleUnstartedTimeout)
+
+	idleWarningPercent := DefaultIdleWarningPercent
+	idleWarningPercentString := os.Getenv("IDLE_WARNING_PERCENT")
+	if len(idleWarningPercentString) != 0 {
+		if v, err := strconv.Atoi(idleWarningPercentString); err != nil {
+			logger.Fatal("Failed to convert the \"IDLE_WARNING_PERCENT\" " +
+				"environment variable to a number.")
+			return
+		} else if v < 0 || v >= 100 {
+			logger.Fatal("The \"IDLE_WARNING_PERCENT\" environment variable must be " +
+				"between 0 and 99.")
+			return
+		} else {
+			idleWarningPercent = v
+		}
+	}
+	idleRunningWarningTimeout = idleRunningTimeout * time.Duration(idleWarningPercent) / 100
+	idleUnstartedWarningTimeout = idleUnstartedTimeout * time.Duration(idleWarningPercent) / 100
+}
+
+// getIdleTimeoutEnv parses an environment variable that specifies an amount of minutes
+func getIdleTimeoutEnv(name string, defaultTimeout time.Duration) time.Duration {
+	timeoutString := os.Getenv(name)
+	if len(timeoutString) == 0 {
+		return defaultTimeout
+	}
+
+	if v, err := strconv.Atoi(timeoutString); err != nil {
+		logger.Fatal("Failed to convert the \"" + name + "\" environment variable to a number.")
+		return defaultTimeout
+	} else if v <= 0 {
+		logger.Fatal("The \"" + name + "\" environment variable must be positive.")
+		return defaultTimeout
+	} else {
+		return time.Duration(v) * time.Minute
+	}
+}
+
+func maxTablesInit() {
+	maxTablesString := os.Getenv("MAX_TABLES")
+	if len(maxTablesString) != 0 {
+		if v, err := strconv.Atoi(maxTablesString); err != nil {
+			logger.Fatal("Failed to convert the \"MAX_TABLES\" environment variable to a number.")
+			return
+		} else if v < 0 {
+			logger.Fatal("The \"MAX_TABLES\" environment variable cannot be negative.")
+			return
+		} else {
+			maxTables = v
+		}
+	}
+}
+
+// validateTableCapacity returns false (and warns the user) if no more tables can be created
+// because of the "MAX_TABLES" limit
+func validateTableCapacity(s *Session) bool {
+	if maxTables > 0 && countNonReplayTables() >= maxTables {
+		s.WarningWithCode(ErrServerAtCapacity, "The server is at capacity. "+
+			"Please wait for some of the current games to finish before creating a new one.")
+		return false
+	}
+
+	return true
+}
+
+// countNonReplayTables returns the number of unstarted and ongoing tables
+// (replays do not count against the "MAX_TABLES" limit)
+func countNonReplayTables() int {
+	tablesMutex.RLock()
+	defer tablesMutex.RUnlock()
+
+	numTables := 0
+	for _, t := range tables {
+		if !t.Replay {
+			numTables++
+		}
+	}
+
+	return numTables
+}
+
 func getTable(s *Session, tableID uint64) (*Table, bool) {
 	// Golang maps are not safe for concurrent use
 	tablesMutex.RLock()
@@ -22,7 +122,8 @@ func getTable(s *Session, tableID uint64) (*Table, bool) {
 
 	if !ok {
 		if s != nil {
-			s.Warning("Table " + strconv.FormatUint(tableID, 10) + " does not exist.")
+			s.WarningWithCode(ErrTableNotFound,
+				"Table "+strconv.FormatUint(tableID, 10)+" does not exist.")
 		}
 		return nil, false
 	}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestIdleTimeoutInit(t *testing.T) {
+	// The cleanup functions run in reverse order,
+	// so this puts back the timeouts after the environment variables are restored
+	t.Cleanup(idleTimeoutInit)
+	setTestEnv(t, "IDLE_TIMEOUT", "")
+	setTestEnv(t, "IDLE_TIMEOUT_RUNNING", "60")
+	setTestEnv(t, "IDLE_TIMEOUT_UNSTARTED", "5")
+	setTestEnv(t, "IDLE_WARNING_PERCENT", "50")
+	idleTimeoutInit()
+
+	if idleRunningTimeout != 60*time.Minute {
+		t.Errorf("expected a running timeout of 60 minutes, but got %v", idleRunningTimeout)
+	}
+	if idleRunningWarningTimeout != 30*time.Minute {
+		t.Errorf("expected a running warning timeout of 30 minutes, but got %v",
+			idleRunningWarningTimeout)
+	}
+	if idleUnstartedTimeout != 5*time.Minute {
+		t.Errorf("expected an unstarted timeout of 5 minutes, but got %v", idleUnstartedTimeout)
+	}
+	if idleUnstartedWarningTimeout != 150*time.Second {
+		t.Errorf("expected an unstarted warning timeout of 150 seconds, but got %v",
+			idleUnstartedWarningTimeout)
+	}
+}
+
+func TestIdleTimeoutInitLegacy(t *testing.T) {
+	t.Cleanup(idleTimeoutInit)
+	setTestEnv(t, "IDLE_TIMEOUT", "90")
+	setTestEnv(t, "IDLE_TIMEOUT_RUNNING", "")
+	setTestEnv(t, "IDLE_TIMEOUT_UNSTARTED", "")
+	setTestEnv(t, "IDLE_WARNING_PERCENT", "")
+	idleTimeoutInit()
+
+	// The old name of the running timeout is still respected
+	if idleRunningTimeout != 90*time.Minute {
+		t.Errorf("expected a running timeout of 90 minutes, but got %v", idleRunningTimeout)
+	}
+	if idleUnstartedTimeout != DefaultIdleUnstartedTimeout {
+		t.Errorf("expected the default unstarted timeout of %v, but got %v",
+			DefaultIdleUnstartedTimeout, idleUnstartedTimeout)
+	}
+	if idleUnstartedTimeout >= idleRunningTimeout {
+		t.Error("the unstarted timeout is not shorter than the running timeout")
+	}
+}
+
+func useTestMaxTables(t *testing.T, limit int) {
+	oldMaxTables := maxTables
+	maxTables = limit
+	t.Cleanup(func() {
+		maxTables = oldMaxTables
+	})
+}
+
+func TestMaxTablesInit(t *testing.T) {
+	useTestMaxTables(t, 0)
+	setTestEnv(t, "MAX_TABLES", "25")
+	maxTablesInit()
+
+	if maxTables != 25 {
+		t.Errorf("expected a limit of 25 tables, but got %v", maxTables)
+	}
+}
+
+func TestValidateTableCapacity(t *testing.T) {
+	resetTestTables(t)
+	useTestMaxTables(t, 2)
+	newTestTable(t, 2)
+	s, conn := newTestWebsocket(t, 10, "Dan", 0)
+
+	// Under the limit
+	if !validateTableCapacity(s) {
+		t.Error("a table could not be created under the limit")
+	}
+
+	// Replays do not count against the limit
+	replay := newTestTable(t, 2)
+	replay.Replay = true
+	if !validateTableCapacity(s) {
+		t.Error("a replay counted against the limit")
+	}
+
+	// At the limit
+	newTestGame(t, 2)
+	if validateTableCapacity(s) {
+		t.Error("a table could be created at the limit")
+	}
+	expectTestWarning(t, conn, "The server is at capacity.")
+
+	// No limit
+	maxTables = 0
+	if !validateTableCapacity(s) {
+		t.Error("a table could not be created without a limit")
+	}
+}
+
+func TestTableCreateAtCapacity(t *testing.T) {
+	resetTestTables(t)
+	useTestMaxTables(t, 1)
+	newTestTable(t, 2)
+
+	s, conn := newTestWebsocket(t, 10, "Dan", 0)
+	commandTableCreate(s, &CommandData{
+		Name: "Test Table",
+		Options: &Options{
+			VariantName: "No Variant",
+		},
+	})
+
+	data := readTestCommand(t, conn, "warning")
+	if !strings.Contains(data, ErrServerAtCapacity) {
+		t.Errorf("expected a warning with the code of %v, but got: %v", ErrServerAtCapacity, data)
+	}
+	if countNonReplayTables() != 1 {
+		t.Errorf("expected 1 table, but got %v", countNonReplayTables())
+	}
+}
+
+func TestTableCreateUnderCapacity(t *testing.T) {
+	if db == nil {
+		t.Skip("creating a table requires a database")
+	}
+	resetTestTables(t)
+	useTestMaxTables(t, 2)
+	newTestTable(t, 2)
+
+	s, _ := newTestWebsocket(t, 10, "Dan", 0)
+	commandTableCreate(s, &CommandData{
+		Name: "Test Table",
+		Options: &Options{
+			VariantName: "No Variant",
+		},
+	})
+
+	if countNonReplayTables() != 2 {
+		t.Errorf("expected 2 tables, but got %v", countNonReplayTables())
+	}
+}
+
+func TestHttpLocalhostMaxTables(t *testing.T) {
+	resetTestTables(t)
+	useTestMaxTables(t, 10)
+	newTestTable(t, 2)
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/maxTables", nil)
+	httpLocalhostMaxTables(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected a status code of %v, but got %v", http.StatusOK, w.Code)
+	}
+	expected := "Current tables: 1\nMaximum tables: 10\n"
+	if w.Body.String() != expected {
+		t.Errorf("expected a response of %q, but got %q", expected, w.Body.String())
+	}
+}
@@ -60,6 +60,34 @@ func (v *Variant) HasReversedSuits() bool {
 	return false
 }
 
+// IsClueTypeAllowed returns false if none of the suits in the variant can ever be touched by the
+// given type of clue (e.g. a variant where every suit is a "mute" suit cannot have color clues)
+// Variants where a clue type intentionally touches nothing (e.g. "Color Blind") are still allowed
+func (v *Variant) IsClueTypeAllowed(clueType int) bool {
+	if clueType == ClueTypeColor && v.ColorCluesTouchNothing {
+		return true
+	}
+	if clueType == ClueTypeRank && v.RankCluesTouchNothing {
+		return true
+	}
+
+	for _, s := range v.Suits {
+		if !s.IsClueTypeRestricted(clueType) {
+			return true
+		}
+	}
+
+	// A special rank (e.g. in "Rainbow-Ones") can still be touched by all clues of a type
+	if clueType == ClueTypeColor && v.SpecialAllClueColors {
+		return true
+	}
+	if clueType == ClueTypeRank && v.SpecialAllClueRanks {
+		return true
+	}
+
+	return false
+}
+
 func (v *Variant) GetDeckSize() int {
 	deckSize := 0
 	for _, s := range v.Suits {
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestVariantIsClueTypeAllowed(t *testing.T) {
+	muteSuit := &Suit{
+		Name:         "Mute",
+		NoClueColors: true,
+	}
+	normalSuit := &Suit{
+		Name:       "Red",
+		ClueColors: []string{"Red"},
+	}
+
+	// Color clues are only allowed if at least one suit can be touched by them
+	allMute := &Variant{
+		Suits: []*Suit{muteSuit, muteSuit},
+	}
+	if allMute.IsClueTypeAllowed(ClueTypeColor) {
+		t.Error("color clues were allowed in a variant where every suit is a mute suit")
+	}
+	if !allMute.IsClueTypeAllowed(ClueTypeRank) {
+		t.Error("rank clues were not allowed in a variant with mute suits")
+	}
+
+	oneMute := &Variant{
+		Suits: []*Suit{normalSuit, muteSuit},
+	}
+	if !oneMute.IsClueTypeAllowed(ClueTypeColor) {
+		t.Error("color clues were not allowed in a variant with only one mute suit")
+	}
+
+	// A special rank can still be touched by every color
+	specialRank := &Variant{
+		Suits:                []*Suit{muteSuit},
+		SpecialRank:          1,
+		SpecialAllClueColors: true,
+	}
+	if !specialRank.IsClueTypeAllowed(ClueTypeColor) {
+		t.Error("color clues were not allowed in a variant with a special rank")
+	}
+
+	// Variants where color clues intentionally touch nothing still allow them
+	colorBlind := &Variant{
+		Suits:                  []*Suit{muteSuit},
+		ColorCluesTouchNothing: true,
+	}
+	if !colorBlind.IsClueTypeAllowed(ClueTypeColor) {
+		t.Error("color clues were not allowed in a variant where they touch nothing")
+	}
+}
+
+func TestVariantIsCardTouchedRestrictedSuits(t *testing.T) {
+	variant := variants["Rainbow (5 Suits)"]
+	rainbowIndex := len(variant.Suits) - 1
+	for i := range variant.ClueColors {
+		clue := NewClue(&CommandData{ // Manual invocation
+			Type:  ActionTypeColorClue,
+			Value: i,
+		})
+		if !variantIsCardTouched(variant.Name, clue, &Card{SuitIndex: rainbowIndex, Rank: 3}) {
+			t.Errorf("a rainbow card was not touched by color clue %v", i)
+		}
+	}
+
+	// A color clue never touches a mute card (and a null card is not touched by anything)
+	for _, variantName := range []string{"White (5 Suits)", "Null (5 Suits)"} {
+		variant := variants[variantName]
+		card := &Card{SuitIndex: len(variant.Suits) - 1, Rank: 3}
+		for i := range variant.ClueColors {
+			clue := NewClue(&CommandData{ // Manual invocation
+				Type:  ActionTypeColorClue,
+				Value: i,
+			})
+			if variantIsCardTouched(variantName, clue, card) {
+				t.Errorf("a color clue touched a mute card in the \"%v\" variant", variantName)
+			}
+		}
+	}
+	rankClue := NewClue(&CommandData{ // Manual invocation
+		Type:  ActionTypeRankClue,
+		Value: 3,
+	})
+	nullVariant := variants["Null (5 Suits)"]
+	nullCard := &Card{SuitIndex: len(nullVariant.Suits) - 1, Rank: 3}
+	if variantIsCardTouched(nullVariant.Name, rankClue, nullCard) {
+		t.Error("a rank clue touched a null card")
+	}
+}
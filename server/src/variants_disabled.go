@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"sync"
+)
+
+var (
+	// Administrators can temporarily disable variants (e.g. if a variant is bugged)
+	// without having to redeploy the server
+	// This is a map of variant names
+	disabledVariants      = make(map[string]struct{})
+	disabledVariantsMutex = sync.RWMutex{}
+	// The disabled variants are saved to disk so that they are remembered after a restart
+	disabledVariantsPath string
+)
+
+func disabledVariantsInit() {
+	disabledVariantsPath = path.Join(dataPath, "disabled_variants.json")
+	if _, err := os.Stat(disabledVariantsPath); os.IsNotExist(err) {
+		return
+	} else if err != nil {
+		logger.Fatal("Failed to check if the \""+disabledVariantsPath+"\" file exists:", err)
+		return
+	}
+
+	var fileContents []byte
+	if v, err := ioutil.ReadFile(disabledVariantsPath); err != nil {
+		logger.Fatal("Failed to read the \""+disabledVariantsPath+"\" file:", err)
+		return
+	} else {
+		fileContents = v
+	}
+	var variantNamesArray []string
+	if err := json.Unmarshal(fileContents, &variantNamesArray); err != nil {
+		logger.Fatal("Failed to convert the disabled variants file to JSON:", err)
+		return
+	}
+
+	for _, variantName := range variantNamesArray {
+		if _, ok := variants[variantName]; !ok {
+			logger.Warning("The disabled variant of \"" + variantName + "\" does not exist.")
+			continue
+		}
+		disabledVariants[variantName] = struct{}{}
+		logger.Info("Variant \"" + variantName + "\" is disabled.")
+	}
+}
+
+func variantIsDisabled(variantName string) bool {
+	disabledVariantsMutex.RLock()
+	_, ok := disabledVariants[variantName]
+	disabledVariantsMutex.RUnlock()
+	return ok
+}
+
+// validateVariantEnabled warns the user if the variant has been disabled by an administrator
+func validateVariantEnabled(s *Session, variantName string) bool {
+	if variantIsDisabled(variantName) {
+		s.Warning("The variant of \"" + variantName + "\" is temporarily disabled.")
+		return false
+	}
+
+	return true
+}
+
+// setVariantEnabled updates the disabled variants and writes them to disk
+func setVariantEnabled(variantName string, enabled bool) error {
+	disabledVariantsMutex.Lock()
+	defer disabledVariantsMutex.Unlock()
+
+	if enabled {
+		delete(disabledVariants, variantName)
+	} else {
+		disabledVariants[variantName] = struct{}{}
+	}
+
+	variantNamesArray := make([]string, 0, len(disabledVariants))
+	for variantName := range disabledVariants {
+		variantNamesArray = append(variantNamesArray, variantName)
+	}
+	sort.Strings(variantNamesArray)
+
+	var fileContents []byte
+	if v, err := json.Marshal(variantNamesArray); err != nil {
+		return err
+	} else {
+		fileContents = v
+	}
+
+	return ioutil.WriteFile(disabledVariantsPath, fileContents, 0644)
+}
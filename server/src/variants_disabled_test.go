@@ -0,0 +1,115 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// useTestDisabledVariants saves the disabled variants to a temporary directory
+func useTestDisabledVariants(t *testing.T) {
+	oldDisabledVariantsPath := disabledVariantsPath
+	disabledVariantsPath = path.Join(newTestDir(t), "disabled_variants.json")
+	disabledVariantsMutex.Lock()
+	disabledVariants = make(map[string]struct{})
+	disabledVariantsMutex.Unlock()
+
+	t.Cleanup(func() {
+		disabledVariantsPath = oldDisabledVariantsPath
+		disabledVariantsMutex.Lock()
+		disabledVariants = make(map[string]struct{})
+		disabledVariantsMutex.Unlock()
+	})
+}
+
+func toggleTestVariant(t *testing.T, variantName string, enabled string) {
+	query := url.Values{}
+	query.Set("name", variantName)
+	query.Set("enabled", enabled)
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/variantToggle?"+query.Encode(), nil)
+	httpLocalhostVariantToggle(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected a status code of %v, but got %v: %v", http.StatusOK, w.Code, w.Body)
+	}
+}
+
+func createTestTable(s *Session, variantName string) {
+	commandTableCreate(s, &CommandData{ // Manual invocation
+		Name: "Test Table",
+		Options: &Options{
+			VariantName: variantName,
+		},
+	})
+}
+
+func TestVariantToggle(t *testing.T) {
+	resetTestTables(t)
+	useTestDisabledVariants(t)
+	s, conn := newTestWebsocket(t, 1, "Alice", 0)
+
+	toggleTestVariant(t, "Rainbow (6 Suits)", "false")
+	createTestTable(s, "Rainbow (6 Suits)")
+	expectTestWarning(t, conn, "is temporarily disabled")
+	if len(tables) != 0 {
+		t.Fatalf("a table was created with a disabled variant")
+	}
+
+	// Existing tables cannot be changed to the disabled variant either
+	tb := newTestTable(t, 2)
+	commandTableSetVariant(tb.Players[0].Session, &CommandData{ // Manual invocation
+		TableID: tb.ID,
+		Options: &Options{
+			VariantName: "Rainbow (6 Suits)",
+		},
+		NoLock: true,
+	})
+	if tb.Options.VariantName != "No Variant" {
+		t.Error("the variant of a table was changed to a disabled variant")
+	}
+
+	// Other variants are not affected
+	if !validateVariantEnabled(s, "No Variant") {
+		t.Error("a variant that was not toggled is disabled")
+	}
+
+	// (creating a table requires a database, so we only check the validation)
+	toggleTestVariant(t, "Rainbow (6 Suits)", "true")
+	if !validateVariantEnabled(s, "Rainbow (6 Suits)") {
+		t.Error("the variant is still disabled after it was enabled again")
+	}
+}
+
+func TestDisabledVariantsPersist(t *testing.T) {
+	useTestDisabledVariants(t)
+
+	toggleTestVariant(t, "Rainbow (6 Suits)", "false")
+	toggleTestVariant(t, "Dark Rainbow (6 Suits)", "false")
+	toggleTestVariant(t, "Dark Rainbow (6 Suits)", "true")
+
+	// Simulate a restart
+	disabledVariantsMutex.Lock()
+	disabledVariants = make(map[string]struct{})
+	disabledVariantsMutex.Unlock()
+	oldDataPath := dataPath
+	dataPath = path.Dir(disabledVariantsPath)
+	t.Cleanup(func() {
+		dataPath = oldDataPath
+	})
+	disabledVariantsInit()
+
+	if !variantIsDisabled("Rainbow (6 Suits)") {
+		t.Error("the disabled variant was not loaded after a restart")
+	}
+	if variantIsDisabled("Dark Rainbow (6 Suits)") {
+		t.Error("the enabled variant was still disabled after a restart")
+	}
+}
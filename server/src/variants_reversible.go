@@ -84,13 +84,40 @@ func variantReversiblePlay(g *Game, c *Card) bool {
 	return failed
 }
 
-// variantReversibleGetMaxScore calculates what the maximum score is,
+// variantReversibleGetPlayableRanks returns the ranks that would successfully play on the given
+// stack (this mirrors the logic in the "variantReversiblePlay()" function)
+func variantReversibleGetPlayableRanks(g *Game, suitIndex int) []int {
+	// Local variables
+	variant := variants[g.Options.VariantName]
+	stack := g.Stacks[suitIndex]
+
+	switch g.PlayStackDirections[suitIndex] {
+	case StackDirectionUndecided:
+		if stack == 0 {
+			return []int{1, 5, StartCardRank}
+		} else if stack == StartCardRank {
+			return []int{2, 4}
+		}
+	case StackDirectionUp:
+		return []int{stack + 1}
+	case StackDirectionDown:
+		if !variant.IsUpOrDown() && stack == 0 {
+			return []int{5}
+		}
+		return []int{stack - 1}
+	}
+
+	// Once a stack is finished, nothing will play on it
+	return make([]int, 0)
+}
+
+// variantReversibleGetMaxScorePerStack calculates what the maximum score is for each stack,
 // accounting for stacks that cannot be completed due to discarded cards
-func variantReversibleGetMaxScore(g *Game) int {
+func variantReversibleGetMaxScorePerStack(g *Game) []int {
 	// Local variables
 	variant := variants[g.Options.VariantName]
 
-	maxScore := 0
+	maxScorePerStack := make([]int, len(g.Stacks))
 	for suitIndex := range g.Stacks {
 		// Make a map that shows if all of some particular rank in this suit has been discarded
 		ranks := []int{1, 2, 3, 4, 5}
@@ -107,20 +134,20 @@ func variantReversibleGetMaxScore(g *Game) int {
 		if g.PlayStackDirections[suitIndex] == StackDirectionUndecided {
 			upWalk := variantReversibleWalkUp(g, allDiscarded)
 			downWalk := variantReversibleWalkDown(g, allDiscarded)
-			maxScore += max(upWalk, downWalk)
+			maxScorePerStack[suitIndex] = max(upWalk, downWalk)
 		} else if g.PlayStackDirections[suitIndex] == StackDirectionUp {
-			maxScore += variantReversibleWalkUp(g, allDiscarded)
+			maxScorePerStack[suitIndex] = variantReversibleWalkUp(g, allDiscarded)
 		} else if g.PlayStackDirections[suitIndex] == StackDirectionDown {
-			maxScore += variantReversibleWalkDown(g, allDiscarded)
+			maxScorePerStack[suitIndex] = variantReversibleWalkDown(g, allDiscarded)
 		} else if g.PlayStackDirections[suitIndex] == StackDirectionFinished {
-			maxScore += 5
+			maxScorePerStack[suitIndex] = 5
 		}
 	}
 
-	return maxScore
+	return maxScorePerStack
 }
 
-// A helper function for "variantReversibleGetMaxScore()"
+// A helper function for "variantReversibleGetMaxScorePerStack()"
 func variantReversibleWalkUp(g *Game, allDiscarded map[int]bool) int {
 	// Local variables
 	variant := variants[g.Options.VariantName]
@@ -152,7 +179,7 @@ func variantReversibleWalkUp(g *Game, allDiscarded map[int]bool) int {
 	return cardsThatCanStillBePlayed
 }
 
-// A helper function for "variantReversibleGetMaxScore()"
+// A helper function for "variantReversibleGetMaxScorePerStack()"
 func variantReversibleWalkDown(g *Game, allDiscarded map[int]bool) int {
 	// Local variables
 	variant := variants[g.Options.VariantName]
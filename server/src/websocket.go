@@ -1,15 +1,48 @@
 package main
 
 import (
+	"os"
+	"strconv"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	melody "gopkg.in/olahol/melody.v1"
 )
 
+const (
+	DefaultWebSocketMaxMessageSize = 8192 // In bytes
+	DefaultWebSocketRateLimit      = 50   // In commands per second
+	DefaultDisconnectGraceSeconds  = 5
+	DefaultWebSocketPingInterval   = 54 // In seconds
+	DefaultWebSocketPongTimeout    = 60 // In seconds
+
+	DefaultWebSocketSendBufferSize = 256 // In messages
+
+	// Melody does not export its errors, so we have to match the text
+	MelodyBufferFullError = "session message buffer is full"
+
+	// How often to try closing a session whose send buffer is full
+	// (the close message has to wait for room in the same buffer)
+	SendBufferFullCloseRetryDelay = time.Millisecond * 100
+)
+
 var (
 	// This is the Melody WebSocket router
 	m *melody.Melody
 
+	// The maximum size of a WebSocket message (in bytes)
+	websocketMaxMessageSize int64
+
+	// Each session has a bucket of command tokens that refills at this many tokens per second
+	// (the bucket holds at most "rateLimitBurst" tokens)
+	rateLimitRate  float64
+	rateLimitBurst float64
+
+	// Players in an ongoing game are not marked as absent until they have been disconnected for
+	// this long (so that a brief network interruption does not affect the game)
+	disconnectGracePeriod time.Duration
+
 	// We keep track of all WebSocket sessions
 	sessions      = make(map[int]*Session)
 	sessionsMutex = sync.RWMutex{}
@@ -19,6 +52,15 @@ var (
 
 	// We keep track of all ongoing WebSocket messages/commands
 	commandWaitGroup sync.WaitGroup
+	// We also keep track of the names of the ongoing commands so that we can report which ones are
+	// stuck when shutting down (this is a map of unique command IDs to descriptions)
+	pendingCommands         sync.Map
+	pendingCommandIDCounter uint64 = 0
+
+	// The sessions with a full send buffer that are being disconnected
+	// Each Melody session is mapped to whether or not the last close message for it was dropped
+	// (see the "websocketCloseFullSession()" function)
+	fullSessionCloseDropped sync.Map
 )
 
 func websocketInit() {
@@ -32,12 +74,215 @@ func websocketInit() {
 	// The default maximum message size is 512 bytes,
 	// but this is not long enough to send game objects
 	// Thus, we have to manually increase it
-	m.Config.MaxMessageSize = 8192
+	maxMessageSizeString := os.Getenv("WEBSOCKET_MAX_MESSAGE_SIZE")
+	if len(maxMessageSizeString) == 0 {
+		websocketMaxMessageSize = DefaultWebSocketMaxMessageSize
+	} else {
+		if v, err := strconv.ParseInt(maxMessageSizeString, 10, 64); err != nil {
+			logger.Fatal("Failed to convert the \"WEBSOCKET_MAX_MESSAGE_SIZE\" " +
+				"environment variable to a number.")
+			return
+		} else {
+			websocketMaxMessageSize = v
+		}
+	}
+	if websocketMaxMessageSize <= 0 {
+		logger.Fatal("The \"WEBSOCKET_MAX_MESSAGE_SIZE\" environment variable must be positive.")
+		return
+	}
+	m.Config.MaxMessageSize = websocketMaxMessageSize
+	logger.Info("Using a maximum WebSocket message size of " +
+		strconv.FormatInt(websocketMaxMessageSize, 10) + " bytes.")
+
+	// Read the rate limit from the environment variables
+	rateLimit := DefaultWebSocketRateLimit
+	rateLimitString := os.Getenv("WEBSOCKET_RATE_LIMIT")
+	if len(rateLimitString) != 0 {
+		if v, err := strconv.Atoi(rateLimitString); err != nil {
+			logger.Fatal("Failed to convert the \"WEBSOCKET_RATE_LIMIT\" " +
+				"environment variable to a number.")
+			return
+		} else {
+			rateLimit = v
+		}
+	}
+	if rateLimit <= 0 {
+		logger.Fatal("The \"WEBSOCKET_RATE_LIMIT\" environment variable must be positive.")
+		return
+	}
+	rateLimitRate = float64(rateLimit)
+	rateLimitBurst = rateLimitRate * RateLimitBurstSeconds
+	logger.Info("Using a WebSocket rate limit of " + strconv.Itoa(rateLimit) +
+		" commands per second.")
+
+	// Melody periodically pings every client and closes the connection if a pong is not received
+	// in time, which will trigger the normal disconnect logic
+	// This prevents connections that died without being closed from lingering forever
+	pingInterval := DefaultWebSocketPingInterval
+	pingIntervalString := os.Getenv("WEBSOCKET_PING_INTERVAL")
+	if len(pingIntervalString) != 0 {
+		if v, err := strconv.Atoi(pingIntervalString); err != nil {
+			logger.Fatal("Failed to convert the \"WEBSOCKET_PING_INTERVAL\" " +
+				"environment variable to a number.")
+			return
+		} else {
+			pingInterval = v
+		}
+	}
+	pongTimeout := DefaultWebSocketPongTimeout
+	pongTimeoutString := os.Getenv("WEBSOCKET_PONG_TIMEOUT")
+	if len(pongTimeoutString) != 0 {
+		if v, err := strconv.Atoi(pongTimeoutString); err != nil {
+			logger.Fatal("Failed to convert the \"WEBSOCKET_PONG_TIMEOUT\" " +
+				"environment variable to a number.")
+			return
+		} else {
+			pongTimeout = v
+		}
+	}
+	if pingInterval <= 0 || pingInterval >= pongTimeout {
+		logger.Fatal("The \"WEBSOCKET_PING_INTERVAL\" environment variable must be positive and " +
+			"less than the \"WEBSOCKET_PONG_TIMEOUT\" environment variable.")
+		return
+	}
+	m.Config.PingPeriod = time.Duration(pingInterval) * time.Second
+	m.Config.PongWait = time.Duration(pongTimeout) * time.Second
+
+	// Messages to each client are queued in a buffer so that a slow client can never block the
+	// goroutine that is sending the message (e.g. when broadcasting a game action to a table)
+	// If a client falls so far behind that the buffer fills up, we disconnect them instead of
+	// silently dropping messages, since their game state would no longer be correct
+	sendBufferSize := DefaultWebSocketSendBufferSize
+	sendBufferSizeString := os.Getenv("WEBSOCKET_SEND_BUFFER_SIZE")
+	if len(sendBufferSizeString) != 0 {
+		if v, err := strconv.Atoi(sendBufferSizeString); err != nil {
+			logger.Fatal("Failed to convert the \"WEBSOCKET_SEND_BUFFER_SIZE\" " +
+				"environment variable to a number.")
+			return
+		} else {
+			sendBufferSize = v
+		}
+	}
+	if sendBufferSize <= 0 {
+		logger.Fatal("The \"WEBSOCKET_SEND_BUFFER_SIZE\" environment variable must be positive.")
+		return
+	}
+	m.Config.MessageBufferSize = sendBufferSize
+
+	// Read the disconnect grace period from the environment variables
+	graceSeconds := DefaultDisconnectGraceSeconds
+	graceSecondsString := os.Getenv("DISCONNECT_GRACE_SECONDS")
+	if len(graceSecondsString) != 0 {
+		if v, err := strconv.Atoi(graceSecondsString); err != nil {
+			logger.Fatal("Failed to convert the \"DISCONNECT_GRACE_SECONDS\" " +
+				"environment variable to a number.")
+			return
+		} else {
+			graceSeconds = v
+		}
+	}
+	if graceSeconds < 0 {
+		logger.Fatal("The \"DISCONNECT_GRACE_SECONDS\" environment variable cannot be negative.")
+		return
+	}
+	disconnectGracePeriod = time.Duration(graceSeconds) * time.Second
+
+	// Read what should happen when the active player disconnects in a timed game
+	activeDisconnectInit()
+
+	// Read how much time players get back after their turn is taken for them on a timeout
+	timeoutDiscardInit()
 
 	// Attach some handlers
 	m.HandleConnect(websocketConnect)
 	m.HandleDisconnect(websocketDisconnect)
 	m.HandleMessage(websocketMessage)
-	// We could also attach a function to HandleError, but this fires on routine
-	// things like disconnects, so it is undesirable
+	// The error handler also fires on routine things like disconnects,
+	// so it only handles full send buffers
+	m.HandleError(websocketError)
+}
+
+func websocketError(ms *melody.Session, err error) {
+	if err.Error() != MelodyBufferFullError {
+		return
+	}
+
+	s := &Session{
+		Session: ms,
+	}
+	if s.SendBufferFull() {
+		// We already started disconnecting them
+		// (this error is from the close message if they are still being disconnected,
+		// so it has to be sent again)
+		if v, ok := fullSessionCloseDropped.Load(ms); ok {
+			atomic.StoreInt32(v.(*int32), 1)
+		}
+		return
+	}
+	s.Set("sendBufferFull", true)
+	logger.Warning("The send buffer for user \"" + s.Username() + "\" is full; disconnecting them.")
+
+	// This is called from the goroutine that is sending the message, so we must not block here
+	go websocketCloseFullSession(s)
+}
+
+// websocketCloseFullSession is meant to be called in a new goroutine
+// The close message goes into the same buffer as every other message,
+// so we keep trying until there is room for it (or until the session is closed some other way)
+// Melody does not return an error when the close message is dropped because the buffer is full
+// (it only calls the error handler), so the error handler tells us about it instead
+// We stop as soon as the close message is in the buffer, since writing to a session while Melody
+// is closing it can panic
+// Once the connection is closed, the normal disconnect logic will run
+// (from the "websocketDisconnect()" function)
+func websocketCloseFullSession(s *Session) {
+	dropped := new(int32)
+	fullSessionCloseDropped.Store(s.Session, dropped)
+	defer fullSessionCloseDropped.Delete(s.Session)
+
+	for !s.IsClosed() {
+		atomic.StoreInt32(dropped, 0)
+		if err := s.CloseWithCode(CloseCodeSendBufferFull, CloseReasonSendBufferFull); err != nil {
+			// The session was closed in the meantime
+			return
+		}
+		if atomic.LoadInt32(dropped) == 0 {
+			// The connection will be closed once the close message is sent
+			return
+		}
+		time.Sleep(SendBufferFullCloseRetryDelay)
+	}
+}
+
+// websocketShutdown disconnects everyone and waits for any ongoing commands to finish so that the
+// game state is consistent before the tables are serialized to disk
+func websocketShutdown() {
+	logger.Info("Shutting down the WebSocket server...")
+
+	// Prevent any new commands from being processed
+	// (new connections will automatically be rejected once the Melody router is closed)
+	blockAllIncomingMessages.Set()
+	closeMsg := melody.FormatCloseMessage(CloseCodeShutdown, CloseReasonShutdown)
+	if err := m.CloseWithMsg(closeMsg); err != nil {
+		logger.Error("Failed to close the Melody router:", err)
+	}
+
+	logger.Info("Waiting for all ongoing WebSocket commands to finish execution...")
+	done := make(chan struct{})
+	go func() {
+		commandWaitGroup.Wait() // Will block until it the counter becomes 0
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		logger.Info("All WebSocket commands have completed.")
+	case <-time.After(WebSocketShutdownTimeout):
+		logger.Error("Timed out while waiting for the WebSocket commands to finish. " +
+			"The following commands are still pending:")
+		pendingCommands.Range(func(key interface{}, value interface{}) bool {
+			logger.Error("- " + value.(string))
+			return true
+		})
+	}
 }
@@ -26,10 +26,13 @@ type WebsocketConnectData struct {
 // This is the third step of logging in; users will only get here if authentication was successful
 func websocketConnect(ms *melody.Session) {
 	// Turn the Melody session into a custom session
-	s := &Session{ms}
+	s := &Session{Session: ms}
 
 	logger.Debug("Entered the \"websocketConnect()\" function for user: " + s.Username())
 
+	// Record when the connection was established (for debugging purposes)
+	s.Set("connectedAt", time.Now())
+
 	// First, perform all the expensive database retrieval to gather the data we need
 	// We want to do this before we start locking any mutexes (to minimize the lock time)
 	data := websocketConnectGetData(s)
@@ -41,6 +44,31 @@ func websocketConnect(ms *melody.Session) {
 	logger.Debug("Acquired session connection write lock for user: " + s.Username())
 	defer sessionConnectMutex.Unlock()
 
+	websocketConnectReplaceSession(s, data)
+
+	// Now, send some additional information to them
+	websocketConnectWelcomeMessage(s, data)
+	websocketConnectUserList(s)
+	websocketConnectTableList(s)
+	websocketConnectChat(s)
+	websocketConnectHistory(s)
+	websocketConnectHistoryFriends(s, data.Friends)
+
+	// Alert everyone that a new user has logged in
+	notifyAllUser(s)
+
+	// They might need to rejoin an ongoing game or shared replay
+	if data.PlayingInOngoingGame {
+		websocketConnectRejoinOngoingGame(s, data)
+	} else if data.SpectatingTable {
+		websocketConnectRespectate(s, data)
+	}
+}
+
+// websocketConnectReplaceSession closes any existing connection for the user and then adds the new
+// session to the session map
+// The "sessionConnectMutex" must be held when calling this function
+func websocketConnectReplaceSession(s *Session, data *WebsocketConnectData) {
 	// Disconnect any existing connections with this username
 	logger.Debug("Acquiring sessions read lock for user: " + s.Username())
 	sessionsMutex.RLock()
@@ -50,7 +78,10 @@ func websocketConnect(ms *melody.Session) {
 	if ok {
 		logger.Info("Closing existing connection for user \"" + s.Username() + "\".")
 		s2.Error("You have logged on from somewhere else, so you have been disconnected here.")
-		if err := s2.Close(); err != nil {
+		if err := s2.CloseWithCode(
+			CloseCodeDuplicateSession,
+			CloseReasonDuplicateSession,
+		); err != nil {
 			// This can occasionally fail and we don't want to report the error to Sentry
 			logger.Info("Failed to manually close a WebSocket connection.")
 		} else {
@@ -65,6 +96,12 @@ func websocketConnect(ms *melody.Session) {
 		websocketDisconnectRemoveFromGames(s2)
 	}
 
+	// Now that any stale session is cleaned up,
+	// check to see if they need to be put back into an ongoing game or a shared replay
+	// (this must be done after the stale session is removed from any games,
+	// since removing it from a game can change which tables they should return to)
+	websocketConnectGetTableData(s, data)
+
 	// Add the connection to a session map so that we can keep track of all of the connections
 	logger.Debug("Acquiring sessions write lock for user: " + s.Username())
 	sessionsMutex.Lock()
@@ -72,24 +109,6 @@ func websocketConnect(ms *melody.Session) {
 	sessions[s.UserID()] = s
 	sessionsMutex.Unlock()
 	logger.Info("User \""+s.Username()+"\" connected;", len(sessions), "user(s) now connected.")
-
-	// Now, send some additional information to them
-	websocketConnectWelcomeMessage(s, data)
-	websocketConnectUserList(s)
-	websocketConnectTableList(s)
-	websocketConnectChat(s)
-	websocketConnectHistory(s)
-	websocketConnectHistoryFriends(s, data.Friends)
-
-	// Alert everyone that a new user has logged in
-	notifyAllUser(s)
-
-	// They might need to rejoin an ongoing game or shared replay
-	if data.PlayingInOngoingGame {
-		websocketConnectRejoinOngoingGame(s, data)
-	} else if data.SpectatingTable {
-		websocketConnectRespectate(s, data)
-	}
 }
 
 func websocketConnectGetData(s *Session) *WebsocketConnectData {
@@ -135,6 +154,10 @@ func websocketConnectGetData(s *Session) *WebsocketConnectData {
 	}
 	data.FirstTimeUser = time.Since(datetimeCreated) < 10*time.Second
 
+	return data
+}
+
+func websocketConnectGetTableData(s *Session, data *WebsocketConnectData) {
 	// Check to see if they are currently playing in an ongoing game
 	logger.Debug("Acquiring tables read lock for user: " + s.Username())
 	tablesMutex.RLock()
@@ -153,17 +176,14 @@ func websocketConnectGetData(s *Session) *WebsocketConnectData {
 	}
 	tablesMutex.RUnlock()
 
-	// Check to see if they are were spectating in a shared replay before they disconnected
-	// (games that they are playing in take priority over shared replays)
+	// Check to see if they are were spectating a game or a shared replay before they disconnected
+	// or before the server restarted
+	// (games that they are playing in take priority over spectating)
 	if !data.PlayingInOngoingGame {
 		logger.Debug("Acquiring tables read lock for user: " + s.Username())
 		tablesMutex.RLock()
 		logger.Debug("Acquired tables read lock for user: " + s.Username())
 		for _, t := range tables {
-			if !t.Replay {
-				continue
-			}
-
 			for id := range t.DisconSpectators {
 				if id != s.UserID() {
 					continue
@@ -176,8 +196,6 @@ func websocketConnectGetData(s *Session) *WebsocketConnectData {
 		}
 		tablesMutex.RUnlock()
 	}
-
-	return data
 }
 
 func websocketConnectWelcomeMessage(s *Session, data *WebsocketConnectData) {
@@ -381,6 +399,9 @@ func websocketConnectRejoinOngoingGame(s *Session, data *WebsocketConnectData) {
 		TableID: data.PlayingInOngoingGameTableID,
 		NoLock:  true,
 	})
+
+	// If their clock was stopped while they were gone, start it again
+	t.Game.ActivePlayerReconnected(s, playerIndex)
 }
 
 func websocketConnectRespectate(s *Session, data *WebsocketConnectData) {
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestWebsocketConnectReplacesStaleSession(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+
+	// Alice is connected and playing in a game
+	s1, conn1 := newTestWebsocket(t, 1, "Alice", 0)
+	tb.Players[0].Session = s1
+	sessionConnectMutex.Lock()
+	websocketConnectReplaceSession(s1, &WebsocketConnectData{})
+	sessionConnectMutex.Unlock()
+
+	// She quickly reconnects (twice) before the first connection is cleaned up
+	s2, conn2 := newTestWebsocket(t, 1, "Alice", 0)
+	s3, _ := newTestWebsocket(t, 1, "Alice", 0)
+	data2 := &WebsocketConnectData{}
+	data3 := &WebsocketConnectData{}
+	sessionConnectMutex.Lock()
+	websocketConnectReplaceSession(s2, data2)
+	websocketConnectReplaceSession(s3, data3)
+	sessionConnectMutex.Unlock()
+
+	// The disconnect events for the old connections fire afterward
+	websocketDisconnect(s1.Session)
+	websocketDisconnect(s2.Session)
+
+	sessionsMutex.RLock()
+	numSessions := len(sessions)
+	s, ok := sessions[1]
+	sessionsMutex.RUnlock()
+	if numSessions != 1 {
+		t.Fatalf("expected exactly 1 session, but there are %v", numSessions)
+	}
+	if !ok || s.SessionID() != s3.SessionID() {
+		t.Error("the newest session is not the one in the session map")
+	}
+
+	// Both of the new connections need to be sent back to the game
+	if !data2.PlayingInOngoingGame || data2.PlayingInOngoingGameTableID != tb.ID ||
+		!data3.PlayingInOngoingGame || data3.PlayingInOngoingGameTableID != tb.ID {
+
+		t.Error("the reconnected session was not put back into the ongoing game")
+	}
+
+	// The old connections are told why they were closed
+	for _, conn := range []*websocket.Conn{conn1, conn2} {
+		expectTestError(t, conn, "You have logged on from somewhere else")
+		expectTestClose(t, conn, CloseCodeDuplicateSession)
+	}
+}
+
+func TestWebsocketConnectDuplicateLoginCloseCode(t *testing.T) {
+	resetTestTables(t)
+
+	s1, conn1 := newTestWebsocket(t, 1, "Alice", 0)
+	sessionConnectMutex.Lock()
+	websocketConnectReplaceSession(s1, &WebsocketConnectData{})
+	sessionConnectMutex.Unlock()
+
+	// Logging in again closes the first connection so that the client does not reconnect
+	s2, conn2 := newTestWebsocket(t, 1, "Alice", 0)
+	sessionConnectMutex.Lock()
+	websocketConnectReplaceSession(s2, &WebsocketConnectData{})
+	sessionConnectMutex.Unlock()
+	websocketDisconnect(s1.Session)
+
+	expectTestError(t, conn1, "You have logged on from somewhere else")
+	expectTestCloseWithReason(t, conn1, CloseCodeDuplicateSession, CloseReasonDuplicateSession)
+	if s2.IsClosed() {
+		t.Fatal("the new connection was closed")
+	}
+
+	// The new connection is unaffected
+	s2.Warning("marker")
+	expectTestWarning(t, conn2, "marker")
+}
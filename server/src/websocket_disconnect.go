@@ -2,16 +2,20 @@ package main
 
 import (
 	"strconv"
+	"time"
 
 	melody "gopkg.in/olahol/melody.v1"
 )
 
 func websocketDisconnect(ms *melody.Session) {
 	// Turn the Melody session into a custom session
-	s := &Session{ms}
+	s := &Session{Session: ms}
 
 	logger.Debug("Entered the \"websocketDisconnect()\" function for user: " + s.Username())
 
+	// Traces are only kept for as long as the session is connected
+	s.StopTrace()
+
 	// We only want one computer to connect to one user at a time
 	// Use a dedicated mutex to prevent race conditions
 	logger.Debug("Acquiring session connection write lock for user: " + s.Username())
@@ -19,9 +23,19 @@ func websocketDisconnect(ms *melody.Session) {
 	logger.Debug("Acquired session connection write lock for user: " + s.Username())
 	defer sessionConnectMutex.Unlock()
 
+	// This also applies to orphaned sessions, which are never removed from the map below
+	lobbyUnsubscribe(s)
+
 	if !websocketDisconnectRemoveFromMap(s) {
 		return
 	}
+
+	// When the server is restarting, everyone is disconnected before the tables are serialized,
+	// so we must leave the players and the spectators where they are
+	// (they will be put back into their games once they reconnect to the new server)
+	if blockAllIncomingMessages.IsSet() {
+		return
+	}
 	websocketDisconnectRemoveFromGames(s)
 
 	// Alert everyone that a user has logged out
@@ -55,6 +69,11 @@ func websocketDisconnectRemoveFromMap(s *Session) bool {
 }
 
 func websocketDisconnectRemoveFromGames(s *Session) {
+	// A user who is no longer connected cannot be seated in a game from the queue
+	if matchmakingRemove(s.UserID()) {
+		logger.Info("User \"" + s.Username() + "\" was removed from the queue.")
+	}
+
 	// Look for the disconnecting player in all of the tables
 	ongoingGameTableIDs := make([]uint64, 0)
 	preGameTableIDs := make([]uint64, 0)
@@ -83,6 +102,20 @@ func websocketDisconnectRemoveFromGames(s *Session) {
 	tablesMutex.RUnlock()
 
 	for _, ongoingGameTableID := range ongoingGameTableIDs {
+		// Their clock might need to be stopped if it is their turn
+		if t, exists := getTableAndLock(s, ongoingGameTableID, true); exists {
+			if playerIndex := t.GetPlayerIndexFromID(s.UserID()); playerIndex != -1 && t.Running {
+				t.Game.ActivePlayerDisconnected(s, playerIndex)
+			}
+			t.Mutex.Unlock()
+		}
+
+		if disconnectGracePeriod > 0 {
+			// Give them a chance to reconnect before they are marked as absent
+			go websocketDisconnectUnattendAfterGracePeriod(s, ongoingGameTableID, disconnectGracePeriod)
+			continue
+		}
+
 		logger.Info("Unattending player \"" + s.Username() + "\" from ongoing table " +
 			strconv.FormatUint(ongoingGameTableID, 10) + " since they disconnected.")
 		commandTableUnattend(s, &CommandData{ // Manual invocation
@@ -118,3 +151,43 @@ func websocketDisconnectRemoveFromGames(s *Session) {
 		t.Mutex.Unlock()
 	}
 }
+
+// websocketDisconnectUnattendAfterGracePeriod is meant to be run in a new goroutine
+func websocketDisconnectUnattendAfterGracePeriod(
+	s *Session,
+	tableID uint64,
+	gracePeriod time.Duration,
+) {
+	time.Sleep(gracePeriod)
+
+	// The server might have started restarting in the meantime
+	if blockAllIncomingMessages.IsSet() {
+		return
+	}
+
+	t, exists := getTableAndLock(s, tableID, true)
+	if !exists {
+		return
+	}
+	defer t.Mutex.Unlock()
+
+	// If they reconnected in the meantime, they will have already reattended the table with a new
+	// session (and if they disconnected again after that, that disconnect has its own grace period)
+	playerIndex := t.GetPlayerIndexFromID(s.UserID())
+	if playerIndex == -1 {
+		return
+	}
+	if s2 := t.Players[playerIndex].Session; s2 != nil && s2.SessionID() != s.SessionID() {
+		logger.Info("Player \"" + s.Username() + "\" reconnected to ongoing table " +
+			strconv.FormatUint(tableID, 10) + " within the grace period.")
+		return
+	}
+
+	logger.Info("Unattending player \"" + s.Username() + "\" from ongoing table " +
+		strconv.FormatUint(tableID, 10) + " since they disconnected " +
+		strconv.Itoa(int(gracePeriod.Seconds())) + " seconds ago.")
+	commandTableUnattend(s, &CommandData{ // Manual invocation
+		TableID: tableID,
+		NoLock:  true,
+	})
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// newTestSpectator adds a fake spectator to the table
+func newTestSpectator(tb *Table, id int, name string) *Spectator {
+	sp := &Spectator{
+		ID:                   id,
+		Name:                 name,
+		Session:              newTestSession(id, name),
+		ShadowingPlayerIndex: -1,
+	}
+	tb.Spectators = append(tb.Spectators, sp)
+	return sp
+}
+
+// addTestSessions puts the sessions of the players and the spectators of the table in the global
+// session map, as if they were connected
+func addTestSessions(tb *Table) {
+	sessionsMutex.Lock()
+	defer sessionsMutex.Unlock()
+
+	for _, p := range tb.Players {
+		sessions[p.ID] = p.Session
+	}
+	for _, sp := range tb.Spectators {
+		sessions[sp.ID] = sp.Session
+	}
+}
+
+func TestWebsocketDisconnectDuringShutdownLeavesTablesAlone(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+	sp := newTestSpectator(tb, 10, "Spectator")
+	addTestSessions(tb)
+
+	oldDisconnectGracePeriod := disconnectGracePeriod
+	disconnectGracePeriod = 0
+	t.Cleanup(func() {
+		disconnectGracePeriod = oldDisconnectGracePeriod
+	})
+
+	blockAllIncomingMessages.Set()
+	for _, p := range tb.Players {
+		websocketDisconnect(p.Session.Session)
+	}
+	websocketDisconnect(sp.Session.Session)
+
+	sessionsMutex.RLock()
+	numSessions := len(sessions)
+	sessionsMutex.RUnlock()
+	if numSessions != 0 {
+		t.Errorf("expected every session to be removed, but there are %v left", numSessions)
+	}
+
+	for _, p := range tb.Players {
+		if !p.Present {
+			t.Errorf("player \"%v\" was marked as absent during the shutdown", p.Name)
+		}
+	}
+	if tb.GetSpectatorIndexFromID(sp.ID) == -1 {
+		t.Error("the spectator was removed from the table during the shutdown")
+	}
+	if _, ok := tb.DisconSpectators[sp.ID]; ok {
+		t.Error("the spectator was marked as disconnected during the shutdown")
+	}
+	if tb.Game.Paused {
+		t.Error("the game was paused during the shutdown")
+	}
+}
+
+func TestWebsocketDisconnectRemovesSpectator(t *testing.T) {
+	resetTestTables(t)
+	tb := newTestGame(t, 2)
+	sp := newTestSpectator(tb, 10, "Spectator")
+	addTestSessions(tb)
+
+	websocketDisconnect(sp.Session.Session)
+
+	if tb.GetSpectatorIndexFromID(sp.ID) != -1 {
+		t.Error("the spectator was not removed from the table")
+	}
+	if _, ok := tb.DisconSpectators[sp.ID]; !ok {
+		t.Error("the spectator was not marked as disconnected")
+	}
+}
+
+func TestWebsocketShutdownWaitsForCommands(t *testing.T) {
+	resetTestTables(t)
+
+	commandWaitGroup.Add(1)
+	done := make(chan struct{})
+	go func() {
+		websocketShutdown()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("the shutdown finished while a command was still running")
+	case <-time.After(100 * time.Millisecond):
+	}
+	if blockAllIncomingMessages.IsNotSet() {
+		t.Error("incoming messages were not blocked during the shutdown")
+	}
+
+	commandWaitGroup.Done()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the shutdown did not finish after the command completed")
+	}
+}
+
+// useTestDisconnectGracePeriod changes the grace period for the duration of the test
+func useTestDisconnectGracePeriod(t *testing.T, gracePeriod time.Duration) {
+	oldDisconnectGracePeriod := disconnectGracePeriod
+	disconnectGracePeriod = gracePeriod
+	t.Cleanup(func() {
+		disconnectGracePeriod = oldDisconnectGracePeriod
+	})
+}
+
+// isTestPlayerPresent returns whether the player is present while holding the table lock
+// (since the grace period goroutine might be modifying it)
+func isTestPlayerPresent(tb *Table, playerIndex int) bool {
+	tb.Mutex.Lock()
+	defer tb.Mutex.Unlock()
+	return tb.Players[playerIndex].Present
+}
+
+// rejoinTestPlayer connects a new session for the player and puts it back into the game
+func rejoinTestPlayer(tb *Table, playerIndex int) *Session {
+	p := tb.Players[playerIndex]
+	s := newTestSession(p.ID, p.Name)
+	s.Set("sessionID", p.Session.SessionID()+1000)
+
+	sessionsMutex.Lock()
+	sessions[p.ID] = s
+	sessionsMutex.Unlock()
+	websocketConnectRejoinOngoingGame(s, &WebsocketConnectData{
+		PlayingInOngoingGame:        true,
+		PlayingInOngoingGameTableID: tb.ID,
+	})
+
+	return s
+}
+
+func TestWebsocketDisconnectReconnectWithinGracePeriod(t *testing.T) {
+	resetTestTables(t)
+	useTestDisconnectGracePeriod(t, 200*time.Millisecond)
+	tb := newTestGame(t, 2)
+	addTestSessions(tb)
+
+	// It is not their turn, so nothing else happens when they disconnect
+	p := tb.Players[1]
+	websocketDisconnect(p.Session.Session)
+	if !isTestPlayerPresent(tb, 1) {
+		t.Fatal("the player was marked as absent before the grace period elapsed")
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	rejoinTestPlayer(tb, 1)
+
+	time.Sleep(300 * time.Millisecond)
+	if !isTestPlayerPresent(tb, 1) {
+		t.Error("the player was marked as absent even though they reconnected")
+	}
+}
+
+func TestWebsocketDisconnectPastGracePeriod(t *testing.T) {
+	resetTestTables(t)
+	useTestDisconnectGracePeriod(t, 200*time.Millisecond)
+	tb := newTestGame(t, 2)
+	addTestSessions(tb)
+
+	websocketDisconnect(tb.Players[1].Session.Session)
+	if !isTestPlayerPresent(tb, 1) {
+		t.Fatal("the player was marked as absent before the grace period elapsed")
+	}
+
+	time.Sleep(300 * time.Millisecond)
+	if isTestPlayerPresent(tb, 1) {
+		t.Error("the player was not marked as absent after the grace period elapsed")
+	}
+}
+
+func TestWebsocketDisconnectTwiceWithinGracePeriod(t *testing.T) {
+	resetTestTables(t)
+	useTestDisconnectGracePeriod(t, 600*time.Millisecond)
+	tb := newTestGame(t, 2)
+	addTestSessions(tb)
+
+	// They reconnect and then disconnect again before the first grace period is over
+	websocketDisconnect(tb.Players[1].Session.Session)
+	time.Sleep(100 * time.Millisecond)
+	s := rejoinTestPlayer(tb, 1)
+	time.Sleep(200 * time.Millisecond)
+	websocketDisconnect(s.Session)
+
+	// The first grace period does not cut the second one short
+	time.Sleep(400 * time.Millisecond)
+	if !isTestPlayerPresent(tb, 1) {
+		t.Fatal("the player was marked as absent before the second grace period elapsed")
+	}
+
+	time.Sleep(400 * time.Millisecond)
+	if isTestPlayerPresent(tb, 1) {
+		t.Error("the player was not marked as absent after the second grace period elapsed")
+	}
+}
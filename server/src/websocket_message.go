@@ -3,15 +3,19 @@ package main
 import (
 	"encoding/json"
 	"net"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	melody "gopkg.in/olahol/melody.v1"
 )
 
 const (
-	RateLimitRate = float64(100) // Number of messages sent
-	RateLimitPer  = float64(2)   // Per seconds
+	// A session can send a burst of commands worth this many seconds of the rate limit
+	RateLimitBurstSeconds = float64(2)
+	// The number of times that a session can exceed the rate limit before it is disconnected
+	RateLimitMaxViolations = 5
 )
 
 // websocketMessage is fired every time a WebSocket user sends a message to the server
@@ -37,13 +41,19 @@ func websocketMessage(ms *melody.Session, msg []byte) {
 	defer commandWaitGroup.Done()
 
 	// Turn the Melody session into a custom session
-	s := &Session{ms}
+	s := &Session{Session: ms}
 
 	if s.Banned() {
-		// We already banned this user, so ignore any of their remaining messages in the queue
+		// We already disconnected this user, so ignore any of their remaining messages in the queue
 		return
 	}
 
+	// Attach context about the user to any log messages
+	logFields := LogFields{
+		"userID":   s.UserID(),
+		"username": s.Username(),
+	}
+
 	if !s.FakeUser() {
 		// Validate that the user is not attempting to flood the server
 		// Algorithm from: http://stackoverflow.com/questions/667508
@@ -51,15 +61,37 @@ func websocketMessage(ms *melody.Session, msg []byte) {
 		timePassed := now.Sub(s.RateLimitLastCheck()).Seconds()
 		s.Set("rateLimitLastCheck", now)
 
-		newRateLimitAllowance := s.RateLimitAllowance() + timePassed*(RateLimitRate/RateLimitPer)
-		if newRateLimitAllowance > RateLimitRate {
-			newRateLimitAllowance = RateLimitRate
+		newRateLimitAllowance := s.RateLimitAllowance() + timePassed*rateLimitRate
+		if newRateLimitAllowance > rateLimitBurst {
+			newRateLimitAllowance = rateLimitBurst
 		}
 
 		if newRateLimitAllowance < 1 {
-			// They are flooding, so automatically ban them
-			logger.Warning("User \"" + s.Username() + "\" triggered rate-limiting; banning them.")
-			ban(s)
+			// They are flooding, so drop this command
+			s.Set("rateLimitAllowance", newRateLimitAllowance)
+			violations := s.RateLimitViolations() + 1
+			s.Set("rateLimitViolations", violations)
+
+			if violations >= RateLimitMaxViolations {
+				logger.WarningWithFields(logFields, "User \""+s.Username()+"\" triggered "+
+					"rate-limiting "+strconv.Itoa(violations)+" times; banning them.")
+
+				// Ignore any of their remaining messages in the queue
+				s.Set("banned", true)
+				ban(s)
+				if err := s.CloseWithCode(
+					CloseCodeRateLimited,
+					CloseReasonRateLimited,
+				); err != nil {
+					logger.ErrorWithFields(logFields, "Failed to close the session for user "+
+						"\""+s.Username()+"\":", err)
+				}
+				return
+			}
+
+			logger.WarningWithFields(logFields, "User \""+s.Username()+"\" triggered rate-limiting; "+
+				"dropping their command.")
+			s.WarningWithCode(ErrRateLimited, "You are sending commands too quickly. Please slow down.")
 			return
 		}
 
@@ -75,17 +107,20 @@ func websocketMessage(ms *melody.Session, msg []byte) {
 	// We use SplitN() with a value of 2 instead of Split() so that if there is a space in the JSON,
 	// the data part of the splice doesn't get messed up
 	if len(result) != 2 {
-		logger.Error("User \"" + s.Username() + "\" sent an invalid WebSocket message (with no data attached to the command).")
+		logger.ErrorWithFields(logFields, "User \""+s.Username()+"\" sent an invalid WebSocket "+
+			"message (with no data attached to the command).")
 		return
 	}
 	command := result[0]
 	jsonData := []byte(result[1])
+	logFields["command"] = command
+	s.RecordTrace(true, command, result[1])
 
 	// Check to see if there is a command handler for this command
 	var commandMapFunction func(*Session, *CommandData)
 	if v, ok := commandMap[command]; !ok {
-		logger.Error("User \"" + s.Username() + "\" sent an invalid command of " +
-			"\"" + command + "\".")
+		logger.ErrorWithFields(logFields, "User \""+s.Username()+"\" sent an invalid command of "+
+			"\""+command+"\".")
 		return
 	} else {
 		commandMapFunction = v
@@ -94,13 +129,25 @@ func websocketMessage(ms *melody.Session, msg []byte) {
 	// Unmarshal the JSON (this code is taken from Golem)
 	var d *CommandData
 	if err := json.Unmarshal(jsonData, &d); err != nil {
-		logger.Error("User \"" + s.Username() + "\" sent a command of " +
-			"\"" + command + "\" with invalid data: " + string(jsonData))
+		logger.ErrorWithFields(logFields, "User \""+s.Username()+"\" sent a command of "+
+			"\""+command+"\" with invalid data: "+string(jsonData))
 		return
 	}
 
+	// Record the last command that this user sent (for debugging purposes)
+	s.Set("lastCommand", command)
+
+	// Record that this command is in progress
+	commandID := atomic.AddUint64(&pendingCommandIDCounter, 1)
+	pendingCommands.Store(commandID, command+" - "+s.Username())
+	defer pendingCommands.Delete(commandID)
+
 	// Call the command handler for this command
-	logger.Info("Command - " + command + " - " + s.Username())
+	if d != nil && d.TableID != 0 {
+		logFields["tableID"] = d.TableID
+	}
+	logger.InfoWithFields(logFields, "Command - "+command+" - "+s.Username())
+	metricsCommandsProcessed.Inc()
 	commandMapFunction(s, d)
 }
 
@@ -128,6 +175,5 @@ func ban(s *Session) {
 		return
 	}
 
-	logoutUser(s.UserID())
 	logger.Info("Successfully banned user \"" + s.Username() + "\" from IP address \"" + ip + "\".")
 }
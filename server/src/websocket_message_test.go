@@ -0,0 +1,63 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWebsocketMessageRateLimit(t *testing.T) {
+	s, conn := newTestWebsocket(t, 1, "Alice", 0)
+
+	// Use up the whole budget of the session so that every command after the first is dropped
+	s.Set("rateLimitAllowance", float64(1))
+	s.Set("rateLimitLastCheck", time.Now())
+	for i := 0; i < RateLimitMaxViolations; i++ {
+		websocketMessage(s.Session, []byte("testFlood {}"))
+	}
+
+	if violations := s.RateLimitViolations(); violations != RateLimitMaxViolations-1 {
+		t.Fatalf("expected %v rate limit violations, but got %v",
+			RateLimitMaxViolations-1, violations)
+	}
+	if s.Banned() {
+		t.Error("the session was disconnected before reaching the maximum number of violations")
+	}
+	for i := 0; i < RateLimitMaxViolations-1; i++ {
+		expectTestWarning(t, conn, "too quickly")
+	}
+
+}
+
+func TestWebsocketMessageRateLimitBan(t *testing.T) {
+	if db == nil {
+		t.Skip("banning the IP address of a flooding user requires a database")
+	}
+
+	s, conn := newTestWebsocket(t, 1, "Alice", 0)
+	s.Set("rateLimitAllowance", float64(0))
+	s.Set("rateLimitLastCheck", time.Now())
+	s.Set("rateLimitViolations", RateLimitMaxViolations-1)
+
+	// One more violation bans the user and closes the connection
+	websocketMessage(s.Session, []byte("testFlood {}"))
+	if !s.Banned() {
+		t.Error("the session was not banned after reaching the maximum number of violations")
+	}
+	expectTestClose(t, conn, CloseCodeRateLimited)
+}
+
+func TestWebsocketMessageRateLimitRefill(t *testing.T) {
+	s, _ := newTestWebsocket(t, 1, "Alice", 0)
+
+	// A session that has been idle for a while gets its budget back (up to the burst size)
+	s.Set("rateLimitAllowance", float64(0))
+	s.Set("rateLimitLastCheck", time.Now().Add(-time.Hour))
+	websocketMessage(s.Session, []byte("testFlood {}"))
+
+	if violations := s.RateLimitViolations(); violations != 0 {
+		t.Errorf("expected no rate limit violations, but got %v", violations)
+	}
+	if allowance := s.RateLimitAllowance(); allowance != rateLimitBurst-1 {
+		t.Errorf("expected an allowance of %v, but got %v", rateLimitBurst-1, allowance)
+	}
+}
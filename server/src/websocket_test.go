@@ -0,0 +1,182 @@
+package main
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	melody "gopkg.in/olahol/melody.v1"
+)
+
+// reinitTestWebsocket runs "websocketInit()" again with the current environment variables and
+// restores the defaults after the test finishes
+func reinitTestWebsocket(t *testing.T) {
+	t.Cleanup(websocketInit)
+	websocketInit()
+}
+
+func TestWebsocketMaxMessageSize(t *testing.T) {
+	reinitTestWebsocket(t)
+	if websocketMaxMessageSize != DefaultWebSocketMaxMessageSize {
+		t.Errorf("expected the default maximum message size of %v, but got %v",
+			DefaultWebSocketMaxMessageSize, websocketMaxMessageSize)
+	}
+
+	setTestEnv(t, "WEBSOCKET_MAX_MESSAGE_SIZE", "65536")
+	reinitTestWebsocket(t)
+	if websocketMaxMessageSize != 65536 {
+		t.Errorf("expected a maximum message size of 65536, but got %v", websocketMaxMessageSize)
+	}
+	if m.Config.MaxMessageSize != 65536 {
+		t.Errorf("expected Melody to use a maximum message size of 65536, but it uses %v",
+			m.Config.MaxMessageSize)
+	}
+}
+
+func TestWebsocketPingReapsDeadConnections(t *testing.T) {
+	resetTestTables(t)
+	setTestEnv(t, "WEBSOCKET_PING_INTERVAL", "1")
+	setTestEnv(t, "WEBSOCKET_PONG_TIMEOUT", "2")
+	reinitTestWebsocket(t)
+	if m.Config.PingPeriod != time.Second || m.Config.PongWait != 2*time.Second {
+		t.Fatalf("expected Melody to use a ping period of 1s and a pong wait of 2s, "+
+			"but it uses %v and %v", m.Config.PingPeriod, m.Config.PongWait)
+	}
+
+	router := melody.New()
+	router.Config.PingPeriod = m.Config.PingPeriod
+	router.Config.PongWait = m.Config.PongWait
+	router.HandleDisconnect(websocketDisconnect)
+	router.HandleError(websocketError)
+	t.Cleanup(func() {
+		router.Close() // nolint: errcheck
+	})
+
+	// The first client never reads from the connection, so it never answers the pings
+	// (the WebSocket library automatically answers pings while reading)
+	dead, _ := connectTestWebsocket(t, router, 1, "Alice")
+	alive, aliveConn := connectTestWebsocket(t, router, 2, "Bob")
+	go func() {
+		for {
+			if _, _, err := aliveConn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+	sessionsMutex.Lock()
+	sessions[dead.UserID()] = dead
+	sessions[alive.UserID()] = alive
+	sessionsMutex.Unlock()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		sessionsMutex.RLock()
+		_, deadOK := sessions[dead.UserID()]
+		_, aliveOK := sessions[alive.UserID()]
+		sessionsMutex.RUnlock()
+		if !aliveOK {
+			t.Fatal("a connection that answered the pings was removed from the session map")
+		}
+		if !deadOK {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Error("the dead connection was not removed from the session map")
+}
+
+func TestWebsocketSendBufferFullDropsStalledSession(t *testing.T) {
+	resetTestTables(t)
+	numMessages := 1000
+
+	// Wait for the disconnect logic of both sessions to finish before the next test starts
+	var disconnected sync.WaitGroup
+	disconnected.Add(2)
+	t.Cleanup(disconnected.Wait)
+	onDisconnect := func(ms *melody.Session) {
+		websocketDisconnect(ms)
+		disconnected.Done()
+	}
+
+	// The stalled client has a small buffer so that it fills up quickly
+	stalledRouter := melody.New()
+	stalledRouter.Config.MessageBufferSize = 4
+	stalledRouter.HandleDisconnect(onDisconnect)
+	stalledRouter.HandleError(websocketError)
+	aliveRouter := melody.New()
+	aliveRouter.Config.MessageBufferSize = numMessages
+	aliveRouter.HandleDisconnect(onDisconnect)
+	aliveRouter.HandleError(websocketError)
+	t.Cleanup(func() {
+		stalledRouter.Close() // nolint: errcheck
+		aliveRouter.Close()   // nolint: errcheck
+	})
+	stalled, stalledConn := connectTestWebsocket(t, stalledRouter, 1, "Alice")
+	alive, aliveConn := connectTestWebsocket(t, aliveRouter, 2, "Bob")
+	sessionsMutex.Lock()
+	sessions[stalled.UserID()] = stalled
+	sessions[alive.UserID()] = alive
+	sessionsMutex.Unlock()
+
+	var numReceived int32
+	go func() {
+		for {
+			if _, msg, err := aliveConn.ReadMessage(); err != nil {
+				return
+			} else if strings.HasPrefix(string(msg), "testMessage ") {
+				atomic.AddInt32(&numReceived, 1)
+			}
+		}
+	}()
+
+	// The stalled client does not read anything until the server has given up on it
+	// (there are a lot of messages so that the operating system buffers fill up as well)
+	data := strings.Repeat("a", 8000)
+	start := time.Now()
+	for i := 0; i < numMessages; i++ {
+		stalled.Emit("testMessage", data)
+		alive.Emit("testMessage", data)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("sending the messages took %v, so the stalled session blocked the others",
+			elapsed)
+	}
+	if !stalled.SendBufferFull() {
+		t.Fatal("the send buffer of the stalled session did not fill up")
+	}
+
+	// Several attempts to close the connection fail while the buffer is still full,
+	// and then the client starts reading again
+	time.Sleep(5 * SendBufferFullCloseRetryDelay)
+	if stalled.IsClosed() {
+		t.Fatal("the stalled session was closed before its buffer had room for the close message")
+	}
+	expectTestClose(t, stalledConn, CloseCodeSendBufferFull)
+
+	// The session goes through the normal disconnect logic
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		sessionsMutex.RLock()
+		_, ok := sessions[stalled.UserID()]
+		sessionsMutex.RUnlock()
+		if !ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the stalled session was not removed from the session map")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// The other client still gets every message
+	deadline = time.Now().Add(5 * time.Second)
+	for atomic.LoadInt32(&numReceived) != int32(numMessages) {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the other client to receive %v messages, but it received %v",
+				numMessages, atomic.LoadInt32(&numReceived))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}